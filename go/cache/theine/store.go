@@ -360,6 +360,26 @@ func (s *Store[K, V]) Set(key K, value V, cost int64, epoch uint32) bool {
 	return ok
 }
 
+// Admit lets the next Set of the key through the doorkeeper, as if the key
+// had already been seen once. It is meant to warm the cache up with keys
+// that are known not to be one-offs.
+func (s *Store[K, V]) Admit(key K) {
+	if !s.doorkeeper {
+		return
+	}
+	h, index := s.index(key)
+	shard := s.shards[index]
+	shard.mu.Lock()
+	defer shard.mu.Unlock()
+	if shard.counter > uint(shard.doorkeeper.Capacity) {
+		shard.doorkeeper.Reset()
+		shard.counter = 0
+	}
+	if !shard.doorkeeper.Insert(h) {
+		shard.counter += 1
+	}
+}
+
 type dequeKV[K cachekey, V cacheval] struct {
 	k K
 	v V
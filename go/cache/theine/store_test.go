@@ -80,3 +80,18 @@ func TestDoorKeeperDynamicSize(t *testing.T) {
 	}
 	require.True(t, shard.doorkeeper.Capacity > 100000)
 }
+
+func TestDoorKeeperAdmit(t *testing.T) {
+	store := NewStore[keyint, cachedint](200000, true)
+	defer store.Close()
+
+	// The first Set of a key is rejected as a possible one-off.
+	require.False(t, store.Set(1, 1, 0, 0))
+	require.True(t, store.Set(1, 1, 0, 0))
+
+	store.Admit(2)
+	require.True(t, store.Set(2, 2, 0, 0))
+	v, ok := store.Get(2, 0)
+	require.True(t, ok)
+	require.Equal(t, cachedint(2), v)
+}
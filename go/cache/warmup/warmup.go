@@ -0,0 +1,213 @@
+/*
+Copyright 2026 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package warmup persists the keys of a plan cache to a local file, so the
+// cache can be warmed up by replanning them after a restart.
+//
+// The snapshot file holds one JSON encoded key per line, the most useful
+// ones first.
+package warmup
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"vitess.io/vitess/go/vt/log"
+)
+
+// Config configures the snapshots and the warm-up of a plan cache.
+type Config struct {
+	// File is the path of the snapshot file. The snapshots and the warm-up
+	// are disabled if it is empty.
+	File string
+	// Interval is how often the keys of the cache are snapshotted.
+	Interval time.Duration
+	// MaxEntries caps the number of keys snapshotted and replanned.
+	MaxEntries int
+	// MaxDuration caps the time spent warming the cache up.
+	MaxDuration time.Duration
+}
+
+// Enabled returns true if a snapshot file is configured.
+func (c Config) Enabled() bool {
+	return c.File != ""
+}
+
+// Save atomically replaces the snapshot file with the given keys.
+func Save[K any](path string, keys []K) error {
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	w := bufio.NewWriter(tmp)
+	enc := json.NewEncoder(w)
+	for _, key := range keys {
+		if err := enc.Encode(key); err != nil {
+			tmp.Close()
+			return err
+		}
+	}
+	if err := w.Flush(); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
+
+// Load reads up to maxEntries keys from the snapshot file. All the keys are
+// read if maxEntries is not positive.
+func Load[K any](path string, maxEntries int) ([]K, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var keys []K
+	dec := json.NewDecoder(bufio.NewReader(f))
+	for maxEntries <= 0 || len(keys) < maxEntries {
+		var key K
+		if err := dec.Decode(&key); err != nil {
+			if errors.Is(err, io.EOF) {
+				break
+			}
+			return keys, err
+		}
+		keys = append(keys, key)
+	}
+	return keys, nil
+}
+
+// WarmUp replans the first MaxEntries keys of the snapshot file, in order,
+// until they are all replanned, MaxDuration elapsed or ctx is done. It
+// returns the number of keys that were successfully replanned.
+// A missing snapshot file is not an error: there is nothing to warm up.
+func WarmUp[K any](ctx context.Context, config Config, replan func(ctx context.Context, key K) error) int {
+	if !config.Enabled() {
+		return 0
+	}
+	start := time.Now()
+	keys, err := Load[K](config.File, config.MaxEntries)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		// A truncated snapshot is still worth replanning.
+		log.Warningf("Error reading the plan cache snapshot %s: %v", config.File, err)
+	}
+	if len(keys) == 0 {
+		return 0
+	}
+
+	if config.MaxDuration > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, config.MaxDuration)
+		defer cancel()
+	}
+	replanned, failed := 0, 0
+	for _, key := range keys {
+		if ctx.Err() != nil {
+			break
+		}
+		if err := replan(ctx, key); err != nil {
+			failed++
+			continue
+		}
+		replanned++
+	}
+	log.Infof("Warmed up the plan cache from %s in %v: %d of %d queries replanned, %d failed", config.File, time.Since(start), replanned, len(keys), failed)
+	return replanned
+}
+
+// Snapshotter periodically saves the keys of a plan cache to the snapshot
+// file of its Config.
+type Snapshotter[K any] struct {
+	config Config
+	// keys returns the keys to snapshot, the most useful ones first, and
+	// at most maxEntries of them if it is positive.
+	keys func(maxEntries int) []K
+
+	mu     sync.Mutex
+	cancel context.CancelFunc
+	done   chan struct{}
+}
+
+// NewSnapshotter creates a Snapshotter. keys returns the keys to snapshot,
+// the most useful ones first, and at most maxEntries of them if it is
+// positive.
+func NewSnapshotter[K any](config Config, keys func(maxEntries int) []K) *Snapshotter[K] {
+	return &Snapshotter[K]{config: config, keys: keys}
+}
+
+// Start starts the periodic snapshots. It is a no-op if the snapshots are
+// disabled or already started.
+func (s *Snapshotter[K]) Start() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if !s.config.Enabled() || s.config.Interval <= 0 || s.cancel != nil {
+		return
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	s.cancel = cancel
+	s.done = make(chan struct{})
+	go func() {
+		defer close(s.done)
+		ticker := time.NewTicker(s.config.Interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				s.Snapshot()
+			}
+		}
+	}()
+}
+
+// Stop stops the periodic snapshots and takes a last one.
+func (s *Snapshotter[K]) Stop() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.cancel == nil {
+		return
+	}
+	s.cancel()
+	<-s.done
+	s.cancel = nil
+	s.Snapshot()
+}
+
+// Snapshot saves the keys of the cache. An empty cache does not overwrite
+// the previous snapshot, e.g. one that could not be replanned yet.
+func (s *Snapshotter[K]) Snapshot() {
+	keys := s.keys(s.config.MaxEntries)
+	if len(keys) == 0 {
+		return
+	}
+	if err := Save(s.config.File, keys); err != nil {
+		log.Warningf("Error saving the plan cache snapshot %s: %v", s.config.File, err)
+	}
+}
@@ -0,0 +1,149 @@
+/*
+Copyright 2026 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package warmup
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type testKey struct {
+	Keyspace string
+	Query    string
+}
+
+func TestSaveLoad(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "plans")
+	keys := []testKey{{"ks", "select 1"}, {"ks", "select\n2"}, {"other", "select 3"}}
+	require.NoError(t, Save(path, keys))
+
+	loaded, err := Load[testKey](path, 0)
+	require.NoError(t, err)
+	assert.Equal(t, keys, loaded)
+
+	loaded, err = Load[testKey](path, 2)
+	require.NoError(t, err)
+	assert.Equal(t, keys[:2], loaded)
+
+	// Saving replaces the previous snapshot and leaves no temporary file.
+	require.NoError(t, Save(path, keys[2:]))
+	loaded, err = Load[testKey](path, 0)
+	require.NoError(t, err)
+	assert.Equal(t, keys[2:], loaded)
+	entries, err := os.ReadDir(filepath.Dir(path))
+	require.NoError(t, err)
+	assert.Len(t, entries, 1)
+}
+
+func TestLoadTruncated(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "plans")
+	require.NoError(t, os.WriteFile(path, []byte("{\"Keyspace\":\"ks\",\"Query\":\"select 1\"}\n{\"Keyspace\":\"ks\",\"Qu"), 0o600))
+
+	loaded, err := Load[testKey](path, 0)
+	assert.Error(t, err)
+	assert.Equal(t, []testKey{{"ks", "select 1"}}, loaded)
+}
+
+func TestWarmUp(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "plans")
+	keys := []testKey{{"ks", "select 1"}, {"ks", "select 2"}, {"ks", "select 3"}, {"ks", "select 4"}}
+	require.NoError(t, Save(path, keys))
+
+	var replanned []testKey
+	replan := func(ctx context.Context, key testKey) error {
+		replanned = append(replanned, key)
+		if key.Query == "select 2" {
+			return errors.New("cannot plan")
+		}
+		return nil
+	}
+
+	n := WarmUp(context.Background(), Config{File: path, MaxEntries: 3}, replan)
+	assert.Equal(t, 2, n)
+	assert.Equal(t, keys[:3], replanned)
+
+	// Nothing to warm up.
+	replanned = nil
+	assert.Zero(t, WarmUp(context.Background(), Config{}, replan))
+	assert.Zero(t, WarmUp(context.Background(), Config{File: filepath.Join(t.TempDir(), "missing")}, replan))
+	assert.Empty(t, replanned)
+}
+
+func TestWarmUpMaxDuration(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "plans")
+	require.NoError(t, Save(path, []testKey{{"ks", "select 1"}, {"ks", "select 2"}}))
+
+	replan := func(ctx context.Context, key testKey) error {
+		<-ctx.Done()
+		return ctx.Err()
+	}
+	start := time.Now()
+	n := WarmUp(context.Background(), Config{File: path, MaxDuration: 10 * time.Millisecond}, replan)
+	assert.Zero(t, n)
+	assert.Less(t, time.Since(start), 5*time.Second)
+}
+
+func TestSnapshotter(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "plans")
+
+	var mu sync.Mutex
+	var keys []testKey
+	var maxEntries []int
+	s := NewSnapshotter(Config{File: path, Interval: time.Millisecond, MaxEntries: 10}, func(n int) []testKey {
+		mu.Lock()
+		defer mu.Unlock()
+		maxEntries = append(maxEntries, n)
+		return keys
+	})
+
+	// An empty cache is not snapshotted.
+	s.Snapshot()
+	_, err := os.Stat(path)
+	assert.ErrorIs(t, err, os.ErrNotExist)
+
+	s.Start()
+	mu.Lock()
+	keys = []testKey{{"ks", "select 1"}}
+	mu.Unlock()
+	assert.Eventually(t, func() bool {
+		loaded, err := Load[testKey](path, 0)
+		return err == nil && len(loaded) == 1
+	}, 5*time.Second, time.Millisecond)
+
+	mu.Lock()
+	keys = []testKey{{"ks", "select 1"}, {"ks", "select 2"}}
+	mu.Unlock()
+	s.Stop()
+	// Stopping takes a last snapshot.
+	loaded, err := Load[testKey](path, 0)
+	require.NoError(t, err)
+	assert.Equal(t, keys, loaded)
+	mu.Lock()
+	assert.Equal(t, 10, maxEntries[len(maxEntries)-1])
+	mu.Unlock()
+
+	// Stopping twice is a no-op.
+	s.Stop()
+}
@@ -79,6 +79,7 @@ var (
 	allowFirstBackup    bool
 	restartBeforeBackup bool
 	upgradeSafe         bool
+	verifyBackupName    string
 
 	// vttablet-like flags
 	initDbNameOverride string
@@ -208,6 +209,7 @@ func init() {
 	Main.Flags().BoolVar(&allowFirstBackup, "allow_first_backup", allowFirstBackup, "Allow this job to take the first backup of an existing shard.")
 	Main.Flags().BoolVar(&restartBeforeBackup, "restart_before_backup", restartBeforeBackup, "Perform a mysqld clean/full restart after applying binlogs, but before taking the backup. Only makes sense to work around xtrabackup bugs.")
 	Main.Flags().BoolVar(&upgradeSafe, "upgrade-safe", upgradeSafe, "Whether to use innodb_fast_shutdown=0 for the backup so it is safe to use for MySQL upgrades.")
+	Main.Flags().StringVar(&verifyBackupName, "verify-backup", verifyBackupName, "Instead of taking a backup, verify the named backup of the shard: restore it into a throwaway mysqld, run CHECK TABLE and compare its GTID position with the manifest. The result is recorded alongside the backup.")
 
 	// vttablet-like flags
 	utils.SetFlagStringVar(Main.Flags(), &initDbNameOverride, "init-db-name-override", initDbNameOverride, "(init parameter) override the name of the db used by vttablet")
@@ -283,6 +285,10 @@ func run(cc *cobra.Command, args []string) error {
 		}
 	}
 
+	if verifyBackupName != "" {
+		return verifyBackup(ctx)
+	}
+
 	// Try to take a backup, if it's been long enough since the last one.
 	// Skip pruning if backup wasn't fully successful. We don't want to be
 	// deleting things if the backup process is not healthy.
@@ -652,6 +658,37 @@ func takeBackup(ctx, backgroundCtx context.Context, topoServer *topo.Server, bac
 	return nil
 }
 
+// verifyBackup verifies the --verify-backup backup, and fails if it does not
+// pass the verification.
+func verifyBackup(ctx context.Context) error {
+	dbName := initDbNameOverride
+	if dbName == "" {
+		dbName = fmt.Sprintf("vt_%s", initKeyspace)
+	}
+	result, err := mysqlctl.VerifyBackup(ctx, mysqlctl.VerifyParams{
+		Logger:               logutil.NewConsoleLogger(),
+		Keyspace:             initKeyspace,
+		Shard:                initShard,
+		BackupName:           verifyBackupName,
+		DbName:               dbName,
+		Concurrency:          concurrency,
+		MysqlPort:            mysqlPort,
+		InitDBSQLFile:        initDBSQLFile,
+		MysqlTimeout:         mysqlTimeout,
+		MysqlShutdownTimeout: mysqlShutdownTimeout,
+		CollationEnv:         collationEnv,
+		Stats:                backupstats.RestoreStats(),
+	})
+	if err != nil {
+		return fmt.Errorf("can't verify backup %v: %w", verifyBackupName, err)
+	}
+	if !result.Success() {
+		return fmt.Errorf("backup %v failed verification: %+v", verifyBackupName, *result)
+	}
+	log.Infof("Backup %v passed verification.", verifyBackupName)
+	return nil
+}
+
 func resetReplication(ctx context.Context, pos replication.Position, mysqld mysqlctl.MysqlDaemon) error {
 	if err := mysqld.StopReplication(ctx, nil); err != nil {
 		return vterrors.Wrap(err, "failed to stop replication")
@@ -776,7 +813,7 @@ func pruneBackups(ctx context.Context, backupStorage backupstorage.BackupStorage
 		}
 		// Remove the backup.
 		log.Infof("Removing old backup %v from %v, since it's older than min_retention_time of %v", backup.Name(), backupDir, minRetentionTime)
-		if err := backupStorage.RemoveBackup(ctx, backupDir, backup.Name()); err != nil {
+		if err := mysqlctl.RemoveBackup(ctx, backupStorage, backupDir, backup.Name(), logutil.NewConsoleLogger()); err != nil {
 			return fmt.Errorf("couldn't remove backup %v from %v: %v", backup.Name(), backupDir, err)
 		}
 		// We successfully removed one backup. Can we afford to prune any more?
@@ -786,6 +823,12 @@ func pruneBackups(ctx context.Context, backupStorage backupstorage.BackupStorage
 			break
 		}
 	}
+	if numBackups < len(backups) {
+		// Remove the chunks of deduplicated backups that only the pruned backups referenced.
+		if _, err := mysqlctl.RemoveUnreferencedChunks(ctx, backupStorage, backupDir); err != nil {
+			return fmt.Errorf("couldn't remove unreferenced chunks from %v: %v", backupDir, err)
+		}
+	}
 	return nil
 }
 
@@ -60,6 +60,34 @@ If no replica-type tablet can be found, the backup can be taken on the primary i
 		Args:                  cobra.ExactArgs(1),
 		RunE:                  commandGetBackups,
 	}
+	// PruneBackups makes a PruneBackups gRPC call to a vtctld.
+	PruneBackups = &cobra.Command{
+		Use:   "PruneBackups [--keep-full <count>] [--keep-for <duration>] [--keep-recovery-from <RFC3339 time>] [--dry-run] <keyspace/shard>",
+		Short: "Removes the backups of the given shard that the retention policy does not keep.",
+		Long: `Removes the backups of the given shard that the retention policy does not keep.
+
+A backup is kept if it is one of the --keep-full most recent full backups, if it was taken within --keep-for, or if
+it is needed for a point-in-time recovery to any time since --keep-recovery-from. A full or incremental backup that a
+kept incremental backup needs to be restored is never removed. Backups that may still be in progress are kept.
+
+With --dry-run, the backups that would be removed are reported, and nothing is removed.`,
+		DisableFlagsInUseLine: true,
+		Args:                  cobra.ExactArgs(1),
+		RunE:                  commandPruneBackups,
+	}
+	// RebuildBackupCatalog makes a RebuildBackupCatalog gRPC call to a vtctld.
+	RebuildBackupCatalog = &cobra.Command{
+		Use:   "RebuildBackupCatalog <keyspace/shard>",
+		Short: "Rebuilds the catalog of the backups of the given shard from the backups.",
+		Long: `Rebuilds the catalog of the backups of the given shard from the backups.
+
+The catalog indexes the backups of a shard in the backup storage, and is kept up to date as backups are taken,
+verified and removed. Rebuilding it reads the MANIFEST of every backup, so it is only needed if the catalog was lost
+or got out of date, e.g. after backups were removed from the backup storage directly.`,
+		DisableFlagsInUseLine: true,
+		Args:                  cobra.ExactArgs(1),
+		RunE:                  commandRebuildBackupCatalog,
+	}
 	// RemoveBackup makes a RemoveBackup gRPC call to a vtctld.
 	RemoveBackup = &cobra.Command{
 		Use:                   "RemoveBackup <keyspace/shard> <backup name>",
@@ -76,6 +104,19 @@ If no replica-type tablet can be found, the backup can be taken on the primary i
 		Args:                  cobra.ExactArgs(1),
 		RunE:                  commandRestoreFromBackup,
 	}
+	// VerifyBackup makes a VerifyBackup gRPC call to a vtctld.
+	VerifyBackup = &cobra.Command{
+		Use:   "VerifyBackup [--concurrency <concurrency>] [--mysql-port <port>] <keyspace/shard> <backup name>",
+		Short: "Restores the given backup into a throwaway mysqld to prove it is restorable.",
+		Long: `Restores the given backup into a throwaway mysqld to prove it is restorable.
+
+The backup is restored by vtctld into a temporary data directory, then CHECK TABLE is run on all its tables
+and the restored GTID position is compared with the one of the backup manifest. The result is recorded in
+the VERIFICATION file of the backup. vtctld must be able to run mysqld, as vttablet does.`,
+		DisableFlagsInUseLine: true,
+		Args:                  cobra.ExactArgs(2),
+		RunE:                  commandVerifyBackup,
+	}
 )
 
 var backupOptions = struct {
@@ -210,6 +251,77 @@ func commandGetBackups(cmd *cobra.Command, args []string) error {
 	return nil
 }
 
+var pruneBackupsOptions = struct {
+	KeepFull         int32
+	KeepFor          time.Duration
+	KeepRecoveryFrom string
+	DryRun           bool
+}{}
+
+func commandPruneBackups(cmd *cobra.Command, args []string) error {
+	keyspace, shard, err := topoproto.ParseKeyspaceShard(cmd.Flags().Arg(0))
+	if err != nil {
+		return err
+	}
+
+	req := &vtctldatapb.PruneBackupsRequest{
+		Keyspace: keyspace,
+		Shard:    shard,
+		KeepFull: pruneBackupsOptions.KeepFull,
+		KeepFor:  protoutil.DurationToProto(pruneBackupsOptions.KeepFor),
+		DryRun:   pruneBackupsOptions.DryRun,
+	}
+
+	if pruneBackupsOptions.KeepRecoveryFrom != "" {
+		t, err := mysqlctl.ParseRFC3339(pruneBackupsOptions.KeepRecoveryFrom)
+		if err != nil {
+			return err
+		}
+
+		req.KeepRecoveryFrom = protoutil.TimeToProto(t)
+	}
+
+	cli.FinishedParsing(cmd)
+
+	resp, err := client.PruneBackups(commandCtx, req)
+	if err != nil {
+		return err
+	}
+
+	data, err := cli.MarshalJSON(resp)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("%s\n", data)
+	return nil
+}
+
+func commandRebuildBackupCatalog(cmd *cobra.Command, args []string) error {
+	keyspace, shard, err := topoproto.ParseKeyspaceShard(cmd.Flags().Arg(0))
+	if err != nil {
+		return err
+	}
+
+	cli.FinishedParsing(cmd)
+
+	resp, err := client.RebuildBackupCatalog(commandCtx, &vtctldatapb.RebuildBackupCatalogRequest{
+		Keyspace: keyspace,
+		Shard:    shard,
+	})
+	if err != nil {
+		return err
+	}
+
+	data, err := cli.MarshalJSON(resp)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("%s\n", data)
+	return nil
+}
+
 func commandRemoveBackup(cmd *cobra.Command, args []string) error {
 	keyspace, shard, err := topoproto.ParseKeyspaceShard(cmd.Flags().Arg(0))
 	if err != nil {
@@ -291,6 +403,44 @@ func commandRestoreFromBackup(cmd *cobra.Command, args []string) error {
 	}
 }
 
+var verifyBackupOptions = struct {
+	Concurrency int32
+	MysqlPort   int32
+}{}
+
+func commandVerifyBackup(cmd *cobra.Command, args []string) error {
+	keyspace, shard, err := topoproto.ParseKeyspaceShard(cmd.Flags().Arg(0))
+	if err != nil {
+		return err
+	}
+
+	name := cmd.Flags().Arg(1)
+
+	cli.FinishedParsing(cmd)
+
+	resp, err := client.VerifyBackup(commandCtx, &vtctldatapb.VerifyBackupRequest{
+		Keyspace:    keyspace,
+		Shard:       shard,
+		Name:        name,
+		Concurrency: verifyBackupOptions.Concurrency,
+		MysqlPort:   verifyBackupOptions.MysqlPort,
+	})
+	if err != nil {
+		return err
+	}
+
+	data, err := cli.MarshalJSON(resp)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("%s\n", data)
+	if !resp.Success {
+		return fmt.Errorf("backup %s of %s/%s failed verification", name, keyspace, shard)
+	}
+	return nil
+}
+
 func init() {
 	Backup.Flags().BoolVar(&backupOptions.AllowPrimary, "allow-primary", false, "Allow the primary of a shard to be used for the backup. WARNING: If using the builtin backup engine, this will shutdown mysqld on the primary and stop writes for the duration of the backup.")
 	Backup.Flags().Int32Var(&backupOptions.Concurrency, "concurrency", 4, "Specifies the number of compression/checksum jobs to run simultaneously.")
@@ -312,6 +462,14 @@ func init() {
 	GetBackups.Flags().BoolVarP(&getBackupsOptions.OutputJSON, "json", "j", false, "Output backup info in JSON format rather than a list of backups.")
 	Root.AddCommand(GetBackups)
 
+	PruneBackups.Flags().Int32Var(&pruneBackupsOptions.KeepFull, "keep-full", 1, "Number of most recent full backups to keep. Must be at least 1.")
+	PruneBackups.Flags().DurationVar(&pruneBackupsOptions.KeepFor, "keep-for", 0, "Keep the backups taken within this duration. Zero disables this rule.")
+	PruneBackups.Flags().StringVar(&pruneBackupsOptions.KeepRecoveryFrom, "keep-recovery-from", "", "Keep the backups needed for a point-in-time recovery to any time since this timestamp, in RFC3339 format (`2006-01-02T15:04:05Z07:00`).")
+	PruneBackups.Flags().BoolVar(&pruneBackupsOptions.DryRun, "dry-run", false, "Only report the backups that would be removed, do not remove them.")
+	Root.AddCommand(PruneBackups)
+
+	Root.AddCommand(RebuildBackupCatalog)
+
 	Root.AddCommand(RemoveBackup)
 
 	RestoreFromBackup.Flags().StringVarP(&restoreFromBackupOptions.BackupTimestamp, "backup-timestamp", "t", "", "Use the backup taken at, or closest before, this timestamp. Omit to use the latest backup. Timestamp format is \"YYYY-mm-DD.HHMMSS\".")
@@ -320,4 +478,8 @@ func init() {
 	RestoreFromBackup.Flags().StringVar(&restoreFromBackupOptions.RestoreToTimestamp, "restore-to-timestamp", "", "Run a point in time recovery that restores up to, and excluding, given timestamp in RFC3339 format (`2006-01-02T15:04:05Z07:00`). This will attempt to use one full backup followed by zero or more incremental backups")
 	RestoreFromBackup.Flags().BoolVar(&restoreFromBackupOptions.DryRun, "dry-run", false, "Only validate restore steps, do not actually restore data")
 	Root.AddCommand(RestoreFromBackup)
+
+	VerifyBackup.Flags().Int32Var(&verifyBackupOptions.Concurrency, "concurrency", 4, "Specifies the number of files to restore simultaneously.")
+	VerifyBackup.Flags().Int32Var(&verifyBackupOptions.MysqlPort, "mysql-port", 0, "Port of the throwaway mysqld the backup is restored into. If 0, a free port is picked.")
+	Root.AddCommand(VerifyBackup)
 }
@@ -0,0 +1,126 @@
+/*
+Copyright 2026 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package command
+
+import (
+	"encoding/hex"
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"vitess.io/vitess/go/cmd/vtctldclient/cli"
+
+	vtctldatapb "vitess.io/vitess/go/vt/proto/vtctldata"
+)
+
+var (
+	// GetTenantDirectory makes a GetTenantDirectory gRPC call to a vtctld.
+	GetTenantDirectory = &cobra.Command{
+		Use:                   "GetTenantDirectory <keyspace> <vindex>",
+		Short:                 "Prints the content of a directory vindex.",
+		Long:                  "Prints the content of a directory vindex, as a JSON map of tenant to hex encoded keyspace id.",
+		DisableFlagsInUseLine: true,
+		Args:                  cobra.ExactArgs(2),
+		RunE:                  commandGetTenantDirectory,
+	}
+	// UpdateTenantDirectory makes an UpdateTenantDirectory gRPC call to a vtctld.
+	UpdateTenantDirectory = &cobra.Command{
+		Use:                   "UpdateTenantDirectory [--set <tenant>=<hex keyspace id> ...] [--remove <tenant> ...] <keyspace> <vindex>",
+		Short:                 "Adds, replaces or removes tenants in a directory vindex.",
+		Long:                  "Adds, replaces or removes tenants in a directory vindex.\nThe changes are applied atomically, and vtgates pick them up without a VSchema rebuild.",
+		Example:               "UpdateTenantDirectory --set acme=80 --set globex=c000 --remove initech commerce tenant_directory",
+		DisableFlagsInUseLine: true,
+		Args:                  cobra.ExactArgs(2),
+		RunE:                  commandUpdateTenantDirectory,
+	}
+)
+
+func commandGetTenantDirectory(cmd *cobra.Command, args []string) error {
+	cli.FinishedParsing(cmd)
+
+	resp, err := client.GetTenantDirectory(commandCtx, &vtctldatapb.GetTenantDirectoryRequest{
+		Keyspace: cmd.Flags().Arg(0),
+		Vindex:   cmd.Flags().Arg(1),
+	})
+	if err != nil {
+		return err
+	}
+
+	return printTenantDirectory(resp.TenantDirectory.GetKeyspaceIds())
+}
+
+var updateTenantDirectoryOptions = struct {
+	Set    []string
+	Remove []string
+}{}
+
+func commandUpdateTenantDirectory(cmd *cobra.Command, args []string) error {
+	if len(updateTenantDirectoryOptions.Set) == 0 && len(updateTenantDirectoryOptions.Remove) == 0 {
+		return fmt.Errorf("at least one of --set or --remove must be specified")
+	}
+
+	set := make(map[string][]byte, len(updateTenantDirectoryOptions.Set))
+	for _, entry := range updateTenantDirectoryOptions.Set {
+		tenant, hexKsid, ok := strings.Cut(entry, "=")
+		if !ok || tenant == "" {
+			return fmt.Errorf("invalid --set value %q, expected <tenant>=<hex keyspace id>", entry)
+		}
+		ksid, err := hex.DecodeString(hexKsid)
+		if err != nil {
+			return fmt.Errorf("invalid keyspace id for tenant %s: %w", tenant, err)
+		}
+		set[tenant] = ksid
+	}
+
+	cli.FinishedParsing(cmd)
+
+	resp, err := client.UpdateTenantDirectory(commandCtx, &vtctldatapb.UpdateTenantDirectoryRequest{
+		Keyspace: cmd.Flags().Arg(0),
+		Vindex:   cmd.Flags().Arg(1),
+		Set:      set,
+		Remove:   updateTenantDirectoryOptions.Remove,
+	})
+	if err != nil {
+		return err
+	}
+
+	return printTenantDirectory(resp.TenantDirectory.GetKeyspaceIds())
+}
+
+func printTenantDirectory(ksids map[string][]byte) error {
+	out := make(map[string]string, len(ksids))
+	for tenant, ksid := range ksids {
+		out[tenant] = hex.EncodeToString(ksid)
+	}
+
+	data, err := cli.MarshalJSON(out)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("%s\n", data)
+	return nil
+}
+
+func init() {
+	Root.AddCommand(GetTenantDirectory)
+
+	UpdateTenantDirectory.Flags().StringArrayVar(&updateTenantDirectoryOptions.Set, "set", nil, "A tenant to add or replace, as <tenant>=<hex keyspace id>. May be repeated.")
+	UpdateTenantDirectory.Flags().StringArrayVar(&updateTenantDirectoryOptions.Remove, "remove", nil, "A tenant to remove. May be repeated.")
+	Root.AddCommand(UpdateTenantDirectory)
+}
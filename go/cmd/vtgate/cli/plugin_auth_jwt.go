@@ -0,0 +1,45 @@
+/*
+Copyright 2026 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cli
+
+// This plugin imports jwtauthserver to register the JWT implementation of AuthServer.
+
+import (
+	"time"
+
+	"vitess.io/vitess/go/mysql/jwtauthserver"
+	"vitess.io/vitess/go/vt/vtgate"
+)
+
+var jwtAuthConfig = jwtauthserver.Config{
+	UsernameClaim: "sub",
+	GroupsClaim:   "groups",
+	Leeway:        time.Minute,
+}
+
+func init() {
+	Main.Flags().StringVar(&jwtAuthConfig.JWKSFile, "mysql-auth-jwt-jwks-file", jwtAuthConfig.JWKSFile, "JWKS file holding the keys JWTs used as MySQL passwords are signed with, for --mysql-auth-server-impl=jwt. Reloaded on SIGHUP.")
+	Main.Flags().StringVar(&jwtAuthConfig.PublicKeysFile, "mysql-auth-jwt-public-keys-file", jwtAuthConfig.PublicKeysFile, "PEM file holding the issuer public keys or certificates JWTs used as MySQL passwords are signed with, for --mysql-auth-server-impl=jwt. Reloaded on SIGHUP.")
+	Main.Flags().StringVar(&jwtAuthConfig.Issuer, "mysql-auth-jwt-issuer", jwtAuthConfig.Issuer, "If set, the issuer JWTs must have in their iss claim.")
+	Main.Flags().StringVar(&jwtAuthConfig.Audience, "mysql-auth-jwt-audience", jwtAuthConfig.Audience, "If set, the audience JWTs must have in their aud claim.")
+	Main.Flags().StringVar(&jwtAuthConfig.UsernameClaim, "mysql-auth-jwt-username-claim", jwtAuthConfig.UsernameClaim, "JWT claim holding the Vitess username. It must match the MySQL user the client connects as.")
+	Main.Flags().StringVar(&jwtAuthConfig.GroupsClaim, "mysql-auth-jwt-groups-claim", jwtAuthConfig.GroupsClaim, "JWT claim holding the groups used by table ACLs.")
+	Main.Flags().DurationVar(&jwtAuthConfig.Leeway, "mysql-auth-jwt-leeway", jwtAuthConfig.Leeway, "Clock skew allowed when validating the JWT exp, nbf and iat claims.")
+	Main.Flags().DurationVar(&jwtAuthConfig.ReloadInterval, "mysql-auth-jwt-reload-interval", jwtAuthConfig.ReloadInterval, "If set, how often to reload the JWT signing keys.")
+
+	vtgate.RegisterPluginInitializer(func() { jwtauthserver.Init(jwtAuthConfig) })
+}
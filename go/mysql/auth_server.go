@@ -62,6 +62,13 @@ type AuthServer interface {
 	DefaultAuthMethodDescription() AuthMethodDescription
 }
 
+// ConnectionClosedAuthServer is an optional interface for auth servers that
+// keep state for the connections they authenticated. ConnectionClosed is
+// called when a connection is closed.
+type ConnectionClosedAuthServer interface {
+	ConnectionClosed(c *Conn)
+}
+
 // AuthMethod interface for concrete auth method implementations.
 // When building an auth server, you usually don't implement these yourself
 // but the helper methods to build AuthMethod instances should be used.
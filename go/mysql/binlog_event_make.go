@@ -324,6 +324,32 @@ func NewMariaDBGTIDEvent(f BinlogFormat, s *FakeBinlogStream, gtid replication.M
 	return NewMariadbBinlogEvent(ev)
 }
 
+// NewMySQL56GTIDEvent returns a MySQL 5.6 specific GTID event.
+func NewMySQL56GTIDEvent(f BinlogFormat, s *FakeBinlogStream, gtid replication.Mysql56GTID, lastCommitted, sequenceNumber int64) BinlogEvent {
+	length := 1 + // flags
+		16 + // SID
+		8 + // GNO
+		1 + // lt_type
+		8 + // last_committed
+		8 // sequence_number
+	data := make([]byte, length)
+
+	copy(data[1:17], gtid.Server[:])
+	binary.LittleEndian.PutUint64(data[17:25], uint64(gtid.Sequence))
+	data[25] = 2 // logical timestamps
+	binary.LittleEndian.PutUint64(data[26:34], uint64(lastCommitted))
+	binary.LittleEndian.PutUint64(data[34:42], uint64(sequenceNumber))
+
+	ev := s.Packetize(f, eGTIDEvent, 0, data)
+	return NewMysql56BinlogEvent(ev)
+}
+
+// NewMySQL56PreviousGTIDsEvent returns a MySQL 5.6 specific PreviousGTIDs event.
+func NewMySQL56PreviousGTIDsEvent(f BinlogFormat, s *FakeBinlogStream, gtids replication.Mysql56GTIDSet) BinlogEvent {
+	ev := s.Packetize(f, ePreviousGTIDsEvent, 0, gtids.SIDBlock())
+	return NewMysql56BinlogEvent(ev)
+}
+
 // NewTableMapEvent returns a TableMap event.
 // Only works with post_header_length=8.
 func NewTableMapEvent(f BinlogFormat, s *FakeBinlogStream, tableID uint64, tm *TableMap) BinlogEvent {
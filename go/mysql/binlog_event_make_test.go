@@ -190,6 +190,42 @@ func TestMariadDBGTIDEVent(t *testing.T) {
 	}
 }
 
+func TestMySQL56GTIDEvent(t *testing.T) {
+	f := NewMySQL56BinlogFormat()
+	s := NewFakeBinlogStream()
+
+	gtid := replication.Mysql56GTID{Server: replication.SID{1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16}, Sequence: 0x123456789abcdef0}
+	event := NewMySQL56GTIDEvent(f, s, gtid, 7, 8)
+	require.True(t, event.IsValid(), "NewMySQL56GTIDEvent().IsValid() is false")
+	require.True(t, event.IsGTID(), "NewMySQL56GTIDEvent().IsGTID() is false")
+
+	event, _, err := event.StripChecksum(f)
+	require.NoError(t, err)
+	got, hasBegin, lastCommitted, sequenceNumber, err := event.GTID(f)
+	require.NoError(t, err)
+	assert.False(t, hasBegin)
+	assert.Equal(t, gtid, got)
+	assert.EqualValues(t, 7, lastCommitted)
+	assert.EqualValues(t, 8, sequenceNumber)
+}
+
+func TestMySQL56PreviousGTIDsEvent(t *testing.T) {
+	f := NewMySQL56BinlogFormat()
+	s := NewFakeBinlogStream()
+
+	pos, err := replication.ParsePosition(replication.Mysql56FlavorID, "16b1039f-22b6-11ed-b765-0a43f95f28a3:1-100")
+	require.NoError(t, err)
+	event := NewMySQL56PreviousGTIDsEvent(f, s, pos.GTIDSet.(replication.Mysql56GTIDSet))
+	require.True(t, event.IsValid(), "NewMySQL56PreviousGTIDsEvent().IsValid() is false")
+	require.True(t, event.IsPreviousGTIDs(), "NewMySQL56PreviousGTIDsEvent().IsPreviousGTIDs() is false")
+
+	event, _, err = event.StripChecksum(f)
+	require.NoError(t, err)
+	got, err := event.PreviousGTIDs(f)
+	require.NoError(t, err)
+	assert.True(t, pos.Equal(got))
+}
+
 func TestTableMapEvent(t *testing.T) {
 	f := NewMySQL56BinlogFormat()
 	s := NewFakeBinlogStream()
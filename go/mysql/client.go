@@ -183,7 +183,7 @@ func ConnectWithAttributes(ctx context.Context, params *ConnParams, attributes C
 // Ping implements mysql ping command.
 func (c *Conn) Ping() error {
 	// This is a new command, need to reset the sequence.
-	c.sequence = 0
+	c.resetSequence()
 	data, pos := c.startEphemeralPacketWithHeader(1)
 	data[pos] = ComPing
 
@@ -204,6 +204,53 @@ func (c *Conn) Ping() error {
 	return vterrors.Errorf(vtrpcpb.Code_INTERNAL, "unexpected packet type: %d", data[0])
 }
 
+// ChangeUser authenticates as another user on the same connection, with
+// COM_CHANGE_USER. It uses the Uname, Pass, DbName and Charset of params.
+// The server resets the session, as if it was a new connection.
+// Returns a SQLError.
+func (c *Conn) ChangeUser(params *ConnParams) error {
+	// This is a new command, need to reset the sequence.
+	c.resetSequence()
+
+	// Scramble the password with the last salt we got, for the auth
+	// method we last used. The server asks to switch if needed.
+	var scrambledPassword []byte
+	if c.authPluginName == CachingSha2Password {
+		scrambledPassword = ScrambleCachingSha2Password(c.salt, []byte(params.Pass))
+	} else {
+		scrambledPassword = ScrambleMysqlNativePassword(c.salt, []byte(params.Pass))
+	}
+
+	length := 1 + // command
+		lenNullString(params.Uname) +
+		1 + len(scrambledPassword) + // auth-response
+		lenNullString(params.DbName) +
+		2 + // character set
+		lenNullString(string(c.authPluginName))
+	data, pos := c.startEphemeralPacketWithHeader(length)
+	pos = writeByte(data, pos, ComChangeUser)
+	pos = writeNullString(data, pos, params.Uname)
+	pos = writeByte(data, pos, byte(len(scrambledPassword)))
+	pos += copy(data[pos:], scrambledPassword)
+	pos = writeNullString(data, pos, params.DbName)
+	pos = writeUint16(data, pos, uint16(params.Charset))
+	pos = writeNullString(data, pos, string(c.authPluginName))
+
+	// Sanity check.
+	if pos != len(data) {
+		return sqlerror.NewSQLErrorf(sqlerror.CRMalformedPacket, sqlerror.SSUnknownSQLState, "ChangeUser: only packed %v bytes, out of %v allocated", pos, len(data))
+	}
+	if err := c.writeEphemeralPacket(); err != nil {
+		return sqlerror.NewSQLErrorf(sqlerror.CRServerGone, sqlerror.SSUnknownSQLState, "cannot send ComChangeUser: %v", err)
+	}
+
+	if err := c.handleAuthResponse(params); err != nil {
+		return err
+	}
+	c.schemaName = params.DbName
+	return nil
+}
+
 // clientHandshake handles the client side of the handshake.
 // Note the connection can be closed while this is running.
 // Returns a SQLError.
@@ -309,6 +356,20 @@ func (c *Conn) clientHandshake(params *ConnParams, attributes ConnectionAttribut
 		c.Capabilities |= CapabilityClientConnAttr
 	}
 
+	// Compression, if asked for in the flags and supported by the server.
+	// Like the MySQL client, we only ask for one algorithm, zstd first.
+	// If the server doesn't support any, we just don't compress.
+	switch compression := uint32(params.Flags) & capabilities; {
+	case compression&CapabilityClientZstdCompressionAlgorithm != 0:
+		c.Capabilities |= CapabilityClientZstdCompressionAlgorithm
+		c.zstdCompressionLevel = DefaultZstdCompressionLevel
+		if params.ZstdCompressionLevel != 0 {
+			c.zstdCompressionLevel = uint8(params.ZstdCompressionLevel)
+		}
+	case compression&CapabilityClientCompress != 0:
+		c.Capabilities |= CapabilityClientCompress
+	}
+
 	// Build and send our handshake response 41.
 	// Note this one will never have SSL flag on.
 	if err := c.writeHandshakeResponse41(capabilities, scrambledPassword, uint8(params.Charset), params, attributes); err != nil {
@@ -320,6 +381,9 @@ func (c *Conn) clientHandshake(params *ConnParams, attributes ConnectionAttribut
 		return err
 	}
 
+	// Switch to the compressed protocol if it was negotiated.
+	c.enableCompression()
+
 	// If the server didn't support DbName in its handshake, set
 	// it now. This is what the 'mysql' client does.
 	if capabilities&CapabilityClientConnectWithDB == 0 && params.DbName != "" {
@@ -551,7 +615,9 @@ func (c *Conn) writeHandshakeResponse41(capabilities uint32, scrambledPassword [
 		CapabilityClientFoundRows&uint32(params.Flags) |
 		// If the server supported
 		// CapabilityClientSessionTrack, we also support it.
-		c.Capabilities&CapabilityClientSessionTrack
+		c.Capabilities&CapabilityClientSessionTrack |
+		// Negotiated compression algorithm, if any.
+		c.Capabilities&(CapabilityClientCompress|CapabilityClientZstdCompressionAlgorithm)
 
 	// FIXME(alainjobart) add multi statement.
 
@@ -589,6 +655,11 @@ func (c *Conn) writeHandshakeResponse41(capabilities uint32, scrambledPassword [
 		length += lenEncIntSize(uint64(attrLength)) + attrLength
 	}
 
+	// The zstd compression level is sent last.
+	if capabilityFlags&CapabilityClientZstdCompressionAlgorithm != 0 {
+		length++
+	}
+
 	data, pos := c.startEphemeralPacketWithHeader(length)
 
 	// Client capability flags.
@@ -635,6 +706,10 @@ func (c *Conn) writeHandshakeResponse41(capabilities uint32, scrambledPassword [
 		}
 	}
 
+	if capabilityFlags&CapabilityClientZstdCompressionAlgorithm != 0 {
+		pos = writeByte(data, pos, c.zstdCompressionLevel)
+	}
+
 	// Sanity-check the length.
 	if pos != len(data) {
 		return sqlerror.NewSQLErrorf(sqlerror.CRMalformedPacket, sqlerror.SSUnknownSQLState, "writeHandshakeResponse41: only packed %v bytes, out of %v allocated", pos, len(data))
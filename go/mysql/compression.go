@@ -0,0 +1,334 @@
+/*
+Copyright 2026 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package mysql
+
+import (
+	"bytes"
+	"compress/zlib"
+	"io"
+	"strings"
+	"sync"
+	"sync/atomic"
+
+	"github.com/klauspost/compress/zstd"
+
+	"vitess.io/vitess/go/vt/vterrors"
+
+	vtrpcpb "vitess.io/vitess/go/vt/proto/vtrpc"
+)
+
+// Compression algorithm names, as used by MySQL's
+// protocol_compression_algorithms system variable.
+const (
+	CompressionZlib         = "zlib"
+	CompressionZstd         = "zstd"
+	CompressionUncompressed = "uncompressed"
+)
+
+const (
+	// compressedHeaderSize is the size of the header of a compressed
+	// packet: 3 bytes of compressed payload length, 1 byte of sequence
+	// and 3 bytes of uncompressed payload length.
+	compressedHeaderSize = 7
+
+	// minCompressLength is the payload size under which we don't
+	// compress, like MIN_COMPRESS_LENGTH in MySQL.
+	minCompressLength = 50
+
+	// DefaultZstdCompressionLevel is the zstd compression level used when
+	// none is specified, same as the MySQL default.
+	DefaultZstdCompressionLevel = 3
+)
+
+// CompressionCapabilities returns the capability flags to advertise for the
+// given list of compression algorithm names.
+func CompressionCapabilities(algorithms []string) (uint32, error) {
+	var capabilities uint32
+	for _, algorithm := range algorithms {
+		switch strings.ToLower(strings.TrimSpace(algorithm)) {
+		case CompressionZlib:
+			capabilities |= CapabilityClientCompress
+		case CompressionZstd:
+			capabilities |= CapabilityClientZstdCompressionAlgorithm
+		case CompressionUncompressed, "":
+		default:
+			return 0, vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "unknown compression algorithm %q, must be one of %s, %s or %s", algorithm, CompressionZlib, CompressionZstd, CompressionUncompressed)
+		}
+	}
+	return capabilities, nil
+}
+
+// CompressionStats are the statistics of a connection using the
+// compressed protocol.
+type CompressionStats struct {
+	// Algorithm is the negotiated compression algorithm, or
+	// CompressionUncompressed if the connection doesn't use compression.
+	Algorithm string
+	// BytesSent and BytesReceived are the compressed bytes exchanged on
+	// the wire, headers included.
+	BytesSent     int64
+	BytesReceived int64
+	// UncompressedBytesSent and UncompressedBytesReceived are the bytes
+	// of the packets that went through the compression layer.
+	UncompressedBytesSent     int64
+	UncompressedBytesReceived int64
+}
+
+// zstdDecoder is shared by all connections, DecodeAll is safe for
+// concurrent use.
+var zstdDecoder = sync.OnceValue(func() *zstd.Decoder {
+	decoder, err := zstd.NewReader(nil, zstd.WithDecoderMaxMemory(MaxPacketSize))
+	if err != nil {
+		panic(err)
+	}
+	return decoder
+})
+
+// zstdEncoders are shared by all connections using the same compression
+// level, EncodeAll is safe for concurrent use.
+var (
+	zstdEncodersMu sync.Mutex
+	zstdEncoders   = make(map[int]*zstd.Encoder)
+)
+
+func zstdEncoder(level int) (*zstd.Encoder, error) {
+	zstdEncodersMu.Lock()
+	defer zstdEncodersMu.Unlock()
+
+	if encoder, ok := zstdEncoders[level]; ok {
+		return encoder, nil
+	}
+	encoder, err := zstd.NewWriter(nil, zstd.WithEncoderLevel(zstd.EncoderLevelFromZstd(level)))
+	if err != nil {
+		return nil, err
+	}
+	zstdEncoders[level] = encoder
+	return encoder, nil
+}
+
+// compressedConn implements the compressed packet framing of the MySQL
+// protocol. It sits between the packet reading and writing code of Conn
+// and the network: every regular packet, header included, is carried
+// in the payload of compressed packets.
+// See https://dev.mysql.com/doc/dev/mysql-server/latest/page_protocol_basic_compression.html
+type compressedConn struct {
+	algorithm string
+	zstdLevel int
+
+	// r and w are the underlying reader and writer.
+	r io.Reader
+	w io.Writer
+
+	// sequence is the sequence of the compressed packets. It is
+	// independent of the sequence of the packets they carry.
+	sequence uint8
+
+	// header is used to read compressed packet headers.
+	header [compressedHeaderSize]byte
+	// in is the payload of the last compressed packet read, and
+	// inBuf holds the decompressed data that hasn't been read yet.
+	in    []byte
+	inBuf []byte
+	// out is used to build the compressed packets we send.
+	out bytes.Buffer
+
+	zlibReader io.ReadCloser
+	zlibWriter *zlib.Writer
+
+	bytesSent                 atomic.Int64
+	bytesReceived             atomic.Int64
+	uncompressedBytesSent     atomic.Int64
+	uncompressedBytesReceived atomic.Int64
+}
+
+func newCompressedConn(algorithm string, zstdLevel int, r io.Reader, w io.Writer) *compressedConn {
+	if zstdLevel == 0 {
+		zstdLevel = DefaultZstdCompressionLevel
+	}
+	return &compressedConn{
+		algorithm: algorithm,
+		zstdLevel: zstdLevel,
+		r:         r,
+		w:         w,
+	}
+}
+
+// Read implements io.Reader. It returns decompressed data, reading
+// a new compressed packet when all the previous one was consumed.
+func (cc *compressedConn) Read(p []byte) (int, error) {
+	for len(cc.inBuf) == 0 {
+		if err := cc.readCompressedPacket(); err != nil {
+			return 0, err
+		}
+	}
+	n := copy(p, cc.inBuf)
+	cc.inBuf = cc.inBuf[n:]
+	return n, nil
+}
+
+func (cc *compressedConn) readCompressedPacket() error {
+	// Errors reading the header are returned as is, so the caller
+	// can tell an io.EOF apart.
+	if _, err := io.ReadFull(cc.r, cc.header[:]); err != nil {
+		return err
+	}
+	length := int(uint32(cc.header[0]) | uint32(cc.header[1])<<8 | uint32(cc.header[2])<<16)
+	uncompressedLength := int(uint32(cc.header[4]) | uint32(cc.header[5])<<8 | uint32(cc.header[6])<<16)
+
+	// Like the MySQL clients, we don't enforce the sequence of the
+	// compressed packets we receive, the peer may legitimately send
+	// an error packet before it read everything we sent.
+	cc.sequence = cc.header[3] + 1
+
+	if cap(cc.in) < length {
+		cc.in = make([]byte, length)
+	}
+	cc.in = cc.in[:length]
+	if _, err := io.ReadFull(cc.r, cc.in); err != nil {
+		return vterrors.Wrapf(err, "io.ReadFull(compressed packet body of length %v) failed", length)
+	}
+	cc.bytesReceived.Add(int64(compressedHeaderSize + length))
+
+	if uncompressedLength == 0 {
+		// The payload was sent uncompressed.
+		cc.inBuf = cc.in
+		cc.uncompressedBytesReceived.Add(int64(length))
+		return nil
+	}
+
+	var out []byte
+	switch cc.algorithm {
+	case CompressionZlib:
+		if cc.zlibReader == nil {
+			zr, err := zlib.NewReader(bytes.NewReader(cc.in))
+			if err != nil {
+				return vterrors.Wrapf(err, "cannot decompress zlib packet")
+			}
+			cc.zlibReader = zr
+		} else if err := cc.zlibReader.(zlib.Resetter).Reset(bytes.NewReader(cc.in), nil); err != nil {
+			return vterrors.Wrapf(err, "cannot decompress zlib packet")
+		}
+		out = make([]byte, uncompressedLength)
+		if _, err := io.ReadFull(cc.zlibReader, out); err != nil {
+			return vterrors.Wrapf(err, "cannot decompress zlib packet")
+		}
+	case CompressionZstd:
+		var err error
+		out, err = zstdDecoder().DecodeAll(cc.in, make([]byte, 0, uncompressedLength))
+		if err != nil {
+			return vterrors.Wrapf(err, "cannot decompress zstd packet")
+		}
+		if len(out) != uncompressedLength {
+			return vterrors.Errorf(vtrpcpb.Code_INTERNAL, "zstd packet decompressed to %v bytes, expected %v", len(out), uncompressedLength)
+		}
+	}
+	cc.inBuf = out
+	cc.uncompressedBytesReceived.Add(int64(uncompressedLength))
+	return nil
+}
+
+// Write implements io.Writer. Every call sends the data right away in
+// as many compressed packets as needed, so buffering has to happen above.
+func (cc *compressedConn) Write(p []byte) (int, error) {
+	written := 0
+	for len(p) > 0 {
+		chunk := p[:min(len(p), MaxPacketSize)]
+		if err := cc.writeCompressedPacket(chunk); err != nil {
+			return written, err
+		}
+		written += len(chunk)
+		p = p[len(chunk):]
+	}
+	return written, nil
+}
+
+func (cc *compressedConn) writeCompressedPacket(data []byte) error {
+	cc.out.Reset()
+	var header [compressedHeaderSize]byte
+	cc.out.Write(header[:])
+
+	uncompressedLength := 0
+	if len(data) >= minCompressLength {
+		if err := cc.compress(data); err != nil {
+			return err
+		}
+		uncompressedLength = len(data)
+		// Don't send compressed data that ended up larger.
+		if cc.out.Len()-compressedHeaderSize >= len(data) {
+			cc.out.Truncate(compressedHeaderSize)
+			uncompressedLength = 0
+		}
+	}
+	if uncompressedLength == 0 {
+		cc.out.Write(data)
+	}
+
+	packet := cc.out.Bytes()
+	length := len(packet) - compressedHeaderSize
+	packet[0] = byte(length)
+	packet[1] = byte(length >> 8)
+	packet[2] = byte(length >> 16)
+	packet[3] = cc.sequence
+	packet[4] = byte(uncompressedLength)
+	packet[5] = byte(uncompressedLength >> 8)
+	packet[6] = byte(uncompressedLength >> 16)
+
+	if n, err := cc.w.Write(packet); err != nil {
+		return vterrors.Wrapf(err, "Write(compressed packet) failed")
+	} else if n != len(packet) {
+		return vterrors.Errorf(vtrpcpb.Code_INTERNAL, "Write(compressed packet) returned a short write: %v < %v", n, len(packet))
+	}
+	cc.sequence++
+	cc.bytesSent.Add(int64(len(packet)))
+	cc.uncompressedBytesSent.Add(int64(len(data)))
+	return nil
+}
+
+// compress appends the compressed data to cc.out.
+func (cc *compressedConn) compress(data []byte) error {
+	switch cc.algorithm {
+	case CompressionZlib:
+		if cc.zlibWriter == nil {
+			cc.zlibWriter = zlib.NewWriter(&cc.out)
+		} else {
+			cc.zlibWriter.Reset(&cc.out)
+		}
+		if _, err := cc.zlibWriter.Write(data); err != nil {
+			return vterrors.Wrapf(err, "cannot compress zlib packet")
+		}
+		if err := cc.zlibWriter.Close(); err != nil {
+			return vterrors.Wrapf(err, "cannot compress zlib packet")
+		}
+	case CompressionZstd:
+		encoder, err := zstdEncoder(cc.zstdLevel)
+		if err != nil {
+			return vterrors.Wrapf(err, "cannot compress zstd packet")
+		}
+		cc.out.Write(encoder.EncodeAll(data, cc.out.AvailableBuffer()))
+	}
+	return nil
+}
+
+func (cc *compressedConn) stats() CompressionStats {
+	return CompressionStats{
+		Algorithm:                 cc.algorithm,
+		BytesSent:                 cc.bytesSent.Load(),
+		BytesReceived:             cc.bytesReceived.Load(),
+		UncompressedBytesSent:     cc.uncompressedBytesSent.Load(),
+		UncompressedBytesReceived: cc.uncompressedBytesReceived.Load(),
+	}
+}
@@ -0,0 +1,188 @@
+/*
+Copyright 2026 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package mysql
+
+import (
+	"bytes"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"vitess.io/vitess/go/sqltypes"
+	"vitess.io/vitess/go/test/utils"
+
+	querypb "vitess.io/vitess/go/vt/proto/query"
+)
+
+func TestCompressionCapabilities(t *testing.T) {
+	capabilities, err := CompressionCapabilities([]string{"zlib", " ZSTD", "uncompressed"})
+	require.NoError(t, err)
+	assert.EqualValues(t, CapabilityClientCompress|CapabilityClientZstdCompressionAlgorithm, capabilities)
+
+	capabilities, err = CompressionCapabilities(nil)
+	require.NoError(t, err)
+	assert.Zero(t, capabilities)
+
+	_, err = CompressionCapabilities([]string{"lz4"})
+	require.ErrorContains(t, err, `unknown compression algorithm "lz4"`)
+}
+
+func TestCompressedConn(t *testing.T) {
+	for _, algorithm := range []string{CompressionZlib, CompressionZstd} {
+		t.Run(algorithm, func(t *testing.T) {
+			var wire bytes.Buffer
+			w := newCompressedConn(algorithm, 0, nil, &wire)
+			r := newCompressedConn(algorithm, 0, &wire, nil)
+
+			small := []byte("short")
+			large := []byte(strings.Repeat("compressible data ", 1000))
+
+			// A small payload is sent as is.
+			n, err := w.Write(small)
+			require.NoError(t, err)
+			require.Equal(t, len(small), n)
+			require.Equal(t, compressedHeaderSize+len(small), wire.Len())
+			assert.Equal(t, []byte{byte(len(small)), 0, 0, 0, 0, 0, 0}, wire.Bytes()[:compressedHeaderSize])
+
+			// A large one is compressed.
+			_, err = w.Write(large)
+			require.NoError(t, err)
+			require.Less(t, wire.Len(), len(large))
+			assert.EqualValues(t, 1, wire.Bytes()[compressedHeaderSize+len(small)+3], "sequence of the second packet")
+
+			got := make([]byte, len(small)+len(large))
+			_, err = io.ReadFull(r, got)
+			require.NoError(t, err)
+			assert.Equal(t, append(small, large...), got)
+			assert.EqualValues(t, 2, r.sequence)
+
+			_, err = r.Read(got)
+			assert.Equal(t, io.EOF, err)
+
+			ws, rs := w.stats(), r.stats()
+			assert.Equal(t, algorithm, ws.Algorithm)
+			assert.EqualValues(t, len(small)+len(large), ws.UncompressedBytesSent)
+			assert.Equal(t, ws.UncompressedBytesSent, rs.UncompressedBytesReceived)
+			assert.Equal(t, ws.BytesSent, rs.BytesReceived)
+			assert.Less(t, ws.BytesSent, ws.UncompressedBytesSent)
+		})
+	}
+}
+
+func TestServerCompression(t *testing.T) {
+	ctx := utils.LeakCheckContext(t)
+
+	// A result large enough to span several compressed packets.
+	longValue := strings.Repeat("a fairly compressible value ", 40)
+	result := &sqltypes.Result{
+		Fields: []*querypb.Field{{Name: "value", Type: querypb.Type_VARCHAR}},
+	}
+	for range 1000 {
+		result.Rows = append(result.Rows, []sqltypes.Value{sqltypes.NewVarChar(longValue)})
+	}
+	th := &testHandler{result: result}
+
+	authServer := NewAuthServerStatic("", "", 0)
+	authServer.entries["user1"] = []*AuthServerStaticEntry{{
+		Password: "password1",
+	}}
+	defer authServer.close()
+
+	l, err := NewListener("tcp", "127.0.0.1:", authServer, th, 0, 0, false, false, 0, 0, false)
+	require.NoError(t, err)
+	host, port := getHostPort(t, l.Addr())
+	capabilities, err := CompressionCapabilities([]string{CompressionZlib, CompressionZstd})
+	require.NoError(t, err)
+	l.CompressionCapabilities.Store(capabilities)
+	go l.Accept()
+	defer cleanupListener(ctx, l, &ConnParams{Host: host, Port: port, Uname: "user1", Pass: "password1"})
+
+	tests := []struct {
+		name      string
+		flags     uint64
+		algorithm string
+	}{{
+		name:      "uncompressed",
+		algorithm: CompressionUncompressed,
+	}, {
+		name:      "zlib",
+		flags:     CapabilityClientCompress,
+		algorithm: CompressionZlib,
+	}, {
+		name:      "zstd",
+		flags:     CapabilityClientZstdCompressionAlgorithm,
+		algorithm: CompressionZstd,
+	}, {
+		name:      "both",
+		flags:     CapabilityClientCompress | CapabilityClientZstdCompressionAlgorithm,
+		algorithm: CompressionZstd,
+	}}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			params := &ConnParams{
+				Host:                 host,
+				Port:                 port,
+				Uname:                "user1",
+				Pass:                 "password1",
+				DbName:               "db1",
+				Flags:                tt.flags,
+				ZstdCompressionLevel: 5,
+			}
+			c, err := Connect(ctx, params)
+			require.NoError(t, err)
+			defer c.Close()
+
+			// Run a few commands, to make sure the sequences are reset
+			// properly between them.
+			for range 3 {
+				qr, err := c.ExecuteFetch("select value from t", 10000, false)
+				require.NoError(t, err)
+				require.Len(t, qr.Rows, len(result.Rows))
+				assert.Equal(t, longValue, qr.Rows[999][0].ToString())
+				require.NoError(t, c.Ping())
+			}
+
+			clientStats := c.CompressionStats()
+			serverStats := th.LastConn().CompressionStats()
+			assert.Equal(t, tt.algorithm, clientStats.Algorithm)
+			assert.Equal(t, tt.algorithm, serverStats.Algorithm)
+			if tt.algorithm == CompressionUncompressed {
+				assert.Zero(t, clientStats.BytesReceived)
+				return
+			}
+			if tt.algorithm == CompressionZstd {
+				assert.EqualValues(t, 5, th.LastConn().zstdCompressionLevel)
+			}
+			assert.Equal(t, serverStats.BytesSent, clientStats.BytesReceived)
+			assert.Equal(t, serverStats.UncompressedBytesSent, clientStats.UncompressedBytesReceived)
+			assert.Equal(t, clientStats.BytesSent, serverStats.BytesReceived)
+			assert.Less(t, clientStats.BytesReceived*10, clientStats.UncompressedBytesReceived)
+		})
+	}
+
+	// The server doesn't compress if it doesn't advertise it.
+	l.CompressionCapabilities.Store(0)
+	c, err := Connect(ctx, &ConnParams{Host: host, Port: port, Uname: "user1", Pass: "password1", Flags: CapabilityClientCompress})
+	require.NoError(t, err)
+	defer c.Close()
+	_, err = c.ExecuteFetch("select value from t", 10000, false)
+	require.NoError(t, err)
+	assert.Equal(t, CompressionUncompressed, c.CompressionStats().Algorithm)
+}
@@ -86,9 +86,14 @@ type Conn struct {
 	// fields, this is set to an empty array (but not nil).
 	fields []*querypb.Field
 
-	// salt is sent by the server during initial handshake to be used for authentication
+	// salt is sent by the server during initial handshake to be used for authentication.
+	// On the server side, it is the last auth plugin data sent to the client.
 	salt []byte
 
+	// clientCapabilities are the capability flags the client sent in
+	// its handshake response. They are only set on the server side.
+	clientCapabilities uint32
+
 	// authPluginName is the name of server's authentication plugin.
 	// It is set during the initial handshake.
 	authPluginName AuthMethodDescription
@@ -131,6 +136,11 @@ type Conn struct {
 	// connection handshake.
 	Attributes ConnectionAttributes
 
+	// QueryAttributes are the query attributes the client sent with
+	// the command being handled, if it negotiated
+	// CLIENT_QUERY_ATTRIBUTES. They are cleared before every command.
+	QueryAttributes map[string]*querypb.BindVariable
+
 	bufferedReader *bufio.Reader
 	flushTimer     *time.Timer
 	flushDelay     time.Duration
@@ -197,6 +207,14 @@ type Conn struct {
 	// Packet encoding variables.
 	sequence uint8
 
+	// compressed is set once the connection switched to the compressed
+	// protocol, see enableCompression.
+	compressed atomic.Pointer[compressedConn]
+
+	// zstdCompressionLevel is the zstd compression level negotiated
+	// during the handshake.
+	zstdCompressionLevel uint8
+
 	// ExpectSemiSyncIndicator is applicable when the connection is used for replication (ComBinlogDump).
 	// When 'true', events are assumed to be padded with 2-byte semi-sync information
 	// See https://dev.mysql.com/doc/internals/en/semi-sync-binlog-event.html
@@ -232,6 +250,12 @@ type PrepareData struct {
 	BindVars    map[string]*querypb.BindVariable
 	StatementID uint32
 	ParamsCount uint16
+
+	// attributeTypes and attributeNames describe the query attributes
+	// last sent with the statement, which are only sent again when the
+	// client rebinds the parameters.
+	attributeTypes []querypb.Type
+	attributeNames []string
 }
 
 // execResult is an enum signifying the result of executing a query
@@ -329,7 +353,7 @@ func (c *Conn) startWriterBuffering() {
 	defer c.bufMu.Unlock()
 
 	c.bufferedWriter = writersPool.Get().(*bufio.Writer)
-	c.bufferedWriter.Reset(c.conn)
+	c.bufferedWriter.Reset(c.getWriter())
 }
 
 // endWriterBuffering must be called to terminate startWriteBuffering.
@@ -377,14 +401,66 @@ func (c *Conn) startFlushTimer() {
 }
 
 // getReader returns reader for connection. It can be *bufio.Reader or net.Conn
-// depending on which buffer size was passed to newServerConn.
+// depending on which buffer size was passed to newServerConn, wrapped in the
+// compression layer once the compressed protocol is in use.
 func (c *Conn) getReader() io.Reader {
+	if cc := c.compressed.Load(); cc != nil {
+		return cc
+	}
+	return c.getRawReader()
+}
+
+// getRawReader returns the reader for the connection, below the
+// compression layer.
+func (c *Conn) getRawReader() io.Reader {
 	if c.bufferedReader != nil {
 		return c.bufferedReader
 	}
 	return c.conn
 }
 
+// getWriter returns the unbuffered writer for the connection. It is
+// the compression layer once the compressed protocol is in use.
+func (c *Conn) getWriter() io.Writer {
+	if cc := c.compressed.Load(); cc != nil {
+		return cc
+	}
+	return c.conn
+}
+
+// resetSequence resets the packet sequences at the start of a new command.
+func (c *Conn) resetSequence() {
+	c.sequence = 0
+	if cc := c.compressed.Load(); cc != nil {
+		cc.sequence = 0
+	}
+}
+
+// enableCompression switches the connection to the compressed protocol, if
+// it was negotiated during the handshake. Both sides switch right after the
+// OK packet that completes the authentication.
+func (c *Conn) enableCompression() {
+	var algorithm string
+	switch {
+	case c.Capabilities&CapabilityClientCompress != 0:
+		algorithm = CompressionZlib
+	case c.Capabilities&CapabilityClientZstdCompressionAlgorithm != 0:
+		algorithm = CompressionZstd
+	default:
+		return
+	}
+	c.compressed.Store(newCompressedConn(algorithm, int(c.zstdCompressionLevel), c.getRawReader(), c.conn))
+}
+
+// CompressionStats returns the statistics of the compressed protocol for
+// this connection. It can be called from any go routine.
+func (c *Conn) CompressionStats() CompressionStats {
+	if cc := c.compressed.Load(); cc != nil {
+		return cc.stats()
+	}
+	return CompressionStats{Algorithm: CompressionUncompressed}
+}
+
 func (c *Conn) readHeaderFrom(r io.Reader) (int, error) {
 	// Note io.ReadFull will return two different types of errors:
 	// 1. if the socket is already closed, and the go runtime knows it,
@@ -405,8 +481,11 @@ func (c *Conn) readHeaderFrom(r io.Reader) (int, error) {
 		return 0, vterrors.Wrapf(err, "io.ReadFull(header size) failed")
 	}
 
+	// With the compressed protocol, the sequence of the packets carried by
+	// compressed packets is not meaningful, only the one of the compressed
+	// packets is. Neither MySQL nor its clients check it.
 	sequence := c.header[3]
-	if sequence != c.sequence {
+	if sequence != c.sequence && c.compressed.Load() == nil {
 		return 0, vterrors.Errorf(vtrpcpb.Code_INTERNAL, "invalid sequence, expected %v got %v", c.sequence, sequence)
 	}
 
@@ -617,7 +696,7 @@ func (c *Conn) writePacket(data []byte) error {
 		}()
 	} else {
 		c.bufMu.Unlock()
-		w = c.conn
+		w = c.getWriter()
 	}
 
 	var header [packetHeaderSize]byte
@@ -721,7 +800,7 @@ func (c *Conn) recycleWritePacket() {
 // Returns SQLError(CRServerGone) if it can't.
 func (c *Conn) writeComQuit() error {
 	// This is a new command, need to reset the sequence.
-	c.sequence = 0
+	c.resetSequence()
 
 	data, pos := c.startEphemeralPacketWithHeader(1)
 	data[pos] = ComQuit
@@ -894,7 +973,7 @@ func (c *Conn) writeEOFPacket(flags uint16, warnings uint16) error {
 // handleNextCommand is called in the server loop to process
 // incoming packets.
 func (c *Conn) handleNextCommand(handler Handler) bool {
-	c.sequence = 0
+	c.resetSequence()
 	data, err := c.readEphemeralPacket()
 	if err != nil {
 		// Don't log EOF errors. They cause too much spam.
@@ -911,6 +990,7 @@ func (c *Conn) handleNextCommand(handler Handler) bool {
 		return false
 	}
 
+	c.QueryAttributes = nil
 	switch data[0] {
 	case ComQuit:
 		c.recycleReadPacket()
@@ -943,6 +1023,8 @@ func (c *Conn) handleNextCommand(handler Handler) bool {
 		}
 	case ComStmtReset:
 		return c.handleComStmtReset(data)
+	case ComChangeUser:
+		return c.handleComChangeUser(handler, data)
 	case ComResetConnection:
 		c.handleComResetConnection(handler)
 		return true
@@ -1046,6 +1128,52 @@ func (c *Conn) handleComResetConnection(handler Handler) {
 	}
 }
 
+func (c *Conn) handleComChangeUser(handler Handler, data []byte) bool {
+	cu, err := c.parseComChangeUser(data)
+	c.recycleReadPacket()
+	if err != nil {
+		log.Errorf("Conn %v: error parsing COM_CHANGE_USER: %v", c, err)
+		return c.writeErrorPacketFromErrorAndLog(err)
+	}
+
+	userData, err := c.listener.authenticate(c, cu.user, cu.authMethod, cu.authResponse)
+	if err != nil {
+		// Like MySQL, close the connection if the authentication failed.
+		return false
+	}
+
+	// Switch to the new user, and reset the session of the previous one.
+	// The reset ends any transaction in progress, the handler may set the
+	// status flags of the new session.
+	cu.apply(c)
+	c.StatusFlags &= NoServerStatusInTrans
+	c.PrepareData = make(map[uint32]*PrepareData)
+	if c.User != "" {
+		connCountPerUser.Add(c.User, -1)
+	}
+	c.User = cu.user
+	c.UserData = userData
+	if c.User != "" {
+		connCountPerUser.Add(c.User, 1)
+	}
+	handler.ComChangeUser(c)
+
+	if c.schemaName != "" {
+		err = handler.ComQuery(c, "use "+sqlescape.EscapeID(c.schemaName), func(result *sqltypes.Result) error {
+			return nil
+		})
+		if err != nil {
+			return c.writeErrorPacketFromErrorAndLog(err)
+		}
+	}
+
+	if err := c.writeOKPacket(&PacketOK{statusFlags: c.StatusFlags}); err != nil {
+		log.Errorf("Error writing ComChangeUser result to %s: %v", c, err)
+		return false
+	}
+	return true
+}
+
 func (c *Conn) handleComStmtReset(data []byte) bool {
 	stmtID, ok := c.parseComStmtReset(data)
 	c.recycleReadPacket()
@@ -1302,8 +1430,11 @@ func (c *Conn) handleComQueryMulti(handler Handler, data []byte) (kontinue bool)
 	}()
 
 	queryStart := time.Now()
-	query := c.parseComQuery(data)
+	query, err := c.parseComQuery(data)
 	c.recycleReadPacket()
+	if err != nil {
+		return c.writeErrorPacketFromErrorAndLog(err)
+	}
 
 	res := c.execQueryMulti(query, handler)
 	if res != execSuccess {
@@ -1436,11 +1567,13 @@ func (c *Conn) handleComQuery(handler Handler, data []byte) (kontinue bool) {
 	}()
 
 	queryStart := time.Now()
-	query := c.parseComQuery(data)
+	query, err := c.parseComQuery(data)
 	c.recycleReadPacket()
+	if err != nil {
+		return c.writeErrorPacketFromErrorAndLog(err)
+	}
 
 	var queries []string
-	var err error
 	if c.Capabilities&CapabilityClientMultiStatements != 0 {
 		queries, err = handler.Env().Parser().SplitStatementToPieces(query)
 		if err != nil {
@@ -61,6 +61,11 @@ type ConnParams struct {
 	// disabled by default.
 	EnableQueryInfo bool
 
+	// ZstdCompressionLevel is the zstd compression level to ask for when
+	// CapabilityClientZstdCompressionAlgorithm is set in Flags. It
+	// defaults to DefaultZstdCompressionLevel.
+	ZstdCompressionLevel int
+
 	// FlushDelay is the delay after which buffered response will be flushed to the client.
 	FlushDelay time.Duration
 
@@ -80,8 +80,11 @@ const (
 	// CLIENT_NO_SCHEMA 1 << 4
 	// Do not permit database.table.column. We do permit it.
 
-	// CLIENT_COMPRESS 1 << 5
-	// We do not support compression. CPU is usually our bottleneck.
+	// CapabilityClientCompress is CLIENT_COMPRESS.
+	// Use the zlib compressed protocol once the handshake is done.
+	// It is only advertised when compression is enabled, as CPU
+	// is usually our bottleneck.
+	CapabilityClientCompress = 1 << 5
 
 	// CLIENT_ODBC 1 << 6
 	// No special behavior since 3.22.
@@ -152,6 +155,28 @@ const (
 	// CapabilityClientDeprecateEOF is CLIENT_DEPRECATE_EOF
 	// Expects an OK (instead of EOF) after the resultset rows of a Text Resultset.
 	CapabilityClientDeprecateEOF = 1 << 24
+
+	// CLIENT_OPTIONAL_RESULTSET_METADATA 1 << 25
+	// Not supported.
+
+	// CapabilityClientZstdCompressionAlgorithm is CLIENT_ZSTD_COMPRESSION_ALGORITHM.
+	// Use the zstd compressed protocol once the handshake is done. The
+	// client sends the compression level at the end of its handshake
+	// response.
+	CapabilityClientZstdCompressionAlgorithm = 1 << 26
+
+	// CapabilityClientQueryAttributes is CLIENT_QUERY_ATTRIBUTES.
+	// COM_QUERY and COM_STMT_EXECUTE carry query attributes, sent
+	// like the parameters of a prepared statement.
+	CapabilityClientQueryAttributes = 1 << 27
+)
+
+// Flags of the cursor type byte of COM_STMT_EXECUTE.
+const (
+	// CursorTypeParameterCountAvailable is PARAMETER_COUNT_AVAILABLE.
+	// The packet carries the number of parameters, which lets a client
+	// send query attributes for a statement without parameters.
+	CursorTypeParameterCountAvailable = 0x08
 )
 
 // Status flags. They are returned by the server in a few cases.
@@ -213,6 +238,9 @@ const (
 	// ComPing is COM_PING.
 	ComPing = 0x0e
 
+	// ComChangeUser is COM_CHANGE_USER.
+	ComChangeUser = 0x11
+
 	// ComBinlogDump is COM_BINLOG_DUMP.
 	ComBinlogDump = 0x12
 
@@ -0,0 +1,410 @@
+/*
+Copyright 2026 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package jwtauthserver
+
+import (
+	"crypto/x509"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"os/signal"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/go-jose/go-jose/v4"
+	"github.com/go-jose/go-jose/v4/jwt"
+
+	"vitess.io/vitess/go/mysql"
+	"vitess.io/vitess/go/mysql/sqlerror"
+	"vitess.io/vitess/go/vt/log"
+)
+
+// signatureAlgorithms are the JWS algorithms we accept. Only asymmetric
+// algorithms are allowed, as vtgate only ever holds the public keys.
+var signatureAlgorithms = []jose.SignatureAlgorithm{
+	jose.RS256, jose.RS384, jose.RS512,
+	jose.PS256, jose.PS384, jose.PS512,
+	jose.ES256, jose.ES384, jose.ES512,
+	jose.EdDSA,
+}
+
+// Config holds the configuration of the JWT auth server.
+type Config struct {
+	// JWKSFile is the path to a JSON Web Key Set holding the keys
+	// tokens can be signed with.
+	JWKSFile string
+	// PublicKeysFile is the path to a PEM file holding the issuer public
+	// keys or certificates tokens can be signed with. Only one of JWKSFile
+	// and PublicKeysFile can be set.
+	PublicKeysFile string
+	// Issuer, if set, must match the "iss" claim.
+	Issuer string
+	// Audience, if set, must be one of the "aud" claim values.
+	Audience string
+	// UsernameClaim is the claim holding the Vitess username. It must match
+	// the user the client connects as.
+	UsernameClaim string
+	// GroupsClaim is the claim holding the groups used by table ACLs.
+	GroupsClaim string
+	// Leeway is the clock skew allowed when validating time based claims.
+	Leeway time.Duration
+	// ReloadInterval, if set, reloads the keys periodically. Keys are
+	// always reloaded on SIGHUP.
+	ReloadInterval time.Duration
+}
+
+// AuthServerJWT implements AuthServer by validating a signed JWT the
+// client sends as its password. As the token is a bearer credential,
+// it is only accepted over mysql_clear_password on TLS connections.
+type AuthServerJWT struct {
+	Config
+	methods []mysql.AuthMethod
+
+	// mu protects keys and expiryTimers.
+	mu   sync.Mutex
+	keys []jose.JSONWebKey
+	// expiryTimers close the connections when the token they
+	// authenticated with expires.
+	expiryTimers map[*mysql.Conn]*time.Timer
+
+	// now returns the current time, it is overridden in tests.
+	now func() time.Time
+
+	// Signal handling related fields.
+	sigChan chan os.Signal
+	ticker  *time.Ticker
+	done    chan struct{}
+}
+
+// Init is public so it can be called from plugin_auth_jwt.go (go/cmd/vtgate)
+func Init(config Config) {
+	if config.JWKSFile == "" && config.PublicKeysFile == "" {
+		log.Infof("Not configuring AuthServerJWT because mysql-auth-jwt-jwks-file and mysql-auth-jwt-public-keys-file are empty")
+		return
+	}
+	if config.JWKSFile != "" && config.PublicKeysFile != "" {
+		log.Exitf("Both mysql-auth-jwt-jwks-file and mysql-auth-jwt-public-keys-file specified, can only use one.")
+	}
+
+	authServerJWT, err := newAuthServerJWT(config)
+	if err != nil {
+		log.Exitf("%s", err)
+	}
+	authServerJWT.installSignalHandlers()
+	mysql.RegisterAuthServer("jwt", authServerJWT)
+}
+
+func newAuthServerJWT(config Config) (*AuthServerJWT, error) {
+	if config.UsernameClaim == "" {
+		return nil, errors.New("the JWT username claim cannot be empty")
+	}
+	keys, err := loadKeys(config.JWKSFile, config.PublicKeysFile)
+	if err != nil {
+		return nil, err
+	}
+	a := &AuthServerJWT{
+		Config:       config,
+		keys:         keys,
+		expiryTimers: make(map[*mysql.Conn]*time.Timer),
+		now:          time.Now,
+	}
+	a.methods = []mysql.AuthMethod{mysql.NewMysqlClearAuthMethod(a, a)}
+	return a, nil
+}
+
+var _ mysql.ConnectionClosedAuthServer = (*AuthServerJWT)(nil)
+
+// AuthMethods returns the list of registered auth methods
+// implemented by this auth server.
+func (a *AuthServerJWT) AuthMethods() []mysql.AuthMethod {
+	return a.methods
+}
+
+// DefaultAuthMethodDescription returns MysqlClearPassword as the default
+// authentication method, as the token is sent as the password.
+func (a *AuthServerJWT) DefaultAuthMethodDescription() mysql.AuthMethodDescription {
+	return mysql.MysqlClearPassword
+}
+
+// HandleUser is part of the UserValidator interface. We
+// handle any user here since we don't check up front.
+func (a *AuthServerJWT) HandleUser(user string) bool {
+	return true
+}
+
+// UserEntryWithPassword is part of the PlainTextStorage interface. The
+// password is the token; once it is validated the connection is closed
+// when the token expires.
+func (a *AuthServerJWT) UserEntryWithPassword(conn *mysql.Conn, user string, password string, remoteAddr net.Addr) (mysql.Getter, error) {
+	if !conn.TLSEnabled() {
+		log.Warningf("Rejecting JWT authentication for user '%v' from %v: TLS is required", user, remoteAddr)
+		return nil, sqlerror.NewSQLErrorf(sqlerror.ERAccessDeniedError, sqlerror.SSAccessDeniedError, "Access denied for user '%v': JWT authentication requires TLS", user)
+	}
+
+	userData, expiry, err := a.validate(user, password)
+	if err != nil {
+		log.Warningf("Rejecting JWT authentication for user '%v' from %v: %v", user, remoteAddr, err)
+		return nil, sqlerror.NewSQLErrorf(sqlerror.ERAccessDeniedError, sqlerror.SSAccessDeniedError, "Access denied for user '%v'", user)
+	}
+	a.closeOnExpiry(conn, expiry)
+	return userData, nil
+}
+
+// validate checks the token signature and claims, and returns the user data
+// it maps to along with its expiry.
+func (a *AuthServerJWT) validate(user, token string) (*mysql.StaticUserData, time.Time, error) {
+	tok, err := jwt.ParseSigned(token, signatureAlgorithms)
+	if err != nil {
+		return nil, time.Time{}, fmt.Errorf("invalid token: %w", err)
+	}
+
+	var (
+		claims       jwt.Claims
+		customClaims map[string]any
+		verified     bool
+	)
+	for _, key := range a.candidateKeys(tok.Headers[0].KeyID) {
+		if err := tok.Claims(key.Key, &claims, &customClaims); err == nil {
+			verified = true
+			break
+		}
+	}
+	if !verified {
+		return nil, time.Time{}, errors.New("token signature does not match any known key")
+	}
+
+	if claims.Expiry == nil {
+		return nil, time.Time{}, errors.New("token has no expiry")
+	}
+	expected := jwt.Expected{Issuer: a.Issuer, Time: a.now()}
+	if a.Audience != "" {
+		expected.AnyAudience = jwt.Audience{a.Audience}
+	}
+	if err := claims.ValidateWithLeeway(expected, a.Leeway); err != nil {
+		return nil, time.Time{}, err
+	}
+
+	username, _ := customClaims[a.UsernameClaim].(string)
+	if username == "" {
+		return nil, time.Time{}, fmt.Errorf("token has no %q claim", a.UsernameClaim)
+	}
+	if username != user {
+		return nil, time.Time{}, fmt.Errorf("MySQL connection username '%v' does not match token username '%v'", user, username)
+	}
+	groups, err := groupsFromClaim(customClaims[a.GroupsClaim])
+	if err != nil {
+		return nil, time.Time{}, fmt.Errorf("invalid %q claim: %w", a.GroupsClaim, err)
+	}
+
+	return &mysql.StaticUserData{Username: username, Groups: groups}, claims.Expiry.Time().Add(a.Leeway), nil
+}
+
+// candidateKeys returns the keys a token with the given key ID can be
+// verified with: the keys with that ID and the keys without an ID.
+func (a *AuthServerJWT) candidateKeys(kid string) []jose.JSONWebKey {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	var keys []jose.JSONWebKey
+	for _, key := range a.keys {
+		if kid == "" || key.KeyID == "" || key.KeyID == kid {
+			keys = append(keys, key)
+		}
+	}
+	return keys
+}
+
+// groupsFromClaim accepts either a single group or a list of groups.
+func groupsFromClaim(claim any) ([]string, error) {
+	switch claim := claim.(type) {
+	case nil:
+		return nil, nil
+	case string:
+		return []string{claim}, nil
+	case []any:
+		groups := make([]string, 0, len(claim))
+		for _, group := range claim {
+			group, ok := group.(string)
+			if !ok {
+				return nil, fmt.Errorf("expected a list of strings, got %v", claim)
+			}
+			groups = append(groups, group)
+		}
+		return groups, nil
+	default:
+		return nil, fmt.Errorf("expected a string or a list of strings, got %v", claim)
+	}
+}
+
+// closeOnExpiry closes the connection once its token expires. Any timer set
+// by a previous authentication on the connection, e.g. before a
+// COM_CHANGE_USER, is replaced.
+func (a *AuthServerJWT) closeOnExpiry(conn *mysql.Conn, expiry time.Time) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if timer, ok := a.expiryTimers[conn]; ok {
+		timer.Stop()
+	}
+	var timer *time.Timer
+	timer = time.AfterFunc(expiry.Sub(a.now()), func() {
+		a.mu.Lock()
+		if a.expiryTimers[conn] == timer {
+			delete(a.expiryTimers, conn)
+		}
+		a.mu.Unlock()
+
+		if !conn.IsClosed() {
+			log.Infof("Closing %s: its JWT expired", conn)
+			conn.Close()
+		}
+	})
+	a.expiryTimers[conn] = timer
+}
+
+// ConnectionClosed is part of the mysql.ConnectionClosedAuthServer
+// interface. It stops the expiry timer of the connection.
+func (a *AuthServerJWT) ConnectionClosed(conn *mysql.Conn) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if timer, ok := a.expiryTimers[conn]; ok {
+		timer.Stop()
+		delete(a.expiryTimers, conn)
+	}
+}
+
+// loadKeys reads the verification keys from either a JWKS file or a PEM
+// file of public keys and certificates.
+func loadKeys(jwksFile, publicKeysFile string) ([]jose.JSONWebKey, error) {
+	var keys []jose.JSONWebKey
+	if jwksFile != "" {
+		data, err := os.ReadFile(jwksFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read mysql-auth-jwt-jwks-file: %w", err)
+		}
+		var jwks jose.JSONWebKeySet
+		if err := json.Unmarshal(data, &jwks); err != nil {
+			return nil, fmt.Errorf("failed to parse mysql-auth-jwt-jwks-file: %w", err)
+		}
+		for _, key := range jwks.Keys {
+			if key.Use != "" && key.Use != "sig" {
+				continue
+			}
+			keys = append(keys, key.Public())
+		}
+	} else {
+		data, err := os.ReadFile(publicKeysFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read mysql-auth-jwt-public-keys-file: %w", err)
+		}
+		for {
+			var block *pem.Block
+			block, data = pem.Decode(data)
+			if block == nil {
+				break
+			}
+			var pub any
+			switch block.Type {
+			case "PUBLIC KEY":
+				pub, err = x509.ParsePKIXPublicKey(block.Bytes)
+			case "CERTIFICATE":
+				var cert *x509.Certificate
+				if cert, err = x509.ParseCertificate(block.Bytes); err == nil {
+					pub = cert.PublicKey
+				}
+			default:
+				continue
+			}
+			if err != nil {
+				return nil, fmt.Errorf("failed to parse mysql-auth-jwt-public-keys-file: %w", err)
+			}
+			keys = append(keys, jose.JSONWebKey{Key: pub})
+		}
+	}
+
+	// Public() returns an invalid key for symmetric keys.
+	validKeys := keys[:0]
+	for _, key := range keys {
+		if key.Valid() {
+			validKeys = append(validKeys, key)
+		}
+	}
+	if len(validKeys) == 0 {
+		return nil, errors.New("no JWT signing keys found")
+	}
+	return validKeys, nil
+}
+
+func (a *AuthServerJWT) reload() {
+	keys, err := loadKeys(a.JWKSFile, a.PublicKeysFile)
+	if err != nil {
+		log.Errorf("Failed to reload JWT keys, keeping the previous ones: %v", err)
+		return
+	}
+
+	a.mu.Lock()
+	a.keys = keys
+	a.mu.Unlock()
+}
+
+func (a *AuthServerJWT) installSignalHandlers() {
+	a.done = make(chan struct{})
+	a.sigChan = make(chan os.Signal, 1)
+	signal.Notify(a.sigChan, syscall.SIGHUP)
+	go func() {
+		for {
+			select {
+			case <-a.done:
+				return
+			case <-a.sigChan:
+				a.reload()
+			}
+		}
+	}()
+
+	// If duration is set, it will reload the keys every interval
+	if a.ReloadInterval > 0 {
+		a.ticker = time.NewTicker(a.ReloadInterval)
+		go func() {
+			for {
+				select {
+				case <-a.done:
+					return
+				case <-a.ticker.C:
+					a.sigChan <- syscall.SIGHUP
+				}
+			}
+		}()
+	}
+}
+
+func (a *AuthServerJWT) close() {
+	if a.done != nil {
+		close(a.done)
+	}
+	if a.ticker != nil {
+		a.ticker.Stop()
+	}
+	if a.sigChan != nil {
+		signal.Stop(a.sigChan)
+	}
+}
@@ -0,0 +1,333 @@
+/*
+Copyright 2026 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package jwtauthserver
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/json"
+	"encoding/pem"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/go-jose/go-jose/v4"
+	"github.com/go-jose/go-jose/v4/jwt"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"vitess.io/vitess/go/mysql"
+	querypb "vitess.io/vitess/go/vt/proto/query"
+)
+
+type testClaims struct {
+	jwt.Claims
+	Groups any `json:"groups,omitempty"`
+}
+
+func generateKey(t *testing.T) *ecdsa.PrivateKey {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	return key
+}
+
+func writeJWKS(t *testing.T, path string, keys map[string]*ecdsa.PrivateKey) {
+	var jwks jose.JSONWebKeySet
+	for kid, key := range keys {
+		jwks.Keys = append(jwks.Keys, jose.JSONWebKey{Key: &key.PublicKey, KeyID: kid, Algorithm: string(jose.ES256), Use: "sig"})
+	}
+	data, err := json.Marshal(jwks)
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(path, data, 0o600))
+}
+
+func signToken(t *testing.T, key *ecdsa.PrivateKey, kid string, claims testClaims) string {
+	opts := (&jose.SignerOptions{}).WithType("JWT")
+	if kid != "" {
+		opts = opts.WithHeader("kid", kid)
+	}
+	signer, err := jose.NewSigner(jose.SigningKey{Algorithm: jose.ES256, Key: key}, opts)
+	require.NoError(t, err)
+	token, err := jwt.Signed(signer).Claims(claims).Serialize()
+	require.NoError(t, err)
+	return token
+}
+
+func newTestConn(tls bool) *mysql.Conn {
+	conn := mysql.GetTestConn()
+	if tls {
+		conn.Capabilities |= mysql.CapabilityClientSSL
+	}
+	return conn
+}
+
+func TestAuthServerJWT(t *testing.T) {
+	key, otherKey := generateKey(t), generateKey(t)
+	jwksFile := filepath.Join(t.TempDir(), "jwks.json")
+	writeJWKS(t, jwksFile, map[string]*ecdsa.PrivateKey{"key1": key})
+
+	a, err := newAuthServerJWT(Config{
+		JWKSFile:      jwksFile,
+		Issuer:        "https://issuer.example.com",
+		Audience:      "vtgate",
+		UsernameClaim: "sub",
+		GroupsClaim:   "groups",
+	})
+	require.NoError(t, err)
+
+	now := time.Now()
+	validClaims := func() testClaims {
+		return testClaims{
+			Claims: jwt.Claims{
+				Subject:  "alice",
+				Issuer:   "https://issuer.example.com",
+				Audience: jwt.Audience{"vtgate", "other"},
+				Expiry:   jwt.NewNumericDate(now.Add(time.Hour)),
+				IssuedAt: jwt.NewNumericDate(now),
+			},
+			Groups: []string{"readers", "writers"},
+		}
+	}
+
+	testcases := []struct {
+		name     string
+		user     string
+		token    func() string
+		noTLS    bool
+		expected *querypb.VTGateCallerID
+		wantErr  bool
+	}{{
+		name:     "valid token",
+		user:     "alice",
+		token:    func() string { return signToken(t, key, "key1", validClaims()) },
+		expected: &querypb.VTGateCallerID{Username: "alice", Groups: []string{"readers", "writers"}},
+	}, {
+		name: "valid token without key id and a single group",
+		user: "alice",
+		token: func() string {
+			claims := validClaims()
+			claims.Groups = "readers"
+			return signToken(t, key, "", claims)
+		},
+		expected: &querypb.VTGateCallerID{Username: "alice", Groups: []string{"readers"}},
+	}, {
+		name:    "not over TLS",
+		user:    "alice",
+		token:   func() string { return signToken(t, key, "key1", validClaims()) },
+		noTLS:   true,
+		wantErr: true,
+	}, {
+		name:    "user does not match the token",
+		user:    "bob",
+		token:   func() string { return signToken(t, key, "key1", validClaims()) },
+		wantErr: true,
+	}, {
+		name:    "unknown key",
+		user:    "alice",
+		token:   func() string { return signToken(t, otherKey, "key1", validClaims()) },
+		wantErr: true,
+	}, {
+		name:    "not a token",
+		user:    "alice",
+		token:   func() string { return "password" },
+		wantErr: true,
+	}, {
+		name: "expired",
+		user: "alice",
+		token: func() string {
+			claims := validClaims()
+			claims.Expiry = jwt.NewNumericDate(now.Add(-time.Minute))
+			return signToken(t, key, "key1", claims)
+		},
+		wantErr: true,
+	}, {
+		name: "no expiry",
+		user: "alice",
+		token: func() string {
+			claims := validClaims()
+			claims.Expiry = nil
+			return signToken(t, key, "key1", claims)
+		},
+		wantErr: true,
+	}, {
+		name: "wrong issuer",
+		user: "alice",
+		token: func() string {
+			claims := validClaims()
+			claims.Issuer = "https://other.example.com"
+			return signToken(t, key, "key1", claims)
+		},
+		wantErr: true,
+	}, {
+		name: "wrong audience",
+		user: "alice",
+		token: func() string {
+			claims := validClaims()
+			claims.Audience = jwt.Audience{"other"}
+			return signToken(t, key, "key1", claims)
+		},
+		wantErr: true,
+	}, {
+		name: "invalid groups",
+		user: "alice",
+		token: func() string {
+			claims := validClaims()
+			claims.Groups = []int{1, 2}
+			return signToken(t, key, "key1", claims)
+		},
+		wantErr: true,
+	}}
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			conn := newTestConn(!tc.noTLS)
+			getter, err := a.UserEntryWithPassword(conn, tc.user, tc.token(), nil)
+			if tc.wantErr {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), "Access denied for user")
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tc.expected, getter.Get())
+		})
+	}
+}
+
+func TestAuthServerJWTClosesExpiredConnections(t *testing.T) {
+	key := generateKey(t)
+	jwksFile := filepath.Join(t.TempDir(), "jwks.json")
+	writeJWKS(t, jwksFile, map[string]*ecdsa.PrivateKey{"key1": key})
+
+	a, err := newAuthServerJWT(Config{JWKSFile: jwksFile, UsernameClaim: "sub"})
+	require.NoError(t, err)
+
+	conn := newTestConn(true)
+	token := signToken(t, key, "key1", testClaims{Claims: jwt.Claims{
+		Subject: "alice",
+		Expiry:  jwt.NewNumericDate(time.Now().Add(time.Hour)),
+	}})
+	_, err = a.UserEntryWithPassword(conn, "alice", token, nil)
+	require.NoError(t, err)
+
+	// Re-authenticating the connection, as COM_CHANGE_USER does, replaces
+	// the expiry of the previous token.
+	token = signToken(t, key, "key1", testClaims{Claims: jwt.Claims{
+		Subject: "alice",
+		Expiry:  jwt.NewNumericDate(time.Now().Add(time.Second)),
+	}})
+	_, err = a.UserEntryWithPassword(conn, "alice", token, nil)
+	require.NoError(t, err)
+
+	assert.False(t, conn.IsClosed())
+	require.Eventually(t, conn.IsClosed, 5*time.Second, 10*time.Millisecond)
+	require.Eventually(t, func() bool {
+		a.mu.Lock()
+		defer a.mu.Unlock()
+		return len(a.expiryTimers) == 0
+	}, 5*time.Second, 10*time.Millisecond)
+}
+
+func TestAuthServerJWTConnectionClosed(t *testing.T) {
+	key := generateKey(t)
+	jwksFile := filepath.Join(t.TempDir(), "jwks.json")
+	writeJWKS(t, jwksFile, map[string]*ecdsa.PrivateKey{"key1": key})
+
+	a, err := newAuthServerJWT(Config{JWKSFile: jwksFile, UsernameClaim: "sub"})
+	require.NoError(t, err)
+
+	conn := newTestConn(true)
+	token := signToken(t, key, "key1", testClaims{Claims: jwt.Claims{
+		Subject: "alice",
+		Expiry:  jwt.NewNumericDate(time.Now().Add(time.Hour)),
+	}})
+	_, err = a.UserEntryWithPassword(conn, "alice", token, nil)
+	require.NoError(t, err)
+	assert.Len(t, a.expiryTimers, 1)
+
+	// The timer of a closed connection is dropped before the token expires.
+	a.ConnectionClosed(conn)
+	assert.Empty(t, a.expiryTimers)
+}
+
+func TestAuthServerJWTReload(t *testing.T) {
+	key, newKey := generateKey(t), generateKey(t)
+	jwksFile := filepath.Join(t.TempDir(), "jwks.json")
+	writeJWKS(t, jwksFile, map[string]*ecdsa.PrivateKey{"key1": key})
+
+	a, err := newAuthServerJWT(Config{JWKSFile: jwksFile, UsernameClaim: "sub", ReloadInterval: 10 * time.Millisecond})
+	require.NoError(t, err)
+	a.installSignalHandlers()
+	defer a.close()
+
+	claims := testClaims{Claims: jwt.Claims{
+		Subject: "alice",
+		Expiry:  jwt.NewNumericDate(time.Now().Add(time.Hour)),
+	}}
+	_, _, err = a.validate("alice", signToken(t, newKey, "key2", claims))
+	require.Error(t, err)
+
+	// Rotate the keys, the new one is picked up by the next reload.
+	writeJWKS(t, jwksFile, map[string]*ecdsa.PrivateKey{"key2": newKey})
+	require.Eventually(t, func() bool {
+		_, _, err := a.validate("alice", signToken(t, newKey, "key2", claims))
+		return err == nil
+	}, 5*time.Second, 10*time.Millisecond)
+	_, _, err = a.validate("alice", signToken(t, key, "key1", claims))
+	require.Error(t, err)
+
+	// A broken file keeps the previous keys.
+	require.NoError(t, os.WriteFile(jwksFile, []byte("not json"), 0o600))
+	a.reload()
+	_, _, err = a.validate("alice", signToken(t, newKey, "key2", claims))
+	require.NoError(t, err)
+}
+
+func TestAuthServerJWTPublicKeysFile(t *testing.T) {
+	key := generateKey(t)
+	der, err := x509.MarshalPKIXPublicKey(&key.PublicKey)
+	require.NoError(t, err)
+	publicKeysFile := filepath.Join(t.TempDir(), "keys.pem")
+	require.NoError(t, os.WriteFile(publicKeysFile, pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der}), 0o600))
+
+	a, err := newAuthServerJWT(Config{PublicKeysFile: publicKeysFile, UsernameClaim: "email"})
+	require.NoError(t, err)
+
+	claims := map[string]any{
+		"email": "alice@example.com",
+		"exp":   time.Now().Add(time.Hour).Unix(),
+	}
+	signer, err := jose.NewSigner(jose.SigningKey{Algorithm: jose.ES256, Key: key}, nil)
+	require.NoError(t, err)
+	token, err := jwt.Signed(signer).Claims(claims).Serialize()
+	require.NoError(t, err)
+
+	userData, _, err := a.validate("alice@example.com", signToken(t, key, "some-kid", testClaims{Claims: jwt.Claims{
+		Expiry: jwt.NewNumericDate(time.Now().Add(time.Hour)),
+	}}))
+	require.Error(t, err, "token without the username claim")
+	assert.Nil(t, userData)
+
+	userData, _, err = a.validate("alice@example.com", token)
+	require.NoError(t, err)
+	assert.Equal(t, "alice@example.com", userData.Username)
+	assert.Empty(t, userData.Groups)
+
+	_, err = newAuthServerJWT(Config{PublicKeysFile: filepath.Join(t.TempDir(), "missing.pem"), UsernameClaim: "sub"})
+	require.Error(t, err)
+}
@@ -58,7 +58,7 @@ const (
 // Returns SQLError(CRServerGone) if it can't.
 func (c *Conn) WriteComQuery(query string) error {
 	// This is a new command, need to reset the sequence.
-	c.sequence = 0
+	c.resetSequence()
 
 	data, pos := c.startEphemeralPacketWithHeader(len(query) + 1)
 	data[pos] = ComQuery
@@ -580,8 +580,53 @@ func (c *Conn) readComQueryResponse(packetOk *PacketOK) (int, error) {
 // Server side methods.
 //
 
-func (c *Conn) parseComQuery(data []byte) string {
-	return string(data[1:])
+func (c *Conn) parseComQuery(data []byte) (string, error) {
+	if c.Capabilities&CapabilityClientQueryAttributes == 0 {
+		return string(data[1:]), nil
+	}
+
+	// The query attributes come first, encoded like the parameters of
+	// COM_STMT_EXECUTE, with their names.
+	payload := data[1:]
+	count, pos, ok := readLenEncInt(payload, 0)
+	if !ok {
+		return "", sqlerror.NewSQLError(sqlerror.CRMalformedPacket, sqlerror.SSUnknownSQLState, "reading query attributes count failed")
+	}
+	if count > uint64(len(payload)) {
+		return "", sqlerror.NewSQLErrorf(sqlerror.CRMalformedPacket, sqlerror.SSUnknownSQLState, "invalid query attributes count: %v", count)
+	}
+	// The parameter set count is always 1.
+	_, pos, ok = readLenEncInt(payload, pos)
+	if !ok {
+		return "", sqlerror.NewSQLError(sqlerror.CRMalformedPacket, sqlerror.SSUnknownSQLState, "reading query attributes set count failed")
+	}
+
+	if count == 0 {
+		return string(payload[pos:]), nil
+	}
+	bitMap, pos, ok := readBytes(payload, pos, (int(count)+7)/8)
+	if !ok {
+		return "", sqlerror.NewSQLError(sqlerror.CRMalformedPacket, sqlerror.SSUnknownSQLState, "reading NULL-bitmap failed")
+	}
+	newParamsBoundFlag, pos, ok := readByte(payload, pos)
+	if !ok || newParamsBoundFlag != 0x01 {
+		return "", sqlerror.NewSQLError(sqlerror.CRMalformedPacket, sqlerror.SSUnknownSQLState, "query attributes sent without their types")
+	}
+	types := make([]querypb.Type, count)
+	names := make([]string, count)
+	for i := range types {
+		var err error
+		types[i], names[i], pos, err = parseParamType(payload, pos, true)
+		if err != nil {
+			return "", err
+		}
+	}
+	attributes, pos, err := c.parseQueryAttributeValues(payload, pos, bitMap, 0, types, names)
+	if err != nil {
+		return "", err
+	}
+	c.QueryAttributes = attributes
+	return string(payload[pos:]), nil
 }
 
 func (c *Conn) parseComSetOption(data []byte) (uint16, bool) {
@@ -623,8 +668,24 @@ func (c *Conn) parseComStmtExecute(prepareData map[uint32]*PrepareData, data []b
 		return stmtID, 0, sqlerror.NewSQLError(sqlerror.CRMalformedPacket, sqlerror.SSUnknownSQLState, "iteration count is not equal to 1")
 	}
 
-	if prepare.ParamsCount > 0 {
-		bitMap, pos, ok = readBytes(payload, pos, (int(prepare.ParamsCount)+7)/8)
+	// With CLIENT_QUERY_ATTRIBUTES, the parameters are followed by the
+	// query attributes, and the packet carries their total count.
+	count := int(prepare.ParamsCount)
+	queryAttributes := c.Capabilities&CapabilityClientQueryAttributes != 0
+	if queryAttributes && (prepare.ParamsCount > 0 || cursorType&CursorTypeParameterCountAvailable != 0) {
+		var total uint64
+		total, pos, ok = readLenEncInt(payload, pos)
+		if !ok {
+			return stmtID, 0, sqlerror.NewSQLError(sqlerror.CRMalformedPacket, sqlerror.SSUnknownSQLState, "reading parameter count failed")
+		}
+		if total < uint64(prepare.ParamsCount) || total > uint64(len(payload)) {
+			return stmtID, 0, sqlerror.NewSQLErrorf(sqlerror.CRMalformedPacket, sqlerror.SSUnknownSQLState, "invalid parameter count: %v", total)
+		}
+		count = int(total)
+	}
+
+	if count > 0 {
+		bitMap, pos, ok = readBytes(payload, pos, (count+7)/8)
 		if !ok {
 			return stmtID, 0, sqlerror.NewSQLError(sqlerror.CRMalformedPacket, sqlerror.SSUnknownSQLState, "reading NULL-bitmap failed")
 		}
@@ -632,27 +693,28 @@ func (c *Conn) parseComStmtExecute(prepareData map[uint32]*PrepareData, data []b
 
 	newParamsBoundFlag, pos, ok := readByte(payload, pos)
 	if ok && newParamsBoundFlag == 0x01 {
-		var mysqlType, flags byte
-		for i := range prepare.ParamsCount {
-			mysqlType, pos, ok = readByte(payload, pos)
-			if !ok {
-				return stmtID, 0, sqlerror.NewSQLError(sqlerror.CRMalformedPacket, sqlerror.SSUnknownSQLState, "reading parameter type failed")
-			}
-
-			flags, pos, ok = readByte(payload, pos)
-			if !ok {
-				return stmtID, 0, sqlerror.NewSQLError(sqlerror.CRMalformedPacket, sqlerror.SSUnknownSQLState, "reading parameter flags failed")
-			}
-
-			// convert MySQL type to internal type.
-			valType, err := sqltypes.MySQLToType(mysqlType, int64(flags))
+		prepare.attributeTypes = prepare.attributeTypes[:0]
+		prepare.attributeNames = prepare.attributeNames[:0]
+		for i := range count {
+			var valType querypb.Type
+			var name string
+			var err error
+			valType, name, pos, err = parseParamType(payload, pos, queryAttributes)
 			if err != nil {
-				return stmtID, 0, sqlerror.NewSQLErrorf(sqlerror.CRMalformedPacket, sqlerror.SSUnknownSQLState, "MySQLToType(%v,%v) failed: %v", mysqlType, flags, err)
+				return stmtID, 0, err
 			}
 
-			prepare.ParamsType[i] = int32(valType)
+			if i < int(prepare.ParamsCount) {
+				prepare.ParamsType[i] = int32(valType)
+			} else {
+				prepare.attributeTypes = append(prepare.attributeTypes, valType)
+				prepare.attributeNames = append(prepare.attributeNames, name)
+			}
 		}
 	}
+	if count > int(prepare.ParamsCount) && len(prepare.attributeTypes) != count-int(prepare.ParamsCount) {
+		return stmtID, 0, sqlerror.NewSQLError(sqlerror.CRMalformedPacket, sqlerror.SSUnknownSQLState, "query attributes sent without their types")
+	}
 
 	for i := range prepare.ParamsCount {
 		var val sqltypes.Value
@@ -675,9 +737,67 @@ func (c *Conn) parseComStmtExecute(prepareData map[uint32]*PrepareData, data []b
 		prepare.BindVars[parameterID] = sqltypes.ValueBindVariable(val)
 	}
 
+	if count > int(prepare.ParamsCount) {
+		attributes, _, err := c.parseQueryAttributeValues(payload, pos, bitMap, int(prepare.ParamsCount), prepare.attributeTypes, prepare.attributeNames)
+		if err != nil {
+			return stmtID, 0, err
+		}
+		c.QueryAttributes = attributes
+	}
+
 	return stmtID, cursorType, nil
 }
 
+// parseParamType reads the type and flags of a parameter of the binary
+// protocol, followed by its name if withName is set.
+func parseParamType(payload []byte, pos int, withName bool) (querypb.Type, string, int, error) {
+	mysqlType, pos, ok := readByte(payload, pos)
+	if !ok {
+		return 0, "", 0, sqlerror.NewSQLError(sqlerror.CRMalformedPacket, sqlerror.SSUnknownSQLState, "reading parameter type failed")
+	}
+
+	flags, pos, ok := readByte(payload, pos)
+	if !ok {
+		return 0, "", 0, sqlerror.NewSQLError(sqlerror.CRMalformedPacket, sqlerror.SSUnknownSQLState, "reading parameter flags failed")
+	}
+
+	var name string
+	if withName {
+		name, pos, ok = readLenEncString(payload, pos)
+		if !ok {
+			return 0, "", 0, sqlerror.NewSQLError(sqlerror.CRMalformedPacket, sqlerror.SSUnknownSQLState, "reading parameter name failed")
+		}
+	}
+
+	// convert MySQL type to internal type.
+	valType, err := sqltypes.MySQLToType(mysqlType, int64(flags))
+	if err != nil {
+		return 0, "", 0, sqlerror.NewSQLErrorf(sqlerror.CRMalformedPacket, sqlerror.SSUnknownSQLState, "MySQLToType(%v,%v) failed: %v", mysqlType, flags, err)
+	}
+	return valType, name, pos, nil
+}
+
+// parseQueryAttributeValues reads the values of the query attributes of
+// the given types and names. Their NULL flags start at the given offset
+// of the NULL-bitmap.
+func (c *Conn) parseQueryAttributeValues(payload []byte, pos int, bitMap []byte, offset int, types []querypb.Type, names []string) (map[string]*querypb.BindVariable, int, error) {
+	attributes := make(map[string]*querypb.BindVariable, len(types))
+	for i, typ := range types {
+		var val sqltypes.Value
+		var ok bool
+		if j := offset + i; (bitMap[j/8] & (1 << uint(j%8))) > 0 {
+			val, pos, ok = c.parseStmtArgs(nil, sqltypes.Null, pos)
+		} else {
+			val, pos, ok = c.parseStmtArgs(payload, typ, pos)
+		}
+		if !ok {
+			return nil, 0, sqlerror.NewSQLErrorf(sqlerror.CRMalformedPacket, sqlerror.SSUnknownSQLState, "decoding query attribute value failed: %v", typ)
+		}
+		attributes[names[i]] = sqltypes.ValueBindVariable(val)
+	}
+	return attributes, pos, nil
+}
+
 func (c *Conn) parseStmtArgs(data []byte, typ querypb.Type, pos int) (sqltypes.Value, int, bool) {
 	switch typ {
 	case sqltypes.Null:
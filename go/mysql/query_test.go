@@ -250,6 +250,102 @@ func TestComStmtExecute(t *testing.T) {
 
 }
 
+func TestComQueryAttributes(t *testing.T) {
+	listener, sConn, cConn := createSocketPair(t)
+	defer func() {
+		listener.Close()
+		sConn.Close()
+		cConn.Close()
+	}()
+
+	data := []byte{ComQuery,
+		2, 1, // attribute count and set count
+		0x02, 1, // NULL-bitmap and new params bound flag
+		0xfd, 0x00, 8, 't', 'r', 'a', 'c', 'e', '_', 'i', 'd',
+		0x08, 0x00, 8, 'p', 'r', 'i', 'o', 'r', 'i', 't', 'y',
+		3, 'a', 'b', 'c',
+		's', 'e', 'l', 'e', 'c', 't', ' ', '1'}
+
+	// Without the capability, the whole payload is the query.
+	query, err := sConn.parseComQuery(data)
+	require.NoError(t, err)
+	assert.Equal(t, string(data[1:]), query)
+	assert.Nil(t, sConn.QueryAttributes)
+
+	sConn.Capabilities |= CapabilityClientQueryAttributes
+	query, err = sConn.parseComQuery(data)
+	require.NoError(t, err)
+	assert.Equal(t, "select 1", query)
+	assert.Equal(t, map[string]*querypb.BindVariable{
+		"trace_id": sqltypes.StringBindVariable("abc"),
+		"priority": sqltypes.NullBindVariable,
+	}, sConn.QueryAttributes)
+
+	// No attributes.
+	sConn.QueryAttributes = nil
+	query, err = sConn.parseComQuery([]byte{ComQuery, 0, 1, 's', 'e', 'l', 'e', 'c', 't', ' ', '1'})
+	require.NoError(t, err)
+	assert.Equal(t, "select 1", query)
+	assert.Nil(t, sConn.QueryAttributes)
+
+	_, err = sConn.parseComQuery([]byte{ComQuery, 1, 1, 0, 1, 0xfd, 0x00, 8, 't', 'r'})
+	require.ErrorContains(t, err, "reading parameter name failed")
+}
+
+func TestComStmtExecuteQueryAttributes(t *testing.T) {
+	listener, sConn, cConn := createSocketPair(t)
+	defer func() {
+		listener.Close()
+		sConn.Close()
+		cConn.Close()
+	}()
+
+	sConn.Capabilities |= CapabilityClientQueryAttributes
+	prepare, _ := MockPrepareData(t)
+	prepare.BindVars = make(map[string]*querypb.BindVariable)
+	prepareData := map[uint32]*PrepareData{prepare.StatementID: prepare}
+
+	// `select * from test_table where id = ?` with a workload_name attribute.
+	data := []byte{ComStmtExecute, 18, 0, 0, 0, 0, 1, 0, 0, 0,
+		2,    // parameter count
+		0, 1, // NULL-bitmap and new params bound flag
+		1, 128, 0, // the parameter has no name
+		0xfd, 0x00, 13, 'w', 'o', 'r', 'k', 'l', 'o', 'a', 'd', '_', 'n', 'a', 'm', 'e',
+		1, 4, 'o', 'l', 'a', 'p'}
+	stmtID, _, err := sConn.parseComStmtExecute(prepareData, data)
+	require.NoError(t, err)
+	require.EqualValues(t, 18, stmtID)
+	assert.Equal(t, sqltypes.Int64BindVariable(1), prepare.BindVars["v1"])
+	assert.Equal(t, map[string]*querypb.BindVariable{
+		"workload_name": sqltypes.StringBindVariable("olap"),
+	}, sConn.QueryAttributes)
+
+	// The types of the attributes are only sent along the parameters.
+	prepare.BindVars = make(map[string]*querypb.BindVariable)
+	data = []byte{ComStmtExecute, 18, 0, 0, 0, 0, 1, 0, 0, 0, 2, 0, 0, 2, 4, 'o', 'l', 't', 'p'}
+	_, _, err = sConn.parseComStmtExecute(prepareData, data)
+	require.NoError(t, err)
+	assert.Equal(t, sqltypes.Int64BindVariable(2), prepare.BindVars["v1"])
+	assert.Equal(t, map[string]*querypb.BindVariable{
+		"workload_name": sqltypes.StringBindVariable("oltp"),
+	}, sConn.QueryAttributes)
+
+	// A statement without parameters announces the attributes with the
+	// PARAMETER_COUNT_AVAILABLE flag.
+	prepareData[19] = &PrepareData{StatementID: 19, BindVars: map[string]*querypb.BindVariable{}}
+	data = []byte{ComStmtExecute, 19, 0, 0, 0, CursorTypeParameterCountAvailable, 1, 0, 0, 0,
+		1, 0x01, 1, 0xfd, 0x00, 1, 'a'}
+	_, _, err = sConn.parseComStmtExecute(prepareData, data)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]*querypb.BindVariable{"a": sqltypes.NullBindVariable}, sConn.QueryAttributes)
+
+	// Attributes can't be sent without their types.
+	prepareData[20] = &PrepareData{StatementID: 20, BindVars: map[string]*querypb.BindVariable{}}
+	data = []byte{ComStmtExecute, 20, 0, 0, 0, CursorTypeParameterCountAvailable, 1, 0, 0, 0, 1, 0x01, 0}
+	_, _, err = sConn.parseComStmtExecute(prepareData, data)
+	require.ErrorContains(t, err, "query attributes sent without their types")
+}
+
 func TestComStmtExecuteUpdStmt(t *testing.T) {
 	listener, sConn, cConn := createSocketPair(t)
 	defer func() {
@@ -74,6 +74,9 @@ var (
 		}
 		return connCount.Get() - totalUsers
 	})
+
+	connCountByCompression = stats.NewGaugesWithSingleLabel("MysqlServerConnCountByCompression", "Active MySQL server connections by compression algorithm", "algorithm")
+	compressionBytes       = stats.NewCountersWithMultiLabels("MysqlServerCompressionBytes", "Bytes exchanged by closed MySQL server connections using the compressed protocol", []string{"Algorithm", "Type"})
 )
 
 // A Handler is an interface used by Listener to send queries.
@@ -136,6 +139,12 @@ type Handler interface {
 
 	ComResetConnection(c *Conn)
 
+	// ComChangeUser is called when a connection successfully
+	// authenticated as another user with COM_CHANGE_USER, once
+	// c.User and c.UserData are updated. The session of the previous
+	// user should be reset.
+	ComChangeUser(c *Conn)
+
 	Env() *vtenv.Environment
 }
 
@@ -149,6 +158,7 @@ func (UnimplementedHandler) NewConnection(*Conn)      {}
 func (UnimplementedHandler) ConnectionReady(*Conn)    {}
 func (UnimplementedHandler) ConnectionClosed(*Conn)   {}
 func (UnimplementedHandler) ComResetConnection(*Conn) {}
+func (UnimplementedHandler) ComChangeUser(*Conn)      {}
 
 // Listener is the MySQL server protocol listener.
 type Listener struct {
@@ -185,6 +195,11 @@ type Listener struct {
 	// beyond which a warning is logged to identify the slow connection
 	SlowConnectWarnThreshold atomic.Int64
 
+	// CompressionCapabilities are the compression capability flags we
+	// advertise, see CompressionCapabilities. Compression is disabled
+	// if it is zero.
+	CompressionCapabilities atomic.Uint32
+
 	// The following parameters are changed by the Accept routine.
 
 	// Incrementing ID for connection id.
@@ -396,12 +411,15 @@ func (l *Listener) handle(conn net.Conn, connectionID uint32, acceptTime time.Ti
 	// Tell the handler about the connection coming and going.
 	l.handler.NewConnection(c)
 	defer l.handler.ConnectionClosed(c)
+	if authServer, ok := l.authServer.(ConnectionClosedAuthServer); ok {
+		defer authServer.ConnectionClosed(c)
+	}
 
 	// Adjust the count of open connections
 	defer connCount.Add(-1)
 
 	// First build and send the server handshake packet.
-	serverAuthPluginData, err := c.writeHandshakeV10(l.ServerVersion, l.authServer, uint8(l.charset), l.TLSConfig.Load() != nil)
+	serverAuthPluginData, err := c.writeHandshakeV10(l.ServerVersion, l.authServer, uint8(l.charset), l.TLSConfig.Load() != nil, l.CompressionCapabilities.Load())
 	if err != nil {
 		if err != io.EOF {
 			log.Errorf("Cannot send HandshakeV10 packet to %s: %v", c, err)
@@ -460,62 +478,9 @@ func (l *Listener) handle(conn net.Conn, connectionID uint32, acceptTime time.Ti
 		defer connCountByTLSVer.Add(versionNoTLS, -1)
 	}
 
-	// See what auth method the AuthServer wants to use for that user.
-	negotiatedAuthMethod, err := negotiateAuthMethod(c, l.authServer, user, clientAuthMethod)
-
-	// We need to send down an additional packet if we either have no negotiated method
-	// at all or incomplete authentication data.
-	//
-	// The latter case happens for example for MySQL 8.0 clients until 8.0.25 who advertise
-	// support for caching_sha2_password by default but with no plugin data.
-	if err != nil || len(clientAuthResponse) == 0 {
-		// If we have no negotiated method yet, we pick the first one
-		// we know about ourselves as that's the last resort option we have here.
-		if err != nil {
-			// The client will disconnect if it doesn't understand
-			// the first auth method that we send, so we only have to send the
-			// first one that we allow for the user.
-			for _, m := range l.authServer.AuthMethods() {
-				if m.HandleUser(c, user) {
-					negotiatedAuthMethod = m
-					break
-				}
-			}
-		}
-
-		if negotiatedAuthMethod == nil {
-			c.writeErrorPacket(sqlerror.CRServerHandshakeErr, sqlerror.SSUnknownSQLState, "No authentication methods available for authentication.")
-			return
-		}
-
-		if !l.AllowClearTextWithoutTLS.Load() && !c.TLSEnabled() && !negotiatedAuthMethod.AllowClearTextWithoutTLS() {
-			c.writeErrorPacket(sqlerror.CRServerHandshakeErr, sqlerror.SSUnknownSQLState, "Cannot use clear text authentication over non-SSL connections.")
-			return
-		}
-
-		serverAuthPluginData, err = negotiatedAuthMethod.AuthPluginData()
-		if err != nil {
-			log.Errorf("Error generating auth switch packet for %s: %v", c, err)
-			return
-		}
-
-		if err := c.writeAuthSwitchRequest(string(negotiatedAuthMethod.Name()), serverAuthPluginData); err != nil {
-			log.Errorf("Error writing auth switch packet for %s: %v", c, err)
-			return
-		}
-
-		clientAuthResponse, err = c.readEphemeralPacket()
-		if err != nil {
-			log.Errorf("Error reading auth switch response for %s: %v", c, err)
-			return
-		}
-		c.recycleReadPacket()
-	}
-
-	userData, err := negotiatedAuthMethod.HandleAuthPluginData(c, user, serverAuthPluginData, clientAuthResponse, conn.RemoteAddr())
+	c.salt = serverAuthPluginData
+	userData, err := l.authenticate(c, user, clientAuthMethod, clientAuthResponse)
 	if err != nil {
-		log.Warningf("Error authenticating user %s using: %s", user, negotiatedAuthMethod.Name())
-		c.writeErrorPacketFromError(err)
 		return
 	}
 
@@ -524,8 +489,14 @@ func (l *Listener) handle(conn net.Conn, connectionID uint32, acceptTime time.Ti
 
 	if c.User != "" {
 		connCountPerUser.Add(c.User, 1)
-		defer connCountPerUser.Add(c.User, -1)
 	}
+	// Look at c.User when the connection closes, it can be changed by
+	// COM_CHANGE_USER.
+	defer func() {
+		if c.User != "" {
+			connCountPerUser.Add(c.User, -1)
+		}
+	}()
 
 	// Set initial db name.
 	if c.schemaName != "" {
@@ -544,6 +515,20 @@ func (l *Listener) handle(conn net.Conn, connectionID uint32, acceptTime time.Ti
 		return
 	}
 
+	// Switch to the compressed protocol if it was negotiated.
+	c.enableCompression()
+	if algorithm := c.CompressionStats().Algorithm; algorithm != CompressionUncompressed {
+		connCountByCompression.Add(algorithm, 1)
+		defer func() {
+			connCountByCompression.Add(algorithm, -1)
+			stats := c.CompressionStats()
+			compressionBytes.Add([]string{algorithm, "Sent"}, stats.BytesSent)
+			compressionBytes.Add([]string{algorithm, "Received"}, stats.BytesReceived)
+			compressionBytes.Add([]string{algorithm, "UncompressedSent"}, stats.UncompressedBytesSent)
+			compressionBytes.Add([]string{algorithm, "UncompressedReceived"}, stats.UncompressedBytesReceived)
+		}()
+	}
+
 	// Record how long we took to establish the connection
 	timings.Record(connectTimingKey, acceptTime)
 
@@ -567,6 +552,78 @@ func (l *Listener) handle(conn net.Conn, connectionID uint32, acceptTime time.Ti
 	}
 }
 
+// authenticate authenticates the user with the auth method and response
+// the client sent in its handshake response or COM_CHANGE_USER, switching
+// to another auth method if needed. c.salt is the auth plugin data the
+// client used, and is updated if we switch. Errors are sent to the client
+// when possible.
+func (l *Listener) authenticate(c *Conn, user string, clientAuthMethod AuthMethodDescription, clientAuthResponse []byte) (Getter, error) {
+	serverAuthPluginData := c.salt
+
+	// See what auth method the AuthServer wants to use for that user.
+	negotiatedAuthMethod, err := negotiateAuthMethod(c, l.authServer, user, clientAuthMethod)
+
+	// We need to send down an additional packet if we either have no negotiated method
+	// at all or incomplete authentication data.
+	//
+	// The latter case happens for example for MySQL 8.0 clients until 8.0.25 who advertise
+	// support for caching_sha2_password by default but with no plugin data.
+	if err != nil || len(clientAuthResponse) == 0 {
+		// If we have no negotiated method yet, we pick the first one
+		// we know about ourselves as that's the last resort option we have here.
+		if err != nil {
+			// The client will disconnect if it doesn't understand
+			// the first auth method that we send, so we only have to send the
+			// first one that we allow for the user.
+			for _, m := range l.authServer.AuthMethods() {
+				if m.HandleUser(c, user) {
+					negotiatedAuthMethod = m
+					break
+				}
+			}
+		}
+
+		if negotiatedAuthMethod == nil {
+			err := sqlerror.NewSQLError(sqlerror.CRServerHandshakeErr, sqlerror.SSUnknownSQLState, "No authentication methods available for authentication.")
+			c.writeErrorPacketFromError(err)
+			return nil, err
+		}
+
+		if !l.AllowClearTextWithoutTLS.Load() && !c.TLSEnabled() && !negotiatedAuthMethod.AllowClearTextWithoutTLS() {
+			err := sqlerror.NewSQLError(sqlerror.CRServerHandshakeErr, sqlerror.SSUnknownSQLState, "Cannot use clear text authentication over non-SSL connections.")
+			c.writeErrorPacketFromError(err)
+			return nil, err
+		}
+
+		serverAuthPluginData, err = negotiatedAuthMethod.AuthPluginData()
+		if err != nil {
+			log.Errorf("Error generating auth switch packet for %s: %v", c, err)
+			return nil, err
+		}
+		c.salt = serverAuthPluginData
+
+		if err := c.writeAuthSwitchRequest(string(negotiatedAuthMethod.Name()), serverAuthPluginData); err != nil {
+			log.Errorf("Error writing auth switch packet for %s: %v", c, err)
+			return nil, err
+		}
+
+		clientAuthResponse, err = c.readEphemeralPacket()
+		if err != nil {
+			log.Errorf("Error reading auth switch response for %s: %v", c, err)
+			return nil, err
+		}
+		c.recycleReadPacket()
+	}
+
+	userData, err := negotiatedAuthMethod.HandleAuthPluginData(c, user, serverAuthPluginData, clientAuthResponse, c.conn.RemoteAddr())
+	if err != nil {
+		log.Warningf("Error authenticating user %s using: %s", user, negotiatedAuthMethod.Name())
+		c.writeErrorPacketFromError(err)
+		return nil, err
+	}
+	return userData, nil
+}
+
 // Close stops the listener, which prevents accept of any new connections. Existing connections won't be closed.
 func (l *Listener) Close() {
 	l.listener.Close()
@@ -582,7 +639,7 @@ func (l *Listener) Shutdown() {
 
 // writeHandshakeV10 writes the Initial Handshake Packet, server side.
 // It returns the salt data.
-func (c *Conn) writeHandshakeV10(serverVersion string, authServer AuthServer, charset uint8, enableTLS bool) ([]byte, error) {
+func (c *Conn) writeHandshakeV10(serverVersion string, authServer AuthServer, charset uint8, enableTLS bool, compression uint32) ([]byte, error) {
 	capabilities := CapabilityClientLongPassword |
 		CapabilityClientFoundRows |
 		CapabilityClientLongFlag |
@@ -595,10 +652,12 @@ func (c *Conn) writeHandshakeV10(serverVersion string, authServer AuthServer, ch
 		CapabilityClientPluginAuth |
 		CapabilityClientPluginAuthLenencClientData |
 		CapabilityClientDeprecateEOF |
-		CapabilityClientConnAttr
+		CapabilityClientConnAttr |
+		CapabilityClientQueryAttributes
 	if enableTLS {
 		capabilities |= CapabilityClientSSL
 	}
+	capabilities |= int(compression & (CapabilityClientCompress | CapabilityClientZstdCompressionAlgorithm))
 
 	// Grab the default auth method. This can only be either
 	// mysql_native_password or caching_sha2_password. Both
@@ -711,6 +770,7 @@ func (l *Listener) parseClientHandshakePacket(c *Conn, firstTime bool, data []by
 	if clientFlags&CapabilityClientProtocol41 == 0 {
 		return "", "", nil, vterrors.Errorf(vtrpc.Code_INTERNAL, "parseClientHandshakePacket: only support protocol 4.1")
 	}
+	c.clientCapabilities = clientFlags
 
 	// Remember a subset of the capabilities, so we can use them
 	// later in the protocol. If we re-received the handshake packet
@@ -724,6 +784,11 @@ func (l *Listener) parseClientHandshakePacket(c *Conn, firstTime bool, data []by
 		c.Capabilities |= CapabilityClientMultiStatements
 	}
 
+	// set connection capability for query attributes
+	if clientFlags&CapabilityClientQueryAttributes > 0 {
+		c.Capabilities |= CapabilityClientQueryAttributes
+	}
+
 	// Max packet size. Don't do anything with this now.
 	// See doc.go for more information.
 	_, pos, ok = readUint32(data, pos)
@@ -751,6 +816,18 @@ func (l *Listener) parseClientHandshakePacket(c *Conn, firstTime bool, data []by
 		return "", "", nil, nil
 	}
 
+	// Compression. Clients only ask for one algorithm, but if they ask
+	// for both we pick zlib, like MySQL does. The zstd compression level
+	// is the last byte of the packet.
+	c.Capabilities &^= CapabilityClientCompress | CapabilityClientZstdCompressionAlgorithm
+	switch compression := clientFlags & l.CompressionCapabilities.Load(); {
+	case compression&CapabilityClientCompress != 0:
+		c.Capabilities |= CapabilityClientCompress
+	case compression&CapabilityClientZstdCompressionAlgorithm != 0:
+		c.Capabilities |= CapabilityClientZstdCompressionAlgorithm
+		c.zstdCompressionLevel = data[len(data)-1]
+	}
+
 	// username
 	username, pos, ok := readNullString(data, pos)
 	if !ok {
@@ -855,6 +932,106 @@ func parseConnAttrs(data []byte, pos int) (ConnectionAttributes, int, error) {
 	return attrs, pos, nil
 }
 
+// comChangeUser is a parsed COM_CHANGE_USER packet. It is only applied to
+// the connection once the new user is authenticated.
+type comChangeUser struct {
+	user         string
+	authMethod   AuthMethodDescription
+	authResponse []byte
+	schemaName   string
+	// characterSet is zero if the packet has none.
+	characterSet collations.ID
+	// attributes are nil if the packet has none.
+	attributes map[string]string
+}
+
+// apply switches the schema name, character set and connection
+// attributes of the connection to the ones of the packet.
+func (cu *comChangeUser) apply(c *Conn) {
+	c.schemaName = cu.schemaName
+	if cu.characterSet != 0 {
+		c.CharacterSet = cu.characterSet
+	}
+	if cu.attributes != nil {
+		c.Attributes = cu.attributes
+	}
+}
+
+// parseComChangeUser parses a COM_CHANGE_USER packet. It does not change
+// the connection.
+// The original data is not pointed at, and can be freed.
+func (c *Conn) parseComChangeUser(data []byte) (*comChangeUser, error) {
+	pos := 1
+
+	username, pos, ok := readNullString(data, pos)
+	if !ok {
+		return nil, sqlerror.NewSQLError(sqlerror.CRMalformedPacket, sqlerror.SSUnknownSQLState, "parseComChangeUser: can't read username")
+	}
+
+	var authResponse []byte
+	if c.clientCapabilities&CapabilityClientSecureConnection != 0 {
+		var l byte
+		l, pos, ok = readByte(data, pos)
+		if !ok {
+			return nil, sqlerror.NewSQLError(sqlerror.CRMalformedPacket, sqlerror.SSUnknownSQLState, "parseComChangeUser: can't read auth-response length")
+		}
+		authResponse, pos, ok = readBytesCopy(data, pos, int(l))
+		if !ok {
+			return nil, sqlerror.NewSQLError(sqlerror.CRMalformedPacket, sqlerror.SSUnknownSQLState, "parseComChangeUser: can't read auth-response")
+		}
+	} else {
+		a := ""
+		a, pos, ok = readNullString(data, pos)
+		if !ok {
+			return nil, sqlerror.NewSQLError(sqlerror.CRMalformedPacket, sqlerror.SSUnknownSQLState, "parseComChangeUser: can't read auth-response")
+		}
+		authResponse = []byte(a)
+	}
+
+	dbname, pos, ok := readNullString(data, pos)
+	if !ok {
+		return nil, sqlerror.NewSQLError(sqlerror.CRMalformedPacket, sqlerror.SSUnknownSQLState, "parseComChangeUser: can't read dbname")
+	}
+
+	cu := &comChangeUser{
+		user:         username,
+		authMethod:   MysqlNativePassword,
+		authResponse: authResponse,
+		schemaName:   dbname,
+	}
+
+	// The rest of the packet is optional.
+	if pos < len(data) {
+		var characterSet uint16
+		characterSet, pos, ok = readUint16(data, pos)
+		if !ok {
+			return nil, sqlerror.NewSQLError(sqlerror.CRMalformedPacket, sqlerror.SSUnknownSQLState, "parseComChangeUser: can't read characterSet")
+		}
+		cu.characterSet = collations.ID(characterSet)
+
+		if c.clientCapabilities&CapabilityClientPluginAuth != 0 {
+			var authMethodStr string
+			authMethodStr, pos, ok = readNullString(data, pos)
+			if !ok {
+				return nil, sqlerror.NewSQLError(sqlerror.CRMalformedPacket, sqlerror.SSUnknownSQLState, "parseComChangeUser: can't read authMethod")
+			}
+			if authMethodStr != "" {
+				cu.authMethod = AuthMethodDescription(authMethodStr)
+			}
+		}
+
+		if c.clientCapabilities&CapabilityClientConnAttr != 0 && pos < len(data) {
+			clientAttributes, _, err := parseConnAttrs(data, pos)
+			if err != nil {
+				log.Warningf("Decode connection attributes send by the client: %v", err)
+			}
+			cu.attributes = clientAttributes
+		}
+	}
+
+	return cu, nil
+}
+
 // writeAuthSwitchRequest writes an auth switch request packet.
 func (c *Conn) writeAuthSwitchRequest(pluginName string, pluginData []byte) error {
 	length := 1 + // AuthSwitchRequestPacket
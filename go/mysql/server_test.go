@@ -75,11 +75,12 @@ var selectRowsResult = &sqltypes.Result{
 
 type testHandler struct {
 	UnimplementedHandler
-	mu       sync.Mutex
-	lastConn *Conn
-	result   *sqltypes.Result
-	err      error
-	warnings uint16
+	mu          sync.Mutex
+	lastConn    *Conn
+	result      *sqltypes.Result
+	err         error
+	warnings    uint16
+	changedUser string
 }
 
 func (th *testHandler) LastConn() *Conn {
@@ -112,6 +113,18 @@ func (th *testHandler) SetWarnings(count uint16) {
 	th.warnings = count
 }
 
+func (th *testHandler) ComChangeUser(c *Conn) {
+	th.mu.Lock()
+	defer th.mu.Unlock()
+	th.changedUser = c.User
+}
+
+func (th *testHandler) ChangedUser() string {
+	th.mu.Lock()
+	defer th.mu.Unlock()
+	return th.changedUser
+}
+
 func (th *testHandler) NewConnection(c *Conn) {
 	th.mu.Lock()
 	defer th.mu.Unlock()
@@ -622,6 +635,96 @@ func TestConnCounts(t *testing.T) {
 	}, 1*time.Second, 10*time.Millisecond)
 }
 
+func TestServerChangeUser(t *testing.T) {
+	ctx := utils.LeakCheckContext(t)
+	th := &testHandler{}
+
+	authServer := NewAuthServerStatic("", "", 0)
+	authServer.entries["changeUser1"] = []*AuthServerStaticEntry{{
+		Password: "password1",
+		UserData: "userData1",
+	}}
+	authServer.entries["changeUser2"] = []*AuthServerStaticEntry{{
+		Password: "password2",
+		UserData: "userData2",
+	}}
+	defer authServer.close()
+
+	l, err := NewListener("tcp", "127.0.0.1:", authServer, th, 0, 0, false, false, 0, 0, false)
+	require.NoError(t, err)
+	host, port := getHostPort(t, l.Addr())
+	params := &ConnParams{
+		Host:   host,
+		Port:   port,
+		Uname:  "changeUser1",
+		Pass:   "password1",
+		DbName: "db1",
+	}
+	go l.Accept()
+	defer cleanupListener(ctx, l, params)
+
+	c, err := Connect(ctx, params)
+	require.NoError(t, err)
+	defer c.Close()
+	checkCountsForUser(t, "changeUser1", 1)
+
+	err = c.ChangeUser(&ConnParams{Uname: "changeUser2", Pass: "password2", DbName: "db2"})
+	require.NoError(t, err)
+	assert.Equal(t, "changeUser2", c.User)
+	// The handler is called once the connection switched to the new user.
+	assert.Equal(t, "changeUser2", th.ChangedUser())
+
+	result, err := c.ExecuteFetch("userData echo", 1, false)
+	require.NoError(t, err)
+	assert.Equal(t, "changeUser2", result.Rows[0][0].ToString())
+	assert.Equal(t, "userData2", result.Rows[0][1].ToString())
+	result, err = c.ExecuteFetch("schema echo", 1, false)
+	require.NoError(t, err)
+	assert.Equal(t, "db2", result.Rows[0][0].ToString())
+	checkCountsForUser(t, "changeUser1", 0)
+	checkCountsForUser(t, "changeUser2", 1)
+
+	// A failed authentication closes the connection.
+	err = c.ChangeUser(&ConnParams{Uname: "changeUser1", Pass: "bad password"})
+	assert.ErrorContains(t, err, "Access denied for user 'changeUser1'")
+	_, err = c.ExecuteFetch("userData echo", 1, false)
+	require.Error(t, err)
+	assert.EventuallyWithT(t, func(t *assert.CollectT) {
+		checkCountsForUser(t, "changeUser2", 0)
+	}, 1*time.Second, 10*time.Millisecond)
+}
+
+func TestParseComChangeUser(t *testing.T) {
+	c := &Conn{
+		clientCapabilities: CapabilityClientSecureConnection | CapabilityClientPluginAuth,
+		schemaName:         "db1",
+		CharacterSet:       collations.ID(33),
+	}
+	data := []byte{ComChangeUser}
+	data = append(data, "user2\x00"...)
+	data = append(data, 3, 'a', 'b', 'c')
+	data = append(data, "db2\x00"...)
+	data = append(data, 45, 0)
+	data = append(data, CachingSha2Password+"\x00"...)
+
+	// The packet is only applied to the connection once the new user is
+	// authenticated.
+	cu, err := c.parseComChangeUser(data)
+	require.NoError(t, err)
+	assert.Equal(t, "user2", cu.user)
+	assert.Equal(t, []byte("abc"), cu.authResponse)
+	assert.Equal(t, CachingSha2Password, cu.authMethod)
+	assert.Equal(t, "db1", c.schemaName)
+	assert.Equal(t, collations.ID(33), c.CharacterSet)
+
+	cu.apply(c)
+	assert.Equal(t, "db2", c.schemaName)
+	assert.Equal(t, collations.ID(45), c.CharacterSet)
+
+	_, err = c.parseComChangeUser(data[:3])
+	assert.ErrorContains(t, err, "can't read username")
+}
+
 func checkCountsForUser(t assert.TestingT, user string, expected int64) {
 	connCounts := connCountPerUser.Counts()
 
@@ -1178,6 +1281,60 @@ func TestCachingSha2PasswordAuthWithTLS(t *testing.T) {
 	conn.writeComQuit()
 }
 
+func TestCachingSha2PasswordChangeUser(t *testing.T) {
+	ctx := utils.LeakCheckContext(t)
+	th := &testHandler{}
+
+	authServer := NewAuthServerStaticWithAuthMethodDescription("", "", 0, CachingSha2Password)
+	authServer.entries["user1"] = []*AuthServerStaticEntry{{Password: "password1"}}
+	authServer.entries["user2"] = []*AuthServerStaticEntry{{Password: "password2", UserData: "userData2"}}
+	defer authServer.close()
+
+	root := t.TempDir()
+	tlstest.CreateCA(root)
+	tlstest.CreateSignedCert(root, tlstest.CA, "01", "server", "server.example.com")
+
+	l, err := NewListener("tcp", "127.0.0.1:", authServer, th, 0, 0, false, false, 0, 0, false)
+	require.NoError(t, err)
+	host, port := getHostPort(t, l.Addr())
+	serverConfig, err := vttls.ServerConfig(
+		path.Join(root, "server-cert.pem"),
+		path.Join(root, "server-key.pem"),
+		"",
+		"",
+		"",
+		tls.VersionTLS12)
+	require.NoError(t, err)
+	params := &ConnParams{
+		Host:       host,
+		Port:       port,
+		Uname:      "user1",
+		Pass:       "password1",
+		SslMode:    vttls.VerifyIdentity,
+		SslCa:      path.Join(root, "ca-cert.pem"),
+		ServerName: "server.example.com",
+	}
+	l.TLSConfig.Store(serverConfig)
+	go l.Accept()
+	defer cleanupListener(ctx, l, params)
+
+	conn, err := Connect(ctx, params)
+	require.NoError(t, err)
+	defer conn.Close()
+
+	// Send a mysql_native_password response, so the server has to ask
+	// to switch to caching_sha2_password.
+	conn.authPluginName = MysqlNativePassword
+	err = conn.ChangeUser(&ConnParams{Uname: "user2", Pass: "password2"})
+	require.NoError(t, err)
+	assert.Equal(t, CachingSha2Password, conn.authPluginName)
+
+	result, err := conn.ExecuteFetch("userData echo", 1, false)
+	require.NoError(t, err)
+	assert.Equal(t, "user2", result.Rows[0][0].ToString())
+	assert.Equal(t, "userData2", result.Rows[0][1].ToString())
+}
+
 type alwaysFallbackAuth struct{}
 
 func (a *alwaysFallbackAuth) UserEntryWithCacheHash(conn *Conn, salt []byte, user string, authResponse []byte, remoteAddr net.Addr) (Getter, CacheState, error) {
@@ -23,6 +23,7 @@ import (
 	"sync/atomic"
 	"time"
 
+	"vitess.io/vitess/go/trace"
 	"vitess.io/vitess/go/vt/log"
 	vtrpcpb "vitess.io/vitess/go/vt/proto/vtrpc"
 	"vitess.io/vitess/go/vt/servenv"
@@ -572,6 +573,23 @@ func (pool *ConnPool[C]) getNew(ctx context.Context) (*Pooled[C], error) {
 	}
 }
 
+// waitForConn waits for a connection to be returned to the pool by another
+// client. The wait has a span of its own, so it stands out in traces.
+func (pool *ConnPool[C]) waitForConn(ctx context.Context, setting *Setting) (*Pooled[C], error) {
+	span, ctx := trace.NewSpan(ctx, "ConnPool.waitForConn")
+	span.Annotate("pool", pool.Name)
+	defer span.Finish()
+
+	start := time.Now()
+	conn, err := pool.wait.waitForConn(ctx, setting)
+	if err != nil {
+		span.Annotate("timeout", true)
+		return nil, ErrTimeout
+	}
+	pool.recordWait(start)
+	return conn, nil
+}
+
 // get returns a pooled connection with no Setting applied
 func (pool *ConnPool[C]) get(ctx context.Context) (*Pooled[C], error) {
 	pool.Metrics.getCount.Add(1)
@@ -594,12 +612,10 @@ func (pool *ConnPool[C]) get(ctx context.Context) (*Pooled[C], error) {
 	// if there are no connections in the setting stacks and we've lent out connections
 	// to other clients, wait until one of the connections is returned
 	if conn == nil {
-		start := time.Now()
-		conn, err = pool.wait.waitForConn(ctx, nil)
+		conn, err = pool.waitForConn(ctx, nil)
 		if err != nil {
-			return nil, ErrTimeout
+			return nil, err
 		}
-		pool.recordWait(start)
 	}
 	// no connections available and no connections to wait for (pool is closed)
 	if conn == nil {
@@ -651,12 +667,10 @@ func (pool *ConnPool[C]) getWithSetting(ctx context.Context, setting *Setting) (
 	// no connections anywhere in the pool; if we've lent out connections to other clients
 	// wait for one of them
 	if conn == nil {
-		start := time.Now()
-		conn, err = pool.wait.waitForConn(ctx, setting)
+		conn, err = pool.waitForConn(ctx, setting)
 		if err != nil {
-			return nil, ErrTimeout
+			return nil, err
 		}
-		pool.recordWait(start)
 	}
 	// no connections available and no connections to wait for (pool is closed)
 	if conn == nil {
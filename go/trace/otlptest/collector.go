@@ -0,0 +1,162 @@
+/*
+Copyright 2026 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package otlptest provides a local stand-in for an OTLP collector, to test
+// the spans exported by the opentelemetry tracing backend.
+package otlptest
+
+import (
+	"context"
+	"io"
+	"net"
+	"net/http"
+	"sync"
+
+	collectortracepb "go.opentelemetry.io/proto/otlp/collector/trace/v1"
+	tracepb "go.opentelemetry.io/proto/otlp/trace/v1"
+	"google.golang.org/grpc"
+	"google.golang.org/protobuf/proto"
+)
+
+// Collector receives spans over OTLP/gRPC and OTLP/HTTP, and keeps them in
+// memory.
+type Collector struct {
+	collectortracepb.UnimplementedTraceServiceServer
+
+	grpcListener net.Listener
+	grpcServer   *grpc.Server
+	httpListener net.Listener
+	httpServer   *http.Server
+
+	mu    sync.Mutex
+	spans []*Span
+}
+
+// Span is a span received by the Collector.
+type Span struct {
+	*tracepb.Span
+	// ServiceName is the service.name resource attribute of the span.
+	ServiceName string
+}
+
+// NewCollector starts a Collector listening on local ports.
+func NewCollector() (*Collector, error) {
+	grpcListener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return nil, err
+	}
+	httpListener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		grpcListener.Close()
+		return nil, err
+	}
+
+	c := &Collector{
+		grpcListener: grpcListener,
+		grpcServer:   grpc.NewServer(),
+		httpListener: httpListener,
+	}
+	collectortracepb.RegisterTraceServiceServer(c.grpcServer, c)
+	mux := http.NewServeMux()
+	mux.HandleFunc("/v1/traces", c.handleHTTP)
+	c.httpServer = &http.Server{Handler: mux}
+
+	go c.grpcServer.Serve(grpcListener)
+	go c.httpServer.Serve(httpListener)
+	return c, nil
+}
+
+// GRPCEndpoint returns the host:port the Collector receives OTLP/gRPC on.
+func (c *Collector) GRPCEndpoint() string {
+	return c.grpcListener.Addr().String()
+}
+
+// HTTPEndpoint returns the host:port the Collector receives OTLP/HTTP on.
+func (c *Collector) HTTPEndpoint() string {
+	return c.httpListener.Addr().String()
+}
+
+// Close stops the Collector.
+func (c *Collector) Close() {
+	c.grpcServer.Stop()
+	c.httpServer.Close()
+}
+
+// Spans returns the spans received so far.
+func (c *Collector) Spans() []*Span {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	spans := make([]*Span, len(c.spans))
+	copy(spans, c.spans)
+	return spans
+}
+
+// SpansNamed returns the spans received so far with the given name.
+func (c *Collector) SpansNamed(name string) []*Span {
+	var spans []*Span
+	for _, span := range c.Spans() {
+		if span.Name == name {
+			spans = append(spans, span)
+		}
+	}
+	return spans
+}
+
+// Export is part of the collectortracepb.TraceServiceServer interface.
+func (c *Collector) Export(ctx context.Context, req *collectortracepb.ExportTraceServiceRequest) (*collectortracepb.ExportTraceServiceResponse, error) {
+	c.add(req)
+	return &collectortracepb.ExportTraceServiceResponse{}, nil
+}
+
+func (c *Collector) handleHTTP(w http.ResponseWriter, r *http.Request) {
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	req := &collectortracepb.ExportTraceServiceRequest{}
+	if err := proto.Unmarshal(body, req); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	c.add(req)
+
+	resp, err := proto.Marshal(&collectortracepb.ExportTraceServiceResponse{})
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/x-protobuf")
+	w.Write(resp)
+}
+
+func (c *Collector) add(req *collectortracepb.ExportTraceServiceRequest) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for _, resourceSpans := range req.ResourceSpans {
+		var serviceName string
+		for _, attr := range resourceSpans.GetResource().GetAttributes() {
+			if attr.Key == "service.name" {
+				serviceName = attr.GetValue().GetStringValue()
+			}
+		}
+		for _, scopeSpans := range resourceSpans.ScopeSpans {
+			for _, span := range scopeSpans.Spans {
+				c.spans = append(c.spans, &Span{Span: span, ServiceName: serviceName})
+			}
+		}
+	}
+}
@@ -0,0 +1,396 @@
+/*
+Copyright 2026 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package trace
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/spf13/pflag"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracegrpc"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.34.0"
+	oteltrace "go.opentelemetry.io/otel/trace"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+
+	"vitess.io/vitess/go/viperutil"
+	"vitess.io/vitess/go/vt/log"
+	"vitess.io/vitess/go/vt/vterrors"
+
+	vtrpcpb "vitess.io/vitess/go/vt/proto/vtrpc"
+)
+
+/*
+This file adds a native OpenTelemetry tracing backend, which exports spans to
+an OTLP collector over gRPC or HTTP. Span contexts are propagated with the W3C
+traceparent and tracestate headers, in the gRPC metadata between Vitess
+components, and in the VT_SPAN_CONTEXT comment or query attribute from MySQL
+clients to vtgate.
+*/
+
+const (
+	otlpProtocolGRPC = "grpc"
+	otlpProtocolHTTP = "http/protobuf"
+)
+
+var (
+	otelConfigKey = viperutil.KeyPrefixFunc(configKey("otel"))
+
+	otlpEndpoint = viperutil.Configure(
+		otelConfigKey("exporter.otlp.endpoint"),
+		viperutil.Options[string]{
+			FlagName: "otel-exporter-otlp-endpoint",
+		},
+	)
+	otlpProtocol = viperutil.Configure(
+		otelConfigKey("exporter.otlp.protocol"),
+		viperutil.Options[string]{
+			Default:  otlpProtocolGRPC,
+			FlagName: "otel-exporter-otlp-protocol",
+		},
+	)
+	otlpInsecure = viperutil.Configure(
+		otelConfigKey("exporter.otlp.insecure"),
+		viperutil.Options[bool]{
+			FlagName: "otel-exporter-otlp-insecure",
+		},
+	)
+)
+
+func init() {
+	// If compiled with plugin_opentelemetry, ensure that trace.RegisterFlags
+	// includes the OpenTelemetry tracing flags.
+	pluginFlags = append(pluginFlags, func(fs *pflag.FlagSet) {
+		fs.String("otel-exporter-otlp-endpoint", "", "host:port, or URL, of the OTLP collector to send spans to. if empty, the standard OTEL_EXPORTER_OTLP_ENDPOINT environment variables are used")
+		fs.String("otel-exporter-otlp-protocol", otlpProtocol.Default(), "protocol used to send spans to the OTLP collector. possible values are 'grpc' or 'http/protobuf'")
+		fs.Bool("otel-exporter-otlp-insecure", false, "whether to send spans to the OTLP collector without TLS")
+
+		viperutil.BindFlags(fs, otlpEndpoint, otlpProtocol, otlpInsecure)
+	})
+}
+
+func newOTLPExporter(ctx context.Context) (*otlptrace.Exporter, error) {
+	endpoint := otlpEndpoint.Get()
+	switch protocol := otlpProtocol.Get(); protocol {
+	case otlpProtocolGRPC:
+		var opts []otlptracegrpc.Option
+		if strings.Contains(endpoint, "://") {
+			opts = append(opts, otlptracegrpc.WithEndpointURL(endpoint))
+		} else if endpoint != "" {
+			opts = append(opts, otlptracegrpc.WithEndpoint(endpoint))
+		}
+		if otlpInsecure.Get() {
+			opts = append(opts, otlptracegrpc.WithInsecure())
+		}
+		return otlptracegrpc.New(ctx, opts...)
+	case otlpProtocolHTTP:
+		var opts []otlptracehttp.Option
+		if strings.Contains(endpoint, "://") {
+			opts = append(opts, otlptracehttp.WithEndpointURL(endpoint))
+		} else if endpoint != "" {
+			opts = append(opts, otlptracehttp.WithEndpoint(endpoint))
+		}
+		if otlpInsecure.Get() {
+			opts = append(opts, otlptracehttp.WithInsecure())
+		}
+		return otlptracehttp.New(ctx, opts...)
+	default:
+		return nil, fmt.Errorf("unknown OTLP protocol %q, possible values are %q or %q", protocol, otlpProtocolGRPC, otlpProtocolHTTP)
+	}
+}
+
+func newOpenTelemetryTracer(serviceName string) (tracingService, io.Closer, error) {
+	exporter, err := newOTLPExporter(context.Background())
+	if err != nil {
+		return nil, nil, err
+	}
+	res, err := resource.Merge(resource.Default(), resource.NewWithAttributes(semconv.SchemaURL, semconv.ServiceName(serviceName)))
+	if err != nil {
+		return nil, nil, err
+	}
+
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(res),
+		sdktrace.WithSampler(sdktrace.ParentBased(sdktrace.TraceIDRatioBased(samplingRate.Get()))),
+	)
+	propagator := propagation.NewCompositeTextMapPropagator(propagation.TraceContext{}, propagation.Baggage{})
+	otel.SetTracerProvider(provider)
+	otel.SetTextMapPropagator(propagator)
+	if enableLogging.Get() {
+		otel.SetErrorHandler(otel.ErrorHandlerFunc(func(err error) {
+			log.Errorf("OpenTelemetry: %v", err)
+		}))
+	}
+	log.Infof("Tracing to an OTLP collector over %s as %v, sampling rate %v", otlpProtocol.Get(), serviceName, samplingRate.Get())
+
+	svc := &openTelemetryService{
+		tracer:     provider.Tracer("vitess.io/vitess/go/trace"),
+		propagator: propagator,
+	}
+	return svc, &otelCloser{provider: provider}, nil
+}
+
+func init() {
+	tracingBackendFactories["opentelemetry"] = newOpenTelemetryTracer
+}
+
+var _ io.Closer = (*otelCloser)(nil)
+
+// otelCloser flushes the spans that are not exported yet when closed.
+type otelCloser struct {
+	provider *sdktrace.TracerProvider
+}
+
+func (c *otelCloser) Close() error {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	return c.provider.Shutdown(ctx)
+}
+
+var _ Span = (*openTelemetrySpan)(nil)
+
+type openTelemetrySpan struct {
+	otelSpan oteltrace.Span
+}
+
+// Finish will mark a span as finished
+func (s openTelemetrySpan) Finish() {
+	s.otelSpan.End()
+}
+
+// Annotate will add information to an existing span
+func (s openTelemetrySpan) Annotate(key string, value any) {
+	s.otelSpan.SetAttributes(otelAttribute(key, value))
+}
+
+func otelAttribute(key string, value any) attribute.KeyValue {
+	switch v := value.(type) {
+	case string:
+		return attribute.String(key, v)
+	case bool:
+		return attribute.Bool(key, v)
+	case int:
+		return attribute.Int(key, v)
+	case int64:
+		return attribute.Int64(key, v)
+	case float64:
+		return attribute.Float64(key, v)
+	case []string:
+		return attribute.StringSlice(key, v)
+	case fmt.Stringer:
+		return attribute.Stringer(key, v)
+	default:
+		return attribute.String(key, fmt.Sprint(v))
+	}
+}
+
+var _ tracingService = (*openTelemetryService)(nil)
+
+type openTelemetryService struct {
+	tracer     oteltrace.Tracer
+	propagator propagation.TextMapPropagator
+}
+
+// New is part of an interface implementation
+func (s *openTelemetryService) New(parent Span, label string) Span {
+	ctx := context.Background()
+	if parent, ok := parent.(openTelemetrySpan); ok {
+		ctx = oteltrace.ContextWithSpan(ctx, parent.otelSpan)
+	}
+	_, span := s.tracer.Start(ctx, label)
+	return openTelemetrySpan{otelSpan: span}
+}
+
+// NewFromString is part of an interface implementation. The parent is
+// either a W3C traceparent, or a base64 encoded JSON map of the traceparent
+// and tracestate headers.
+func (s *openTelemetryService) NewFromString(parent, label string) (Span, error) {
+	var carrier propagation.MapCarrier
+	if strings.Count(parent, "-") == 3 {
+		carrier = propagation.MapCarrier{"traceparent": parent}
+	} else {
+		textMap, err := extractMapFromString(parent)
+		if err != nil {
+			return nil, err
+		}
+		carrier = propagation.MapCarrier(textMap)
+	}
+	ctx := s.propagator.Extract(context.Background(), carrier)
+	if !oteltrace.SpanContextFromContext(ctx).IsValid() {
+		return nil, vterrors.New(vtrpcpb.Code_INVALID_ARGUMENT, "failed to deserialize span context")
+	}
+	_, span := s.tracer.Start(ctx, label)
+	return openTelemetrySpan{otelSpan: span}, nil
+}
+
+// FromContext is part of an interface implementation
+func (s *openTelemetryService) FromContext(ctx context.Context) (Span, bool) {
+	span := oteltrace.SpanFromContext(ctx)
+	if !span.SpanContext().IsValid() {
+		return nil, false
+	}
+	return openTelemetrySpan{otelSpan: span}, true
+}
+
+// NewContext is part of an interface implementation
+func (s *openTelemetryService) NewContext(parent context.Context, span Span) context.Context {
+	otelSpan, ok := span.(openTelemetrySpan)
+	if !ok {
+		return nil
+	}
+	return oteltrace.ContextWithSpan(parent, otelSpan.otelSpan)
+}
+
+// AddGrpcServerOptions is part of an interface implementation
+func (s *openTelemetryService) AddGrpcServerOptions(addInterceptors func(s grpc.StreamServerInterceptor, u grpc.UnaryServerInterceptor)) {
+	addInterceptors(s.streamServerInterceptor, s.unaryServerInterceptor)
+}
+
+// AddGrpcClientOptions is part of an interface implementation
+func (s *openTelemetryService) AddGrpcClientOptions(addInterceptors func(s grpc.StreamClientInterceptor, u grpc.UnaryClientInterceptor)) {
+	addInterceptors(s.streamClientInterceptor, s.unaryClientInterceptor)
+}
+
+// metadataCarrier adapts the gRPC metadata to the propagation.TextMapCarrier
+// interface.
+type metadataCarrier metadata.MD
+
+func (c metadataCarrier) Get(key string) string {
+	if values := metadata.MD(c).Get(key); len(values) > 0 {
+		return values[0]
+	}
+	return ""
+}
+
+func (c metadataCarrier) Set(key, value string) {
+	metadata.MD(c).Set(key, value)
+}
+
+func (c metadataCarrier) Keys() []string {
+	keys := make([]string, 0, len(c))
+	for key := range c {
+		keys = append(keys, key)
+	}
+	return keys
+}
+
+// startServerSpan starts the span of a gRPC call, as a child of the span
+// propagated in the incoming metadata, if any.
+func (s *openTelemetryService) startServerSpan(ctx context.Context, method string) (context.Context, oteltrace.Span) {
+	md, _ := metadata.FromIncomingContext(ctx)
+	ctx = s.propagator.Extract(ctx, metadataCarrier(md))
+	return s.tracer.Start(ctx, method, oteltrace.WithSpanKind(oteltrace.SpanKindServer))
+}
+
+// startClientSpan starts the span of a gRPC call, and propagates it in the
+// outgoing metadata.
+func (s *openTelemetryService) startClientSpan(ctx context.Context, method string) (context.Context, oteltrace.Span) {
+	ctx, span := s.tracer.Start(ctx, method, oteltrace.WithSpanKind(oteltrace.SpanKindClient))
+	md, ok := metadata.FromOutgoingContext(ctx)
+	if ok {
+		md = md.Copy()
+	} else {
+		md = metadata.MD{}
+	}
+	s.propagator.Inject(ctx, metadataCarrier(md))
+	return metadata.NewOutgoingContext(ctx, md), span
+}
+
+func finishSpan(span oteltrace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
+
+func (s *openTelemetryService) unaryServerInterceptor(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+	ctx, span := s.startServerSpan(ctx, info.FullMethod)
+	resp, err := handler(ctx, req)
+	finishSpan(span, err)
+	return resp, err
+}
+
+// tracedServerStream overrides the context of a grpc.ServerStream with the
+// one of the span of the call.
+type tracedServerStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (ss *tracedServerStream) Context() context.Context {
+	return ss.ctx
+}
+
+func (s *openTelemetryService) streamServerInterceptor(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	ctx, span := s.startServerSpan(ss.Context(), info.FullMethod)
+	err := handler(srv, &tracedServerStream{ServerStream: ss, ctx: ctx})
+	finishSpan(span, err)
+	return err
+}
+
+func (s *openTelemetryService) unaryClientInterceptor(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+	ctx, span := s.startClientSpan(ctx, method)
+	err := invoker(ctx, method, req, reply, cc, opts...)
+	finishSpan(span, err)
+	return err
+}
+
+// tracedClientStream ends the span of a streaming call when the stream is
+// done, i.e. when receiving a message fails.
+type tracedClientStream struct {
+	grpc.ClientStream
+	span oteltrace.Span
+	once sync.Once
+}
+
+func (cs *tracedClientStream) RecvMsg(m any) error {
+	err := cs.ClientStream.RecvMsg(m)
+	if err != nil {
+		cs.once.Do(func() {
+			if err == io.EOF {
+				finishSpan(cs.span, nil)
+				return
+			}
+			finishSpan(cs.span, err)
+		})
+	}
+	return err
+}
+
+func (s *openTelemetryService) streamClientInterceptor(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string, streamer grpc.Streamer, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+	ctx, span := s.startClientSpan(ctx, method)
+	cs, err := streamer(ctx, desc, cc, method, opts...)
+	if err != nil {
+		finishSpan(span, err)
+		return nil, err
+	}
+	return &tracedClientStream{ClientStream: cs, span: span}, nil
+}
@@ -0,0 +1,192 @@
+/*
+Copyright 2026 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package trace
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"testing"
+
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	oteltrace "go.opentelemetry.io/otel/trace"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+
+	"vitess.io/vitess/go/trace/otlptest"
+	"vitess.io/vitess/go/viperutil/vipertest"
+)
+
+func newTestOpenTelemetryService(t *testing.T) (*openTelemetryService, *tracetest.SpanRecorder) {
+	recorder := tracetest.NewSpanRecorder()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+	t.Cleanup(func() { provider.Shutdown(context.Background()) })
+	return &openTelemetryService{
+		tracer:     provider.Tracer("test"),
+		propagator: propagation.TraceContext{},
+	}, recorder
+}
+
+func TestOpenTelemetryExport(t *testing.T) {
+	for _, protocol := range []string{otlpProtocolGRPC, otlpProtocolHTTP} {
+		t.Run(protocol, func(t *testing.T) {
+			collector, err := otlptest.NewCollector()
+			require.NoError(t, err)
+			defer collector.Close()
+
+			v := viper.New()
+			t.Cleanup(vipertest.Stub(t, v, tracingServer))
+			t.Cleanup(vipertest.Stub(t, v, otlpEndpoint))
+			t.Cleanup(vipertest.Stub(t, v, otlpProtocol))
+			t.Cleanup(vipertest.Stub(t, v, otlpInsecure))
+			t.Cleanup(vipertest.Stub(t, v, samplingRate))
+			v.Set(tracingServer.Key(), "opentelemetry")
+			v.Set(otlpProtocol.Key(), protocol)
+			v.Set(otlpInsecure.Key(), true)
+			v.Set(samplingRate.Key(), 1.0)
+			if protocol == otlpProtocolGRPC {
+				v.Set(otlpEndpoint.Key(), collector.GRPCEndpoint())
+			} else {
+				v.Set(otlpEndpoint.Key(), "http://"+collector.HTTPEndpoint())
+			}
+
+			closer := StartTracing("vtgate")
+			defer func() { currentTracer = noopTracingServer{} }()
+
+			parent, ctx := NewSpan(context.Background(), "parent")
+			parent.Annotate("keyspace", "ks")
+			child, _ := NewSpan(ctx, "child")
+			child.Annotate("rows", 10)
+			child.Finish()
+			parent.Finish()
+			// Closing the tracer flushes the spans.
+			require.NoError(t, closer.Close())
+
+			parents := collector.SpansNamed("parent")
+			children := collector.SpansNamed("child")
+			require.Len(t, parents, 1)
+			require.Len(t, children, 1)
+			assert.Equal(t, "vtgate", parents[0].ServiceName)
+			assert.Equal(t, parents[0].TraceId, children[0].TraceId)
+			assert.Equal(t, parents[0].SpanId, children[0].ParentSpanId)
+			require.Len(t, parents[0].Attributes, 1)
+			assert.Equal(t, "keyspace", parents[0].Attributes[0].Key)
+			assert.Equal(t, "ks", parents[0].Attributes[0].Value.GetStringValue())
+			require.Len(t, children[0].Attributes, 1)
+			assert.Equal(t, int64(10), children[0].Attributes[0].Value.GetIntValue())
+		})
+	}
+}
+
+func TestOpenTelemetryUnknownProtocol(t *testing.T) {
+	v := viper.New()
+	t.Cleanup(vipertest.Stub(t, v, otlpProtocol))
+	v.Set(otlpProtocol.Key(), "thrift")
+
+	_, _, err := newOpenTelemetryTracer("vtgate")
+	assert.ErrorContains(t, err, `unknown OTLP protocol "thrift"`)
+}
+
+func TestOpenTelemetryNewFromString(t *testing.T) {
+	svc, recorder := newTestOpenTelemetryService(t)
+	traceparent := "00-0af7651916cd43dd8448eb211c80319c-b7ad6b7169203331-01"
+
+	textMap, err := json.Marshal(map[string]string{"traceparent": traceparent})
+	require.NoError(t, err)
+	for _, parent := range []string{traceparent, base64.StdEncoding.EncodeToString(textMap)} {
+		span, err := svc.NewFromString(parent, "query")
+		require.NoError(t, err)
+		span.Finish()
+	}
+
+	spans := recorder.Ended()
+	require.Len(t, spans, 2)
+	for _, span := range spans {
+		assert.Equal(t, "0af7651916cd43dd8448eb211c80319c", span.SpanContext().TraceID().String())
+		assert.Equal(t, "b7ad6b7169203331", span.Parent().SpanID().String())
+		assert.True(t, span.Parent().IsRemote())
+	}
+
+	_, err = svc.NewFromString("00-not-a-traceparent", "query")
+	assert.ErrorContains(t, err, "failed to deserialize span context")
+	_, err = svc.NewFromString("this is not base64", "query")
+	assert.Error(t, err)
+}
+
+func TestOpenTelemetryContext(t *testing.T) {
+	svc, _ := newTestOpenTelemetryService(t)
+
+	_, ok := svc.FromContext(context.Background())
+	assert.False(t, ok)
+
+	span := svc.New(nil, "parent")
+	ctx := svc.NewContext(context.Background(), span)
+	fromCtx, ok := svc.FromContext(ctx)
+	require.True(t, ok)
+	assert.Equal(t, span, fromCtx)
+
+	child := svc.New(fromCtx, "child")
+	assert.Equal(t,
+		span.(openTelemetrySpan).otelSpan.SpanContext().TraceID(),
+		child.(openTelemetrySpan).otelSpan.SpanContext().TraceID())
+
+	assert.Nil(t, svc.NewContext(context.Background(), NoopSpan{}))
+}
+
+func TestOpenTelemetryGrpcPropagation(t *testing.T) {
+	svc, recorder := newTestOpenTelemetryService(t)
+
+	span := svc.New(nil, "vtgate")
+	ctx := svc.NewContext(context.Background(), span)
+
+	// The client interceptor propagates the span in the outgoing metadata...
+	var outgoing metadata.MD
+	invoker := func(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn, opts ...grpc.CallOption) error {
+		outgoing, _ = metadata.FromOutgoingContext(ctx)
+		return nil
+	}
+	require.NoError(t, svc.unaryClientInterceptor(ctx, "/queryservice.Query/Execute", nil, nil, nil, invoker))
+	require.Len(t, outgoing.Get("traceparent"), 1)
+
+	// ...that the server interceptor continues.
+	var serverSpan oteltrace.Span
+	handler := func(ctx context.Context, req any) (any, error) {
+		serverSpan = oteltrace.SpanFromContext(ctx)
+		return nil, nil
+	}
+	incoming := metadata.NewIncomingContext(context.Background(), outgoing)
+	_, err := svc.unaryServerInterceptor(incoming, nil, &grpc.UnaryServerInfo{FullMethod: "/queryservice.Query/Execute"}, handler)
+	require.NoError(t, err)
+	span.Finish()
+
+	traceID := span.(openTelemetrySpan).otelSpan.SpanContext().TraceID()
+	assert.Equal(t, traceID, serverSpan.SpanContext().TraceID())
+
+	spans := recorder.Ended()
+	require.Len(t, spans, 3)
+	client, server := spans[0], spans[1]
+	assert.Equal(t, oteltrace.SpanKindClient, client.SpanKind())
+	assert.Equal(t, oteltrace.SpanKindServer, server.SpanKind())
+	assert.Equal(t, client.SpanContext().SpanID(), server.Parent().SpanID())
+	assert.Equal(t, hex.EncodeToString(traceID[:]), client.SpanContext().TraceID().String())
+}
@@ -706,3 +706,6 @@ func (f *fakeTopoServer) WatchSrvVSchema(ctx context.Context, cell string, callb
 	sv, err := f.GetSrvVSchema(ctx, cell)
 	callback(sv, err)
 }
+
+func (f *fakeTopoServer) WatchTenantDirectory(ctx context.Context, keyspace, name string, callback func(*vschemapb.TenantDirectory, error) bool) {
+}
@@ -20,8 +20,10 @@ package azblobbackupstorage
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"io"
+	"net/http"
 	"net/url"
 	"os"
 	"strings"
@@ -32,7 +34,7 @@ import (
 	"github.com/Azure/azure-storage-blob-go/azblob"
 	"github.com/spf13/pflag"
 
-	"vitess.io/vitess/go/vt/mysqlctl/errors"
+	errorsbackup "vitess.io/vitess/go/vt/mysqlctl/errors"
 	"vitess.io/vitess/go/vt/utils"
 
 	"vitess.io/vitess/go/viperutil"
@@ -214,7 +216,7 @@ type AZBlobBackupHandle struct {
 	waitGroup sync.WaitGroup
 	ctx       context.Context
 	cancel    context.CancelFunc
-	errors.PerFileErrorRecorder
+	errorsbackup.PerFileErrorRecorder
 }
 
 // Directory implements BackupHandle.
@@ -300,6 +302,9 @@ func (bh *AZBlobBackupHandle) ReadFile(ctx context.Context, filename string) (io
 
 	resp, err := blobURL.Download(ctx, 0, azblob.CountToEnd, azblob.BlobAccessConditions{}, false, azblob.ClientProvidedKeyOptions{})
 	if err != nil {
+		if isNotFound(err) {
+			return nil, fmt.Errorf("%w: %w", backupstorage.ErrFileNotFound, err)
+		}
 		return nil, err
 	}
 	return resp.Body(azblob.RetryReaderOptions{
@@ -311,6 +316,71 @@ func (bh *AZBlobBackupHandle) ReadFile(ctx context.Context, filename string) (io
 	}), nil
 }
 
+// chunkObjName returns the object name of a chunk of the directory of the backup.
+func (bh *AZBlobBackupHandle) chunkObjName(name string) string {
+	// The directory of read-only backups includes their name.
+	dir := bh.dir
+	if bh.readOnly {
+		dir = strings.TrimSuffix(dir, "/"+bh.name)
+	}
+	return objName(backupstorage.ChunksDir, dir, name)
+}
+
+// HasChunk implements backupstorage.ChunkHandle.
+func (bh *AZBlobBackupHandle) HasChunk(ctx context.Context, name string) (bool, error) {
+	containerURL, err := bh.bs.containerURL()
+	if err != nil {
+		return false, err
+	}
+	// Setting the metadata of the chunk refreshes its modification time.
+	metadata := azblob.Metadata{"lastused": time.Now().UTC().Format(time.RFC3339)}
+	_, err = containerURL.NewBlobURL(bh.chunkObjName(name)).SetMetadata(ctx, metadata, azblob.BlobAccessConditions{}, azblob.ClientProvidedKeyOptions{})
+	if err != nil {
+		if isNotFound(err) {
+			return false, nil
+		}
+		return false, err
+	}
+	return true, nil
+}
+
+// AddChunk implements backupstorage.ChunkHandle.
+func (bh *AZBlobBackupHandle) AddChunk(ctx context.Context, name string, data []byte) error {
+	if bh.readOnly {
+		return fmt.Errorf("AddChunk cannot be called on read-only backup")
+	}
+	containerURL, err := bh.bs.containerURL()
+	if err != nil {
+		return err
+	}
+	// The blob is only visible once its block list is committed.
+	_, err = azblob.UploadBufferToBlockBlob(ctx, data, containerURL.NewBlockBlobURL(bh.chunkObjName(name)), azblob.UploadToBlockBlobOptions{})
+	return err
+}
+
+// ReadChunk implements backupstorage.ChunkHandle.
+func (bh *AZBlobBackupHandle) ReadChunk(ctx context.Context, name string) (io.ReadCloser, error) {
+	if !bh.readOnly {
+		return nil, fmt.Errorf("ReadChunk cannot be called on read-write backup")
+	}
+	containerURL, err := bh.bs.containerURL()
+	if err != nil {
+		return nil, err
+	}
+	obj := bh.chunkObjName(name)
+	resp, err := containerURL.NewBlobURL(obj).Download(ctx, 0, azblob.CountToEnd, azblob.BlobAccessConditions{}, false, azblob.ClientProvidedKeyOptions{})
+	if err != nil {
+		return nil, err
+	}
+	return resp.Body(azblob.RetryReaderOptions{
+		MaxRetryRequests: defaultRetryCount,
+		NotifyFailedRead: func(failureCount int, lastError error, offset int64, count int64, willRetry bool) {
+			log.Warningf("ReadChunk: [azblob] container: %s, chunk: %s, error: %v", containerName, obj, lastError)
+		},
+		TreatEarlyCloseAsError: true,
+	}), nil
+}
+
 // AZBlobBackupStorage structs implements the BackupStorage interface for AZBlob
 type AZBlobBackupStorage struct {
 }
@@ -444,6 +514,66 @@ func (bs *AZBlobBackupStorage) RemoveBackup(ctx context.Context, dir, name strin
 	return err
 }
 
+// ReopenBackup implements backupstorage.ReopenStorage.
+func (bs *AZBlobBackupStorage) ReopenBackup(ctx context.Context, dir, name string) (backupstorage.BackupHandle, error) {
+	return bs.StartBackup(ctx, dir, name)
+}
+
+// ListChunks implements backupstorage.ChunkStorage.
+func (bs *AZBlobBackupStorage) ListChunks(ctx context.Context, dir string) ([]backupstorage.ChunkInfo, error) {
+	searchPrefix := objName(backupstorage.ChunksDir, dir, "")
+	log.Infof("ListChunks: [azblob] container: %s, directory: %v", containerName, searchPrefix)
+
+	containerURL, err := bs.containerURL()
+	if err != nil {
+		return nil, err
+	}
+
+	var chunks []backupstorage.ChunkInfo
+	for marker := (azblob.Marker{}); marker.NotDone(); {
+		resp, err := containerURL.ListBlobsFlatSegment(ctx, marker, azblob.ListBlobsSegmentOptions{
+			Prefix: searchPrefix,
+		})
+		if err != nil {
+			return nil, err
+		}
+		for _, item := range resp.Segment.BlobItems {
+			chunks = append(chunks, backupstorage.ChunkInfo{
+				Name:    strings.TrimPrefix(item.Name, searchPrefix),
+				ModTime: item.Properties.LastModified,
+				Version: string(item.Properties.Etag),
+			})
+		}
+		marker = resp.NextMarker
+	}
+	return chunks, nil
+}
+
+// RemoveChunk implements backupstorage.ChunkStorage. Adding a chunk or
+// setting its metadata in HasChunk changes its ETag, so the chunk is only
+// deleted if its ETag is still the one it was listed with.
+func (bs *AZBlobBackupStorage) RemoveChunk(ctx context.Context, dir string, chunk backupstorage.ChunkInfo) (bool, error) {
+	containerURL, err := bs.containerURL()
+	if err != nil {
+		return false, err
+	}
+	conds := azblob.BlobAccessConditions{
+		ModifiedAccessConditions: azblob.ModifiedAccessConditions{IfMatch: azblob.ETag(chunk.Version)},
+	}
+	_, err = containerURL.NewBlobURL(objName(backupstorage.ChunksDir, dir, chunk.Name)).Delete(ctx, azblob.DeleteSnapshotsOptionInclude, conds)
+	if err != nil {
+		var storageErr azblob.StorageError
+		switch {
+		case isNotFound(err):
+			return true, nil
+		case errors.As(err, &storageErr) && storageErr.Response() != nil && storageErr.Response().StatusCode == http.StatusPreconditionFailed:
+			return false, nil
+		}
+		return false, err
+	}
+	return true, nil
+}
+
 // Close implements BackupStorage.
 func (bs *AZBlobBackupStorage) Close() error {
 	// This function is a No-op
@@ -455,6 +585,13 @@ func (bs *AZBlobBackupStorage) WithParams(params backupstorage.Params) backupsto
 	return bs
 }
 
+// isNotFound returns whether err is the error of an operation on a blob that
+// does not exist.
+func isNotFound(err error) bool {
+	var storageErr azblob.StorageError
+	return errors.As(err, &storageErr) && storageErr.Response() != nil && storageErr.Response().StatusCode == http.StatusNotFound
+}
+
 // objName joins path parts into an object name.
 // Unlike path.Join, it doesn't collapse ".." or strip trailing slashes.
 // It also adds the value of the -azblob-backup-storage-root flag if set.
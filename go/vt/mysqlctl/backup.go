@@ -24,6 +24,7 @@ import (
 	"io"
 	"os"
 	"path/filepath"
+	"slices"
 	"strings"
 	"time"
 
@@ -123,6 +124,12 @@ func registerBackupFlags(fs *pflag.FlagSet) {
 // - shuts down Mysqld during the backup
 // - remember if we were replicating, restore the exact same state
 func Backup(ctx context.Context, params BackupParams) error {
+	_, err := backup(ctx, params)
+	return err
+}
+
+// backup takes a backup, and returns whether it is usable, or empty.
+func backup(ctx context.Context, params BackupParams) (BackupResult, error) {
 	if params.Stats == nil {
 		params.Stats = backupstats.NoStats()
 	}
@@ -133,7 +140,7 @@ func Backup(ctx context.Context, params BackupParams) error {
 	// Start the backup with the BackupStorage.
 	bs, err := backupstorage.GetBackupStorage()
 	if err != nil {
-		return vterrors.Wrap(err, "unable to get backup storage")
+		return BackupUnusable, vterrors.Wrap(err, "unable to get backup storage")
 	}
 	defer bs.Close()
 
@@ -151,7 +158,7 @@ func Backup(ctx context.Context, params BackupParams) error {
 
 	bh, err := bs.StartBackup(ctx, backupDir, name)
 	if err != nil {
-		return vterrors.Wrap(err, "StartBackup failed")
+		return BackupUnusable, vterrors.Wrap(err, "StartBackup failed")
 	}
 	params.Logger.Infof("Starting backup %v", bh.Name())
 
@@ -169,7 +176,7 @@ func Backup(ctx context.Context, params BackupParams) error {
 	} else {
 		be, err = GetBackupEngine(params.BackupEngine)
 		if err != nil {
-			return vterrors.Wrap(err, "failed to find backup engine")
+			return BackupUnusable, vterrors.Wrap(err, "failed to find backup engine")
 		}
 	}
 
@@ -198,13 +205,17 @@ func Backup(ctx context.Context, params BackupParams) error {
 			// finish error, return the backup error.
 			logger.Errorf2(finishErr, "failed to finish backup: %v")
 		}
-		return err
+		return backupResult, err
+	}
+
+	if backupResult == BackupUsable && finishErr == nil {
+		catalogBackup(ctx, bs, backupDir, bh.Name(), logger)
 	}
 
 	// The backup worked, so just return the finish error, if any.
 	backupstats.DeprecatedBackupDurationS.Set(int64(time.Since(startTs).Seconds()))
 	params.Stats.Scope(backupstats.Operation("Backup")).TimedIncrement(time.Since(startTs))
-	return finishErr
+	return backupResult, finishErr
 }
 
 // ParseBackupName parses the backup name for a given dir/name, according to
@@ -400,6 +411,14 @@ func Restore(ctx context.Context, params RestoreParams) (*BackupManifest, error)
 	if err != nil {
 		return nil, vterrors.Wrap(err, "ListBackups failed")
 	}
+	if params.BackupName != "" {
+		bhs = slices.DeleteFunc(bhs, func(bh backupstorage.BackupHandle) bool {
+			return bh.Name() != params.BackupName
+		})
+		if len(bhs) == 0 {
+			return nil, vterrors.Errorf(vtrpc.Code_NOT_FOUND, "backup %v not found in directory %v", params.BackupName, backupDir)
+		}
+	}
 
 	if len(bhs) == 0 {
 		// There are no backups (not even broken/incomplete ones).
@@ -422,7 +441,8 @@ func Restore(ctx context.Context, params RestoreParams) (*BackupManifest, error)
 		return nil, ErrNoBackup
 	}
 
-	restorePath, err := FindBackupToRestore(ctx, params, bhs)
+	catalog := loadBackupCatalog(ctx, bs, backupDir, params.Logger)
+	restorePath, err := findBackupToRestore(ctx, params, bhs, catalog)
 	if err != nil {
 		return nil, err
 	}
@@ -435,6 +455,16 @@ func Restore(ctx context.Context, params RestoreParams) (*BackupManifest, error)
 	if err != nil {
 		return nil, vterrors.Wrap(err, "Failed to find restore engine")
 	}
+	if params.RowFilter != nil {
+		// Only the rows of a logical backup can be filtered, and the binary
+		// logs of an incremental restore would bring the other rows back.
+		if _, ok := re.(*LogicalBackupEngine); !ok {
+			return nil, vterrors.Errorf(vtrpc.Code_FAILED_PRECONDITION, "backup %v is not a logical backup, it can't be restored into a different key range", bh.Name())
+		}
+		if restorePath.Len() > 1 {
+			return nil, vterrors.Errorf(vtrpc.Code_FAILED_PRECONDITION, "a point in time recovery can't be restored into a different key range")
+		}
+	}
 	params.Logger.Infof("Restore: %v", restorePath.String())
 	if params.DryRun {
 		return nil, nil
@@ -479,6 +509,13 @@ func Restore(ctx context.Context, params RestoreParams) (*BackupManifest, error)
 	if err := params.Mysqld.Start(context.Background(), params.Cnf); err != nil {
 		return nil, err
 	}
+	if params.OnRestoredPosition != nil {
+		pos, err := params.Mysqld.PrimaryPosition(ctx)
+		if err != nil {
+			return nil, vterrors.Wrap(err, "failed to read the restored position")
+		}
+		params.OnRestoredPosition(pos)
+	}
 	if err = ensureRestoredGTIDPurgedMatchesManifest(ctx, manifest, &params); err != nil {
 		return nil, err
 	}
@@ -355,6 +355,22 @@ func TestRestoreExecutesRestoreWithScopedParams(t *testing.T) {
 	require.Equal(t, "Fake", executeRestoreStats.ScopeV[backupstats.ScopeImplementation])
 }
 
+// TestRestoreReportsRestoredPosition tests that Restore reports the position
+// of the restored mysqld to OnRestoredPosition.
+func TestRestoreReportsRestoredPosition(t *testing.T) {
+	env := createFakeBackupRestoreEnv(t)
+	pos := replication.MustParsePosition(replication.Mysql56FlavorID, "16b1039f-22b6-11ed-b765-0a43f95f28a3:1-99")
+	env.mysqld.SetPrimaryPositionLocked(pos)
+	var restoredPos replication.Position
+	env.restoreParams.OnRestoredPosition = func(pos replication.Position) {
+		restoredPos = pos
+	}
+
+	_, err := Restore(env.ctx, env.restoreParams)
+	require.Nil(t, err, env.logger.Events)
+	require.Equal(t, pos, restoredPos)
+}
+
 // TestRestoreNoStats tests that if RestoreParams.Stats is nil, then Restore will
 // pass non-nil Stats to sub-components.
 func TestRestoreNoStats(t *testing.T) {
@@ -85,6 +85,9 @@ type BackupParams struct {
 	// Position of last known backup. If non empty, then this value indicates the backup should be incremental
 	// and as of this position
 	IncrementalFromPos string
+	// BinlogArchive indicates the incremental backup archives only the first closed binary log past
+	// IncrementalFromPos, without rotating the binary logs.
+	BinlogArchive bool
 	// Stats let's backup engines report detailed backup timings.
 	Stats backupstats.Stats
 	// UpgradeSafe indicates whether the backup is safe for upgrade and created with innodb_fast_shutdown=0
@@ -93,6 +96,8 @@ type BackupParams struct {
 	MysqlShutdownTimeout time.Duration
 	// BackupEngine allows us to override which backup engine should be used for a request
 	BackupEngine string
+	// IOThrottler, if set, holds the I/O of the backup back while the host is under pressure.
+	IOThrottler IOThrottler
 }
 
 func (b *BackupParams) Copy() BackupParams {
@@ -108,9 +113,11 @@ func (b *BackupParams) Copy() BackupParams {
 		TabletAlias:          b.TabletAlias,
 		BackupTime:           b.BackupTime,
 		IncrementalFromPos:   b.IncrementalFromPos,
+		BinlogArchive:        b.BinlogArchive,
 		Stats:                b.Stats,
 		UpgradeSafe:          b.UpgradeSafe,
 		MysqlShutdownTimeout: b.MysqlShutdownTimeout,
+		IOThrottler:          b.IOThrottler,
 	}
 }
 
@@ -151,6 +158,16 @@ type RestoreParams struct {
 	MysqlShutdownTimeout time.Duration
 	// AllowedBackupEngines if present will filter out any backups taken with engines not included in the list
 	AllowedBackupEngines []string
+	// BackupName, if set, is the name of the only backup that can be restored.
+	BackupName string
+	// IOThrottler, if set, holds the I/O of the restore back while the host is under pressure.
+	IOThrottler IOThrottler
+	// RowFilter, if set, selects the rows that are restored, to restore the backup of
+	// a shard into a shard of a different key range. Only logical backups support it.
+	RowFilter RestoreRowFilter
+	// OnRestoredPosition, if set, is called with the GTID position the restored mysqld got along with the data,
+	// before the restore sets it from the MANIFEST if they differ.
+	OnRestoredPosition func(pos replication.Position)
 }
 
 func (p *RestoreParams) Copy() RestoreParams {
@@ -170,6 +187,10 @@ func (p *RestoreParams) Copy() RestoreParams {
 		DryRun:               p.DryRun,
 		Stats:                p.Stats,
 		MysqlShutdownTimeout: p.MysqlShutdownTimeout,
+		BackupName:           p.BackupName,
+		IOThrottler:          p.IOThrottler,
+		RowFilter:            p.RowFilter,
+		OnRestoredPosition:   p.OnRestoredPosition,
 	}
 }
 
@@ -339,6 +360,9 @@ type BackupManifest struct {
 
 	// IncrementalDetails is nil for non-incremental backups
 	IncrementalDetails *IncrementalBackupDetails
+
+	// Size is the number of bytes of the backup files, if known by the backup engine.
+	Size int64 `json:",omitempty"`
 }
 
 func (m *BackupManifest) HashKey() string {
@@ -433,8 +457,8 @@ func (p *RestorePath) String() string {
 }
 
 // findLatestSuccessfulBackup returns the handle and manifest for the last good backup,
-// which can be either full or increment
-func findLatestSuccessfulBackup(ctx context.Context, logger logutil.Logger, bhs []backupstorage.BackupHandle, excludeBackupName string) (backupstorage.BackupHandle, *BackupManifest, error) {
+// which can be either full or increment. The manifests are taken from the catalog, which may be nil.
+func findLatestSuccessfulBackup(ctx context.Context, logger logutil.Logger, bhs []backupstorage.BackupHandle, catalog *BackupCatalog, excludeBackupName string) (backupstorage.BackupHandle, *BackupManifest, error) {
 	for index := len(bhs) - 1; index >= 0; index-- {
 		bh := bhs[index]
 		if bh.Name() == excludeBackupName {
@@ -445,7 +469,7 @@ func findLatestSuccessfulBackup(ctx context.Context, logger logutil.Logger, bhs
 			continue
 		}
 		// Check that the backup MANIFEST exists and can be successfully decoded.
-		bm, err := GetBackupManifest(ctx, bh)
+		bm, err := catalog.GetManifest(ctx, bh)
 		if err != nil {
 			logger.Warningf("Possibly incomplete backup %v on BackupStorage: can't read MANIFEST: %v)", bh.Name(), err)
 			continue
@@ -470,7 +494,8 @@ func findLatestSuccessfulBackupPosition(ctx context.Context, params BackupParams
 	if err != nil {
 		return "", pos, vterrors.Wrap(err, "ListBackups failed")
 	}
-	bh, manifest, err := findLatestSuccessfulBackup(ctx, params.Logger, bhs, excludeBackupName)
+	catalog := loadBackupCatalog(ctx, bs, backupDir, params.Logger)
+	bh, manifest, err := findLatestSuccessfulBackup(ctx, params.Logger, bhs, catalog, excludeBackupName)
 	if err != nil {
 		return "", pos, vterrors.Wrap(err, "FindLatestSuccessfulBackup failed")
 	}
@@ -495,7 +520,7 @@ func findBackupPosition(ctx context.Context, params BackupParams, backupName str
 		if bh.Name() != backupName {
 			continue
 		}
-		manifest, err := GetBackupManifest(ctx, bh)
+		manifest, err := loadBackupCatalog(ctx, bs, backupDir, params.Logger).GetManifest(ctx, bh)
 		if err != nil {
 			return pos, vterrors.Wrapf(err, "GetBackupManifest failed for backup: %v", backupName)
 		}
@@ -507,6 +532,12 @@ func findBackupPosition(ctx context.Context, params BackupParams, backupName str
 // FindBackupToRestore returns a path, a sequence of backup handles, to be restored.
 // The returned handles stand for valid backups with complete manifests.
 func FindBackupToRestore(ctx context.Context, params RestoreParams, bhs []backupstorage.BackupHandle) (restorePath *RestorePath, err error) {
+	return findBackupToRestore(ctx, params, bhs, nil)
+}
+
+// findBackupToRestore is FindBackupToRestore, with the manifests taken from
+// the catalog, which may be nil.
+func findBackupToRestore(ctx context.Context, params RestoreParams, bhs []backupstorage.BackupHandle, catalog *BackupCatalog) (restorePath *RestorePath, err error) {
 	// if a StartTime is provided in params, then find a backup that was taken at or before that time
 	checkBackupTime := !params.StartTime.IsZero()
 	backupDir := GetBackupDir(params.Keyspace, params.Shard)
@@ -522,7 +553,7 @@ func FindBackupToRestore(ctx context.Context, params RestoreParams, bhs []backup
 	// Let's first populate the manifests
 	for i, bh := range bhs {
 		// Check that the backup MANIFEST exists and can be successfully decoded.
-		bm, err := GetBackupManifest(ctx, bh)
+		bm, err := catalog.GetManifest(ctx, bh)
 		if err != nil {
 			params.Logger.Warningf("Possibly incomplete backup %v in directory %v on BackupStorage: can't read MANIFEST: %v)", bh.Name(), backupDir, err)
 			continue
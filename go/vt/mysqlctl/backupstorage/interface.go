@@ -20,8 +20,10 @@ package backupstorage
 
 import (
 	"context"
+	stderrors "errors"
 	"fmt"
 	"io"
+	"time"
 
 	"github.com/spf13/pflag"
 
@@ -130,6 +132,104 @@ type BackupStorage interface {
 	WithParams(Params) BackupStorage
 }
 
+// ChunksDir is the directory, relative to the root of a BackupStorage, under
+// which the chunks of deduplicated backups are stored, in a subdirectory per
+// backup directory. Being outside of the backup directories, chunks are not
+// returned by ListBackups, and are not removed by RemoveBackup.
+const ChunksDir = ".chunks"
+
+// ChunkHandle is implemented by the BackupHandle of the storages that can
+// store content-addressed chunks. The chunks are shared by all the backups
+// of a directory, so that a chunk is stored only once no matter how many
+// backups reference it.
+// Chunk names are guaranteed to only contain lowercase hexadecimal
+// characters. All the methods should be thread safe.
+type ChunkHandle interface {
+	// HasChunk returns whether a chunk is already stored. If it is, its
+	// modification time is refreshed, since the backup is going to reference
+	// it: the chunks modified recently are not garbage collected.
+	// Only works for read-write backups (created by StartBackup).
+	HasChunk(ctx context.Context, name string) (bool, error)
+
+	// AddChunk stores a chunk. Adding a chunk that is already stored
+	// replaces it. A chunk must never be visible partially written.
+	// Only works for read-write backups (created by StartBackup).
+	AddChunk(ctx context.Context, name string, data []byte) error
+
+	// ReadChunk starts reading a chunk.
+	// Only works for read-only backups (created by ListBackups).
+	ReadChunk(ctx context.Context, name string) (io.ReadCloser, error)
+}
+
+// ChunkStorage is implemented by the BackupStorage whose BackupHandle
+// implement ChunkHandle, to garbage collect the chunks that are no longer
+// referenced by any backup.
+type ChunkStorage interface {
+	// ListChunks returns all the chunks of a directory.
+	ListChunks(ctx context.Context, dir string) ([]ChunkInfo, error)
+
+	// RemoveChunk removes a chunk, as returned by ListChunks, from a
+	// directory, unless it was added again or found by HasChunk since it
+	// was listed. It returns whether the chunk was removed. Removing a
+	// chunk that does not exist is not an error.
+	RemoveChunk(ctx context.Context, dir string, chunk ChunkInfo) (bool, error)
+}
+
+// ChunkInfo describes a chunk returned by ChunkStorage.ListChunks.
+type ChunkInfo struct {
+	// Name is the name of the chunk.
+	Name string
+
+	// ModTime is when the chunk was last added, or found by HasChunk.
+	ModTime time.Time
+
+	// Version identifies the version of the chunk, for the storages that
+	// change it along with ModTime, empty otherwise.
+	Version string
+}
+
+// ErrFileNotFound is wrapped by the errors BackupHandle.ReadFile returns
+// for a file that does not exist, for the BackupStorage implementing
+// ChunkStorage, so that a backup without MANIFEST can be told apart from
+// a MANIFEST that can't be read.
+var ErrFileNotFound = stderrors.New("file not found")
+
+// ReopenStorage is implemented by the BackupStorage that can add files to a
+// finished backup, e.g. to record the result of its verification.
+type ReopenStorage interface {
+	// ReopenBackup returns a read-write handle on an existing backup.
+	// AddFile and EndBackup can be called on it, AbortBackup must not
+	// as it would remove the backup. Adding a file that already exists
+	// replaces it.
+	ReopenBackup(ctx context.Context, dir, name string) (BackupHandle, error)
+}
+
+// CatalogDir is the directory, relative to the root of a BackupStorage, under
+// which the catalogs of the backup directories are stored, in a subdirectory
+// per backup directory. Being outside of the backup directories, catalogs are
+// not returned by ListBackups.
+const CatalogDir = ".catalog"
+
+// ErrCatalogConflict is returned by WriteCatalog when the catalog was changed
+// since the version that was read.
+var ErrCatalogConflict = stderrors.New("the backup catalog was changed concurrently")
+
+// CatalogStorage is implemented by the BackupStorage that can store the
+// catalog of a directory: an index of its backups that saves reading their
+// MANIFEST one by one. The catalog is replaced with compare-and-swap
+// semantics, so that concurrent updates are never lost.
+type CatalogStorage interface {
+	// ReadCatalog returns the catalog of a directory, and its version.
+	// It returns no data and an empty version if there is no catalog.
+	ReadCatalog(ctx context.Context, dir string) (data []byte, version string, err error)
+
+	// WriteCatalog replaces the catalog of a directory, if its version is
+	// still the given one, or if there is no catalog and the given version
+	// is empty. It returns ErrCatalogConflict otherwise. A catalog must
+	// never be visible partially written.
+	WriteCatalog(ctx context.Context, dir string, data []byte, version string) error
+}
+
 // BackupStorageMap contains the registered implementations for BackupStorage
 var BackupStorageMap = make(map[string]BackupStorage)
 
@@ -0,0 +1,229 @@
+/*
+Copyright 2026 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package backupstorage
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// UploadCheckpointSuffix is appended to the name of a file being uploaded in
+// parts to name the sidecar holding its UploadCheckpoint. File names only
+// contain alphanumerical characters and hyphens, so a sidecar never collides
+// with a file of the backup. The sidecar is removed once the file is complete.
+const UploadCheckpointSuffix = ".upload-checkpoint"
+
+// ResumableWriter is implemented by the io.WriteCloser returned by AddFile
+// when the file is uploaded in parts that are checkpointed as they are
+// committed. When AddFile is called again for a file whose upload failed,
+// the parts whose content is unchanged are not uploaded again: the upload
+// continues from the first part that differs, or that was not committed.
+type ResumableWriter interface {
+	io.WriteCloser
+
+	// CloseWithError ends a failed upload without completing the file.
+	// The parts committed so far are kept for the next AddFile of the
+	// same file to resume from.
+	CloseWithError(err error) error
+
+	// ResumedBytes returns the number of bytes written so far that were
+	// committed by a previous attempt, and were not uploaded again.
+	ResumedBytes() int64
+}
+
+// UploadPart describes a part of a file committed to the storage.
+type UploadPart struct {
+	// Number is the 1-based index of the part in the file.
+	Number int
+	Size   int64
+	// SHA256 is the hex-encoded SHA-256 of the part content, used to
+	// check the part is unchanged when an upload is resumed.
+	SHA256 string
+	// ETag is the identifier the storage returned for the part, if any.
+	ETag string `json:",omitempty"`
+}
+
+// UploadCheckpoint records the parts of a file committed to the storage, in
+// order, so that a failed upload can be resumed.
+type UploadCheckpoint struct {
+	// UploadID identifies the upload in the storage, if it needs one.
+	UploadID string `json:",omitempty"`
+	// PartSize is the size of every part but the last one.
+	PartSize int64
+	Parts    []UploadPart
+}
+
+// ParseUploadCheckpoint parses a checkpoint written by MarshalUploadCheckpoint.
+func ParseUploadCheckpoint(data []byte) (*UploadCheckpoint, error) {
+	checkpoint := &UploadCheckpoint{}
+	if err := json.Unmarshal(data, checkpoint); err != nil {
+		return nil, fmt.Errorf("cannot parse upload checkpoint: %w", err)
+	}
+	if checkpoint.PartSize <= 0 {
+		return nil, fmt.Errorf("invalid upload checkpoint part size %v", checkpoint.PartSize)
+	}
+	return checkpoint, nil
+}
+
+// MarshalUploadCheckpoint encodes a checkpoint to be stored in a sidecar.
+func MarshalUploadCheckpoint(checkpoint *UploadCheckpoint) ([]byte, error) {
+	return json.Marshal(checkpoint)
+}
+
+// PartUploader is implemented by the storages to commit the parts of a file
+// written by a ResumableWriter.
+type PartUploader interface {
+	// UploadPart durably stores a part, replacing any previous content
+	// of the part, and returns its ETag, if any.
+	UploadPart(ctx context.Context, number int, data []byte) (etag string, err error)
+
+	// SaveCheckpoint durably stores the checkpoint of the upload.
+	SaveCheckpoint(ctx context.Context, checkpoint *UploadCheckpoint) error
+
+	// Complete assembles the parts of the checkpoint into the file, and
+	// removes the checkpoint. It frees the resources of the upload, even
+	// if it fails.
+	Complete(ctx context.Context, checkpoint *UploadCheckpoint) error
+
+	// Release frees the resources of an upload that is not completed,
+	// keeping the parts and the checkpoint it committed.
+	Release() error
+}
+
+// resumableWriter implements ResumableWriter on top of a PartUploader.
+type resumableWriter struct {
+	ctx        context.Context
+	uploader   PartUploader
+	checkpoint *UploadCheckpoint
+
+	buf     []byte
+	parts   int
+	resumed int64
+	err     error
+	closed  bool
+}
+
+// NewResumableWriter returns a ResumableWriter that cuts what is written into
+// parts of checkpoint.PartSize bytes, and commits them with the uploader.
+// The checkpoint is the one of a previous attempt to upload the file, or an
+// empty one to start a new upload. The ResumableWriter is not thread safe.
+func NewResumableWriter(ctx context.Context, uploader PartUploader, checkpoint *UploadCheckpoint) ResumableWriter {
+	return &resumableWriter{
+		ctx:        ctx,
+		uploader:   uploader,
+		checkpoint: checkpoint,
+		buf:        make([]byte, 0, checkpoint.PartSize),
+	}
+}
+
+// Write is part of the io.Writer interface.
+func (w *resumableWriter) Write(p []byte) (int, error) {
+	if w.closed {
+		return 0, io.ErrClosedPipe
+	}
+	if w.err != nil {
+		return 0, w.err
+	}
+	written := 0
+	for len(p) > 0 {
+		n := min(len(p), int(w.checkpoint.PartSize)-len(w.buf))
+		w.buf = append(w.buf, p[:n]...)
+		p = p[n:]
+		written += n
+		if len(w.buf) == int(w.checkpoint.PartSize) {
+			if err := w.flush(); err != nil {
+				w.err = err
+				return written, err
+			}
+		}
+	}
+	return written, nil
+}
+
+// flush commits the buffered part, unless the previous attempt already
+// committed the same content.
+func (w *resumableWriter) flush() error {
+	sum := sha256.Sum256(w.buf)
+	part := UploadPart{
+		Number: w.parts + 1,
+		Size:   int64(len(w.buf)),
+		SHA256: hex.EncodeToString(sum[:]),
+	}
+	if w.parts < len(w.checkpoint.Parts) {
+		if committed := w.checkpoint.Parts[w.parts]; committed.Size == part.Size && committed.SHA256 == part.SHA256 {
+			w.parts++
+			w.resumed += part.Size
+			w.buf = w.buf[:0]
+			return nil
+		}
+		// The content differs from the previous attempt from this part on.
+		w.checkpoint.Parts = w.checkpoint.Parts[:w.parts]
+	}
+
+	etag, err := w.uploader.UploadPart(w.ctx, part.Number, w.buf)
+	if err != nil {
+		return fmt.Errorf("cannot upload part %v: %w", part.Number, err)
+	}
+	part.ETag = etag
+	w.checkpoint.Parts = append(w.checkpoint.Parts, part)
+	w.parts++
+	w.buf = w.buf[:0]
+	if err := w.uploader.SaveCheckpoint(w.ctx, w.checkpoint); err != nil {
+		return fmt.Errorf("cannot save upload checkpoint: %w", err)
+	}
+	return nil
+}
+
+// Close is part of the io.Closer interface. It commits the last part and
+// completes the file.
+func (w *resumableWriter) Close() error {
+	if w.closed {
+		return nil
+	}
+	w.closed = true
+	if w.err != nil {
+		return errors.Join(w.err, w.uploader.Release())
+	}
+	// A file has at least one part, even if empty.
+	if len(w.buf) > 0 || w.parts == 0 {
+		if err := w.flush(); err != nil {
+			return errors.Join(err, w.uploader.Release())
+		}
+	}
+	// The previous attempt may have committed a longer file.
+	w.checkpoint.Parts = w.checkpoint.Parts[:w.parts]
+	return w.uploader.Complete(w.ctx, w.checkpoint)
+}
+
+// CloseWithError is part of the ResumableWriter interface.
+func (w *resumableWriter) CloseWithError(err error) error {
+	if w.closed {
+		return nil
+	}
+	w.closed = true
+	return w.uploader.Release()
+}
+
+// ResumedBytes is part of the ResumableWriter interface.
+func (w *resumableWriter) ResumedBytes() int64 {
+	return w.resumed
+}
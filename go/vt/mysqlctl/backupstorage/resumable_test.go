@@ -0,0 +1,147 @@
+/*
+Copyright 2026 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package backupstorage
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// memoryPartUploader is a PartUploader keeping the parts in memory.
+type memoryPartUploader struct {
+	parts      map[int][]byte
+	checkpoint []byte
+	file       []byte
+	uploaded   []int
+	// failPart is the number of a part whose upload fails.
+	failPart int
+	released bool
+}
+
+func (u *memoryPartUploader) UploadPart(ctx context.Context, number int, data []byte) (string, error) {
+	if number == u.failPart {
+		u.failPart = 0
+		return "", errors.New("injected failure")
+	}
+	u.parts[number] = bytes.Clone(data)
+	u.uploaded = append(u.uploaded, number)
+	return "", nil
+}
+
+func (u *memoryPartUploader) SaveCheckpoint(ctx context.Context, checkpoint *UploadCheckpoint) error {
+	var err error
+	u.checkpoint, err = MarshalUploadCheckpoint(checkpoint)
+	return err
+}
+
+func (u *memoryPartUploader) Complete(ctx context.Context, checkpoint *UploadCheckpoint) error {
+	u.file = nil
+	for _, part := range checkpoint.Parts {
+		u.file = append(u.file, u.parts[part.Number]...)
+	}
+	u.checkpoint = nil
+	return nil
+}
+
+func (u *memoryPartUploader) Release() error {
+	u.released = true
+	return nil
+}
+
+// attempt writes data, and returns the writer once closed.
+func (u *memoryPartUploader) attempt(t *testing.T, data []byte) (ResumableWriter, error) {
+	checkpoint := &UploadCheckpoint{PartSize: 4}
+	if u.checkpoint != nil {
+		var err error
+		checkpoint, err = ParseUploadCheckpoint(u.checkpoint)
+		require.NoError(t, err)
+	}
+	u.uploaded = nil
+	u.released = false
+	w := NewResumableWriter(context.Background(), u, checkpoint)
+	// Write in small pieces, not aligned with the parts.
+	for len(data) > 0 {
+		n := min(len(data), 3)
+		if _, err := w.Write(data[:n]); err != nil {
+			require.NoError(t, w.CloseWithError(err))
+			return w, err
+		}
+		data = data[n:]
+	}
+	return w, w.Close()
+}
+
+func TestResumableWriter(t *testing.T) {
+	u := &memoryPartUploader{parts: make(map[int][]byte), failPart: 3}
+	data := []byte("0123456789abcdefghij")
+
+	// The upload of the third part fails: the first two are checkpointed.
+	_, err := u.attempt(t, data)
+	require.ErrorContains(t, err, "cannot upload part 3: injected failure")
+	assert.True(t, u.released)
+	assert.Equal(t, []int{1, 2}, u.uploaded)
+	assert.Nil(t, u.file)
+	checkpoint, err := ParseUploadCheckpoint(u.checkpoint)
+	require.NoError(t, err)
+	assert.Len(t, checkpoint.Parts, 2)
+
+	// The retry resumes from the third part.
+	w, err := u.attempt(t, data)
+	require.NoError(t, err)
+	assert.Equal(t, []int{3, 4, 5}, u.uploaded)
+	assert.EqualValues(t, 8, w.ResumedBytes())
+	assert.Equal(t, data, u.file)
+	assert.Nil(t, u.checkpoint)
+
+	// Once complete, the file is uploaded again from scratch.
+	_, err = u.attempt(t, data)
+	require.NoError(t, err)
+	assert.Equal(t, []int{1, 2, 3, 4, 5}, u.uploaded)
+}
+
+func TestResumableWriterChangedContent(t *testing.T) {
+	u := &memoryPartUploader{parts: make(map[int][]byte), failPart: 4}
+	_, err := u.attempt(t, []byte("0123456789abcdefghij"))
+	require.Error(t, err)
+
+	// The second part differs: it is uploaded again along with the next ones,
+	// and the parts of the previous attempt past the new end are dropped.
+	data := []byte("0123xxxx89a")
+	w, err := u.attempt(t, data)
+	require.NoError(t, err)
+	assert.Equal(t, []int{2, 3}, u.uploaded)
+	assert.EqualValues(t, 4, w.ResumedBytes())
+	assert.Equal(t, data, u.file)
+}
+
+func TestResumableWriterEmptyFile(t *testing.T) {
+	u := &memoryPartUploader{parts: make(map[int][]byte)}
+	w, err := u.attempt(t, nil)
+	require.NoError(t, err)
+	assert.Equal(t, []int{1}, u.uploaded)
+	assert.Empty(t, u.file)
+
+	// A closed writer cannot be written to.
+	_, err = w.Write([]byte("x"))
+	require.Error(t, err)
+	require.NoError(t, w.Close())
+}
@@ -0,0 +1,113 @@
+/*
+Copyright 2026 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package mysqlctl
+
+import (
+	"context"
+	"time"
+
+	"vitess.io/vitess/go/protoutil"
+	"vitess.io/vitess/go/vt/logutil"
+	"vitess.io/vitess/go/vt/mysqlctl/backupstorage"
+	"vitess.io/vitess/go/vt/proto/vtrpc"
+	"vitess.io/vitess/go/vt/vterrors"
+
+	mysqlctlpb "vitess.io/vitess/go/vt/proto/mysqlctl"
+)
+
+// ArchiveBinlogs archives the closed binary logs that have transactions past the latest backup
+// of the shard, without rotating the binary logs. Each binary log is archived as an incremental
+// backup of its own, with its first and last positions and timestamps in its MANIFEST, so that
+// point in time recoveries restore it like any other incremental backup.
+// It returns the number of archived binary logs.
+func ArchiveBinlogs(ctx context.Context, params BackupParams) (archived int, err error) {
+	var lastBackupTime time.Time
+	for {
+		p := params.Copy()
+		p.IncrementalFromPos = AutoIncrementalFromPos
+		p.BinlogArchive = true
+		p.BackupTime = time.Now()
+		// Backup names have a resolution of one second, and must be unique.
+		if next := lastBackupTime.Truncate(time.Second).Add(time.Second); p.BackupTime.Before(next) {
+			select {
+			case <-ctx.Done():
+				return archived, ctx.Err()
+			case <-time.After(time.Until(next)):
+			}
+			p.BackupTime = time.Now()
+		}
+
+		result, err := backup(ctx, p)
+		if err != nil {
+			return archived, err
+		}
+		if result != BackupUsable {
+			// No closed binary log is left to archive.
+			return archived, nil
+		}
+		archived++
+		lastBackupTime = p.BackupTime
+	}
+}
+
+// LatestRecoveryPoint returns the latest time the shard can be restored to with its backups: the
+// time of the last transaction of its latest backup if it is incremental, or the time of its
+// latest full backup.
+func LatestRecoveryPoint(ctx context.Context, bs backupstorage.BackupStorage, keyspace, shard string, logger logutil.Logger) (time.Time, error) {
+	backupDir := GetBackupDir(keyspace, shard)
+	bhs, err := bs.ListBackups(ctx, backupDir)
+	if err != nil {
+		return time.Time{}, vterrors.Wrap(err, "ListBackups failed")
+	}
+	catalog := loadBackupCatalog(ctx, bs, backupDir, logger)
+	_, manifest, err := findLatestSuccessfulBackup(ctx, logger, bhs, catalog, "")
+	if err != nil {
+		return time.Time{}, err
+	}
+	if !manifest.Incremental {
+		return fullBackupSnapshotTime(manifest)
+	}
+	if manifest.IncrementalDetails == nil {
+		return time.Time{}, vterrors.Errorf(vtrpc.Code_FAILED_PRECONDITION, "incremental backup at position %v has no timestamps", manifest.Position)
+	}
+	lastTimestamp, err := ParseRFC3339(manifest.IncrementalDetails.LastTimestamp)
+	if err != nil {
+		return time.Time{}, vterrors.Wrapf(err, "parsing manifest LastTimestamp %s", manifest.IncrementalDetails.LastTimestamp)
+	}
+	return lastTimestamp, nil
+}
+
+// BinlogsLastTimestamp returns the time of the last transaction of the given binary logs, or the
+// zero time if they have none. The binary logs are read from the last one, until one has a
+// transaction.
+func BinlogsLastTimestamp(ctx context.Context, cnf *Mycnf, mysqld MysqlDaemon, binlogs []string) (time.Time, error) {
+	for i := len(binlogs) - 1; i >= 0; i-- {
+		fe := FileEntry{Base: backupBinlogDir, Name: binlogs[i]}
+		fullPath, err := fe.fullPath(cnf)
+		if err != nil {
+			return time.Time{}, err
+		}
+		resp, err := mysqld.ReadBinlogFilesTimestamps(ctx, &mysqlctlpb.ReadBinlogFilesTimestampsRequest{BinlogFileNames: []string{fullPath}})
+		if err != nil {
+			return time.Time{}, vterrors.Wrapf(err, "reading timestamps from binlog file %v", binlogs[i])
+		}
+		if resp.GetLastTimestamp() != nil {
+			return protoutil.TimeFromProto(resp.GetLastTimestamp()).UTC(), nil
+		}
+	}
+	return time.Time{}, nil
+}
@@ -0,0 +1,85 @@
+/*
+Copyright 2026 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package mysqlctl
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"vitess.io/vitess/go/vt/logutil"
+	"vitess.io/vitess/go/vt/mysqlctl/backupstorage"
+	"vitess.io/vitess/go/vt/mysqlctl/filebackupstorage"
+)
+
+func TestLatestRecoveryPoint(t *testing.T) {
+	ctx := context.Background()
+	oldRoot := filebackupstorage.FileBackupStorageRoot
+	defer func() { filebackupstorage.FileBackupStorageRoot = oldRoot }()
+	filebackupstorage.FileBackupStorageRoot = t.TempDir()
+
+	bs := backupstorage.BackupStorageMap["file"]
+	day := func(d int) time.Time {
+		return time.Date(2025, 1, d, 0, 0, 0, 0, time.UTC)
+	}
+	// addBackup adds a backup, with a MANIFEST unless manifest is nil.
+	addBackup := func(backupTime time.Time, manifest *BackupManifest) {
+		bh, err := bs.StartBackup(ctx, "ks/0", backupTime.Format(BackupTimestampFormat)+".zone1-0000000100")
+		require.NoError(t, err)
+		if manifest != nil {
+			wc, err := bh.AddFile(ctx, backupManifestFileName, backupstorage.FileSizeUnknown)
+			require.NoError(t, err)
+			require.NoError(t, json.NewEncoder(wc).Encode(manifest))
+			require.NoError(t, wc.Close())
+		}
+		require.NoError(t, bh.EndBackup(ctx))
+	}
+
+	_, err := LatestRecoveryPoint(ctx, bs, "ks", "0", logutil.NewMemoryLogger())
+	require.ErrorIs(t, err, ErrNoCompleteBackup)
+
+	// A builtin full backup is true to the time it started.
+	addBackup(day(1), &BackupManifest{
+		BackupMethod: builtinBackupEngineName,
+		BackupTime:   FormatRFC3339(day(1)),
+		FinishedTime: FormatRFC3339(day(1).Add(time.Hour)),
+	})
+	recoveryPoint, err := LatestRecoveryPoint(ctx, bs, "ks", "0", logutil.NewMemoryLogger())
+	require.NoError(t, err)
+	assert.Equal(t, day(1), recoveryPoint)
+
+	// An archived binary log extends it up to its last transaction. Backups
+	// that may still be in progress are ignored.
+	addBackup(day(2), &BackupManifest{
+		BackupMethod: builtinBackupEngineName,
+		BackupTime:   FormatRFC3339(day(2)),
+		FinishedTime: FormatRFC3339(day(2)),
+		Incremental:  true,
+		IncrementalDetails: &IncrementalBackupDetails{
+			FirstTimestamp: FormatRFC3339(day(1).Add(time.Minute)),
+			LastTimestamp:  FormatRFC3339(day(1).Add(20 * time.Hour)),
+		},
+	})
+	addBackup(day(3), nil)
+	recoveryPoint, err = LatestRecoveryPoint(ctx, bs, "ks", "0", logutil.NewMemoryLogger())
+	require.NoError(t, err)
+	assert.Equal(t, day(1).Add(20*time.Hour), recoveryPoint)
+}
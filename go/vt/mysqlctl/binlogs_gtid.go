@@ -262,6 +262,29 @@ func FindPITRPath(restoreToGTIDSet replication.GTIDSet, manifests [](*BackupMani
 	return shortestPath, nil
 }
 
+// fullBackupSnapshotTime returns the time the data of a full backup is true to.
+func fullBackupSnapshotTime(manifest *BackupManifest) (time.Time, error) {
+	startTime, err := ParseRFC3339(manifest.BackupTime)
+	if err != nil {
+		return time.Time{}, vterrors.Wrapf(err, "parsing manifest BackupTime %s", manifest.BackupTime)
+	}
+	finishedTime, err := ParseRFC3339(manifest.FinishedTime)
+	if err != nil {
+		return time.Time{}, vterrors.Wrapf(err, "parsing manifest FinishedTime %s", manifest.FinishedTime)
+	}
+	switch manifest.BackupMethod {
+	case xtrabackupEngineName:
+		// Xtrabackup backups are true to the time they complete (the snapshot is taken at the very end).
+		// Therefore the finish time best represents the backup time.
+		return finishedTime, nil
+	case builtinBackupEngineName:
+		// Builtin takes down the MySQL server. Hence the _start time_ represents the backup time best
+		return startTime, nil
+	default:
+		return startTime, nil
+	}
+}
+
 // FindPITRToTimePath evaluates the shortest path to recover a restoreToGTIDSet. The past is composed of:
 // - a full backup, followed by:
 // - zero or more incremental backups
@@ -283,25 +306,9 @@ func FindPITRToTimePath(restoreToTime time.Time, manifests [](*BackupManifest))
 		if manifest.Incremental {
 			continue
 		}
-		startTime, err := ParseRFC3339(manifest.BackupTime)
-		if err != nil {
-			return nil, vterrors.Wrapf(err, "parsing manifest BackupTime %s", manifest.BackupTime)
-		}
-		finishedTime, err := ParseRFC3339(manifest.FinishedTime)
+		compareWithTime, err := fullBackupSnapshotTime(manifest)
 		if err != nil {
-			return nil, vterrors.Wrapf(err, "parsing manifest FinishedTime %s", manifest.FinishedTime)
-		}
-		var compareWithTime time.Time
-		switch manifest.BackupMethod {
-		case xtrabackupEngineName:
-			// Xtrabackup backups are true to the time they complete (the snapshot is taken at the very end).
-			// Therefore the finish time best represents the backup time.
-			compareWithTime = finishedTime
-		case builtinBackupEngineName:
-			// Builtin takes down the MySQL server. Hence the _start time_ represents the backup time best
-			compareWithTime = startTime
-		default:
-			compareWithTime = startTime
+			return nil, err
 		}
 		if restoreToTime.Before(compareWithTime) {
 			// We want a bfull backup whose time is _before_ restore-to-time, and we will top it with
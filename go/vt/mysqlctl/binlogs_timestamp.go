@@ -0,0 +1,146 @@
+/*
+Copyright 2026 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package mysqlctl
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"vitess.io/vitess/go/mysql"
+	"vitess.io/vitess/go/mysql/replication"
+	"vitess.io/vitess/go/vt/proto/vtrpc"
+	"vitess.io/vitess/go/vt/vterrors"
+)
+
+const (
+	// binlogEventHeaderLength is the length of the header of a binary log
+	// event, in binlog format version 4.
+	binlogEventHeaderLength = 19
+)
+
+// binlogFileMagic is the header of every binary log file.
+var binlogFileMagic = []byte{0xfe, 'b', 'i', 'n'}
+
+// ResolveBinlogFileTimestamp reads a binary log file, and resolves the given time to the
+// GTID position the file reaches once all its transactions committed before that time are
+// applied. Transactions are applied in the order they appear in the binary log, so the scan
+// stops at the first transaction committed at, or after, the given time: reachedTime is then
+// true, and no later transaction, in this file or in any later one, is to be applied.
+//
+// The commit time of a transaction is the timestamp of its last event. Binary log timestamps
+// have a resolution of one second.
+func ResolveBinlogFileTimestamp(binlogFile string, restoreToTime time.Time) (pos replication.Position, reachedTime bool, err error) {
+	f, err := os.Open(binlogFile)
+	if err != nil {
+		return pos, false, err
+	}
+	defer f.Close()
+	r := bufio.NewReader(f)
+
+	magic := make([]byte, len(binlogFileMagic))
+	if _, err := io.ReadFull(r, magic); err != nil {
+		return pos, false, vterrors.Wrapf(err, "cannot read binary log file %v", binlogFile)
+	}
+	if !bytes.Equal(magic, binlogFileMagic) {
+		return pos, false, vterrors.Errorf(vtrpc.Code_INVALID_ARGUMENT, "%v is not a binary log file", binlogFile)
+	}
+
+	var gtidSet replication.GTIDSet = replication.Mysql56GTIDSet{}
+	var format mysql.BinlogFormat
+	// gtid is the GTID of the transaction being read, if any.
+	var gtid replication.GTID
+	for {
+		ev, err := readBinlogFileEvent(r)
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return pos, false, vterrors.Wrapf(err, "cannot read binary log file %v", binlogFile)
+		}
+		if ev.IsFormatDescription() {
+			if format, err = ev.Format(); err != nil {
+				return pos, false, vterrors.Wrapf(err, "cannot parse format description event of %v", binlogFile)
+			}
+			continue
+		}
+		if format.IsZero() {
+			return pos, false, vterrors.Errorf(vtrpc.Code_FAILED_PRECONDITION, "binary log file %v does not start with a format description event", binlogFile)
+		}
+		if ev, _, err = ev.StripChecksum(format); err != nil {
+			return pos, false, vterrors.Wrapf(err, "cannot strip checksum of an event in %v", binlogFile)
+		}
+
+		switch {
+		case ev.IsPreviousGTIDs():
+			previous, err := ev.PreviousGTIDs(format)
+			if err != nil {
+				return pos, false, vterrors.Wrapf(err, "cannot parse previous GTIDs of %v", binlogFile)
+			}
+			gtidSet = gtidSet.Union(previous.GTIDSet)
+		case ev.IsGTID():
+			if gtid, _, _, _, err = ev.GTID(format); err != nil {
+				return pos, false, vterrors.Wrapf(err, "cannot parse GTID event of %v", binlogFile)
+			}
+		case gtid == nil:
+			// Not part of a transaction.
+		case ev.IsQuery():
+			q, err := ev.Query(format)
+			if err != nil {
+				return pos, false, vterrors.Wrapf(err, "cannot parse query event of %v", binlogFile)
+			}
+			if strings.EqualFold(q.SQL, "BEGIN") {
+				continue
+			}
+			// A COMMIT or ROLLBACK of a non transactional change, or a DDL.
+			fallthrough
+		case ev.IsXID(), ev.IsTransactionPayload():
+			if !time.Unix(int64(ev.Timestamp()), 0).Before(restoreToTime) {
+				return replication.Position{GTIDSet: gtidSet}, true, nil
+			}
+			gtidSet = gtidSet.AddGTID(gtid)
+			gtid = nil
+		}
+	}
+	return replication.Position{GTIDSet: gtidSet}, false, nil
+}
+
+// readBinlogFileEvent reads the next event of a binary log file. It returns io.EOF at the
+// end of the file.
+func readBinlogFileEvent(r io.Reader) (mysql.BinlogEvent, error) {
+	header := make([]byte, binlogEventHeaderLength)
+	if _, err := io.ReadFull(r, header); err != nil {
+		return nil, err
+	}
+	length := binary.LittleEndian.Uint32(header[9:13])
+	if length < binlogEventHeaderLength {
+		return nil, vterrors.Errorf(vtrpc.Code_INTERNAL, "invalid binary log event length %v", length)
+	}
+	buf := make([]byte, length)
+	copy(buf, header)
+	if _, err := io.ReadFull(r, buf[binlogEventHeaderLength:]); err != nil {
+		if err == io.EOF {
+			err = io.ErrUnexpectedEOF
+		}
+		return nil, err
+	}
+	return mysql.NewMysql56BinlogEvent(buf), nil
+}
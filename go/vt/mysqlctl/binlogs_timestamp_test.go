@@ -0,0 +1,108 @@
+/*
+Copyright 2026 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package mysqlctl
+
+import (
+	"os"
+	"path"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"vitess.io/vitess/go/mysql"
+	"vitess.io/vitess/go/mysql/replication"
+)
+
+func TestResolveBinlogFileTimestamp(t *testing.T) {
+	sid, err := replication.ParseSID("16b1039f-22b6-11ed-b765-0a43f95f28a3")
+	require.NoError(t, err)
+	pos := func(gtids string) replication.Position {
+		return replication.MustParsePosition(replication.Mysql56FlavorID, "16b1039f-22b6-11ed-b765-0a43f95f28a3:"+gtids)
+	}
+	start := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	f := mysql.NewMySQL56BinlogFormat()
+	s := mysql.NewFakeBinlogStream()
+	s.Timestamp = uint32(start.Unix())
+	events := []mysql.BinlogEvent{
+		mysql.NewFormatDescriptionEvent(f, s),
+		mysql.NewMySQL56PreviousGTIDsEvent(f, s, pos("1-100").GTIDSet.(replication.Mysql56GTIDSet)),
+	}
+	// addTransaction adds a transaction, committed secs after start.
+	addTransaction := func(gno int64, secs int, ddl bool) {
+		s.Timestamp = uint32(start.Unix())
+		events = append(events, mysql.NewMySQL56GTIDEvent(f, s, replication.Mysql56GTID{Server: sid, Sequence: gno}, 0, 0))
+		if ddl {
+			s.Timestamp = uint32(start.Unix()) + uint32(secs)
+			events = append(events, mysql.NewQueryEvent(f, s, mysql.Query{Database: "vt_ks", SQL: "create table t (id int)"}))
+			return
+		}
+		events = append(events, mysql.NewQueryEvent(f, s, mysql.Query{Database: "vt_ks", SQL: "BEGIN"}))
+		s.Timestamp = uint32(start.Unix()) + uint32(secs)
+		events = append(events, mysql.NewXIDEvent(f, s))
+	}
+	addTransaction(101, 10, true)
+	addTransaction(102, 20, false)
+	addTransaction(103, 30, false)
+	// Transactions appear in commit order, not in the order of their
+	// timestamps: this one is only applied along with the previous one.
+	addTransaction(104, 25, false)
+	// The rotation to the next file happens much later.
+	s.Timestamp = uint32(start.Unix()) + 3600
+	events = append(events, mysql.NewRotateEvent(f, s, 4, "binlog.000002"))
+
+	binlogFile := path.Join(t.TempDir(), "binlog.000001")
+	data := []byte{0xfe, 'b', 'i', 'n'}
+	for _, ev := range events {
+		data = append(data, ev.Bytes()...)
+	}
+	require.NoError(t, os.WriteFile(binlogFile, data, 0o644))
+
+	tcases := []struct {
+		name        string
+		secs        int
+		pos         replication.Position
+		reachedTime bool
+	}{
+		{name: "before any transaction", secs: 5, pos: pos("1-100"), reachedTime: true},
+		{name: "commit time is excluded", secs: 20, pos: pos("1-101"), reachedTime: true},
+		{name: "between transactions", secs: 21, pos: pos("1-102"), reachedTime: true},
+		{name: "before a transaction with an earlier timestamp", secs: 26, pos: pos("1-102"), reachedTime: true},
+		{name: "after all transactions", secs: 31, pos: pos("1-104")},
+		{name: "events outside transactions are ignored", secs: 3601, pos: pos("1-104")},
+	}
+	for _, tc := range tcases {
+		t.Run(tc.name, func(t *testing.T) {
+			pos, reachedTime, err := ResolveBinlogFileTimestamp(binlogFile, start.Add(time.Duration(tc.secs)*time.Second))
+			require.NoError(t, err)
+			assert.Equal(t, tc.reachedTime, reachedTime)
+			assert.True(t, tc.pos.Equal(pos), "expected %v, got %v", tc.pos, pos)
+		})
+	}
+
+	// Files that are not binary logs are rejected.
+	require.NoError(t, os.WriteFile(binlogFile, []byte("not a binlog"), 0o644))
+	_, _, err = ResolveBinlogFileTimestamp(binlogFile, start)
+	require.ErrorContains(t, err, "is not a binary log file")
+
+	// So are truncated ones.
+	require.NoError(t, os.WriteFile(binlogFile, data[:len(data)-5], 0o644))
+	_, _, err = ResolveBinlogFileTimestamp(binlogFile, start.Add(time.Hour))
+	require.ErrorContains(t, err, "unexpected EOF")
+}
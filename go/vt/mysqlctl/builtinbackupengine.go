@@ -131,6 +131,20 @@ type builtinBackupManifest struct {
 	// ExternalDecompressor will be used. If neither are set, the restore will
 	// abort.
 	ExternalDecompressor string
+
+	// Encryption is the algorithm the backup files were encrypted with, after
+	// being compressed, if EncryptionKeyID is set.
+	Encryption string `json:",omitempty"`
+
+	// EncryptionKeyID is the id of the key the backup files were encrypted
+	// with, or empty if they were not encrypted. On restore, the key is looked
+	// up by this id in the configured BackupKeyProvider.
+	EncryptionKeyID string `json:",omitempty"`
+
+	// Deduplicated is true if the backup files were split in chunks, stored
+	// once for all the backups of the directory, rather than stored as files
+	// of the backup. The chunks of each file are listed in its FileEntry.
+	Deduplicated bool `json:",omitempty"`
 }
 
 // FileEntry is one file to backup
@@ -146,9 +160,18 @@ type FileEntry struct {
 	Name string
 
 	// Hash is the hash of the final data (transformed and
-	// compressed if specified) stored in the BackupStorage.
+	// compressed if specified) stored in the BackupStorage, or
+	// of the file itself for a deduplicated backup.
 	Hash string
 
+	// Chunks are the names of the chunks the file is made of, in
+	// order, for a deduplicated backup.
+	Chunks []string `json:",omitempty"`
+
+	// Size is the number of bytes of the file stored in the
+	// BackupStorage, or of the file itself for a deduplicated backup.
+	Size int64 `json:",omitempty"`
+
 	// ParentPath is an optional prefix to the Base path. If empty, it is ignored. Useful
 	// for writing files in a temporary directory
 	ParentPath string
@@ -173,6 +196,10 @@ func registerBuiltinBackupEngineFlags(fs *pflag.FlagSet) {
 	fs.UintVar(&builtinBackupFileReadBufferSize, "builtinbackup-file-read-buffer-size", builtinBackupFileReadBufferSize, "read files using an IO buffer of this many bytes. Golang defaults are used when set to 0.")
 	fs.UintVar(&builtinBackupFileWriteBufferSize, "builtinbackup-file-write-buffer-size", builtinBackupFileWriteBufferSize, "write files using an IO buffer of this many bytes. Golang defaults are used when set to 0.")
 	fs.StringVar(&builtinIncrementalRestorePath, "builtinbackup-incremental-restore-path", builtinIncrementalRestorePath, "the directory where incremental restore files, namely binlog files, are extracted to. In k8s environments, this should be set to a directory that is shared between the vttablet and mysqld pods. The path should exist. When empty, the default OS temp dir is assumed.")
+	fs.Var(&builtinBackupReadRateLimit, "builtinbackup-read-rate-limit", "maximum number of bytes per second read by all the files of a builtin backup or restore: MySQL files when backing up, backup files when restoring. Zero means no limit.")
+	fs.Var(&builtinBackupWriteRateLimit, "builtinbackup-write-rate-limit", "maximum number of bytes per second written by all the files of a builtin backup or restore: backup files when backing up, MySQL files when restoring. Zero means no limit.")
+	fs.Var(&builtinBackupPerFileReadRateLimit, "builtinbackup-per-file-read-rate-limit", "maximum number of bytes per second read by each file of a builtin backup or restore. Zero means no limit.")
+	fs.Var(&builtinBackupPerFileWriteRateLimit, "builtinbackup-per-file-write-rate-limit", "maximum number of bytes per second written by each file of a builtin backup or restore. Zero means no limit.")
 }
 
 // fullPath returns the full path of the entry, based on its type
@@ -297,8 +324,11 @@ func (be *BuiltinBackupEngine) executeIncrementalBackup(ctx context.Context, par
 	// Shortly we will compare a binlog's "Previous GTIDs" with the backup's position. For the purpose of comparison, we
 	// ignore the purged GTIDs:
 
-	if err := params.Mysqld.FlushBinaryLogs(ctx); err != nil {
-		return BackupUnusable, vterrors.Wrapf(err, "cannot flush binary logs in incremental backup")
+	if !params.BinlogArchive {
+		// Binary log archiving only copies the binary logs MySQL closed on its own.
+		if err := params.Mysqld.FlushBinaryLogs(ctx); err != nil {
+			return BackupUnusable, vterrors.Wrapf(err, "cannot flush binary logs in incremental backup")
+		}
 	}
 	binaryLogs, err := params.Mysqld.GetBinaryLogs(ctx)
 	if err != nil {
@@ -345,6 +375,15 @@ func (be *BuiltinBackupEngine) executeIncrementalBackup(ctx context.Context, par
 		// Empty backup.
 		return BackupEmpty, nil
 	}
+	if params.BinlogArchive && len(binaryLogsToBackup) > 1 {
+		// Binary logs are archived one at a time. The Previous-GTIDs of the binary log that follows
+		// the archived one is the position of the archive.
+		incrementalBackupToGTID, err = getBinlogPreviousGTIDs(ctx, binaryLogsToBackup[1])
+		if err != nil {
+			return BackupUnusable, vterrors.Wrapf(err, "cannot evaluate binlog archive to pos")
+		}
+		binaryLogsToBackup = binaryLogsToBackup[:1]
+	}
 	incrementalBackupFromPosition, err := replication.ParsePosition(replication.Mysql56FlavorID, incrementalBackupFromGTID)
 	if err != nil {
 		return BackupUnusable, vterrors.Wrapf(err, "cannot parse position %v", incrementalBackupFromGTID)
@@ -614,8 +653,21 @@ func (be *BuiltinBackupEngine) backupFiles(
 	}
 	params.Logger.Infof("found %v files to backup", len(fes))
 
+	enc, err := newBackupEncryption(ctx)
+	if err != nil {
+		return err
+	}
+	if enc != nil {
+		params.Logger.Infof("encrypting backup files with key %q", enc.keyID)
+	}
+
+	dedup, err := newBackupDedup(bh, enc)
+	if err != nil {
+		return err
+	}
+
 	// The error here can be ignored safely. Failed FileEntry's are handled in the next 'if' statement.
-	_ = be.backupFileEntries(ctx, fes, bh, params)
+	_ = be.backupFileEntries(ctx, fes, bh, params, enc, dedup)
 
 	// BackupHandle supports the BackupErrorRecorder interface for tracking errors
 	// across any goroutines that fan out to take the backup. This means that we
@@ -646,16 +698,26 @@ func (be *BuiltinBackupEngine) backupFiles(
 			}
 			bh.ResetErrorForFile(file)
 		}
-		err = be.backupFileEntries(ctx, newFEs, bh, params)
+		err = be.backupFileEntries(ctx, newFEs, bh, params, enc, dedup)
 		if err != nil {
 			return err
 		}
+		// Record the hash and chunks of the files that were backed up again.
+		for i := range newFEs {
+			if newFEs[i].Name != "" {
+				fes[i].Hash = newFEs[i].Hash
+				fes[i].Chunks = newFEs[i].Chunks
+			}
+		}
+	}
+	if dedup != nil {
+		dedup.logSummary(params.Logger)
 	}
 
 	// Backup the MANIFEST file and apply retry logic.
 	var manifestErr error
 	for currentRetry := 0; currentRetry <= maxRetriesPerFile; currentRetry++ {
-		manifestErr = be.backupManifest(ctx, params, bh, backupPosition, purgedPosition, fromPosition, fromBackupName, serverUUID, mysqlVersion, incrDetails, fes, currentRetry)
+		manifestErr = be.backupManifest(ctx, params, bh, backupPosition, purgedPosition, fromPosition, fromBackupName, serverUUID, mysqlVersion, incrDetails, fes, enc, dedup != nil, currentRetry)
 		if manifestErr == nil {
 			break
 		}
@@ -671,7 +733,7 @@ func (be *BuiltinBackupEngine) backupFiles(
 // This function will ignore empty FileEntry, allowing the retry mechanism to send a partially empty slice, to not
 // mess up the index of retriable FileEntry.
 // This function does not leave any background operation behind itself, all calls to bh.AddFile will be finished or canceled.
-func (be *BuiltinBackupEngine) backupFileEntries(ctx context.Context, fes []FileEntry, bh backupstorage.BackupHandle, params BackupParams) error {
+func (be *BuiltinBackupEngine) backupFileEntries(ctx context.Context, fes []FileEntry, bh backupstorage.BackupHandle, params BackupParams, enc *backupEncryption, dedup *backupDedup) error {
 	ctxCancel, cancel := context.WithCancel(ctx)
 	defer func() {
 		// If we reached this defer in all cases we can cancel the context.
@@ -705,7 +767,12 @@ func (be *BuiltinBackupEngine) backupFileEntries(ctx context.Context, fes []File
 
 			// Backup the individual file.
 			var errBackupFile error
-			if errBackupFile = be.backupFile(ctxCancel, params, bh, fe, name); errBackupFile != nil {
+			if dedup != nil {
+				errBackupFile = be.backupFileChunks(ctxCancel, params, dedup, fe)
+			} else {
+				errBackupFile = be.backupFile(ctxCancel, params, bh, fe, name, enc)
+			}
+			if errBackupFile != nil {
 				bh.RecordError(name, vterrors.Wrapf(errBackupFile, "failed to backup file '%s'", name))
 				if fe.RetryCount >= maxRetriesPerFile {
 					// this is the last attempt, and we have an error, we can cancel everything and fail fast.
@@ -834,7 +901,7 @@ func (bp *backupPipe) ReportProgress(ctx context.Context, period time.Duration,
 }
 
 // backupFile backs up an individual file.
-func (be *BuiltinBackupEngine) backupFile(ctx context.Context, params BackupParams, bh backupstorage.BackupHandle, fe *FileEntry, name string) (finalErr error) {
+func (be *BuiltinBackupEngine) backupFile(ctx context.Context, params BackupParams, bh backupstorage.BackupHandle, fe *FileEntry, name string, enc *backupEncryption) (finalErr error) {
 	// We need another context that does not live outside of this function.
 	// Reporting progress, compressing and writing are operations that will be
 	// over by the time we exit this function, they can use this cancelable context.
@@ -867,7 +934,8 @@ func (be *BuiltinBackupEngine) backupFile(ctx context.Context, params BackupPara
 	}
 
 	retryStr := retryToString(fe.RetryCount)
-	br := newBackupReader(fe.Name, fi.Size(), timedSource)
+	limits := newFileIOLimits(cancelableCtx, params.IOThrottler)
+	br := newBackupReader(fe.Name, fi.Size(), limits.reader(timedSource))
 	go br.ReportProgress(cancelableCtx, builtinBackupProgress, params.Logger, false /*restore*/, retryStr)
 
 	// Open the destination file for writing, and a buffer.
@@ -881,7 +949,21 @@ func (be *BuiltinBackupEngine) backupFile(ctx context.Context, params BackupPara
 
 	defer func(name, fileName string) {
 		closeDestAt := time.Now()
-		if rerr := dest.Close(); rerr != nil {
+		var rerr error
+		if rw, ok := dest.(backupstorage.ResumableWriter); ok {
+			if finalErr != nil {
+				// Keep what was uploaded, for the retry of the file to resume from it.
+				rerr = rw.CloseWithError(finalErr)
+			} else {
+				rerr = rw.Close()
+			}
+			if resumed := rw.ResumedBytes(); resumed > 0 {
+				params.Logger.Infof("Resumed upload of %v: %v bytes were already uploaded by a previous attempt", fe.Name, resumed)
+			}
+		} else {
+			rerr = dest.Close()
+		}
+		if rerr != nil {
 			rerr = vterrors.Wrapf(rerr, "failed to close file %v,%v", name, fe.Name)
 			params.Logger.Error(rerr)
 			finalErr = errors.Join(finalErr, rerr)
@@ -892,10 +974,10 @@ func (be *BuiltinBackupEngine) backupFile(ctx context.Context, params BackupPara
 	destStats := params.Stats.Scope(stats.Operation("Destination:Write"))
 	timedDest := ioutil.NewMeteredWriteCloser(dest, destStats.TimedIncrementBytes)
 
-	bw := newBackupWriter(fe.Name, builtinBackupStorageWriteBufferSize, fi.Size(), timedDest)
+	bw := newBackupWriter(fe.Name, builtinBackupStorageWriteBufferSize, fi.Size(), limits.writer(timedDest))
 
 	// We create the following inner function because:
-	// - we must `defer` the compressor's Close() function
+	// - we must `defer` the compressor's and encryptor's Close() functions
 	// - but it must take place before we close the pipe reader&writer
 	createAndCopy := func() (createAndCopyErr error) {
 		var reader io.Reader = br
@@ -912,6 +994,26 @@ func (be *BuiltinBackupEngine) backupFile(ctx context.Context, params BackupPara
 			}
 
 		}()
+		// Create the encryption pipe, if necessary. It is closed after the
+		// compressor, which writes to it.
+		if enc != nil {
+			encryptor := enc.newEncryptor(writer, backupFileID(bh.Name(), fe))
+
+			encryptStats := params.Stats.Scope(stats.Operation("Encryptor:Write"))
+			writer = ioutil.NewMeteredWriter(encryptor, encryptStats.TimedIncrementBytes)
+
+			defer func() {
+				// Close the encryptor to write the last chunk.
+				closeEncryptorAt := time.Now()
+				if cerr := encryptor.Close(); cerr != nil {
+					cerr = vterrors.Wrapf(cerr, "failed to close encryptor %v", fe.Name)
+					params.Logger.Error(cerr)
+					createAndCopyErr = errors.Join(createAndCopyErr, cerr)
+				}
+				params.Stats.Scope(stats.Operation("Encryptor:Close")).TimedIncrement(time.Since(closeEncryptorAt))
+			}()
+		}
+
 		// Create the gzip compression pipe, if necessary.
 		if backupStorageCompress {
 			var compressor io.WriteCloser
@@ -958,11 +1060,21 @@ func (be *BuiltinBackupEngine) backupFile(ctx context.Context, params BackupPara
 		return errors.Join(finalErr, err)
 	}
 
-	// Save the hash.
+	// Save the hash and the size.
 	fe.Hash = bw.HashString()
+	fe.Size = atomic.LoadInt64(&bw.nn)
 	return nil
 }
 
+// fileEntriesSize returns the total size of the files of a backup.
+func fileEntriesSize(fes []FileEntry) int64 {
+	var size int64
+	for _, fe := range fes {
+		size += fe.Size
+	}
+	return size
+}
+
 func (be *BuiltinBackupEngine) backupManifest(
 	ctx context.Context,
 	params BackupParams,
@@ -975,6 +1087,8 @@ func (be *BuiltinBackupEngine) backupManifest(
 	mysqlVersion string,
 	incrDetails *IncrementalBackupDetails,
 	fes []FileEntry,
+	enc *backupEncryption,
+	deduplicated bool,
 	currentAttempt int,
 ) (finalErr error) {
 	retryStr := retryToString(currentAttempt)
@@ -1021,6 +1135,7 @@ func (be *BuiltinBackupEngine) backupManifest(
 				MySQLVersion:       mysqlVersion,
 				UpgradeSafe:        params.UpgradeSafe,
 				IncrementalDetails: incrDetails,
+				Size:               fileEntriesSize(fes),
 			},
 
 			// Builtin-specific fields
@@ -1028,6 +1143,11 @@ func (be *BuiltinBackupEngine) backupManifest(
 			SkipCompress:         !backupStorageCompress,
 			CompressionEngine:    CompressionEngineName,
 			ExternalDecompressor: ManifestExternalDecompressorCmd,
+			Deduplicated:         deduplicated,
+		}
+		if enc != nil {
+			bm.Encryption = AES256GCMEncryption
+			bm.EncryptionKeyID = enc.keyID
 		}
 		data, err := json.MarshalIndent(bm, "", "  ")
 		if err != nil {
@@ -1069,41 +1189,65 @@ func (be *BuiltinBackupEngine) executeRestoreFullBackup(ctx context.Context, par
 // executeRestoreIncrementalBackup executes a restore of an incremental backup, and expect to run on top of a full backup's restore.
 // It restores any (zero or more) binary log files and applies them onto the underlying database one at a time, but only applies those transactions
 // that fall within params.RestoreToPos.GTIDSet. The rest (typically a suffix of the last binary log) are discarded.
+// With params.RestoreToTimestamp, each binary log file is first scanned to resolve the timestamp to the position of the last transaction
+// committed before it, and the restore stops right after that transaction.
+// It returns the position the restore stopped at: the backup position, unless the restore stopped at params.RestoreToTimestamp.
 // The underlying mysql database is expected to be up and running.
-func (be *BuiltinBackupEngine) executeRestoreIncrementalBackup(ctx context.Context, params RestoreParams, bh backupstorage.BackupHandle, bm builtinBackupManifest) error {
+func (be *BuiltinBackupEngine) executeRestoreIncrementalBackup(ctx context.Context, params RestoreParams, bh backupstorage.BackupHandle, bm builtinBackupManifest) (replication.Position, error) {
 	params.Logger.Infof("Restoring incremental backup to position: %v", bm.Position)
 	createdDir, err := be.restoreFiles(ctx, params, bh, bm)
 	defer os.RemoveAll(createdDir)
+	if err != nil {
+		// don't delete the file here because that is how we detect an interrupted restore
+		return replication.Position{}, vterrors.Wrap(err, "failed to restore files")
+	}
 	mysqld, ok := params.Mysqld.(*Mysqld)
 	if !ok {
-		return vterrors.Errorf(vtrpc.Code_UNIMPLEMENTED, "expected: Mysqld")
+		return replication.Position{}, vterrors.Errorf(vtrpc.Code_UNIMPLEMENTED, "expected: Mysqld")
 	}
 	for _, fe := range bm.FileEntries {
 		fe.ParentPath = createdDir
 		binlogFile, err := fe.fullPath(params.Cnf)
 		if err != nil {
-			return vterrors.Wrap(err, "failed to restore file")
+			return replication.Position{}, vterrors.Wrap(err, "failed to restore file")
 		}
+		defer os.Remove(binlogFile)
 		req := &mysqlctlpb.ApplyBinlogFileRequest{
-			BinlogFileName:        binlogFile,
-			BinlogRestoreDatetime: protoutil.TimeToProto(params.RestoreToTimestamp),
+			BinlogFileName: binlogFile,
 		}
-		if params.RestoreToPos.GTIDSet != nil {
+		reachedTime := false
+		switch {
+		case params.RestoreToPos.GTIDSet != nil:
 			req.BinlogRestorePosition = params.RestoreToPos.GTIDSet.String()
+		case !params.RestoreToTimestamp.IsZero():
+			var pos replication.Position
+			pos, reachedTime, err = ResolveBinlogFileTimestamp(binlogFile, params.RestoreToTimestamp)
+			if err != nil {
+				return replication.Position{}, vterrors.Wrapf(err, "failed to resolve timestamp %v in binlog file %v", FormatRFC3339(params.RestoreToTimestamp), binlogFile)
+			}
+			if reachedTime {
+				params.Logger.Infof("Restore: timestamp %v resolves to position %v", FormatRFC3339(params.RestoreToTimestamp), pos)
+				bm.Position = pos
+			}
+			req.BinlogRestorePosition = pos.GTIDSet.String()
 		}
-		if err := mysqld.ApplyBinlogFile(ctx, req); err != nil {
-			return vterrors.Wrapf(err, "failed to apply binlog file %v", binlogFile)
+		if req.BinlogRestorePosition == "" && reachedTime {
+			// Nothing to apply, while an empty position would apply everything.
+			params.Logger.Infof("Skipped binlog file: %v", binlogFile)
+		} else {
+			if err := mysqld.ApplyBinlogFile(ctx, req); err != nil {
+				return replication.Position{}, vterrors.Wrapf(err, "failed to apply binlog file %v", binlogFile)
+			}
+			params.Logger.Infof("Applied binlog file: %v", binlogFile)
+		}
+		if reachedTime {
+			// No transaction past this point is to be applied.
+			return bm.Position, nil
 		}
-		defer os.Remove(binlogFile)
-		params.Logger.Infof("Applied binlog file: %v", binlogFile)
-	}
-	if err != nil {
-		// don't delete the file here because that is how we detect an interrupted restore
-		return vterrors.Wrap(err, "failed to restore files")
 	}
 	params.Logger.Infof("Restored incremental backup files to: %v", createdDir)
 
-	return nil
+	return bm.Position, nil
 }
 
 // ExecuteRestore restores from a backup. If the restore is successful
@@ -1121,7 +1265,7 @@ func (be *BuiltinBackupEngine) ExecuteRestore(ctx context.Context, params Restor
 	}
 
 	if bm.Incremental {
-		err = be.executeRestoreIncrementalBackup(ctx, params, bh, bm)
+		bm.Position, err = be.executeRestoreIncrementalBackup(ctx, params, bh, bm)
 	} else {
 		err = be.executeRestoreFullBackup(ctx, params, bh, bm)
 	}
@@ -1165,6 +1309,15 @@ func (be *BuiltinBackupEngine) restoreFiles(ctx context.Context, params RestoreP
 		}()
 	}
 
+	enc, err := newRestoreEncryption(ctx, bm)
+	if err != nil {
+		return "", err
+	}
+
+	if _, ok := bh.(backupstorage.ChunkHandle); bm.Deduplicated && !ok {
+		return "", vterrors.Errorf(vtrpc.Code_FAILED_PRECONDITION, "backup is deduplicated, but the %q backup storage does not support it", backupstorage.BackupStorageImplementation)
+	}
+
 	if bm.Incremental {
 		createdDir, err = os.MkdirTemp(builtinIncrementalRestorePath, "restore-incremental-*")
 		if err != nil {
@@ -1172,7 +1325,7 @@ func (be *BuiltinBackupEngine) restoreFiles(ctx context.Context, params RestoreP
 		}
 	}
 	fes := bm.FileEntries
-	_ = be.restoreFileEntries(ctx, fes, bh, bm, params, createdDir)
+	_ = be.restoreFileEntries(ctx, fes, bh, bm, enc, params, createdDir)
 	if files := bh.GetFailedFiles(); len(files) > 0 {
 		newFEs := make([]FileEntry, len(fes))
 		for _, file := range files {
@@ -1186,11 +1339,12 @@ func (be *BuiltinBackupEngine) restoreFiles(ctx context.Context, params RestoreP
 				Name:       oldFes.Name,
 				ParentPath: oldFes.ParentPath,
 				Hash:       oldFes.Hash,
+				Chunks:     oldFes.Chunks,
 				RetryCount: 1,
 			}
 			bh.ResetErrorForFile(file)
 		}
-		err = be.restoreFileEntries(ctx, newFEs, bh, bm, params, createdDir)
+		err = be.restoreFileEntries(ctx, newFEs, bh, bm, enc, params, createdDir)
 		if err != nil {
 			return "", err
 		}
@@ -1198,7 +1352,7 @@ func (be *BuiltinBackupEngine) restoreFiles(ctx context.Context, params RestoreP
 	return createdDir, nil
 }
 
-func (be *BuiltinBackupEngine) restoreFileEntries(ctx context.Context, fes []FileEntry, bh backupstorage.BackupHandle, bm builtinBackupManifest, params RestoreParams, createdDir string) error {
+func (be *BuiltinBackupEngine) restoreFileEntries(ctx context.Context, fes []FileEntry, bh backupstorage.BackupHandle, bm builtinBackupManifest, enc *backupEncryption, params RestoreParams, createdDir string) error {
 	g, ctx := errgroup.WithContext(ctx)
 	g.SetLimit(params.Concurrency)
 
@@ -1225,7 +1379,13 @@ func (be *BuiltinBackupEngine) restoreFileEntries(ctx context.Context, fes []Fil
 
 			// And restore the file.
 			params.Logger.Infof("Copying file %v: %v %s", name, fe.Name, retryToString(fe.RetryCount))
-			if errRestore := be.restoreFile(ctx, params, bh, fe, bm, name); errRestore != nil {
+			var errRestore error
+			if bm.Deduplicated {
+				errRestore = be.restoreFileChunks(ctx, params, bh.(backupstorage.ChunkHandle), fe, bm, enc)
+			} else {
+				errRestore = be.restoreFile(ctx, params, bh, fe, bm, enc, name)
+			}
+			if errRestore != nil {
 				bh.RecordError(name, vterrors.Wrapf(errRestore, "failed to restore file %v to %v", name, fe.Name))
 				if fe.RetryCount >= maxRetriesPerFile {
 					// this is the last attempt, and we have an error, we can return an error, which will let errgroup
@@ -1241,7 +1401,7 @@ func (be *BuiltinBackupEngine) restoreFileEntries(ctx context.Context, fes []Fil
 }
 
 // restoreFile restores an individual file.
-func (be *BuiltinBackupEngine) restoreFile(ctx context.Context, params RestoreParams, bh backupstorage.BackupHandle, fe *FileEntry, bm builtinBackupManifest, name string) (finalErr error) {
+func (be *BuiltinBackupEngine) restoreFile(ctx context.Context, params RestoreParams, bh backupstorage.BackupHandle, fe *FileEntry, bm builtinBackupManifest, enc *backupEncryption, name string) (finalErr error) {
 	ctx, cancel := context.WithCancel(ctx)
 	defer cancel()
 
@@ -1264,7 +1424,8 @@ func (be *BuiltinBackupEngine) restoreFile(ctx context.Context, params RestorePa
 
 	// Create the backup/source reader and start reporting progress
 	retryStr := retryToString(fe.RetryCount)
-	br := newBackupReader(fe.Name, 0, timedSource)
+	limits := newFileIOLimits(ctx, params.IOThrottler)
+	br := newBackupReader(fe.Name, 0, limits.reader(timedSource))
 	go br.ReportProgress(ctx, builtinBackupProgress, params.Logger, true, retryStr)
 	defer func() {
 		if err := br.Close(finalErr == nil); err != nil {
@@ -1292,7 +1453,13 @@ func (be *BuiltinBackupEngine) restoreFile(ctx context.Context, params RestorePa
 	writeStats := params.Stats.Scope(stats.Operation("Destination:Write"))
 	timedDest := ioutil.NewMeteredWriter(dest, writeStats.TimedIncrementBytes)
 
-	bufferedDest := bufio.NewWriterSize(timedDest, int(builtinBackupFileWriteBufferSize))
+	bufferedDest := bufio.NewWriterSize(limits.writer(timedDest), int(builtinBackupFileWriteBufferSize))
+
+	// Create the decrypter if needed.
+	if enc != nil {
+		decryptStats := params.Stats.Scope(stats.Operation("Decryptor:Read"))
+		reader = ioutil.NewMeteredReader(enc.newDecryptor(reader, backupFileID(bh.Name(), fe)), decryptStats.TimedIncrementBytes)
+	}
 
 	// Create the uncompresser if needed.
 	if !bm.SkipCompress {
@@ -0,0 +1,338 @@
+/*
+Copyright 2026 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package mysqlctl
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"math/rand/v2"
+	"slices"
+	"strings"
+	"time"
+
+	"golang.org/x/sync/errgroup"
+
+	"vitess.io/vitess/go/vt/logutil"
+	"vitess.io/vitess/go/vt/mysqlctl/backupstorage"
+	"vitess.io/vitess/go/vt/proto/vtrpc"
+	"vitess.io/vitess/go/vt/vterrors"
+)
+
+const (
+	// backupCatalogUpdateAttempts is how many times an update of a catalog
+	// is attempted while other updates conflict with it.
+	backupCatalogUpdateAttempts = 10
+
+	// backupCatalogBuildConcurrency is how many MANIFEST are read in
+	// parallel to build a catalog.
+	backupCatalogBuildConcurrency = 16
+)
+
+// BackupCatalog is the index of the complete backups of a directory. It is
+// stored in the BackupStorage, next to the backups, when the BackupStorage
+// implements backupstorage.CatalogStorage. It records a summary of the
+// MANIFEST of each backup, so that the backups can be listed and selected
+// without reading their MANIFEST one by one.
+//
+// The catalog is updated when a backup ends, is verified or is removed, and
+// can be rebuilt from the backups with RebuildBackupCatalog. It is only an
+// index: the backups it lists that are no longer in the BackupStorage are
+// ignored, and the MANIFEST of the backups it misses is read from them.
+type BackupCatalog struct {
+	// Backups are the complete backups of the directory, sorted by name.
+	Backups []*BackupCatalogEntry
+}
+
+// BackupCatalogEntry is the summary of a backup in a BackupCatalog.
+type BackupCatalogEntry struct {
+	// BackupManifest holds the common fields of the MANIFEST of the
+	// backup: its engine, size, positions, incremental parent, etc.
+	BackupManifest
+
+	// Verification is the result of the last verification of the backup,
+	// if it was verified.
+	Verification *BackupCatalogVerification `json:",omitempty"`
+}
+
+// BackupCatalogVerification summarizes the result of the verification of a
+// backup.
+type BackupCatalogVerification struct {
+	// VerifiedAt is the time of the verification, in RFC3339 format.
+	VerifiedAt string
+	// Success is whether the backup passed the verification.
+	Success bool
+}
+
+func newBackupCatalogVerification(result *VerificationResult) *BackupCatalogVerification {
+	return &BackupCatalogVerification{
+		VerifiedAt: result.VerifiedAt,
+		Success:    result.Success(),
+	}
+}
+
+// Entry returns the entry of a backup, or nil if the catalog has none. It
+// can be called on a nil catalog.
+func (c *BackupCatalog) Entry(name string) *BackupCatalogEntry {
+	if c == nil {
+		return nil
+	}
+	i, ok := slices.BinarySearchFunc(c.Backups, name, compareBackupCatalogEntry)
+	if !ok {
+		return nil
+	}
+	return c.Backups[i]
+}
+
+// GetManifest returns the manifest of a backup from its entry in the catalog,
+// or reads it from the backup if the catalog has none. It can be called on a
+// nil catalog.
+func (c *BackupCatalog) GetManifest(ctx context.Context, bh backupstorage.BackupHandle) (*BackupManifest, error) {
+	if entry := c.Entry(bh.Name()); entry != nil {
+		return &entry.BackupManifest, nil
+	}
+	return GetBackupManifest(ctx, bh)
+}
+
+// GetEntry returns the entry of a backup from the catalog, or reads it from
+// the backup if the catalog has none. It can be called on a nil catalog.
+func (c *BackupCatalog) GetEntry(ctx context.Context, bh backupstorage.BackupHandle) (*BackupCatalogEntry, error) {
+	if entry := c.Entry(bh.Name()); entry != nil {
+		return entry, nil
+	}
+	return readBackupCatalogEntry(ctx, bh)
+}
+
+// put adds an entry to the catalog, replacing the one of the same backup.
+func (c *BackupCatalog) put(entry *BackupCatalogEntry) {
+	i, ok := slices.BinarySearchFunc(c.Backups, entry.BackupName, compareBackupCatalogEntry)
+	if ok {
+		c.Backups[i] = entry
+		return
+	}
+	c.Backups = slices.Insert(c.Backups, i, entry)
+}
+
+// remove removes the entries of backups from the catalog.
+func (c *BackupCatalog) remove(names ...string) {
+	c.Backups = slices.DeleteFunc(c.Backups, func(entry *BackupCatalogEntry) bool {
+		return slices.Contains(names, entry.BackupName)
+	})
+}
+
+func compareBackupCatalogEntry(entry *BackupCatalogEntry, name string) int {
+	return strings.Compare(entry.BackupName, name)
+}
+
+// ReadBackupCatalog returns the catalog of a directory, or nil if the
+// BackupStorage does not store catalogs, or if the directory has none yet.
+func ReadBackupCatalog(ctx context.Context, bs backupstorage.BackupStorage, dir string) (*BackupCatalog, error) {
+	cs, ok := bs.(backupstorage.CatalogStorage)
+	if !ok {
+		return nil, nil
+	}
+	data, _, err := cs.ReadCatalog(ctx, dir)
+	if err != nil || data == nil {
+		return nil, err
+	}
+	catalog := &BackupCatalog{}
+	if err := json.Unmarshal(data, catalog); err != nil {
+		return nil, vterrors.Wrapf(err, "can't decode the backup catalog of %v", dir)
+	}
+	return catalog, nil
+}
+
+// loadBackupCatalog returns the catalog of a directory, or nil if there is
+// none or if it can't be read. The callers then read the MANIFEST of the
+// backups.
+func loadBackupCatalog(ctx context.Context, bs backupstorage.BackupStorage, dir string, logger logutil.Logger) *BackupCatalog {
+	catalog, err := ReadBackupCatalog(ctx, bs, dir)
+	if err != nil {
+		logger.Warningf("Ignoring the backup catalog of %v: %v", dir, err)
+		return nil
+	}
+	return catalog
+}
+
+// RebuildBackupCatalog rebuilds the catalog of a directory from its backups,
+// e.g. because it is missing or out of date, and returns it.
+func RebuildBackupCatalog(ctx context.Context, bs backupstorage.BackupStorage, dir string, logger logutil.Logger) (*BackupCatalog, error) {
+	if _, ok := bs.(backupstorage.CatalogStorage); !ok {
+		return nil, vterrors.Errorf(vtrpc.Code_UNIMPLEMENTED, "backup storage %v does not store backup catalogs", backupstorage.BackupStorageImplementation)
+	}
+	return updateBackupCatalog(ctx, bs, dir, func(*BackupCatalog) (*BackupCatalog, error) {
+		return buildBackupCatalog(ctx, bs, dir, logger)
+	})
+}
+
+// buildBackupCatalog builds the catalog of a directory by reading the
+// MANIFEST of all its backups.
+func buildBackupCatalog(ctx context.Context, bs backupstorage.BackupStorage, dir string, logger logutil.Logger) (*BackupCatalog, error) {
+	bhs, err := bs.ListBackups(ctx, dir)
+	if err != nil {
+		return nil, vterrors.Wrap(err, "ListBackups failed")
+	}
+
+	entries := make([]*BackupCatalogEntry, len(bhs))
+	g, gctx := errgroup.WithContext(ctx)
+	g.SetLimit(backupCatalogBuildConcurrency)
+	for i, bh := range bhs {
+		g.Go(func() error {
+			entry, err := readBackupCatalogEntry(gctx, bh)
+			if err != nil {
+				logger.Warningf("Not cataloging possibly incomplete backup %v/%v: %v", dir, bh.Name(), err)
+				return gctx.Err()
+			}
+			entries[i] = entry
+			return nil
+		})
+	}
+	if err := g.Wait(); err != nil {
+		return nil, err
+	}
+
+	catalog := &BackupCatalog{}
+	for _, entry := range entries {
+		if entry != nil {
+			catalog.put(entry)
+		}
+	}
+	return catalog, nil
+}
+
+// readBackupCatalogEntry reads the catalog entry of a backup from its
+// MANIFEST, and from the result of its last verification if any.
+func readBackupCatalogEntry(ctx context.Context, bh backupstorage.BackupHandle) (*BackupCatalogEntry, error) {
+	manifest, err := GetBackupManifest(ctx, bh)
+	if err != nil {
+		return nil, err
+	}
+	entry := &BackupCatalogEntry{BackupManifest: *manifest}
+	entry.BackupName = bh.Name()
+	if result, err := readVerificationResult(ctx, bh); err == nil {
+		entry.Verification = newBackupCatalogVerification(result)
+	}
+	return entry, nil
+}
+
+// updateBackupCatalog replaces the catalog of a directory with the one update
+// returns, given the current catalog, or nil if there is none or if it can't
+// be decoded. The catalog is left as is if update returns nil. If the catalog
+// is changed concurrently, the update is retried on the new catalog.
+func updateBackupCatalog(ctx context.Context, bs backupstorage.BackupStorage, dir string, update func(catalog *BackupCatalog) (*BackupCatalog, error)) (*BackupCatalog, error) {
+	cs, ok := bs.(backupstorage.CatalogStorage)
+	if !ok {
+		return nil, nil
+	}
+	for attempt := 1; ; attempt++ {
+		data, version, err := cs.ReadCatalog(ctx, dir)
+		if err != nil {
+			return nil, err
+		}
+		var catalog *BackupCatalog
+		if data != nil {
+			catalog = &BackupCatalog{}
+			if err := json.Unmarshal(data, catalog); err != nil {
+				// The catalog is corrupted, it is replaced.
+				catalog = nil
+			}
+		}
+		catalog, err = update(catalog)
+		if err != nil || catalog == nil {
+			return nil, err
+		}
+		if data, err = json.MarshalIndent(catalog, "", "  "); err != nil {
+			return nil, err
+		}
+		err = cs.WriteCatalog(ctx, dir, data, version)
+		if err == nil {
+			return catalog, nil
+		}
+		if !errors.Is(err, backupstorage.ErrCatalogConflict) || attempt == backupCatalogUpdateAttempts {
+			return nil, err
+		}
+		// Another update won the race. Wait a little, not to race again.
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(rand.N(100 * time.Millisecond)):
+		}
+	}
+}
+
+// changeBackupCatalog applies a change to the catalog of a directory. If
+// there is no catalog, it is built from the backups first when build is set,
+// and the change is skipped otherwise. A failure to update the catalog is
+// only logged: the backups it misses are still found, and it can be repaired
+// with RebuildBackupCatalog.
+func changeBackupCatalog(ctx context.Context, bs backupstorage.BackupStorage, dir string, logger logutil.Logger, build bool, change func(catalog *BackupCatalog)) {
+	_, err := updateBackupCatalog(ctx, bs, dir, func(catalog *BackupCatalog) (*BackupCatalog, error) {
+		if catalog == nil {
+			if !build {
+				return nil, nil
+			}
+			var err error
+			if catalog, err = buildBackupCatalog(ctx, bs, dir, logger); err != nil {
+				return nil, err
+			}
+		}
+		change(catalog)
+		return catalog, nil
+	})
+	if err != nil {
+		logger.Warningf("Failed to update the backup catalog of %v, it can be repaired with RebuildBackupCatalog: %v", dir, err)
+	}
+}
+
+// catalogBackup adds a backup that just ended to the catalog of its directory.
+func catalogBackup(ctx context.Context, bs backupstorage.BackupStorage, dir, name string, logger logutil.Logger) {
+	if _, ok := bs.(backupstorage.CatalogStorage); !ok {
+		return
+	}
+	bhs, err := bs.ListBackups(ctx, dir)
+	if err != nil {
+		logger.Warningf("Failed to add backup %v/%v to the backup catalog: %v", dir, name, err)
+		return
+	}
+	i := slices.IndexFunc(bhs, func(bh backupstorage.BackupHandle) bool {
+		return bh.Name() == name
+	})
+	if i < 0 {
+		logger.Warningf("Failed to add backup %v/%v to the backup catalog: it is not listed", dir, name)
+		return
+	}
+	entry, err := readBackupCatalogEntry(ctx, bhs[i])
+	if err != nil {
+		logger.Warningf("Failed to add backup %v/%v to the backup catalog: %v", dir, name, err)
+		return
+	}
+	changeBackupCatalog(ctx, bs, dir, logger, true, func(catalog *BackupCatalog) {
+		catalog.put(entry)
+	})
+}
+
+// RemoveBackup removes a backup from the BackupStorage, and from the catalog
+// of its directory.
+func RemoveBackup(ctx context.Context, bs backupstorage.BackupStorage, dir, name string, logger logutil.Logger) error {
+	if err := bs.RemoveBackup(ctx, dir, name); err != nil {
+		return err
+	}
+	changeBackupCatalog(ctx, bs, dir, logger, false, func(catalog *BackupCatalog) {
+		catalog.remove(name)
+	})
+	return nil
+}
@@ -0,0 +1,138 @@
+/*
+Copyright 2026 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package mysqlctl
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"vitess.io/vitess/go/vt/logutil"
+	"vitess.io/vitess/go/vt/mysqlctl/backupstorage"
+	"vitess.io/vitess/go/vt/mysqlctl/filebackupstorage"
+)
+
+func TestBackupCatalog(t *testing.T) {
+	ctx := context.Background()
+	oldRoot := filebackupstorage.FileBackupStorageRoot
+	defer func() { filebackupstorage.FileBackupStorageRoot = oldRoot }()
+	filebackupstorage.FileBackupStorageRoot = t.TempDir()
+
+	bs := backupstorage.BackupStorageMap["file"]
+	logger := logutil.NewMemoryLogger()
+	dir := "ks/0"
+	// addBackup adds a backup, with a MANIFEST unless manifest is nil.
+	addBackup := func(name string, manifest *BackupManifest) {
+		bh, err := bs.StartBackup(ctx, dir, name)
+		require.NoError(t, err)
+		if manifest != nil {
+			manifest.BackupName = name
+			wc, err := bh.AddFile(ctx, backupManifestFileName, backupstorage.FileSizeUnknown)
+			require.NoError(t, err)
+			require.NoError(t, json.NewEncoder(wc).Encode(manifest))
+			require.NoError(t, wc.Close())
+		}
+		require.NoError(t, bh.EndBackup(ctx))
+	}
+	catalogNames := func() []string {
+		catalog, err := ReadBackupCatalog(ctx, bs, dir)
+		require.NoError(t, err)
+		require.NotNil(t, catalog)
+		var names []string
+		for _, entry := range catalog.Backups {
+			names = append(names, entry.BackupName)
+		}
+		return names
+	}
+
+	addBackup("backup1", &BackupManifest{BackupMethod: "builtin", Size: 100})
+	addBackup("backup2", nil)
+	catalog, err := ReadBackupCatalog(ctx, bs, dir)
+	require.NoError(t, err)
+	assert.Nil(t, catalog)
+
+	// The first cataloged backup builds the catalog, without the backups
+	// that have no MANIFEST.
+	addBackup("backup3", &BackupManifest{BackupMethod: "xtrabackup", Incremental: true})
+	catalogBackup(ctx, bs, dir, "backup3", logger)
+	assert.Equal(t, []string{"backup1", "backup3"}, catalogNames())
+	catalog, err = ReadBackupCatalog(ctx, bs, dir)
+	require.NoError(t, err)
+	assert.EqualValues(t, 100, catalog.Entry("backup1").Size)
+	assert.True(t, catalog.Entry("backup3").Incremental)
+	assert.Nil(t, catalog.Entry("backup2"))
+
+	// The verification of a backup is recorded in its entry.
+	result := &VerificationResult{BackupName: "backup1", VerifiedAt: "2025-01-01T00:00:00Z", FailedTables: []string{"t1"}}
+	require.NoError(t, writeVerificationResult(ctx, bs, dir, result))
+	changeBackupCatalog(ctx, bs, dir, logger, false, func(catalog *BackupCatalog) {
+		catalog.Entry("backup1").Verification = newBackupCatalogVerification(result)
+	})
+	catalog, err = ReadBackupCatalog(ctx, bs, dir)
+	require.NoError(t, err)
+	assert.Equal(t, &BackupCatalogVerification{VerifiedAt: "2025-01-01T00:00:00Z"}, catalog.Entry("backup1").Verification)
+
+	// The manifests of the backups missing from the catalog are read from
+	// the backups.
+	addBackup("backup4", &BackupManifest{BackupMethod: "builtin"})
+	bhs, err := bs.ListBackups(ctx, dir)
+	require.NoError(t, err)
+	require.Len(t, bhs, 4)
+	manifest, err := catalog.GetManifest(ctx, bhs[3])
+	require.NoError(t, err)
+	assert.Equal(t, "backup4", manifest.BackupName)
+	_, err = catalog.GetManifest(ctx, bhs[1])
+	assert.Error(t, err)
+
+	// Removed backups are removed from the catalog.
+	require.NoError(t, RemoveBackup(ctx, bs, dir, "backup3", logger))
+	assert.Equal(t, []string{"backup1"}, catalogNames())
+
+	// Concurrent updates of the catalog are all applied.
+	var wg sync.WaitGroup
+	for i := range 10 {
+		name := fmt.Sprintf("backup%d", 10+i)
+		addBackup(name, &BackupManifest{BackupMethod: "builtin"})
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			catalogBackup(ctx, bs, dir, name, logger)
+		}()
+	}
+	wg.Wait()
+	assert.Len(t, catalogNames(), 11)
+
+	// The catalog is rebuilt from the backups, with the result of their
+	// verification.
+	require.NoError(t, bs.RemoveBackup(ctx, dir, "backup10"))
+	catalog, err = RebuildBackupCatalog(ctx, bs, dir, logger)
+	require.NoError(t, err)
+	assert.Len(t, catalog.Backups, 11)
+	assert.Nil(t, catalog.Entry("backup10"))
+	assert.NotNil(t, catalog.Entry("backup4"))
+	assert.Equal(t, &BackupCatalogVerification{VerifiedAt: "2025-01-01T00:00:00Z"}, catalog.Entry("backup1").Verification)
+	assert.Equal(t, catalog.Backups, func() []*BackupCatalogEntry {
+		c, err := ReadBackupCatalog(ctx, bs, dir)
+		require.NoError(t, err)
+		return c.Backups
+	}())
+}
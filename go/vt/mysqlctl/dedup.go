@@ -0,0 +1,606 @@
+/*
+Copyright 2026 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package mysqlctl
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"hash"
+	"io"
+	"math/bits"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/dustin/go-humanize"
+	"github.com/spf13/pflag"
+
+	"vitess.io/vitess/go/ioutil"
+	"vitess.io/vitess/go/vt/log"
+	"vitess.io/vitess/go/vt/logutil"
+	stats "vitess.io/vitess/go/vt/mysqlctl/backupstats"
+	"vitess.io/vitess/go/vt/mysqlctl/backupstorage"
+	"vitess.io/vitess/go/vt/servenv"
+	"vitess.io/vitess/go/vt/vterrors"
+
+	logutilpb "vitess.io/vitess/go/vt/proto/logutil"
+	vtrpcpb "vitess.io/vitess/go/vt/proto/vtrpc"
+)
+
+/*
+
+The builtin backup engine can deduplicate the files of the backups of a shard,
+so that a full backup of a mostly static shard only uploads what changed since
+the previous backups.
+
+In a deduplicated backup, each file is split in content-defined chunks: the
+boundaries of the chunks are where a rolling hash (a "gear" hash, as in FastCDC)
+of the last bytes matches a mask, so that they move along with the data when
+bytes are inserted or removed, and the chunks after a change are the same as
+before it.
+
+Each chunk is compressed and encrypted on its own, as configured for the backup,
+and stored in the BackupStorage under its name, a hash of its content, in a
+directory shared by all the backups of the shard. A chunk that is already
+stored is not uploaded again. The MANIFEST of the backup lists the names of the
+chunks of each file, in order.
+
+The chunks are not removed along with the backups: once backups have been
+removed, RemoveUnreferencedChunks removes the chunks that no backup references
+anymore. A backup in progress references the chunks it adds or reuses only once
+its MANIFEST is written, so the chunks modified during the last
+--builtinbackup-dedup-gc-grace-period are kept: adding a chunk, or finding that
+it is already stored, refreshes its modification time.
+
+*/
+
+const (
+	// minDedupChunkSize is the smallest value of --builtinbackup-dedup-chunk-size.
+	minDedupChunkSize = 64 * 1024
+)
+
+var (
+	// builtinBackupDeduplicate is true if the builtin backups are deduplicated.
+	builtinBackupDeduplicate bool
+
+	// builtinBackupDedupChunkSize is the average size of the chunks of the
+	// deduplicated backups.
+	builtinBackupDedupChunkSize = 1024 * 1024
+
+	// chunkGCGracePeriod is how long after it was last modified an
+	// unreferenced chunk is kept by RemoveUnreferencedChunks, and how long
+	// after it started a backup without a MANIFEST is considered in progress
+	// rather than failed. It must be longer than the longest backup.
+	chunkGCGracePeriod = 24 * time.Hour
+
+	// gearTable maps each byte to a random value for the rolling hash of the
+	// chunker. It must never change, or the chunks of new backups would not
+	// match the ones already stored.
+	gearTable [256]uint64
+
+	// chunkCodecLogger discards the logs of the compressors and decompressors
+	// of the chunks, which would otherwise log for each chunk.
+	chunkCodecLogger = logutil.NewCallbackLogger(func(*logutilpb.Event) {})
+)
+
+func init() {
+	for _, cmd := range []string{"vtbackup", "vtcombo", "vttablet", "vttestserver"} {
+		servenv.OnParseFor(cmd, registerBackupDedupFlags)
+	}
+
+	for _, cmd := range []string{"vtbackup", "vtcombo", "vtctld", "vttablet", "vttestserver"} {
+		servenv.OnParseFor(cmd, registerChunkGCFlags)
+	}
+
+	// Fill the gear table with a splitmix64 sequence from a fixed seed.
+	seed := uint64(0x766974657373)
+	for i := range gearTable {
+		seed += 0x9e3779b97f4a7c15
+		z := seed
+		z = (z ^ (z >> 30)) * 0xbf58476d1ce4e5b9
+		z = (z ^ (z >> 27)) * 0x94d049bb133111eb
+		gearTable[i] = z ^ (z >> 31)
+	}
+}
+
+func registerBackupDedupFlags(fs *pflag.FlagSet) {
+	fs.BoolVar(&builtinBackupDeduplicate, "builtinbackup-deduplicate", builtinBackupDeduplicate, "split the files of builtin backups in content-defined chunks, stored once for all the backups of a shard, so that a backup only uploads the chunks that are not stored yet. Requires a backup storage that supports it: file, s3, gcs or azblob. Not supported with --external-compressor.")
+	fs.IntVar(&builtinBackupDedupChunkSize, "builtinbackup-dedup-chunk-size", builtinBackupDedupChunkSize, "average size in bytes of the chunks of deduplicated builtin backups, rounded down to a power of two. Smaller chunks deduplicate better, but make more requests to the backup storage. Changing it prevents new backups from reusing the chunks of the previous ones.")
+}
+
+func registerChunkGCFlags(fs *pflag.FlagSet) {
+	fs.DurationVar(&chunkGCGracePeriod, "builtinbackup-dedup-gc-grace-period", chunkGCGracePeriod, "how long the chunks of deduplicated builtin backups that no backup references are kept after they were last added or reused by a backup, before being removed. It must be longer than the longest backup.")
+}
+
+// chunker splits a stream in content-defined chunks.
+type chunker struct {
+	r   io.Reader
+	eof bool
+
+	// buf holds the data read from r, from start to end.
+	buf        []byte
+	start, end int
+
+	minSize, maxSize int
+	mask             uint64
+}
+
+// newChunker returns a chunker of r, with chunks of avgSize bytes on average,
+// rounded down to a power of two.
+func newChunker(r io.Reader, avgSize int) *chunker {
+	maskBits := bits.Len(uint(avgSize)) - 1
+	avgSize = 1 << maskBits
+	return &chunker{
+		r:       r,
+		buf:     make([]byte, 8*avgSize),
+		minSize: avgSize / 4,
+		maxSize: 4 * avgSize,
+		// The highest bits of the hash depend on the most bytes.
+		mask: ^uint64(0) << (64 - maskBits),
+	}
+}
+
+// next returns the next chunk, or io.EOF once all the data has been read. The
+// chunk is only valid until the next call.
+func (c *chunker) next() ([]byte, error) {
+	if c.end-c.start < c.maxSize && !c.eof {
+		// Move the remaining data to the front, and fill the buffer, so that
+		// it holds at least a chunk of the maximum size.
+		c.end = copy(c.buf, c.buf[c.start:c.end])
+		c.start = 0
+		for c.end < len(c.buf) && !c.eof {
+			n, err := c.r.Read(c.buf[c.end:])
+			c.end += n
+			if err == io.EOF {
+				c.eof = true
+			} else if err != nil {
+				return nil, err
+			}
+		}
+	}
+	if c.start == c.end {
+		return nil, io.EOF
+	}
+
+	n := c.cut(c.buf[c.start:c.end])
+	chunk := c.buf[c.start : c.start+n]
+	c.start += n
+	return chunk, nil
+}
+
+// cut returns the length of the chunk at the beginning of data.
+func (c *chunker) cut(data []byte) int {
+	if len(data) <= c.minSize {
+		return len(data)
+	}
+	end := min(len(data), c.maxSize)
+	var h uint64
+	for i := c.minSize; i < end; i++ {
+		h = h<<1 + gearTable[data[i]]
+		if h&c.mask == 0 {
+			return i + 1
+		}
+	}
+	return end
+}
+
+// chunkCodec encodes the chunks of a deduplicated backup before they are
+// stored, and decodes them on restore.
+type chunkCodec struct {
+	// compressionEngine is the engine the chunks are compressed with, or
+	// empty if they are not compressed.
+	compressionEngine string
+
+	// enc encrypts the chunks, if not nil.
+	enc *backupEncryption
+}
+
+// encoding identifies how the chunks are encoded, as chunks encoded
+// differently can't be shared between backups.
+func (cc *chunkCodec) encoding() string {
+	switch cc.compressionEngine {
+	case "":
+		return "none"
+	case PgzipCompressor, PargzipCompressor:
+		return "gzip"
+	default:
+		return cc.compressionEngine
+	}
+}
+
+// name returns the name of a chunk: the hex-encoded SHA-256 of its encoding
+// and content. When the chunks are encrypted, it is an HMAC keyed from the
+// encryption key instead, so that the names don't reveal the content, and
+// the chunks of backups encrypted with different keys are not mixed.
+func (cc *chunkCodec) name(data []byte) string {
+	var h hash.Hash
+	if cc.enc != nil {
+		h = hmac.New(sha256.New, cc.enc.chunkNameKey)
+	} else {
+		h = sha256.New()
+	}
+	h.Write([]byte(cc.encoding()))
+	h.Write([]byte{0})
+	h.Write(data)
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// encode compresses and encrypts the chunk of the given name, as configured.
+func (cc *chunkCodec) encode(name string, data []byte) ([]byte, error) {
+	var buf bytes.Buffer
+	var writer io.Writer = &buf
+
+	var encryptor io.WriteCloser
+	if cc.enc != nil {
+		encryptor = cc.enc.newEncryptor(writer, dedupChunkID(name))
+		writer = encryptor
+	}
+
+	if cc.compressionEngine != "" {
+		compressor, err := newBuiltinCompressor(cc.compressionEngine, writer, chunkCodecLogger)
+		if err != nil {
+			return nil, vterrors.Wrap(err, "can't create compressor")
+		}
+		_, err = compressor.Write(data)
+		if cerr := compressor.Close(); err == nil {
+			err = cerr
+		}
+		if err != nil {
+			return nil, vterrors.Wrap(err, "can't compress chunk")
+		}
+	} else if _, err := writer.Write(data); err != nil {
+		return nil, err
+	}
+
+	if encryptor != nil {
+		if err := encryptor.Close(); err != nil {
+			return nil, vterrors.Wrap(err, "can't encrypt chunk")
+		}
+	}
+	return buf.Bytes(), nil
+}
+
+// decode decrypts and decompresses the chunk of the given name, as
+// configured.
+func (cc *chunkCodec) decode(name string, r io.Reader) ([]byte, error) {
+	if cc.enc != nil {
+		r = cc.enc.newDecryptor(r, dedupChunkID(name))
+	}
+	if cc.compressionEngine != "" {
+		decompressor, err := newBuiltinDecompressor(cc.compressionEngine, r, chunkCodecLogger)
+		if err != nil {
+			return nil, vterrors.Wrap(err, "can't create decompressor")
+		}
+		defer decompressor.Close()
+		r = decompressor
+	}
+	return io.ReadAll(r)
+}
+
+// backupDedup adds the chunks of the files of a deduplicated backup to the
+// backup storage.
+type backupDedup struct {
+	ch        backupstorage.ChunkHandle
+	codec     chunkCodec
+	chunkSize int
+
+	// stored holds the names of the chunks known to be stored, so that
+	// chunks repeated in the backup are only checked once.
+	stored sync.Map
+
+	// Number of chunks of the backup, and number and size of the chunks the
+	// backup added to the storage.
+	chunks, added, addedBytes atomic.Int64
+}
+
+// newBackupDedup returns the deduplication of a new backup, or nil if backups
+// are not deduplicated.
+func newBackupDedup(bh backupstorage.BackupHandle, enc *backupEncryption) (*backupDedup, error) {
+	if !builtinBackupDeduplicate {
+		return nil, nil
+	}
+	ch, ok := bh.(backupstorage.ChunkHandle)
+	if !ok {
+		return nil, vterrors.Errorf(vtrpcpb.Code_FAILED_PRECONDITION, "--builtinbackup-deduplicate is not supported by the %q backup storage", backupstorage.BackupStorageImplementation)
+	}
+	if ExternalCompressorCmd != "" {
+		return nil, vterrors.Errorf(vtrpcpb.Code_FAILED_PRECONDITION, "--builtinbackup-deduplicate is not supported with --external-compressor")
+	}
+	if builtinBackupDedupChunkSize < minDedupChunkSize {
+		return nil, vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "--builtinbackup-dedup-chunk-size must be at least %d, got %d", minDedupChunkSize, builtinBackupDedupChunkSize)
+	}
+
+	dedup := &backupDedup{
+		ch:        ch,
+		codec:     chunkCodec{enc: enc},
+		chunkSize: builtinBackupDedupChunkSize,
+	}
+	if backupStorageCompress {
+		dedup.codec.compressionEngine = CompressionEngineName
+	}
+	return dedup, nil
+}
+
+// addChunk adds a chunk to the storage, unless it is already stored, and
+// returns its name.
+func (d *backupDedup) addChunk(ctx context.Context, params BackupParams, limits *fileIOLimits, data []byte) (string, error) {
+	name := d.codec.name(data)
+	d.chunks.Add(1)
+	if _, ok := d.stored.Load(name); ok {
+		return name, nil
+	}
+
+	ok, err := d.ch.HasChunk(ctx, name)
+	if err != nil {
+		return "", vterrors.Wrapf(err, "can't check chunk %v", name)
+	}
+	if !ok {
+		encoded, err := d.codec.encode(name, data)
+		if err != nil {
+			return "", err
+		}
+		if err := limits.waitWriteAll(len(encoded)); err != nil {
+			return "", err
+		}
+		addChunkAt := time.Now()
+		if err := d.ch.AddChunk(ctx, name, encoded); err != nil {
+			return "", vterrors.Wrapf(err, "can't add chunk %v", name)
+		}
+		params.Stats.Scope(stats.Operation("Destination:Write")).TimedIncrementBytes(len(encoded), time.Since(addChunkAt))
+		d.added.Add(1)
+		d.addedBytes.Add(int64(len(encoded)))
+	}
+	d.stored.Store(name, struct{}{})
+	return name, nil
+}
+
+// logSummary logs how much of the backup was deduplicated.
+func (d *backupDedup) logSummary(logger logutil.Logger) {
+	logger.Infof("Deduplicated backup: added %d of its %d chunks to the backup storage (%s)",
+		d.added.Load(), d.chunks.Load(), humanize.IBytes(uint64(d.addedBytes.Load())))
+}
+
+// backupFileChunks backs up an individual file of a deduplicated backup: the
+// file is split in chunks, the chunks that are not stored yet are added to the
+// backup storage, and the names of all of them are recorded in fe.Chunks.
+func (be *BuiltinBackupEngine) backupFileChunks(ctx context.Context, params BackupParams, dedup *backupDedup, fe *FileEntry) (finalErr error) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	// Open the source file for reading.
+	openSourceAt := time.Now()
+	source, err := fe.open(params.Cnf, true)
+	if err != nil {
+		return err
+	}
+	params.Stats.Scope(stats.Operation("Source:Open")).TimedIncrement(time.Since(openSourceAt))
+
+	defer func() {
+		closeSourceAt := time.Now()
+		source.Close()
+		params.Stats.Scope(stats.Operation("Source:Close")).TimedIncrement(time.Since(closeSourceAt))
+	}()
+
+	readStats := params.Stats.Scope(stats.Operation("Source:Read"))
+	timedSource := ioutil.NewMeteredReadCloser(source, readStats.TimedIncrementBytes)
+
+	fi, err := source.Stat()
+	if err != nil {
+		return err
+	}
+
+	retryStr := retryToString(fe.RetryCount)
+	limits := newFileIOLimits(ctx, params.IOThrottler)
+	br := newBackupReader(fe.Name, fi.Size(), limits.reader(timedSource))
+	go br.ReportProgress(ctx, builtinBackupProgress, params.Logger, false /*restore*/, retryStr)
+	defer func() {
+		if err := br.Close(finalErr == nil); err != nil {
+			finalErr = errors.Join(finalErr, vterrors.Wrap(err, "failed to close the source reader"))
+		}
+	}()
+
+	var reader io.Reader = br
+	if builtinBackupFileReadBufferSize > 0 {
+		reader = bufio.NewReaderSize(br, int(builtinBackupFileReadBufferSize))
+	}
+
+	params.Logger.Infof("Backing up file: %v %s", fe.Name, retryStr)
+	fe.Chunks = nil
+	chunker := newChunker(reader, dedup.chunkSize)
+	for {
+		data, err := chunker.next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return vterrors.Wrap(err, "cannot read source file")
+		}
+		name, err := dedup.addChunk(ctx, params, limits, data)
+		if err != nil {
+			return err
+		}
+		fe.Chunks = append(fe.Chunks, name)
+	}
+
+	// Save the hash of the file, checked once its chunks are restored.
+	fe.Hash = br.HashString()
+	fe.Size = atomic.LoadInt64(&br.nn)
+	return nil
+}
+
+// restoreFileChunks restores an individual file of a deduplicated backup from
+// its chunks.
+func (be *BuiltinBackupEngine) restoreFileChunks(ctx context.Context, params RestoreParams, ch backupstorage.ChunkHandle, fe *FileEntry, bm builtinBackupManifest, enc *backupEncryption) (finalErr error) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	codec := chunkCodec{enc: enc}
+	if !bm.SkipCompress {
+		codec.compressionEngine = bm.CompressionEngine
+		if codec.compressionEngine == "" {
+			// for backward compatibility
+			codec.compressionEngine = PgzipCompressor
+		}
+	}
+
+	// Open the destination file for writing.
+	openDestAt := time.Now()
+	dest, err := fe.open(params.Cnf, false)
+	if err != nil {
+		return vterrors.Wrap(err, "can't open destination file for writing")
+	}
+	params.Stats.Scope(stats.Operation("Destination:Open")).TimedIncrement(time.Since(openDestAt))
+
+	defer func() {
+		closeDestAt := time.Now()
+		if cerr := dest.Close(); cerr != nil {
+			finalErr = errors.Join(finalErr, vterrors.Wrap(cerr, "failed to close destination file"))
+		}
+		params.Stats.Scope(stats.Operation("Destination:Close")).TimedIncrement(time.Since(closeDestAt))
+	}()
+
+	writeStats := params.Stats.Scope(stats.Operation("Destination:Write"))
+	timedDest := ioutil.NewMeteredWriter(dest, writeStats.TimedIncrementBytes)
+
+	// The backupPipe hashes the restored file and reports progress.
+	retryStr := retryToString(fe.RetryCount)
+	limits := newFileIOLimits(ctx, params.IOThrottler)
+	bw := newBackupWriter(fe.Name, int(builtinBackupFileWriteBufferSize), 0, limits.writer(timedDest))
+	go bw.ReportProgress(ctx, builtinBackupProgress, params.Logger, true /*restore*/, retryStr)
+	defer func() {
+		if err := bw.Close(finalErr == nil); err != nil {
+			finalErr = errors.Join(finalErr, vterrors.Wrap(err, "failed to flush destination buffer"))
+		}
+	}()
+
+	for i, name := range fe.Chunks {
+		data, err := readChunk(ctx, params, limits, ch, codec, name)
+		if err != nil {
+			return vterrors.Wrapf(err, "can't restore chunk %d of %v", i, fe.Name)
+		}
+		if _, err := bw.Write(data); err != nil {
+			return vterrors.Wrap(err, "failed to write file contents")
+		}
+	}
+
+	// Check the hash.
+	if hash := bw.HashString(); hash != fe.Hash {
+		return vterrors.Errorf(vtrpcpb.Code_INTERNAL, "hash mismatch for %v, got %v expected %v", fe.Name, hash, fe.Hash)
+	}
+	return nil
+}
+
+// readChunk reads and decodes a chunk, and checks its content.
+func readChunk(ctx context.Context, params RestoreParams, limits *fileIOLimits, ch backupstorage.ChunkHandle, codec chunkCodec, name string) ([]byte, error) {
+	openSourceAt := time.Now()
+	source, err := ch.ReadChunk(ctx, name)
+	if err != nil {
+		return nil, vterrors.Wrap(err, "can't open chunk for reading")
+	}
+	params.Stats.Scope(stats.Operation("Source:Open")).TimedIncrement(time.Since(openSourceAt))
+	defer source.Close()
+
+	readStats := params.Stats.Scope(stats.Operation("Source:Read"))
+	data, err := codec.decode(name, limits.reader(ioutil.NewMeteredReader(source, readStats.TimedIncrementBytes)))
+	if err != nil {
+		return nil, err
+	}
+	if got := codec.name(data); got != name {
+		return nil, vterrors.Errorf(vtrpcpb.Code_DATA_LOSS, "chunk %v is corrupted: its content hashes to %v", name, got)
+	}
+	return data, nil
+}
+
+// RemoveUnreferencedChunks removes the chunks of a backup directory that are
+// not referenced by any of its backups anymore, e.g. after backups were
+// removed, and returns how many were removed. It does nothing if the backup
+// storage does not store chunks.
+//
+// A backup in progress references the chunks it added or reused only once its
+// MANIFEST is written. They are kept as long as the backup is shorter than
+// --builtinbackup-dedup-gc-grace-period: the chunks modified during the grace
+// period are never removed, nothing is removed while a backup without
+// MANIFEST started during the grace period, and a chunk is not removed if a
+// backup added or reused it since it was listed.
+//
+// Nothing is removed if the MANIFEST of a backup exists but can't be read, as
+// the chunks it references would be removed.
+func RemoveUnreferencedChunks(ctx context.Context, bs backupstorage.BackupStorage, dir string) (int, error) {
+	cs, ok := bs.(backupstorage.ChunkStorage)
+	if !ok {
+		return 0, nil
+	}
+	chunks, err := cs.ListChunks(ctx, dir)
+	if err != nil {
+		return 0, vterrors.Wrapf(err, "can't list chunks of %v", dir)
+	}
+	if len(chunks) == 0 {
+		return 0, nil
+	}
+
+	bhs, err := bs.ListBackups(ctx, dir)
+	if err != nil {
+		return 0, vterrors.Wrapf(err, "can't list backups of %v", dir)
+	}
+	referenced := make(map[string]bool)
+	for _, bh := range bhs {
+		var bm builtinBackupManifest
+		if err := getBackupManifestInto(ctx, bh, &bm); err != nil {
+			if !errors.Is(vterrors.UnwrapAll(err), backupstorage.ErrFileNotFound) {
+				return 0, vterrors.Wrapf(err, "can't read the MANIFEST of backup %v of %v", bh.Name(), dir)
+			}
+			backupTime, _, perr := ParseBackupName(dir, bh.Name())
+			if perr == nil && backupTime != nil && time.Since(*backupTime) < chunkGCGracePeriod {
+				log.Infof("Not removing unreferenced chunks of %v, backup %v may be in progress: %v", dir, bh.Name(), err)
+				return 0, nil
+			}
+			continue
+		}
+		for _, fe := range bm.FileEntries {
+			for _, name := range fe.Chunks {
+				referenced[name] = true
+			}
+		}
+	}
+
+	removed, recent := 0, 0
+	for _, chunk := range chunks {
+		if referenced[chunk.Name] {
+			continue
+		}
+		if time.Since(chunk.ModTime) < chunkGCGracePeriod {
+			recent++
+			continue
+		}
+		ok, err := cs.RemoveChunk(ctx, dir, chunk)
+		if err != nil {
+			return removed, vterrors.Wrapf(err, "can't remove chunk %v of %v", chunk.Name, dir)
+		}
+		if !ok {
+			recent++
+			continue
+		}
+		removed++
+	}
+	log.Infof("Removed %d unreferenced chunks of %v, kept %d recently modified ones, %d are still referenced", removed, dir, recent, len(chunks)-removed-recent)
+	return removed, nil
+}
@@ -0,0 +1,381 @@
+/*
+Copyright 2026 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package mysqlctl
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"math/rand/v2"
+	"os"
+	"path"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"vitess.io/vitess/go/vt/logutil"
+	"vitess.io/vitess/go/vt/mysqlctl/backupstats"
+	"vitess.io/vitess/go/vt/mysqlctl/backupstorage"
+	"vitess.io/vitess/go/vt/mysqlctl/filebackupstorage"
+)
+
+func randomBytes(seed uint64, size int) []byte {
+	data := make([]byte, size)
+	r := rand.New(rand.NewPCG(seed, seed))
+	for i := range data {
+		data[i] = byte(r.Uint32())
+	}
+	return data
+}
+
+func chunksForTest(t *testing.T, data []byte, avgSize int) [][]byte {
+	var chunks [][]byte
+	c := newChunker(bytes.NewReader(data), avgSize)
+	for {
+		chunk, err := c.next()
+		if err == io.EOF {
+			return chunks
+		}
+		require.NoError(t, err)
+		chunks = append(chunks, bytes.Clone(chunk))
+	}
+}
+
+func TestChunker(t *testing.T) {
+	const avgSize = 64 * 1024
+	data := randomBytes(1, 5*1024*1024)
+
+	chunks := chunksForTest(t, data, avgSize)
+	assert.Equal(t, data, bytes.Join(chunks, nil))
+	for i, chunk := range chunks {
+		assert.LessOrEqual(t, len(chunk), 4*avgSize)
+		if i < len(chunks)-1 {
+			assert.Greater(t, len(chunk), avgSize/4)
+		}
+	}
+	// The average size is about the requested one.
+	assert.InDelta(t, 1.25*avgSize, len(data)/len(chunks), 0.5*avgSize)
+
+	// The boundaries are defined by the content: inserting data only
+	// changes the chunks around it.
+	inserted := bytes.Clone(data[:len(data)/2])
+	inserted = append(inserted, []byte("inserted")...)
+	inserted = append(inserted, data[len(data)/2:]...)
+	same := 0
+	known := map[string]bool{}
+	for _, chunk := range chunks {
+		known[string(chunk)] = true
+	}
+	insertedChunks := chunksForTest(t, inserted, avgSize)
+	for _, chunk := range insertedChunks {
+		if known[string(chunk)] {
+			same++
+		}
+	}
+	assert.GreaterOrEqual(t, same, len(insertedChunks)-2)
+
+	// Small and empty inputs.
+	assert.Equal(t, [][]byte{[]byte("small")}, chunksForTest(t, []byte("small"), avgSize))
+	assert.Empty(t, chunksForTest(t, nil, avgSize))
+}
+
+func TestChunkCodec(t *testing.T) {
+	data := bytes.Repeat([]byte("chunk of a deduplicated backup. "), 10000)
+	enc := newTestBackupEncryption(t)
+
+	names := map[string]bool{}
+	for _, codec := range []chunkCodec{
+		{},
+		{compressionEngine: ZstdCompressor},
+		{compressionEngine: PargzipCompressor},
+		{enc: enc},
+		{compressionEngine: ZstdCompressor, enc: enc},
+	} {
+		encoded, err := codec.encode(codec.name(data), data)
+		require.NoError(t, err)
+		if codec.compressionEngine != "" {
+			assert.Less(t, len(encoded), len(data))
+		}
+
+		// pargzip can't decompress, pgzip is used instead, which must
+		// give the chunks the same name.
+		if codec.compressionEngine == PargzipCompressor {
+			name := codec.name(data)
+			codec.compressionEngine = PgzipCompressor
+			assert.Equal(t, name, codec.name(data))
+		}
+
+		decoded, err := codec.decode(codec.name(data), bytes.NewReader(encoded))
+		require.NoError(t, err)
+		assert.Equal(t, data, decoded)
+
+		// An encrypted chunk stored under the name of another chunk can't
+		// be decrypted.
+		if codec.enc != nil {
+			_, err = codec.decode(codec.name([]byte("another chunk")), bytes.NewReader(encoded))
+			require.ErrorContains(t, err, "can't decrypt chunk 0")
+		}
+
+		// Chunks encoded differently have different names.
+		name := codec.name(data)
+		assert.Regexp(t, "^[0-9a-f]{64}$", name)
+		assert.False(t, names[name], "duplicate name %v", name)
+		names[name] = true
+	}
+
+	// Chunks encrypted with another key have different names.
+	assert.False(t, names[(&chunkCodec{enc: newTestBackupEncryption(t)}).name(data)])
+}
+
+func TestDeduplicatedBackup(t *testing.T) {
+	ctx := context.Background()
+	oldDeduplicate, oldChunkSize, oldCompress := builtinBackupDeduplicate, builtinBackupDedupChunkSize, backupStorageCompress
+	oldRoot := filebackupstorage.FileBackupStorageRoot
+	defer func() {
+		builtinBackupDeduplicate, builtinBackupDedupChunkSize, backupStorageCompress = oldDeduplicate, oldChunkSize, oldCompress
+		filebackupstorage.FileBackupStorageRoot = oldRoot
+	}()
+	builtinBackupDeduplicate = true
+	builtinBackupDedupChunkSize = minDedupChunkSize
+	backupStorageCompress = true
+	filebackupstorage.FileBackupStorageRoot = t.TempDir()
+
+	bs := backupstorage.BackupStorageMap["file"]
+	dir := "ks/0"
+	cnf := &Mycnf{DataDir: t.TempDir()}
+	fe := FileEntry{Base: backupData, Name: "t1.ibd"}
+	data := randomBytes(2, 2*1024*1024)
+	require.NoError(t, os.WriteFile(path.Join(cnf.DataDir, fe.Name), data, 0600))
+
+	// backup takes a backup of the file, with a MANIFEST.
+	backup := func(name string) (*backupDedup, FileEntry) {
+		bh, err := bs.StartBackup(ctx, dir, name)
+		require.NoError(t, err)
+		dedup, err := newBackupDedup(bh, nil)
+		require.NoError(t, err)
+		params := BackupParams{Cnf: cnf, Logger: logutil.NewMemoryLogger(), Stats: backupstats.NoStats()}
+		fe := fe
+		require.NoError(t, (&BuiltinBackupEngine{}).backupFileChunks(ctx, params, dedup, &fe))
+
+		wc, err := bh.AddFile(ctx, backupManifestFileName, backupstorage.FileSizeUnknown)
+		require.NoError(t, err)
+		bm := builtinBackupManifest{CompressionEngine: CompressionEngineName, FileEntries: []FileEntry{fe}, Deduplicated: true}
+		require.NoError(t, json.NewEncoder(wc).Encode(bm))
+		require.NoError(t, wc.Close())
+		require.NoError(t, bh.EndBackup(ctx))
+		return dedup, fe
+	}
+
+	dedup1, fe1 := backup("2025-01-01.000000.zone1-0000000100")
+	assert.Equal(t, dedup1.chunks.Load(), dedup1.added.Load())
+	assert.Equal(t, int64(len(fe1.Chunks)), dedup1.chunks.Load())
+
+	// Change a page of the file: the next backup only adds the few chunks
+	// around it.
+	copy(data[1024*1024:], randomBytes(3, 16*1024))
+	require.NoError(t, os.WriteFile(path.Join(cnf.DataDir, fe.Name), data, 0600))
+	dedup2, fe2 := backup("2025-01-02.000000.zone1-0000000100")
+	assert.NotZero(t, dedup2.added.Load())
+	assert.LessOrEqual(t, dedup2.added.Load(), int64(3))
+	assert.NotEqual(t, fe1.Hash, fe2.Hash)
+
+	// Both backups can be restored.
+	restore := func(fe FileEntry) []byte {
+		bhs, err := bs.ListBackups(ctx, dir)
+		require.NoError(t, err)
+		require.NotEmpty(t, bhs)
+		params := RestoreParams{Cnf: &Mycnf{DataDir: t.TempDir()}, Logger: logutil.NewMemoryLogger(), Stats: backupstats.NoStats()}
+		bm := builtinBackupManifest{CompressionEngine: PgzipCompressor, Deduplicated: true}
+		require.NoError(t, (&BuiltinBackupEngine{}).restoreFileChunks(ctx, params, bhs[0].(backupstorage.ChunkHandle), &fe, bm, nil))
+		restored, err := os.ReadFile(path.Join(params.Cnf.DataDir, fe.Name))
+		require.NoError(t, err)
+		return restored
+	}
+	assert.Equal(t, data, restore(fe2))
+	assert.NotEqual(t, data, restore(fe1))
+
+	// A corrupted file is detected.
+	badFE := fe2
+	badFE.Hash = fe1.Hash
+	bhs, err := bs.ListBackups(ctx, dir)
+	require.NoError(t, err)
+	params := RestoreParams{Cnf: &Mycnf{DataDir: t.TempDir()}, Logger: logutil.NewMemoryLogger(), Stats: backupstats.NoStats()}
+	err = (&BuiltinBackupEngine{}).restoreFileChunks(ctx, params, bhs[0].(backupstorage.ChunkHandle), &badFE, builtinBackupManifest{CompressionEngine: PgzipCompressor}, nil)
+	require.ErrorContains(t, err, "hash mismatch")
+
+	// Nothing is removed while all the chunks are referenced.
+	removed, err := RemoveUnreferencedChunks(ctx, bs, dir)
+	require.NoError(t, err)
+	assert.Zero(t, removed)
+
+	// ageChunks makes the chunks look last modified before the grace period.
+	ageChunks := func() {
+		chunksDir := path.Join(filebackupstorage.FileBackupStorageRoot, backupstorage.ChunksDir, dir)
+		entries, err := os.ReadDir(chunksDir)
+		require.NoError(t, err)
+		old := time.Now().Add(-2 * chunkGCGracePeriod)
+		for _, entry := range entries {
+			require.NoError(t, os.Chtimes(path.Join(chunksDir, entry.Name()), old, old))
+		}
+	}
+
+	// Once the first backup is removed, only its chunks that are not in the
+	// second backup are, once the grace period is over.
+	onlyInFirst := 0
+	for _, name := range fe1.Chunks {
+		if !slices.Contains(fe2.Chunks, name) {
+			onlyInFirst++
+		}
+	}
+	require.NoError(t, bs.RemoveBackup(ctx, dir, "2025-01-01.000000.zone1-0000000100"))
+	removed, err = RemoveUnreferencedChunks(ctx, bs, dir)
+	require.NoError(t, err)
+	assert.Zero(t, removed)
+	ageChunks()
+	removed, err = RemoveUnreferencedChunks(ctx, bs, dir)
+	require.NoError(t, err)
+	assert.Equal(t, onlyInFirst, removed)
+	assert.Equal(t, data, restore(fe2))
+
+	// Nothing is removed while a recent backup may be in progress.
+	require.NoError(t, bs.RemoveBackup(ctx, dir, "2025-01-02.000000.zone1-0000000100"))
+	bh, err := bs.StartBackup(ctx, dir, time.Now().UTC().Format(BackupTimestampFormat)+".zone1-0000000100")
+	require.NoError(t, err)
+	removed, err = RemoveUnreferencedChunks(ctx, bs, dir)
+	require.NoError(t, err)
+	assert.Zero(t, removed)
+	require.NoError(t, bh.AbortBackup(ctx))
+
+	// The chunks reused by a backup without MANIFEST are kept, even if the
+	// backup is not considered in progress anymore.
+	bh, err = bs.StartBackup(ctx, dir, "2025-01-03.000000.zone1-0000000100")
+	require.NoError(t, err)
+	dedup, err := newBackupDedup(bh, nil)
+	require.NoError(t, err)
+	backupParams := BackupParams{Cnf: cnf, Logger: logutil.NewMemoryLogger(), Stats: backupstats.NoStats()}
+	reusingFE := fe
+	require.NoError(t, (&BuiltinBackupEngine{}).backupFileChunks(ctx, backupParams, dedup, &reusingFE))
+	assert.Zero(t, dedup.added.Load())
+	removed, err = RemoveUnreferencedChunks(ctx, bs, dir)
+	require.NoError(t, err)
+	assert.Zero(t, removed)
+	require.NoError(t, bh.AbortBackup(ctx))
+
+	ageChunks()
+	removed, err = RemoveUnreferencedChunks(ctx, bs, dir)
+	require.NoError(t, err)
+	assert.Equal(t, len(fe2.Chunks), removed)
+}
+
+// gcRaceStorage is a file BackupStorage that calls beforeRemoveChunk before
+// removing each chunk, to act between the listings of RemoveUnreferencedChunks
+// and the removal of the chunks.
+type gcRaceStorage struct {
+	backupstorage.BackupStorage
+	beforeRemoveChunk func(name string)
+}
+
+func (bs *gcRaceStorage) ListChunks(ctx context.Context, dir string) ([]backupstorage.ChunkInfo, error) {
+	return bs.BackupStorage.(backupstorage.ChunkStorage).ListChunks(ctx, dir)
+}
+
+func (bs *gcRaceStorage) RemoveChunk(ctx context.Context, dir string, chunk backupstorage.ChunkInfo) (bool, error) {
+	bs.beforeRemoveChunk(chunk.Name)
+	return bs.BackupStorage.(backupstorage.ChunkStorage).RemoveChunk(ctx, dir, chunk)
+}
+
+func TestRemoveUnreferencedChunksSafety(t *testing.T) {
+	ctx := context.Background()
+	oldDeduplicate, oldChunkSize := builtinBackupDeduplicate, builtinBackupDedupChunkSize
+	oldRoot := filebackupstorage.FileBackupStorageRoot
+	defer func() {
+		builtinBackupDeduplicate, builtinBackupDedupChunkSize = oldDeduplicate, oldChunkSize
+		filebackupstorage.FileBackupStorageRoot = oldRoot
+	}()
+	builtinBackupDeduplicate = true
+	builtinBackupDedupChunkSize = minDedupChunkSize
+	filebackupstorage.FileBackupStorageRoot = t.TempDir()
+
+	bs := backupstorage.BackupStorageMap["file"]
+	dir := "ks/0"
+	cnf := &Mycnf{DataDir: t.TempDir()}
+	fe := FileEntry{Base: backupData, Name: "t1.ibd"}
+	require.NoError(t, os.WriteFile(path.Join(cnf.DataDir, fe.Name), randomBytes(4, 512*1024), 0600))
+	params := BackupParams{Cnf: cnf, Logger: logutil.NewMemoryLogger(), Stats: backupstats.NoStats()}
+	chunksDir := path.Join(filebackupstorage.FileBackupStorageRoot, backupstorage.ChunksDir, dir)
+	ageChunks := func() {
+		entries, err := os.ReadDir(chunksDir)
+		require.NoError(t, err)
+		old := time.Now().Add(-2 * chunkGCGracePeriod)
+		for _, entry := range entries {
+			require.NoError(t, os.Chtimes(path.Join(chunksDir, entry.Name()), old, old))
+		}
+	}
+	countChunks := func() int {
+		entries, err := os.ReadDir(chunksDir)
+		require.NoError(t, err)
+		return len(entries)
+	}
+
+	// A backup whose MANIFEST can't be decoded keeps its chunks, and the
+	// garbage collection fails.
+	name := "2025-01-01.000000.zone1-0000000100"
+	bh, err := bs.StartBackup(ctx, dir, name)
+	require.NoError(t, err)
+	dedup, err := newBackupDedup(bh, nil)
+	require.NoError(t, err)
+	backupFE := fe
+	require.NoError(t, (&BuiltinBackupEngine{}).backupFileChunks(ctx, params, dedup, &backupFE))
+	wc, err := bh.AddFile(ctx, backupManifestFileName, backupstorage.FileSizeUnknown)
+	require.NoError(t, err)
+	_, err = wc.Write([]byte("{not json"))
+	require.NoError(t, err)
+	require.NoError(t, wc.Close())
+	require.NoError(t, bh.EndBackup(ctx))
+	ageChunks()
+	chunks := countChunks()
+	require.NotZero(t, chunks)
+	_, err = RemoveUnreferencedChunks(ctx, bs, dir)
+	require.ErrorContains(t, err, "can't read the MANIFEST of backup "+name)
+	assert.Equal(t, chunks, countChunks())
+
+	// Without the MANIFEST, the backup failed and its chunks are removed,
+	// unless a backup that started once they were listed reuses them.
+	require.NoError(t, os.Remove(path.Join(filebackupstorage.FileBackupStorageRoot, dir, name, backupManifestFileName)))
+	bh, err = bs.StartBackup(ctx, dir, "2025-01-02.000000.zone1-0000000100")
+	require.NoError(t, err)
+	reused := backupFE.Chunks[0]
+	racing := &gcRaceStorage{BackupStorage: bs, beforeRemoveChunk: func(name string) {
+		if name == reused {
+			ok, err := bh.(backupstorage.ChunkHandle).HasChunk(ctx, name)
+			require.NoError(t, err)
+			require.True(t, ok)
+		}
+	}}
+	removed, err := RemoveUnreferencedChunks(ctx, racing, dir)
+	require.NoError(t, err)
+	assert.Equal(t, chunks-1, removed)
+	entries, err := os.ReadDir(chunksDir)
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+	assert.Equal(t, reused, entries[0].Name())
+	require.NoError(t, bh.AbortBackup(ctx))
+}
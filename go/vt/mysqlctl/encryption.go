@@ -0,0 +1,501 @@
+/*
+Copyright 2026 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package mysqlctl
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/spf13/pflag"
+
+	"vitess.io/vitess/go/vt/servenv"
+	"vitess.io/vitess/go/vt/vterrors"
+
+	vtrpcpb "vitess.io/vitess/go/vt/proto/vtrpc"
+)
+
+/*
+
+The builtin backup engine can encrypt the files of a backup before they are
+handed to the BackupStorage, so that they never leave the host in clear.
+
+The encryption stage sits between the compressor and the BackupHandle writer.
+Each file is encrypted with AES-256-GCM in chunks, so that it can be streamed
+and each chunk authenticated on its own:
+
+	header: magic (8 bytes)
+	chunk:  length (4 bytes) | nonce (12 bytes) | sealed chunk (length + 16 bytes of tag)
+
+The length of a chunk is the length of its plaintext, with its highest bit set
+on the last chunk of the file. The length and the index of a chunk are
+authenticated as additional data, so that chunks cannot be reordered, dropped
+or truncated without the decryption failing. So is the identity of the file:
+the name of the backup and of the file entry for the files of a backup, and
+the name of the chunk for the chunks of deduplicated backups, which are shared
+between backups. A file, or a chunk of it, then can't be swapped with another
+one encrypted with the same key without the decryption failing either.
+
+The nonce of a chunk is synthetic: it is a MAC of the index, the length and the
+plaintext of the chunk, with a key derived from the encryption key. Encrypting
+the same file again then gives the same bytes, which lets a failed resumable
+upload resume from its last committed part, while chunks with different
+content never share a nonce. The only thing it reveals is which chunks of the
+files encrypted with a key are equal, at the same index.
+
+The keys are provided by a BackupKeyProvider, selected with
+--builtinbackup-encryption-key-provider. The id of the key a backup was
+encrypted with is recorded in its MANIFEST, and the key is looked up by that id
+on restore.
+
+*/
+
+const (
+	// AES256GCMEncryption is the name of the encryption algorithm of builtin
+	// backups, as recorded in their MANIFEST.
+	AES256GCMEncryption = "aes-256-gcm"
+
+	// FileBackupKeyProvider is the name of the key provider that reads the
+	// keys from --builtinbackup-encryption-key-file.
+	FileBackupKeyProvider = "file"
+
+	encryptionMagic         = "VTBKENC1"
+	encryptionChunkSize     = 64 * 1024
+	encryptionLastChunkFlag = 1 << 31
+	encryptionKeySize       = 32
+)
+
+var (
+	// builtinBackupEncryptionKeyProvider is the name of the BackupKeyProvider
+	// used to encrypt the backups. Backups are not encrypted if it is empty.
+	builtinBackupEncryptionKeyProvider string
+
+	// builtinBackupEncryptionKeyFile is the file the "file" key provider reads
+	// the keys from.
+	builtinBackupEncryptionKeyFile string
+
+	// BackupKeyProviders is the list of registered BackupKeyProvider, by name.
+	BackupKeyProviders = map[string]BackupKeyProvider{}
+
+	errTruncatedEncryptedFile = errors.New("encrypted backup file is truncated")
+)
+
+// BackupKeyProvider provides the keys the builtin backup engine encrypts and
+// decrypts the files of a backup with.
+type BackupKeyProvider interface {
+	// CurrentKey returns the key to encrypt a new backup with, and its id.
+	CurrentKey(ctx context.Context) (keyID string, key []byte, err error)
+
+	// Key returns the key with the given id, to decrypt a backup.
+	Key(ctx context.Context, keyID string) ([]byte, error)
+}
+
+func init() {
+	for _, cmd := range []string{"vtbackup", "vtcombo", "vttablet", "vttestserver"} {
+		servenv.OnParseFor(cmd, registerBackupEncryptionFlags)
+	}
+	BackupKeyProviders[FileBackupKeyProvider] = fileKeyProvider{}
+}
+
+func registerBackupEncryptionFlags(fs *pflag.FlagSet) {
+	fs.StringVar(&builtinBackupEncryptionKeyProvider, "builtinbackup-encryption-key-provider", builtinBackupEncryptionKeyProvider, "key provider used to encrypt the files of builtin backups before they are sent to the backup storage, and to decrypt them on restore. Backups are not encrypted when empty. Supported values are 'file'.")
+	fs.StringVar(&builtinBackupEncryptionKeyFile, "builtinbackup-encryption-key-file", builtinBackupEncryptionKeyFile, "file with the keys of the 'file' backup encryption key provider, one '<key id> <hex encoded 32 bytes key>' per line. The first key encrypts new backups, the others are only used to restore older backups.")
+}
+
+// backupEncryption encrypts or decrypts the files of a backup with a key.
+type backupEncryption struct {
+	keyID string
+	aead  cipher.AEAD
+
+	// nonceKey is the key the nonces of the encrypted chunks are computed
+	// with, derived from the encryption key.
+	nonceKey []byte
+
+	// chunkNameKey is the key the names of the chunks of deduplicated
+	// backups are computed with, derived from the encryption key.
+	chunkNameKey []byte
+}
+
+// newBackupEncryption returns the encryption of a new backup, with the
+// current key of the configured key provider, or nil if backups are not
+// encrypted.
+func newBackupEncryption(ctx context.Context) (*backupEncryption, error) {
+	if builtinBackupEncryptionKeyProvider == "" {
+		return nil, nil
+	}
+	provider, err := getBackupKeyProvider()
+	if err != nil {
+		return nil, err
+	}
+	keyID, key, err := provider.CurrentKey(ctx)
+	if err != nil {
+		return nil, vterrors.Wrap(err, "can't get the backup encryption key")
+	}
+	return newBackupEncryptionWithKey(keyID, key)
+}
+
+// newRestoreEncryption returns the encryption of a backup, with the key of
+// the given id, or nil if the backup is not encrypted.
+func newRestoreEncryption(ctx context.Context, bm builtinBackupManifest) (*backupEncryption, error) {
+	if bm.EncryptionKeyID == "" {
+		return nil, nil
+	}
+	if bm.Encryption != AES256GCMEncryption {
+		return nil, vterrors.Errorf(vtrpcpb.Code_FAILED_PRECONDITION, "unsupported backup encryption %q", bm.Encryption)
+	}
+	if builtinBackupEncryptionKeyProvider == "" {
+		return nil, vterrors.Errorf(vtrpcpb.Code_FAILED_PRECONDITION, "backup is encrypted with key %q, but --builtinbackup-encryption-key-provider is not set", bm.EncryptionKeyID)
+	}
+	provider, err := getBackupKeyProvider()
+	if err != nil {
+		return nil, err
+	}
+	key, err := provider.Key(ctx, bm.EncryptionKeyID)
+	if err != nil {
+		return nil, vterrors.Wrapf(err, "can't get the backup encryption key %q", bm.EncryptionKeyID)
+	}
+	return newBackupEncryptionWithKey(bm.EncryptionKeyID, key)
+}
+
+func getBackupKeyProvider() (BackupKeyProvider, error) {
+	provider, ok := BackupKeyProviders[builtinBackupEncryptionKeyProvider]
+	if !ok {
+		return nil, vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "no registered backup encryption key provider %q", builtinBackupEncryptionKeyProvider)
+	}
+	return provider, nil
+}
+
+func newBackupEncryptionWithKey(keyID string, key []byte) (*backupEncryption, error) {
+	if len(key) != encryptionKeySize {
+		return nil, vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "backup encryption key %q must be %d bytes long, got %d", keyID, encryptionKeySize, len(key))
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+	deriveKey := func(purpose string) []byte {
+		mac := hmac.New(sha256.New, key)
+		mac.Write([]byte(purpose))
+		return mac.Sum(nil)
+	}
+	return &backupEncryption{
+		keyID:        keyID,
+		aead:         aead,
+		nonceKey:     deriveKey("vitess backup chunk nonces"),
+		chunkNameKey: deriveKey("vitess backup chunk names"),
+	}, nil
+}
+
+// backupFileID returns the identity of a file of a backup, authenticated
+// with each of its chunks.
+func backupFileID(backupName string, fe *FileEntry) []byte {
+	return encryptedFileID("file", backupName, fe.Base, fe.Name)
+}
+
+// dedupChunkID returns the identity of a chunk of deduplicated backups,
+// authenticated with each of its encrypted chunks. It does not include the
+// name of a backup, as the chunks are shared between backups.
+func dedupChunkID(name string) []byte {
+	return encryptedFileID("chunk", name)
+}
+
+// encryptedFileID encodes the parts of the identity of an encrypted file,
+// each prefixed with its length so that different parts never encode the
+// same.
+func encryptedFileID(parts ...string) []byte {
+	var id []byte
+	for _, part := range parts {
+		id = binary.BigEndian.AppendUint32(id, uint32(len(part)))
+		id = append(id, part...)
+	}
+	return id
+}
+
+// chunkAdditionalData returns the additional data of the chunk of the given
+// file, index and header.
+func chunkAdditionalData(fileID []byte, header [4]byte, index uint32) []byte {
+	ad := make([]byte, len(fileID)+8)
+	n := copy(ad, fileID)
+	copy(ad[n:], header[:])
+	binary.BigEndian.PutUint32(ad[n+4:], index)
+	return ad
+}
+
+// chunkNonce returns the synthetic nonce of a chunk, from its additional data
+// and plaintext.
+func (enc *backupEncryption) chunkNonce(ad, plain []byte) []byte {
+	mac := hmac.New(sha256.New, enc.nonceKey)
+	mac.Write(ad)
+	mac.Write(plain)
+	return mac.Sum(nil)[:enc.aead.NonceSize()]
+}
+
+// newEncryptor returns a writer that encrypts what is written to it to w, as
+// the file of the given identity. It must be closed to write the last chunk,
+// which does not close w.
+func (enc *backupEncryption) newEncryptor(w io.Writer, fileID []byte) io.WriteCloser {
+	return &encryptor{enc: enc, w: w, fileID: fileID, buf: make([]byte, 0, encryptionChunkSize)}
+}
+
+type encryptor struct {
+	enc           *backupEncryption
+	w             io.Writer
+	fileID        []byte
+	headerWritten bool
+	index         uint32
+	buf           []byte
+	sealed        []byte
+	closed        bool
+}
+
+func (e *encryptor) Write(p []byte) (int, error) {
+	if e.closed {
+		return 0, io.ErrClosedPipe
+	}
+	n := 0
+	for len(p) > 0 {
+		// Only flush a full chunk once more data comes, so that the last
+		// chunk is the one flushed by Close.
+		if len(e.buf) == encryptionChunkSize {
+			if err := e.flush(false); err != nil {
+				return n, err
+			}
+		}
+		c := copy(e.buf[len(e.buf):encryptionChunkSize], p)
+		e.buf = e.buf[:len(e.buf)+c]
+		p = p[c:]
+		n += c
+	}
+	return n, nil
+}
+
+func (e *encryptor) Close() error {
+	if e.closed {
+		return nil
+	}
+	e.closed = true
+	return e.flush(true)
+}
+
+func (e *encryptor) flush(last bool) error {
+	if !e.headerWritten {
+		if _, err := e.w.Write([]byte(encryptionMagic)); err != nil {
+			return err
+		}
+		e.headerWritten = true
+	}
+	if e.index == ^uint32(0) {
+		return vterrors.Errorf(vtrpcpb.Code_OUT_OF_RANGE, "too many chunks to encrypt")
+	}
+
+	length := uint32(len(e.buf))
+	if last {
+		length |= encryptionLastChunkFlag
+	}
+	var header [4]byte
+	binary.BigEndian.PutUint32(header[:], length)
+	ad := chunkAdditionalData(e.fileID, header, e.index)
+	nonce := e.enc.chunkNonce(ad, e.buf)
+	e.sealed = append(append(e.sealed[:0], header[:]...), nonce...)
+	e.sealed = e.enc.aead.Seal(e.sealed, nonce, e.buf, ad)
+	if _, err := e.w.Write(e.sealed); err != nil {
+		return err
+	}
+	e.index++
+	e.buf = e.buf[:0]
+	return nil
+}
+
+// newDecryptor returns a reader that decrypts what is read from r, which must
+// have been encrypted as the file of the given identity.
+func (enc *backupEncryption) newDecryptor(r io.Reader, fileID []byte) io.Reader {
+	return &decryptor{enc: enc, r: r, fileID: fileID}
+}
+
+type decryptor struct {
+	enc        *backupEncryption
+	r          io.Reader
+	fileID     []byte
+	headerRead bool
+	index      uint32
+	nonce      []byte
+	sealed     []byte
+	plain      []byte
+	pos        int
+	last       bool
+}
+
+func (d *decryptor) Read(p []byte) (int, error) {
+	for d.pos == len(d.plain) {
+		if d.last {
+			// Anything after the last chunk was not written by the encryptor.
+			var b [1]byte
+			if n, _ := io.ReadFull(d.r, b[:]); n > 0 {
+				return 0, vterrors.Errorf(vtrpcpb.Code_DATA_LOSS, "unexpected data after the last chunk of an encrypted backup file")
+			}
+			return 0, io.EOF
+		}
+		if err := d.readChunk(); err != nil {
+			return 0, err
+		}
+	}
+	n := copy(p, d.plain[d.pos:])
+	d.pos += n
+	return n, nil
+}
+
+func (d *decryptor) readChunk() error {
+	if !d.headerRead {
+		header := make([]byte, len(encryptionMagic))
+		if _, err := io.ReadFull(d.r, header); err != nil {
+			return truncatedIfEOF(err)
+		}
+		if !bytes.Equal(header, []byte(encryptionMagic)) {
+			return vterrors.Errorf(vtrpcpb.Code_DATA_LOSS, "backup file is not encrypted, or not with the builtin backup encryption")
+		}
+		d.headerRead = true
+		d.nonce = make([]byte, d.enc.aead.NonceSize())
+	}
+
+	var header [4]byte
+	if _, err := io.ReadFull(d.r, header[:]); err != nil {
+		return truncatedIfEOF(err)
+	}
+	length := binary.BigEndian.Uint32(header[:])
+	d.last = length&encryptionLastChunkFlag != 0
+	length &^= encryptionLastChunkFlag
+	if length > encryptionChunkSize {
+		return vterrors.Errorf(vtrpcpb.Code_DATA_LOSS, "invalid chunk length %d in encrypted backup file", length)
+	}
+
+	if _, err := io.ReadFull(d.r, d.nonce); err != nil {
+		return truncatedIfEOF(err)
+	}
+	sealedSize := int(length) + d.enc.aead.Overhead()
+	if cap(d.sealed) < sealedSize {
+		d.sealed = make([]byte, sealedSize)
+	}
+	d.sealed = d.sealed[:sealedSize]
+	if _, err := io.ReadFull(d.r, d.sealed); err != nil {
+		return truncatedIfEOF(err)
+	}
+
+	var err error
+	d.plain, err = d.enc.aead.Open(d.plain[:0], d.nonce, d.sealed, chunkAdditionalData(d.fileID, header, d.index))
+	if err != nil {
+		return vterrors.Wrapf(err, "can't decrypt chunk %d of encrypted backup file with key %q", d.index, d.enc.keyID)
+	}
+	d.index++
+	d.pos = 0
+	return nil
+}
+
+func truncatedIfEOF(err error) error {
+	if errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) {
+		return errTruncatedEncryptedFile
+	}
+	return err
+}
+
+// fileKeyProvider reads the keys from --builtinbackup-encryption-key-file,
+// one "<key id> <hex encoded key>" per line. Empty lines and lines starting
+// with '#' are ignored. The file is read on each call, so that keys can be
+// rotated without a restart.
+type fileKeyProvider struct{}
+
+// CurrentKey implements the BackupKeyProvider interface. It returns the first
+// key of the file.
+func (fileKeyProvider) CurrentKey(_ context.Context) (string, []byte, error) {
+	keys, err := readBackupKeyFile(builtinBackupEncryptionKeyFile)
+	if err != nil {
+		return "", nil, err
+	}
+	return keys[0].id, keys[0].key, nil
+}
+
+// Key implements the BackupKeyProvider interface.
+func (fileKeyProvider) Key(_ context.Context, keyID string) ([]byte, error) {
+	keys, err := readBackupKeyFile(builtinBackupEncryptionKeyFile)
+	if err != nil {
+		return nil, err
+	}
+	for _, k := range keys {
+		if k.id == keyID {
+			return k.key, nil
+		}
+	}
+	return nil, vterrors.Errorf(vtrpcpb.Code_NOT_FOUND, "no key %q in %s", keyID, builtinBackupEncryptionKeyFile)
+}
+
+type backupKey struct {
+	id  string
+	key []byte
+}
+
+func readBackupKeyFile(path string) ([]backupKey, error) {
+	if path == "" {
+		return nil, vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "--builtinbackup-encryption-key-file is required by the %q backup encryption key provider", FileBackupKeyProvider)
+	}
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var keys []backupKey
+	scanner := bufio.NewScanner(f)
+	for lineNumber := 1; scanner.Scan(); lineNumber++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		fields := strings.Fields(line)
+		if len(fields) != 2 {
+			return nil, fmt.Errorf("%s:%d: expected '<key id> <hex encoded key>'", path, lineNumber)
+		}
+		key, err := hex.DecodeString(fields[1])
+		if err != nil {
+			return nil, fmt.Errorf("%s:%d: invalid key: %w", path, lineNumber, err)
+		}
+		if len(key) != encryptionKeySize {
+			return nil, fmt.Errorf("%s:%d: key must be %d bytes long, got %d", path, lineNumber, encryptionKeySize, len(key))
+		}
+		keys = append(keys, backupKey{id: fields[0], key: key})
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	if len(keys) == 0 {
+		return nil, fmt.Errorf("%s: no backup encryption key", path)
+	}
+	return keys, nil
+}
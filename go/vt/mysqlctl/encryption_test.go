@@ -0,0 +1,298 @@
+/*
+Copyright 2026 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package mysqlctl
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"vitess.io/vitess/go/vt/logutil"
+)
+
+func newTestBackupEncryption(t *testing.T) *backupEncryption {
+	key := make([]byte, encryptionKeySize)
+	_, err := rand.Read(key)
+	require.NoError(t, err)
+	enc, err := newBackupEncryptionWithKey("test", key)
+	require.NoError(t, err)
+	return enc
+}
+
+var testFileID = backupFileID("backup", &FileEntry{Base: backupData, Name: "file"})
+
+func encryptForTest(t *testing.T, enc *backupEncryption, data []byte) []byte {
+	var buf bytes.Buffer
+	encryptor := enc.newEncryptor(&buf, testFileID)
+	// Write in odd sizes to cross the chunk boundaries.
+	for len(data) > 0 {
+		n := min(len(data), 10000)
+		_, err := encryptor.Write(data[:n])
+		require.NoError(t, err)
+		data = data[n:]
+	}
+	require.NoError(t, encryptor.Close())
+	return buf.Bytes()
+}
+
+func TestBackupEncryptionRoundTrip(t *testing.T) {
+	enc := newTestBackupEncryption(t)
+
+	for _, size := range []int{0, 1, encryptionChunkSize - 1, encryptionChunkSize, encryptionChunkSize + 1, 3*encryptionChunkSize + 42} {
+		t.Run(fmt.Sprintf("%d bytes", size), func(t *testing.T) {
+			data := make([]byte, size)
+			_, err := rand.Read(data)
+			require.NoError(t, err)
+
+			encrypted := encryptForTest(t, enc, data)
+			// A short plaintext may appear in the ciphertext by chance.
+			if size > 16 {
+				assert.NotContains(t, string(encrypted), string(data))
+			}
+
+			decrypted, err := io.ReadAll(enc.newDecryptor(bytes.NewReader(encrypted), testFileID))
+			require.NoError(t, err)
+			assert.Equal(t, data, decrypted)
+		})
+	}
+}
+
+func TestBackupEncryptionTampering(t *testing.T) {
+	enc := newTestBackupEncryption(t)
+	data := bytes.Repeat([]byte("vitess"), encryptionChunkSize)
+	encrypted := encryptForTest(t, enc, data)
+	sealedChunkSize := 4 + enc.aead.NonceSize() + encryptionChunkSize + enc.aead.Overhead()
+	headerSize := len(encryptionMagic)
+
+	testCases := []struct {
+		name      string
+		encrypted []byte
+		wantErr   string
+	}{{
+		name:      "flipped bit",
+		encrypted: func() []byte { b := bytes.Clone(encrypted); b[headerSize+100] ^= 1; return b }(),
+		wantErr:   "can't decrypt chunk 0",
+	}, {
+		name:      "truncated in a chunk",
+		encrypted: encrypted[:len(encrypted)-10],
+		wantErr:   errTruncatedEncryptedFile.Error(),
+	}, {
+		name:      "last chunk dropped",
+		encrypted: encrypted[:headerSize+sealedChunkSize],
+		wantErr:   errTruncatedEncryptedFile.Error(),
+	}, {
+		name: "chunks swapped",
+		encrypted: func() []byte {
+			b := bytes.Clone(encrypted[:headerSize])
+			b = append(b, encrypted[headerSize+sealedChunkSize:headerSize+2*sealedChunkSize]...)
+			b = append(b, encrypted[headerSize:headerSize+sealedChunkSize]...)
+			return append(b, encrypted[headerSize+2*sealedChunkSize:]...)
+		}(),
+		wantErr: "can't decrypt chunk 0",
+	}, {
+		name:      "trailing data",
+		encrypted: append(bytes.Clone(encrypted), 0),
+		wantErr:   "unexpected data after the last chunk",
+	}, {
+		name:      "not encrypted",
+		encrypted: data,
+		wantErr:   "backup file is not encrypted",
+	}}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := io.ReadAll(enc.newDecryptor(bytes.NewReader(tc.encrypted), testFileID))
+			require.ErrorContains(t, err, tc.wantErr)
+		})
+	}
+
+	// A file encrypted with another key can't be decrypted.
+	_, err := io.ReadAll(newTestBackupEncryption(t).newDecryptor(bytes.NewReader(encrypted), testFileID))
+	require.ErrorContains(t, err, "can't decrypt chunk 0")
+
+	// Nor can a file, with the same key, as another file of the backup, or
+	// as the same file of another backup.
+	for _, fileID := range [][]byte{
+		backupFileID("backup", &FileEntry{Base: backupData, Name: "other"}),
+		backupFileID("backup", &FileEntry{Base: backupInnodbDataHomeDir, Name: "file"}),
+		backupFileID("other", &FileEntry{Base: backupData, Name: "file"}),
+		dedupChunkID("file"),
+	} {
+		_, err := io.ReadAll(enc.newDecryptor(bytes.NewReader(encrypted), fileID))
+		require.ErrorContains(t, err, "can't decrypt chunk 0")
+	}
+}
+
+func TestBackupEncryptionDeterministic(t *testing.T) {
+	enc := newTestBackupEncryption(t)
+	data := make([]byte, 3*encryptionChunkSize+42)
+	_, err := rand.Read(data)
+	require.NoError(t, err)
+
+	// Encrypting the same data again gives the same bytes, for a resumed
+	// upload to find its committed parts unchanged.
+	encrypted := encryptForTest(t, enc, data)
+	assert.Equal(t, encrypted, encryptForTest(t, enc, data))
+
+	// Once the data changes, the nonces of the chunks change too, so that
+	// no nonce is reused for another plaintext.
+	nonceSize := enc.aead.NonceSize()
+	sealedChunkSize := 4 + nonceSize + encryptionChunkSize + enc.aead.Overhead()
+	nonce := func(encrypted []byte, index int) []byte {
+		offset := len(encryptionMagic) + index*sealedChunkSize + 4
+		return encrypted[offset : offset+nonceSize]
+	}
+	changed := bytes.Clone(data)
+	changed[encryptionChunkSize+1] ^= 1
+	reencrypted := encryptForTest(t, enc, changed)
+	assert.Equal(t, nonce(encrypted, 0), nonce(reencrypted, 0))
+	assert.NotEqual(t, nonce(encrypted, 1), nonce(reencrypted, 1))
+	assert.Equal(t, nonce(encrypted, 2), nonce(reencrypted, 2))
+
+	// Equal chunks at different indexes don't share a nonce.
+	encrypted = encryptForTest(t, enc, bytes.Repeat([]byte("vitess"), encryptionChunkSize))
+	assert.NotEqual(t, nonce(encrypted, 0), nonce(encrypted, 1))
+}
+
+func TestBackupEncryptionWithCompression(t *testing.T) {
+	enc := newTestBackupEncryption(t)
+	data := bytes.Repeat([]byte("compress me, then encrypt me. "), 10000)
+
+	// Same order as the builtin backup engine: the compressor writes to the
+	// encryptor, which writes to the backup storage.
+	var buf bytes.Buffer
+	encryptor := enc.newEncryptor(&buf, testFileID)
+	compressor, err := newBuiltinCompressor(ZstdCompressor, encryptor, logutil.NewMemoryLogger())
+	require.NoError(t, err)
+	_, err = compressor.Write(data)
+	require.NoError(t, err)
+	require.NoError(t, compressor.Close())
+	require.NoError(t, encryptor.Close())
+	assert.Less(t, buf.Len(), len(data))
+
+	decompressor, err := newBuiltinDecompressor(ZstdCompressor, enc.newDecryptor(&buf, testFileID), logutil.NewMemoryLogger())
+	require.NoError(t, err)
+	defer decompressor.Close()
+	decrypted, err := io.ReadAll(decompressor)
+	require.NoError(t, err)
+	assert.Equal(t, data, decrypted)
+}
+
+func TestFileKeyProvider(t *testing.T) {
+	ctx := context.Background()
+	oldKeyFile := builtinBackupEncryptionKeyFile
+	defer func() {
+		builtinBackupEncryptionKeyFile = oldKeyFile
+	}()
+
+	current := bytes.Repeat([]byte{1}, encryptionKeySize)
+	previous := bytes.Repeat([]byte{2}, encryptionKeySize)
+	builtinBackupEncryptionKeyFile = path.Join(t.TempDir(), "keys")
+	content := fmt.Sprintf("# backup keys\n\nkey-2 %s\nkey-1  %s\n", hex.EncodeToString(current), hex.EncodeToString(previous))
+	require.NoError(t, os.WriteFile(builtinBackupEncryptionKeyFile, []byte(content), 0600))
+
+	provider := BackupKeyProviders[FileBackupKeyProvider]
+	keyID, key, err := provider.CurrentKey(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, "key-2", keyID)
+	assert.Equal(t, current, key)
+
+	key, err = provider.Key(ctx, "key-1")
+	require.NoError(t, err)
+	assert.Equal(t, previous, key)
+
+	_, err = provider.Key(ctx, "key-0")
+	require.ErrorContains(t, err, `no key "key-0"`)
+
+	testCases := []struct {
+		content string
+		wantErr string
+	}{{
+		content: "",
+		wantErr: "no backup encryption key",
+	}, {
+		content: "key-1\n",
+		wantErr: ":1: expected '<key id> <hex encoded key>'",
+	}, {
+		content: "# comment\nkey-1 zz\n",
+		wantErr: ":2: invalid key",
+	}, {
+		content: "key-1 0102\n",
+		wantErr: ":1: key must be 32 bytes long, got 2",
+	}}
+	for _, tc := range testCases {
+		require.NoError(t, os.WriteFile(builtinBackupEncryptionKeyFile, []byte(tc.content), 0600))
+		_, _, err := provider.CurrentKey(ctx)
+		require.ErrorContains(t, err, tc.wantErr)
+	}
+
+	builtinBackupEncryptionKeyFile = ""
+	_, _, err = provider.CurrentKey(ctx)
+	require.ErrorContains(t, err, "--builtinbackup-encryption-key-file is required")
+}
+
+func TestNewBackupAndRestoreEncryption(t *testing.T) {
+	ctx := context.Background()
+	oldKeyProvider, oldKeyFile := builtinBackupEncryptionKeyProvider, builtinBackupEncryptionKeyFile
+	defer func() {
+		builtinBackupEncryptionKeyProvider, builtinBackupEncryptionKeyFile = oldKeyProvider, oldKeyFile
+	}()
+
+	// Backups are not encrypted by default.
+	builtinBackupEncryptionKeyProvider = ""
+	enc, err := newBackupEncryption(ctx)
+	require.NoError(t, err)
+	assert.Nil(t, enc)
+	enc, err = newRestoreEncryption(ctx, builtinBackupManifest{})
+	require.NoError(t, err)
+	assert.Nil(t, enc)
+
+	bm := builtinBackupManifest{Encryption: AES256GCMEncryption, EncryptionKeyID: "key-1"}
+	_, err = newRestoreEncryption(ctx, bm)
+	require.ErrorContains(t, err, `backup is encrypted with key "key-1", but --builtinbackup-encryption-key-provider is not set`)
+
+	builtinBackupEncryptionKeyProvider = "vault"
+	_, err = newBackupEncryption(ctx)
+	require.ErrorContains(t, err, `no registered backup encryption key provider "vault"`)
+
+	builtinBackupEncryptionKeyProvider = FileBackupKeyProvider
+	builtinBackupEncryptionKeyFile = path.Join(t.TempDir(), "keys")
+	require.NoError(t, os.WriteFile(builtinBackupEncryptionKeyFile, []byte("key-1 "+hex.EncodeToString(bytes.Repeat([]byte{1}, encryptionKeySize))), 0600))
+
+	backupEnc, err := newBackupEncryption(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, "key-1", backupEnc.keyID)
+	restoreEnc, err := newRestoreEncryption(ctx, bm)
+	require.NoError(t, err)
+
+	data := []byte("hello, world!")
+	decrypted, err := io.ReadAll(restoreEnc.newDecryptor(bytes.NewReader(encryptForTest(t, backupEnc, data)), testFileID))
+	require.NoError(t, err)
+	assert.Equal(t, data, decrypted)
+
+	bm.Encryption = "rot13"
+	_, err = newRestoreEncryption(ctx, bm)
+	require.ErrorContains(t, err, `unsupported backup encryption "rot13"`)
+}
@@ -0,0 +1,109 @@
+/*
+Copyright 2026 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package filebackupstorage
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"os"
+	"path"
+	"syscall"
+
+	"vitess.io/vitess/go/os2"
+	"vitess.io/vitess/go/vt/mysqlctl/backupstorage"
+)
+
+// catalogFileName is the name of the file holding the catalog of a directory.
+const catalogFileName = "CATALOG"
+
+// ReadCatalog is part of the backupstorage.CatalogStorage interface. The
+// version of a catalog is the hash of its content.
+func (fbs *FileBackupStorage) ReadCatalog(ctx context.Context, dir string) ([]byte, string, error) {
+	data, err := os.ReadFile(catalogPath(dir))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, "", nil
+		}
+		return nil, "", err
+	}
+	return data, catalogVersion(data), nil
+}
+
+// WriteCatalog is part of the backupstorage.CatalogStorage interface.
+func (fbs *FileBackupStorage) WriteCatalog(ctx context.Context, dir string, data []byte, version string) error {
+	p := catalogPath(dir)
+	catalogDir := path.Dir(p)
+	if err := os2.MkdirAll(catalogDir); err != nil {
+		return err
+	}
+
+	// The lock serializes the writers, so that the catalog is not replaced
+	// between the check of its version and its replacement.
+	lock, err := os.OpenFile(p+".lock", os.O_CREATE|os.O_RDWR, os2.PermFile)
+	if err != nil {
+		return err
+	}
+	defer lock.Close()
+	if err := syscall.Flock(int(lock.Fd()), syscall.LOCK_EX); err != nil {
+		return err
+	}
+	defer syscall.Flock(int(lock.Fd()), syscall.LOCK_UN) // nolint:errcheck
+
+	current, err := os.ReadFile(p)
+	switch {
+	case os.IsNotExist(err):
+		if version != "" {
+			return backupstorage.ErrCatalogConflict
+		}
+	case err != nil:
+		return err
+	case catalogVersion(current) != version:
+		return backupstorage.ErrCatalogConflict
+	}
+
+	// Write the catalog to a temporary file first, and rename it once
+	// complete, so that the catalog is never seen partially written.
+	f, err := os.CreateTemp(catalogDir, catalogFileName+".*.tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(f.Name())
+	_, err = f.Write(data)
+	if err == nil {
+		err = f.Chmod(os2.PermFile)
+	}
+	if err == nil {
+		err = f.Sync()
+	}
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		return err
+	}
+	return os.Rename(f.Name(), p)
+}
+
+func catalogPath(dir string) string {
+	return path.Join(FileBackupStorageRoot, backupstorage.CatalogDir, dir, catalogFileName)
+}
+
+func catalogVersion(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
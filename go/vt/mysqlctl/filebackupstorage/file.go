@@ -24,6 +24,8 @@ import (
 	"io"
 	"os"
 	"path"
+	"strings"
+	"time"
 
 	"github.com/spf13/pflag"
 
@@ -41,11 +43,16 @@ var (
 	// Exported for test purposes.
 	FileBackupStorageRoot string
 
+	// resumableUploads makes the files larger than a part be written in
+	// checkpointed parts.
+	resumableUploads bool
+
 	defaultFileBackupStorage = newFileBackupStorage(backupstorage.NoParams())
 )
 
 func registerFlags(fs *pflag.FlagSet) {
 	fs.StringVar(&FileBackupStorageRoot, "file_backup_storage_root", "", "Root directory for the file backup storage.")
+	fs.BoolVar(&resumableUploads, "file-backup-storage-resumable-uploads", resumableUploads, "write the files larger than a part one part at a time, checkpointing the written parts in a sidecar file, so that retrying a failed write resumes from the last written part.")
 }
 
 func init() {
@@ -97,11 +104,16 @@ func (fbh *FileBackupHandle) AddFile(ctx context.Context, filename string, files
 		return nil, fmt.Errorf("AddFile cannot be called on read-only backup")
 	}
 	p := path.Join(FileBackupStorageRoot, fbh.dir, fbh.name, filename)
+	stat := fbh.fbs.params.Stats.Scope(stats.Operation("File:Write"))
+	if resumableUploads && filesize > uploadPartSize {
+		// Large files are written in checkpointed parts, so that a retry
+		// resumes from the last part written.
+		return newResumableFile(ctx, p, uploadPartSize, stat)
+	}
 	f, err := os2.Create(p)
 	if err != nil {
 		return nil, err
 	}
-	stat := fbh.fbs.params.Stats.Scope(stats.Operation("File:Write"))
 	return ioutil.NewMeteredWriteCloser(f, stat.TimedIncrementBytes), nil
 }
 
@@ -128,6 +140,70 @@ func (fbh *FileBackupHandle) ReadFile(ctx context.Context, filename string) (io.
 	}
 	p := path.Join(FileBackupStorageRoot, fbh.dir, fbh.name, filename)
 	f, err := os.Open(p)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, fmt.Errorf("%w: %w", backupstorage.ErrFileNotFound, err)
+		}
+		return nil, err
+	}
+	stat := fbh.fbs.params.Stats.Scope(stats.Operation("File:Read"))
+	return ioutil.NewMeteredReadCloser(f, stat.TimedIncrementBytes), nil
+}
+
+// HasChunk is part of the backupstorage.ChunkHandle interface
+func (fbh *FileBackupHandle) HasChunk(ctx context.Context, name string) (bool, error) {
+	if fbh.readOnly {
+		return false, fmt.Errorf("HasChunk cannot be called on read-only backup")
+	}
+	now := time.Now()
+	err := os.Chtimes(chunkPath(fbh.dir, name), now, now)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return false, nil
+		}
+		return false, err
+	}
+	return true, nil
+}
+
+// AddChunk is part of the backupstorage.ChunkHandle interface
+func (fbh *FileBackupHandle) AddChunk(ctx context.Context, name string, data []byte) error {
+	if fbh.readOnly {
+		return fmt.Errorf("AddChunk cannot be called on read-only backup")
+	}
+	p := chunkPath(fbh.dir, name)
+	dir := path.Dir(p)
+	if err := os2.MkdirAll(dir); err != nil {
+		return err
+	}
+
+	// Write the chunk to a temporary file first, and rename it once complete,
+	// so that a chunk is never seen partially written.
+	f, err := os.CreateTemp(dir, name+".*.tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(f.Name())
+	stat := fbh.fbs.params.Stats.Scope(stats.Operation("File:Write"))
+	_, err = ioutil.NewMeteredWriter(f, stat.TimedIncrementBytes).Write(data)
+	if err == nil {
+		err = f.Chmod(os2.PermFile)
+	}
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		return err
+	}
+	return os.Rename(f.Name(), p)
+}
+
+// ReadChunk is part of the backupstorage.ChunkHandle interface
+func (fbh *FileBackupHandle) ReadChunk(ctx context.Context, name string) (io.ReadCloser, error) {
+	if !fbh.readOnly {
+		return nil, fmt.Errorf("ReadChunk cannot be called on read-write backup")
+	}
+	f, err := os.Open(chunkPath(fbh.dir, name))
 	if err != nil {
 		return nil, err
 	}
@@ -192,6 +268,65 @@ func (fbs *FileBackupStorage) RemoveBackup(ctx context.Context, dir, name string
 	return os.RemoveAll(p)
 }
 
+// ReopenBackup is part of the backupstorage.ReopenStorage interface
+func (fbs *FileBackupStorage) ReopenBackup(ctx context.Context, dir, name string) (backupstorage.BackupHandle, error) {
+	if _, err := os.Stat(path.Join(FileBackupStorageRoot, dir, name)); err != nil {
+		return nil, err
+	}
+	return NewBackupHandle(fbs, dir, name, false /*readOnly*/), nil
+}
+
+// ListChunks is part of the backupstorage.ChunkStorage interface
+func (fbs *FileBackupStorage) ListChunks(ctx context.Context, dir string) ([]backupstorage.ChunkInfo, error) {
+	fi, err := os.ReadDir(path.Join(FileBackupStorageRoot, backupstorage.ChunksDir, dir))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	result := make([]backupstorage.ChunkInfo, 0, len(fi))
+	for _, entry := range fi {
+		// Skip the temporary files of the chunks being added.
+		if entry.IsDir() || strings.Contains(entry.Name(), ".") {
+			continue
+		}
+		info, err := entry.Info()
+		if err != nil {
+			if os.IsNotExist(err) {
+				continue
+			}
+			return nil, err
+		}
+		result = append(result, backupstorage.ChunkInfo{Name: entry.Name(), ModTime: info.ModTime()})
+	}
+	return result, nil
+}
+
+// RemoveChunk is part of the backupstorage.ChunkStorage interface
+func (fbs *FileBackupStorage) RemoveChunk(ctx context.Context, dir string, chunk backupstorage.ChunkInfo) (bool, error) {
+	// Move the chunk out of the way first: from then on, HasChunk does not
+	// find it and a backup adds it again. Then check that HasChunk did not
+	// find it before it was moved, and if it did, move it back.
+	p := chunkPath(dir, chunk.Name)
+	removing := p + ".removing"
+	if err := os.Rename(p, removing); err != nil {
+		if os.IsNotExist(err) {
+			return true, nil
+		}
+		return false, err
+	}
+	info, err := os.Stat(removing)
+	if err != nil || info.ModTime().After(chunk.ModTime) {
+		if rerr := os.Rename(removing, p); rerr != nil {
+			return false, rerr
+		}
+		return false, err
+	}
+	return true, os.Remove(removing)
+}
+
 // Close implements BackupStorage.
 func (fbs *FileBackupStorage) Close() error {
 	return nil
@@ -201,6 +336,10 @@ func (fbs *FileBackupStorage) WithParams(params backupstorage.Params) backupstor
 	return &FileBackupStorage{params}
 }
 
+func chunkPath(dir, name string) string {
+	return path.Join(FileBackupStorageRoot, backupstorage.ChunksDir, dir, name)
+}
+
 func init() {
 	backupstorage.BackupStorageMap["file"] = defaultFileBackupStorage
 }
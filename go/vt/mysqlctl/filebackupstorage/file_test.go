@@ -18,8 +18,11 @@ package filebackupstorage
 
 import (
 	"context"
+	"errors"
 	"io"
+	"os"
 	"testing"
+	"time"
 
 	"vitess.io/vitess/go/vt/mysqlctl/backupstorage"
 )
@@ -191,4 +194,213 @@ func TestFileContents(t *testing.T) {
 	if err := rc.Close(); err != nil {
 		t.Fatalf("rc.Close failed: %v", err)
 	}
+	if _, err := bhs[0].ReadFile(ctx, "missing"); !errors.Is(err, backupstorage.ErrFileNotFound) {
+		t.Fatalf("ReadFile of a missing file returned wrong error: %v", err)
+	}
+}
+
+func TestChunks(t *testing.T) {
+	fbs := setupFileBackupStorage(t)
+	ctx := context.Background()
+
+	dir := "keyspace/shard"
+	name := "cell-0001-2015-01-14-10-00-00"
+	chunk := "0123456789abcdef"
+	contents := "contents of the chunk"
+
+	// add a chunk to a new backup
+	bh, err := fbs.StartBackup(ctx, dir, name)
+	if err != nil {
+		t.Fatalf("fbs.StartBackup failed: %v", err)
+	}
+	ch := bh.(backupstorage.ChunkHandle)
+	if ok, err := ch.HasChunk(ctx, chunk); err != nil || ok {
+		t.Fatalf("HasChunk on missing chunk returned wrong result: %v %v", ok, err)
+	}
+	if err := ch.AddChunk(ctx, chunk, []byte(contents)); err != nil {
+		t.Fatalf("AddChunk failed: %v", err)
+	}
+	// finding the chunk refreshes its modification time
+	old := time.Now().Add(-48 * time.Hour)
+	if err := os.Chtimes(chunkPath(dir, chunk), old, old); err != nil {
+		t.Fatalf("os.Chtimes failed: %v", err)
+	}
+	if ok, err := ch.HasChunk(ctx, chunk); err != nil || !ok {
+		t.Fatalf("HasChunk on added chunk returned wrong result: %v %v", ok, err)
+	}
+	if err := bh.EndBackup(ctx); err != nil {
+		t.Fatalf("bh.EndBackup failed: %v", err)
+	}
+
+	// the chunks don't show up as a backup
+	bhs, err := fbs.ListBackups(ctx, dir)
+	if err != nil || len(bhs) != 1 {
+		t.Fatalf("ListBackups returned wrong return: %v %v", err, bhs)
+	}
+	rc, err := bhs[0].(backupstorage.ChunkHandle).ReadChunk(ctx, chunk)
+	if err != nil {
+		t.Fatalf("ReadChunk failed: %v", err)
+	}
+	data, err := io.ReadAll(rc)
+	if err != nil || string(data) != contents {
+		t.Fatalf("ReadChunk returned wrong result: %v %q", err, data)
+	}
+	if err := rc.Close(); err != nil {
+		t.Fatalf("rc.Close failed: %v", err)
+	}
+
+	// the chunks survive the removal of the backup, until removed
+	if err := fbs.RemoveBackup(ctx, dir, name); err != nil {
+		t.Fatalf("RemoveBackup failed: %v", err)
+	}
+	cs := fbs.(backupstorage.ChunkStorage)
+	chunks, err := cs.ListChunks(ctx, dir)
+	if err != nil || len(chunks) != 1 || chunks[0].Name != chunk || time.Since(chunks[0].ModTime) > time.Hour {
+		t.Fatalf("ListChunks returned wrong result: %v %v", err, chunks)
+	}
+	// a chunk found by a backup since it was listed is not removed
+	listed := chunks[0]
+	listed.ModTime = listed.ModTime.Add(-time.Minute)
+	if removed, err := cs.RemoveChunk(ctx, dir, listed); err != nil || removed {
+		t.Fatalf("RemoveChunk of a modified chunk returned wrong result: %v %v", removed, err)
+	}
+	if removed, err := cs.RemoveChunk(ctx, dir, chunks[0]); err != nil || !removed {
+		t.Fatalf("RemoveChunk returned wrong result: %v %v", removed, err)
+	}
+	if removed, err := cs.RemoveChunk(ctx, dir, chunks[0]); err != nil || !removed {
+		t.Fatalf("RemoveChunk of a removed chunk returned wrong result: %v %v", removed, err)
+	}
+	chunks, err = cs.ListChunks(ctx, dir)
+	if err != nil || len(chunks) != 0 {
+		t.Fatalf("ListChunks after RemoveChunk returned wrong result: %v %v", err, chunks)
+	}
+}
+
+func TestCatalog(t *testing.T) {
+	fbs := setupFileBackupStorage(t)
+	ctx := context.Background()
+	cs := fbs.(backupstorage.CatalogStorage)
+	dir := "keyspace/shard"
+
+	// there is no catalog yet
+	data, version, err := cs.ReadCatalog(ctx, dir)
+	if err != nil || data != nil || version != "" {
+		t.Fatalf("ReadCatalog of a missing catalog returned wrong result: %v %q %q", err, data, version)
+	}
+	if err := cs.WriteCatalog(ctx, dir, []byte("v1"), "some-version"); err != backupstorage.ErrCatalogConflict {
+		t.Fatalf("WriteCatalog with a version of a missing catalog returned wrong result: %v", err)
+	}
+	if err := cs.WriteCatalog(ctx, dir, []byte("v1"), ""); err != nil {
+		t.Fatalf("WriteCatalog failed: %v", err)
+	}
+
+	// the catalog doesn't show up as a backup
+	bhs, err := fbs.ListBackups(ctx, dir)
+	if err != nil || len(bhs) != 0 {
+		t.Fatalf("ListBackups returned wrong return: %v %v", err, bhs)
+	}
+
+	// only the writer that read the current version replaces the catalog
+	data, version, err = cs.ReadCatalog(ctx, dir)
+	if err != nil || string(data) != "v1" || version == "" {
+		t.Fatalf("ReadCatalog returned wrong result: %v %q %q", err, data, version)
+	}
+	if err := cs.WriteCatalog(ctx, dir, []byte("v2"), version); err != nil {
+		t.Fatalf("WriteCatalog failed: %v", err)
+	}
+	if err := cs.WriteCatalog(ctx, dir, []byte("v2 bis"), version); err != backupstorage.ErrCatalogConflict {
+		t.Fatalf("WriteCatalog with a stale version returned wrong result: %v", err)
+	}
+	if err := cs.WriteCatalog(ctx, dir, []byte("v2 bis"), ""); err != backupstorage.ErrCatalogConflict {
+		t.Fatalf("WriteCatalog of an existing catalog with no version returned wrong result: %v", err)
+	}
+	data, _, err = cs.ReadCatalog(ctx, dir)
+	if err != nil || string(data) != "v2" {
+		t.Fatalf("ReadCatalog returned wrong result: %v %q", err, data)
+	}
+}
+
+func TestResumableFile(t *testing.T) {
+	fbs := setupFileBackupStorage(t)
+	ctx := context.Background()
+	defer func(partSize int64) { uploadPartSize = partSize }(uploadPartSize)
+	uploadPartSize = 4
+	defer func() { resumableUploads = false }()
+
+	dir := "keyspace/shard"
+	name := "cell-0001-2015-01-14-10-00-00"
+	filename := "0"
+	contents := "0123456789abcdefghij"
+	bh, err := fbs.StartBackup(ctx, dir, name)
+	if err != nil {
+		t.Fatalf("fbs.StartBackup failed: %v", err)
+	}
+
+	// files are not resumable unless enabled
+	wc, err := bh.AddFile(ctx, filename, int64(len(contents)))
+	if err != nil {
+		t.Fatalf("bh.AddFile failed: %v", err)
+	}
+	if _, ok := wc.(backupstorage.ResumableWriter); ok {
+		t.Fatalf("AddFile returned a ResumableWriter without --file-backup-storage-resumable-uploads")
+	}
+	if err := wc.Close(); err != nil {
+		t.Fatalf("Close failed: %v", err)
+	}
+	resumableUploads = true
+
+	// the first attempt fails after writing 2 parts and a half
+	wc, err = bh.AddFile(ctx, filename, int64(len(contents)))
+	if err != nil {
+		t.Fatalf("bh.AddFile failed: %v", err)
+	}
+	rw, ok := wc.(backupstorage.ResumableWriter)
+	if !ok {
+		t.Fatalf("AddFile of a large file did not return a ResumableWriter: %T", wc)
+	}
+	if _, err := rw.Write([]byte(contents[:10])); err != nil {
+		t.Fatalf("Write failed: %v", err)
+	}
+	if err := rw.CloseWithError(io.ErrUnexpectedEOF); err != nil {
+		t.Fatalf("CloseWithError failed: %v", err)
+	}
+
+	// the retry resumes from the 3rd part
+	wc, err = bh.AddFile(ctx, filename, int64(len(contents)))
+	if err != nil {
+		t.Fatalf("bh.AddFile failed: %v", err)
+	}
+	rw = wc.(backupstorage.ResumableWriter)
+	if _, err := rw.Write([]byte(contents)); err != nil {
+		t.Fatalf("Write failed: %v", err)
+	}
+	if err := rw.Close(); err != nil {
+		t.Fatalf("Close failed: %v", err)
+	}
+	if resumed := rw.ResumedBytes(); resumed != 8 {
+		t.Fatalf("ResumedBytes returned wrong result: %v", resumed)
+	}
+	if err := bh.EndBackup(ctx); err != nil {
+		t.Fatalf("bh.EndBackup failed: %v", err)
+	}
+
+	// the file is complete, and its checkpoint is removed
+	bhs, err := fbs.ListBackups(ctx, dir)
+	if err != nil || len(bhs) != 1 {
+		t.Fatalf("ListBackups returned wrong return: %v %v", err, bhs)
+	}
+	rc, err := bhs[0].ReadFile(ctx, filename)
+	if err != nil {
+		t.Fatalf("ReadFile failed: %v", err)
+	}
+	data, err := io.ReadAll(rc)
+	if err != nil || string(data) != contents {
+		t.Fatalf("ReadFile returned wrong result: %v %q", err, data)
+	}
+	if err := rc.Close(); err != nil {
+		t.Fatalf("rc.Close failed: %v", err)
+	}
+	if _, err := bhs[0].ReadFile(ctx, filename+backupstorage.UploadCheckpointSuffix); err == nil {
+		t.Fatalf("the upload checkpoint was not removed")
+	}
 }
@@ -0,0 +1,135 @@
+/*
+Copyright 2026 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package filebackupstorage
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path"
+	"time"
+
+	"vitess.io/vitess/go/os2"
+	stats "vitess.io/vitess/go/vt/mysqlctl/backupstats"
+	"vitess.io/vitess/go/vt/mysqlctl/backupstorage"
+)
+
+// uploadPartSize is the size of the parts large files are written in.
+var uploadPartSize int64 = 64 * 1024 * 1024
+
+// filePartUploader implements backupstorage.PartUploader for a file: parts
+// are written in place, and synced before the checkpoint is saved next to
+// the file.
+type filePartUploader struct {
+	f              *os.File
+	checkpointPath string
+	partSize       int64
+	stat           stats.Stats
+}
+
+// newResumableFile opens a file to be written in parts. If the checkpoint of
+// a previous attempt to write the file exists, the file is resumed from it.
+func newResumableFile(ctx context.Context, p string, partSize int64, stat stats.Stats) (backupstorage.ResumableWriter, error) {
+	checkpointPath := p + backupstorage.UploadCheckpointSuffix
+	checkpoint := &backupstorage.UploadCheckpoint{PartSize: partSize}
+	flags := os.O_RDWR | os.O_CREATE | os.O_TRUNC
+	if data, err := os.ReadFile(checkpointPath); err == nil {
+		if previous, err := backupstorage.ParseUploadCheckpoint(data); err == nil && previous.PartSize == partSize {
+			checkpoint = previous
+			flags &^= os.O_TRUNC
+		}
+	} else if !os.IsNotExist(err) {
+		return nil, err
+	}
+
+	f, err := os.OpenFile(p, flags, os2.PermFile)
+	if err != nil {
+		return nil, err
+	}
+	return backupstorage.NewResumableWriter(ctx, &filePartUploader{
+		f:              f,
+		checkpointPath: checkpointPath,
+		partSize:       partSize,
+		stat:           stat,
+	}, checkpoint), nil
+}
+
+// UploadPart is part of the backupstorage.PartUploader interface.
+func (u *filePartUploader) UploadPart(ctx context.Context, number int, data []byte) (string, error) {
+	start := time.Now()
+	if _, err := u.f.WriteAt(data, int64(number-1)*u.partSize); err != nil {
+		return "", err
+	}
+	if err := u.f.Sync(); err != nil {
+		return "", err
+	}
+	u.stat.TimedIncrementBytes(len(data), time.Since(start))
+	return "", nil
+}
+
+// SaveCheckpoint is part of the backupstorage.PartUploader interface.
+func (u *filePartUploader) SaveCheckpoint(ctx context.Context, checkpoint *backupstorage.UploadCheckpoint) error {
+	data, err := backupstorage.MarshalUploadCheckpoint(checkpoint)
+	if err != nil {
+		return err
+	}
+	// Replace the checkpoint atomically, so that it is never seen partially written.
+	f, err := os.CreateTemp(path.Dir(u.checkpointPath), path.Base(u.checkpointPath)+".*.tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(f.Name())
+	_, err = f.Write(data)
+	if err == nil {
+		err = f.Sync()
+	}
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		return err
+	}
+	return os.Rename(f.Name(), u.checkpointPath)
+}
+
+// Complete is part of the backupstorage.PartUploader interface.
+func (u *filePartUploader) Complete(ctx context.Context, checkpoint *backupstorage.UploadCheckpoint) error {
+	var size int64
+	for _, part := range checkpoint.Parts {
+		size += part.Size
+	}
+	// A previous attempt may have written a longer file.
+	err := u.f.Truncate(size)
+	if err == nil {
+		err = u.f.Sync()
+	}
+	if cerr := u.f.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		return err
+	}
+	if err := os.Remove(u.checkpointPath); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	return nil
+}
+
+// Release is part of the backupstorage.PartUploader interface.
+func (u *filePartUploader) Release() error {
+	return u.f.Close()
+}
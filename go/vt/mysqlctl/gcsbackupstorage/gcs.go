@@ -20,19 +20,23 @@ package gcsbackupstorage
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"io"
+	"net/http"
 	"sort"
 	"strings"
 	"sync"
+	"time"
 
 	"cloud.google.com/go/storage"
 	"github.com/spf13/pflag"
 	"golang.org/x/oauth2/google"
+	"google.golang.org/api/googleapi"
 	"google.golang.org/api/iterator"
 	"google.golang.org/api/option"
 
-	"vitess.io/vitess/go/vt/mysqlctl/errors"
+	errorsbackup "vitess.io/vitess/go/vt/mysqlctl/errors"
 	"vitess.io/vitess/go/vt/utils"
 
 	"vitess.io/vitess/go/trace"
@@ -67,7 +71,7 @@ type GCSBackupHandle struct {
 	dir      string
 	name     string
 	readOnly bool
-	errors.PerFileErrorRecorder
+	errorsbackup.PerFileErrorRecorder
 }
 
 // Directory implements BackupHandle.
@@ -111,6 +115,50 @@ func (bh *GCSBackupHandle) ReadFile(ctx context.Context, filename string) (io.Re
 		return nil, fmt.Errorf("ReadFile cannot be called on read-write backup")
 	}
 	object := objName(bh.dir, bh.name, filename)
+	r, err := bh.client.Bucket(bucket).Object(object).NewReader(ctx)
+	if errors.Is(err, storage.ErrObjectNotExist) {
+		return nil, fmt.Errorf("%w: %w", backupstorage.ErrFileNotFound, err)
+	}
+	return r, err
+}
+
+// HasChunk implements backupstorage.ChunkHandle.
+func (bh *GCSBackupHandle) HasChunk(ctx context.Context, name string) (bool, error) {
+	// Updating the metadata of the chunk refreshes its modification time.
+	object := objName(backupstorage.ChunksDir, bh.dir, name)
+	attrs := storage.ObjectAttrsToUpdate{
+		Metadata: map[string]string{"last-used": time.Now().UTC().Format(time.RFC3339)},
+	}
+	if _, err := bh.client.Bucket(bucket).Object(object).Update(ctx, attrs); err != nil {
+		if errors.Is(err, storage.ErrObjectNotExist) {
+			return false, nil
+		}
+		return false, err
+	}
+	return true, nil
+}
+
+// AddChunk implements backupstorage.ChunkHandle.
+func (bh *GCSBackupHandle) AddChunk(ctx context.Context, name string, data []byte) error {
+	if bh.readOnly {
+		return fmt.Errorf("AddChunk cannot be called on read-only backup")
+	}
+	// The object is only created when the writer is closed successfully.
+	object := objName(backupstorage.ChunksDir, bh.dir, name)
+	w := bh.client.Bucket(bucket).Object(object).NewWriter(ctx)
+	if _, err := w.Write(data); err != nil {
+		w.Close()
+		return err
+	}
+	return w.Close()
+}
+
+// ReadChunk implements backupstorage.ChunkHandle.
+func (bh *GCSBackupHandle) ReadChunk(ctx context.Context, name string) (io.ReadCloser, error) {
+	if !bh.readOnly {
+		return nil, fmt.Errorf("ReadChunk cannot be called on read-write backup")
+	}
+	object := objName(backupstorage.ChunksDir, bh.dir, name)
 	return bh.client.Bucket(bucket).Object(object).NewReader(ctx)
 }
 
@@ -223,6 +271,65 @@ func (bs *GCSBackupStorage) RemoveBackup(ctx context.Context, dir, name string)
 	return nil
 }
 
+// ReopenBackup implements backupstorage.ReopenStorage.
+func (bs *GCSBackupStorage) ReopenBackup(ctx context.Context, dir, name string) (backupstorage.BackupHandle, error) {
+	return bs.StartBackup(ctx, dir, name)
+}
+
+// ListChunks implements backupstorage.ChunkStorage.
+func (bs *GCSBackupStorage) ListChunks(ctx context.Context, dir string) ([]backupstorage.ChunkInfo, error) {
+	c, err := bs.client(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	searchPrefix := objName(backupstorage.ChunksDir, dir, "" /* include trailing slash */)
+	var chunks []backupstorage.ChunkInfo
+	it := c.Bucket(bucket).Objects(ctx, &storage.Query{Prefix: searchPrefix})
+	for {
+		obj, err := it.Next()
+		if err == iterator.Done {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		chunks = append(chunks, backupstorage.ChunkInfo{
+			Name:    strings.TrimPrefix(obj.Name, searchPrefix),
+			ModTime: obj.Updated,
+			Version: fmt.Sprintf("%d/%d", obj.Generation, obj.Metageneration),
+		})
+	}
+	return chunks, nil
+}
+
+// RemoveChunk implements backupstorage.ChunkStorage. Adding a chunk changes
+// its generation and HasChunk its metageneration, so the chunk is only
+// deleted if both are still the ones it was listed with.
+func (bs *GCSBackupStorage) RemoveChunk(ctx context.Context, dir string, chunk backupstorage.ChunkInfo) (bool, error) {
+	c, err := bs.client(ctx)
+	if err != nil {
+		return false, err
+	}
+
+	var conds storage.Conditions
+	if _, err := fmt.Sscanf(chunk.Version, "%d/%d", &conds.GenerationMatch, &conds.MetagenerationMatch); err != nil {
+		return false, fmt.Errorf("invalid version %q of chunk %q: %v", chunk.Version, chunk.Name, err)
+	}
+	object := objName(backupstorage.ChunksDir, dir, chunk.Name)
+	if err := c.Bucket(bucket).Object(object).If(conds).Delete(ctx); err != nil {
+		var apiErr *googleapi.Error
+		switch {
+		case errors.Is(err, storage.ErrObjectNotExist):
+			return true, nil
+		case errors.As(err, &apiErr) && apiErr.Code == http.StatusPreconditionFailed:
+			return false, nil
+		}
+		return false, fmt.Errorf("unable to delete %q from bucket %q: %v", object, bucket, err)
+	}
+	return true, nil
+}
+
 // Close implements BackupStorage.
 func (bs *GCSBackupStorage) Close() error {
 	bs.mu.Lock()
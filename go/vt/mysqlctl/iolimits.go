@@ -0,0 +1,255 @@
+/*
+Copyright 2026 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package mysqlctl
+
+import (
+	"context"
+	"io"
+	"strconv"
+	"sync/atomic"
+	"time"
+
+	"golang.org/x/time/rate"
+
+	"vitess.io/vitess/go/stats"
+)
+
+// ioRateLimitBurst is the largest number of bytes read or written at once
+// under a rate limit.
+const ioRateLimitBurst = 256 * 1024
+
+// IOThrottler holds the I/O of the builtin backups and restores back while
+// the host is under pressure, e.g. because its replication lag or its load
+// is too high.
+type IOThrottler interface {
+	// Throttle blocks until the I/O may proceed, or until ctx is done.
+	Throttle(ctx context.Context)
+}
+
+// ioRateLimit is a number of bytes per second, that can be set with a flag
+// and changed at any time. Zero means no limit.
+type ioRateLimit struct {
+	atomic.Int64
+}
+
+// Set is part of the pflag.Value interface.
+func (l *ioRateLimit) Set(s string) error {
+	v, err := strconv.ParseInt(s, 10, 64)
+	if err != nil {
+		return err
+	}
+	l.Store(max(v, 0))
+	return nil
+}
+
+// String is part of the pflag.Value interface.
+func (l *ioRateLimit) String() string {
+	return strconv.FormatInt(l.Load(), 10)
+}
+
+// Type is part of the pflag.Value interface.
+func (l *ioRateLimit) Type() string {
+	return "int"
+}
+
+// apply makes the limiter enforce the current limit.
+func (l *ioRateLimit) apply(limiter *rate.Limiter) {
+	limit := rate.Inf
+	if v := l.Load(); v > 0 {
+		limit = rate.Limit(v)
+	}
+	if limiter.Limit() != limit {
+		limiter.SetLimit(limit)
+	}
+}
+
+var (
+	builtinBackupReadRateLimit         ioRateLimit
+	builtinBackupWriteRateLimit        ioRateLimit
+	builtinBackupPerFileReadRateLimit  ioRateLimit
+	builtinBackupPerFileWriteRateLimit ioRateLimit
+
+	// The limiters shared by all the files backed up or restored.
+	builtinBackupReadLimiter  = rate.NewLimiter(rate.Inf, ioRateLimitBurst)
+	builtinBackupWriteLimiter = rate.NewLimiter(rate.Inf, ioRateLimitBurst)
+
+	statsBuiltinBackupRateLimitWait = stats.NewCounterDuration("BuiltinBackupRateLimitWait", "Time the builtin backups and restores waited on their I/O rate limits")
+	statsBuiltinBackupThrottledWait = stats.NewCounterDuration("BuiltinBackupThrottledWait", "Time the builtin backups and restores were held back by the throttler")
+)
+
+// BuiltinBackupReadRateLimit returns the maximum number of bytes per second
+// read by the builtin backups and restores, across all files.
+func BuiltinBackupReadRateLimit() int64 {
+	return builtinBackupReadRateLimit.Load()
+}
+
+// SetBuiltinBackupReadRateLimit changes the maximum number of bytes per second
+// read by the builtin backups and restores, across all files. It applies to
+// the backups and restores in progress. Zero means no limit.
+func SetBuiltinBackupReadRateLimit(bytesPerSecond int64) {
+	builtinBackupReadRateLimit.Store(max(bytesPerSecond, 0))
+}
+
+// BuiltinBackupWriteRateLimit returns the maximum number of bytes per second
+// written by the builtin backups and restores, across all files.
+func BuiltinBackupWriteRateLimit() int64 {
+	return builtinBackupWriteRateLimit.Load()
+}
+
+// SetBuiltinBackupWriteRateLimit changes the maximum number of bytes per second
+// written by the builtin backups and restores, across all files. It applies to
+// the backups and restores in progress. Zero means no limit.
+func SetBuiltinBackupWriteRateLimit(bytesPerSecond int64) {
+	builtinBackupWriteRateLimit.Store(max(bytesPerSecond, 0))
+}
+
+// BuiltinBackupPerFileReadRateLimit returns the maximum number of bytes per
+// second read by the builtin backups and restores, for each file.
+func BuiltinBackupPerFileReadRateLimit() int64 {
+	return builtinBackupPerFileReadRateLimit.Load()
+}
+
+// SetBuiltinBackupPerFileReadRateLimit changes the maximum number of bytes per
+// second read by the builtin backups and restores, for each file. It applies
+// to the files being backed up or restored. Zero means no limit.
+func SetBuiltinBackupPerFileReadRateLimit(bytesPerSecond int64) {
+	builtinBackupPerFileReadRateLimit.Store(max(bytesPerSecond, 0))
+}
+
+// BuiltinBackupPerFileWriteRateLimit returns the maximum number of bytes per
+// second written by the builtin backups and restores, for each file.
+func BuiltinBackupPerFileWriteRateLimit() int64 {
+	return builtinBackupPerFileWriteRateLimit.Load()
+}
+
+// SetBuiltinBackupPerFileWriteRateLimit changes the maximum number of bytes per
+// second written by the builtin backups and restores, for each file. It applies
+// to the files being backed up or restored. Zero means no limit.
+func SetBuiltinBackupPerFileWriteRateLimit(bytesPerSecond int64) {
+	builtinBackupPerFileWriteRateLimit.Store(max(bytesPerSecond, 0))
+}
+
+// fileIOLimits limits the I/O of a file backed up or restored: its reads and
+// writes wait on the global and per-file rate limits, and on the throttler.
+type fileIOLimits struct {
+	ctx          context.Context
+	throttler    IOThrottler
+	readLimiter  *rate.Limiter
+	writeLimiter *rate.Limiter
+}
+
+func newFileIOLimits(ctx context.Context, throttler IOThrottler) *fileIOLimits {
+	return &fileIOLimits{
+		ctx:          ctx,
+		throttler:    throttler,
+		readLimiter:  rate.NewLimiter(rate.Inf, ioRateLimitBurst),
+		writeLimiter: rate.NewLimiter(rate.Inf, ioRateLimitBurst),
+	}
+}
+
+// wait blocks until n bytes, at most ioRateLimitBurst, may be read or written.
+func (l *fileIOLimits) wait(n int, globalLimit *ioRateLimit, globalLimiter *rate.Limiter, perFileLimit *ioRateLimit, perFileLimiter *rate.Limiter) error {
+	if l.throttler != nil {
+		start := time.Now()
+		l.throttler.Throttle(l.ctx)
+		statsBuiltinBackupThrottledWait.Add(time.Since(start))
+		if err := l.ctx.Err(); err != nil {
+			return err
+		}
+	}
+
+	globalLimit.apply(globalLimiter)
+	perFileLimit.apply(perFileLimiter)
+	if globalLimiter.Limit() == rate.Inf && perFileLimiter.Limit() == rate.Inf {
+		return nil
+	}
+	start := time.Now()
+	defer func() {
+		statsBuiltinBackupRateLimitWait.Add(time.Since(start))
+	}()
+	if err := perFileLimiter.WaitN(l.ctx, n); err != nil {
+		return err
+	}
+	return globalLimiter.WaitN(l.ctx, n)
+}
+
+func (l *fileIOLimits) waitRead(n int) error {
+	return l.wait(n, &builtinBackupReadRateLimit, builtinBackupReadLimiter, &builtinBackupPerFileReadRateLimit, l.readLimiter)
+}
+
+func (l *fileIOLimits) waitWrite(n int) error {
+	return l.wait(n, &builtinBackupWriteRateLimit, builtinBackupWriteLimiter, &builtinBackupPerFileWriteRateLimit, l.writeLimiter)
+}
+
+// waitWriteAll blocks until n bytes may be written, in as many bursts as needed.
+func (l *fileIOLimits) waitWriteAll(n int) error {
+	for n > 0 {
+		burst := min(n, ioRateLimitBurst)
+		if err := l.waitWrite(burst); err != nil {
+			return err
+		}
+		n -= burst
+	}
+	return nil
+}
+
+// reader returns a reader whose reads wait on the read limits.
+func (l *fileIOLimits) reader(r io.Reader) io.Reader {
+	return &limitedReader{r: r, limits: l}
+}
+
+// writer returns a writer whose writes wait on the write limits.
+func (l *fileIOLimits) writer(w io.Writer) io.Writer {
+	return &limitedWriter{w: w, limits: l}
+}
+
+type limitedReader struct {
+	r      io.Reader
+	limits *fileIOLimits
+}
+
+// Read is part of the io.Reader interface.
+func (lr *limitedReader) Read(p []byte) (int, error) {
+	p = p[:min(len(p), ioRateLimitBurst)]
+	if err := lr.limits.waitRead(len(p)); err != nil {
+		return 0, err
+	}
+	return lr.r.Read(p)
+}
+
+type limitedWriter struct {
+	w      io.Writer
+	limits *fileIOLimits
+}
+
+// Write is part of the io.Writer interface.
+func (lw *limitedWriter) Write(p []byte) (int, error) {
+	written := 0
+	for len(p) > 0 {
+		n := min(len(p), ioRateLimitBurst)
+		if err := lw.limits.waitWrite(n); err != nil {
+			return written, err
+		}
+		n, err := lw.w.Write(p[:n])
+		written += n
+		if err != nil {
+			return written, err
+		}
+		p = p[n:]
+	}
+	return written, nil
+}
@@ -0,0 +1,93 @@
+/*
+Copyright 2026 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package mysqlctl
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// countingThrottler is an IOThrottler that counts its calls, and blocks until
+// the context is done once throttled.
+type countingThrottler struct {
+	calls     int
+	throttled bool
+}
+
+func (t *countingThrottler) Throttle(ctx context.Context) {
+	t.calls++
+	if t.throttled {
+		<-ctx.Done()
+	}
+}
+
+func TestIORateLimitFlag(t *testing.T) {
+	var l ioRateLimit
+	require.NoError(t, l.Set("1048576"))
+	assert.Equal(t, "1048576", l.String())
+	require.NoError(t, l.Set("-1"))
+	assert.Equal(t, "0", l.String())
+	require.Error(t, l.Set("1MB"))
+}
+
+func TestFileIOLimits(t *testing.T) {
+	defer SetBuiltinBackupPerFileWriteRateLimit(BuiltinBackupPerFileWriteRateLimit())
+	defer SetBuiltinBackupPerFileReadRateLimit(BuiltinBackupPerFileReadRateLimit())
+
+	data := bytes.Repeat([]byte("x"), 2*ioRateLimitBurst)
+	copyData := func(limits *fileIOLimits) (time.Duration, error) {
+		start := time.Now()
+		var buf bytes.Buffer
+		_, err := io.Copy(limits.writer(&buf), limits.reader(bytes.NewReader(data)))
+		if err == nil {
+			assert.Equal(t, data, buf.Bytes())
+		}
+		return time.Since(start), err
+	}
+
+	// Without limits, the throttler is checked for every read and write.
+	throttler := &countingThrottler{}
+	_, err := copyData(newFileIOLimits(context.Background(), throttler))
+	require.NoError(t, err)
+	assert.GreaterOrEqual(t, throttler.calls, 4)
+
+	// The first burst is free, the second one waits for a quarter of a second.
+	SetBuiltinBackupPerFileReadRateLimit(4 * ioRateLimitBurst)
+	elapsed, err := copyData(newFileIOLimits(context.Background(), nil))
+	require.NoError(t, err)
+	assert.GreaterOrEqual(t, elapsed, 200*time.Millisecond)
+
+	// The limits change while the file is copied.
+	SetBuiltinBackupPerFileReadRateLimit(0)
+	SetBuiltinBackupPerFileWriteRateLimit(4 * ioRateLimitBurst)
+	elapsed, err = copyData(newFileIOLimits(context.Background(), nil))
+	require.NoError(t, err)
+	assert.GreaterOrEqual(t, elapsed, 200*time.Millisecond)
+	SetBuiltinBackupPerFileWriteRateLimit(0)
+
+	// A throttled copy stops when its context is done.
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	_, err = copyData(newFileIOLimits(ctx, &countingThrottler{throttled: true}))
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+}
@@ -0,0 +1,1061 @@
+/*
+Copyright 2026 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package mysqlctl
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"hash"
+	"hash/crc32"
+	"io"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/spf13/pflag"
+	"golang.org/x/sync/errgroup"
+
+	"vitess.io/vitess/go/mysql/replication"
+	"vitess.io/vitess/go/sqlescape"
+	"vitess.io/vitess/go/sqltypes"
+	"vitess.io/vitess/go/vt/dbconnpool"
+	"vitess.io/vitess/go/vt/mysqlctl/backupstorage"
+	"vitess.io/vitess/go/vt/servenv"
+	"vitess.io/vitess/go/vt/sqlparser"
+	"vitess.io/vitess/go/vt/vterrors"
+
+	querypb "vitess.io/vitess/go/vt/proto/query"
+	tabletmanagerdatapb "vitess.io/vitess/go/vt/proto/tabletmanagerdata"
+	"vitess.io/vitess/go/vt/proto/vtrpc"
+)
+
+const (
+	logicalBackupEngineName = "logical"
+
+	// logicalBackupStatementSize is the size past which an INSERT statement
+	// of a data file is ended, and another one started. It is well below the
+	// default max_allowed_packet.
+	logicalBackupStatementSize = 1024 * 1024
+)
+
+var (
+	// logicalBackupChunkRows is the number of rows past which the data of a
+	// table is split into chunks, backed up and restored in parallel.
+	logicalBackupChunkRows int64 = 1_000_000
+	// logicalBackupShouldDrain drains a tablet when taking a logical backup.
+	logicalBackupShouldDrain = false
+
+	// logicalBackupSessionQueries set up the session of the connections that
+	// read and write the data, so that it is not altered on the way.
+	logicalBackupSessionQueries = []string{
+		"SET NAMES utf8mb4",
+		"SET SESSION time_zone = '+00:00'",
+	}
+	// logicalRestoreSessionQueries set up the session of the connections
+	// that restore a logical backup. The restored transactions are not
+	// written to the binary logs: the restored GTID position is the one of
+	// the backup.
+	logicalRestoreSessionQueries = []string{
+		"SET SESSION sql_log_bin = 0",
+		"SET SESSION foreign_key_checks = 0",
+		"SET SESSION unique_checks = 0",
+		"SET SESSION sql_mode = 'NO_AUTO_VALUE_ON_ZERO'",
+	}
+)
+
+// LogicalBackupManifest represents a backup of the logical engine.
+type LogicalBackupManifest struct {
+	// BackupManifest is an anonymous embedding of the base manifest struct.
+	// Its MySQLVersion is left empty, as a logical backup can be restored
+	// on any version of MySQL.
+	BackupManifest
+
+	// ServerVersion is the version of the MySQL server the backup was taken
+	// from.
+	ServerVersion string
+	// CompressionEngine is the engine the data files are compressed with, or
+	// empty if they are not compressed.
+	CompressionEngine string `json:",omitempty"`
+	// Databases are the backed up databases, with their tables and views.
+	Databases []*LogicalDatabase
+}
+
+// LogicalDatabase is a database of a logical backup.
+type LogicalDatabase struct {
+	Name string
+	// CreateStatement is the CREATE DATABASE statement of the database.
+	CreateStatement string
+	Tables          []*LogicalTable
+	// Views are created once all the tables are.
+	Views []*LogicalView
+}
+
+// LogicalTable is a table of a logical backup.
+type LogicalTable struct {
+	Name string
+	// CreateStatement is the CREATE TABLE statement of the table.
+	CreateStatement string
+	// Columns are the columns the data files have values for, i.e. all but
+	// the generated columns.
+	Columns []string
+	// Chunks are the data files of the table. Each one holds the INSERT
+	// statements of a range of rows, and can be restored independently.
+	Chunks []*LogicalChunk
+}
+
+// LogicalChunk is a data file of a logical backup.
+type LogicalChunk struct {
+	// Name is the name of the file in the backup.
+	Name string
+	// Rows is the number of rows the file holds.
+	Rows int64
+	// Size is the number of bytes of the file, compressed if it is.
+	Size int64
+	// Hash is the crc32 of the file, compressed if it is.
+	Hash string
+}
+
+// LogicalView is a view of a logical backup.
+type LogicalView struct {
+	Name string
+	// CreateStatement is the CREATE VIEW statement of the view.
+	CreateStatement string
+}
+
+// LogicalBackupEngine takes backups with SQL statements, read through a
+// consistent snapshot of the running mysqld: the schema of the databases,
+// and INSERT statements for the rows of each table, split in chunks backed
+// up and restored in parallel. As they hold no file of the data directory,
+// the backups can be restored on another version of MySQL.
+type LogicalBackupEngine struct{}
+
+func init() {
+	BackupRestoreEngineMap[logicalBackupEngineName] = &LogicalBackupEngine{}
+
+	for _, cmd := range []string{"vtbackup", "vtcombo", "vttablet", "vttestserver", "vtctld"} {
+		servenv.OnParseFor(cmd, registerLogicalBackupEngineFlags)
+	}
+}
+
+func registerLogicalBackupEngineFlags(fs *pflag.FlagSet) {
+	fs.Int64Var(&logicalBackupChunkRows, "logical-backup-chunk-rows", logicalBackupChunkRows, "number of rows past which the logical backup engine splits a table into chunks, backed up and restored in parallel.")
+	fs.BoolVar(&logicalBackupShouldDrain, "logical-backup-should-drain", logicalBackupShouldDrain, "decide if we should drain while taking a logical backup or continue to serving traffic")
+}
+
+// RestoreRowFilter selects the rows a logical restore keeps, to restore the
+// backup of a shard into a shard of a different key range.
+type RestoreRowFilter interface {
+	// TableFilter returns the filter of the rows of a table, whose data
+	// files have values for the given columns. It returns nil to restore
+	// all the rows of the table.
+	TableFilter(database, table string, columns []string) (*RestoreTableFilter, error)
+}
+
+// RestoreTableFilter selects the rows of a table a logical restore keeps.
+type RestoreTableFilter struct {
+	// Columns are the indexes, in the columns of the data files, of the
+	// values the rows are selected by. Only these values are decoded.
+	Columns []int
+	// Keep tells whether a row, with the values of Columns, is restored.
+	Keep func(ctx context.Context, values []sqltypes.Value) (bool, error)
+}
+
+// logicalChunkJob is a chunk of a table to back up or restore.
+type logicalChunkJob struct {
+	database *LogicalDatabase
+	table    *LogicalTable
+	chunk    *LogicalChunk
+	// where selects the rows of the chunk, when backing up.
+	where string
+	// filter selects the rows of the chunk, when restoring. It is nil to
+	// restore all of them.
+	filter *RestoreTableFilter
+}
+
+// ExecuteBackup is part of the BackupEngine interface.
+func (be *LogicalBackupEngine) ExecuteBackup(ctx context.Context, params BackupParams, bh backupstorage.BackupHandle) (result BackupResult, finalErr error) {
+	params.Logger.Infof("Executing logical backup at %v for keyspace/shard %v/%v on tablet %v, concurrency: %v",
+		params.BackupTime, params.Keyspace, params.Shard, params.TabletAlias, params.Concurrency)
+
+	serverUUID, err := params.Mysqld.GetServerUUID(ctx)
+	if err != nil {
+		return BackupUnusable, vterrors.Wrap(err, "can't get server uuid")
+	}
+	mysqlVersion, err := params.Mysqld.GetVersionString(ctx)
+	if err != nil {
+		return BackupUnusable, vterrors.Wrap(err, "can't get MySQL version")
+	}
+
+	conns, pos, err := openLogicalSnapshot(ctx, params, max(params.Concurrency, 1))
+	if err != nil {
+		return BackupUnusable, vterrors.Wrap(err, "can't open a consistent snapshot")
+	}
+	defer func() {
+		for _, conn := range conns {
+			_, _ = conn.ExecuteFetch("ROLLBACK", 0, false)
+			conn.Close()
+		}
+	}()
+	params.Logger.Infof("Logical backup: reading a consistent snapshot at position %v", pos)
+
+	bm := &LogicalBackupManifest{
+		BackupManifest: BackupManifest{
+			BackupName:     bh.Name(),
+			BackupMethod:   logicalBackupEngineName,
+			Position:       pos,
+			PurgedPosition: pos,
+			BackupTime:     FormatRFC3339(params.BackupTime.UTC()),
+			ServerUUID:     serverUUID,
+			TabletAlias:    params.TabletAlias,
+			Keyspace:       params.Keyspace,
+			Shard:          params.Shard,
+			UpgradeSafe:    true,
+		},
+		ServerVersion: mysqlVersion,
+	}
+	if backupStorageCompress {
+		bm.CompressionEngine = logicalBackupCompressionEngine()
+	}
+
+	jobs, err := readLogicalSchema(ctx, conns[0], bm)
+	if err != nil {
+		return BackupUnusable, err
+	}
+
+	params.Logger.Infof("Logical backup: backing up %v chunks", len(jobs))
+	g, gctx := errgroup.WithContext(ctx)
+	jobsCh := make(chan *logicalChunkJob)
+	for _, conn := range conns {
+		g.Go(func() error {
+			for job := range jobsCh {
+				if err := backupLogicalChunk(gctx, params, bh, conn, bm.CompressionEngine, job); err != nil {
+					return vterrors.Wrapf(err, "failed to back up chunk %v of table %v.%v", job.chunk.Name, job.database.Name, job.table.Name)
+				}
+			}
+			return nil
+		})
+	}
+	g.Go(func() error {
+		defer close(jobsCh)
+		for _, job := range jobs {
+			select {
+			case jobsCh <- job:
+			case <-gctx.Done():
+				return gctx.Err()
+			}
+		}
+		return nil
+	})
+	if err := g.Wait(); err != nil {
+		return BackupUnusable, err
+	}
+
+	for _, job := range jobs {
+		bm.Size += job.chunk.Size
+	}
+	bm.FinishedTime = FormatRFC3339(time.Now().UTC())
+
+	params.Logger.Infof("Writing backup MANIFEST")
+	mwc, err := bh.AddFile(ctx, backupManifestFileName, backupstorage.FileSizeUnknown)
+	if err != nil {
+		return BackupUnusable, vterrors.Wrapf(err, "cannot add %v to backup", backupManifestFileName)
+	}
+	defer closeFile(mwc, backupManifestFileName, params.Logger, &finalErr)
+
+	data, err := json.MarshalIndent(bm, "", "  ")
+	if err != nil {
+		return BackupUnusable, vterrors.Wrapf(err, "cannot JSON encode %v", backupManifestFileName)
+	}
+	if _, err := mwc.Write(data); err != nil {
+		return BackupUnusable, vterrors.Wrapf(err, "cannot write %v", backupManifestFileName)
+	}
+
+	params.Logger.Infof("Backup completed")
+	return BackupUsable, nil
+}
+
+// logicalBackupCompressionEngine returns the engine the data files are
+// compressed with. The external compressors are not supported, as the data
+// files are restored by vttablet itself.
+func logicalBackupCompressionEngine() string {
+	if CompressionEngineName == ExternalCompressor || ExternalCompressorCmd != "" {
+		return PgzipCompressor
+	}
+	return CompressionEngineName
+}
+
+// openLogicalSnapshot opens connections that all read the same consistent
+// snapshot of the databases, and returns the GTID position of the snapshot.
+// The transactions of the snapshot are started while a global read lock is
+// held, so that no transaction commits in between.
+func openLogicalSnapshot(ctx context.Context, params BackupParams, count int) (conns []*dbconnpool.DBConnection, pos replication.Position, err error) {
+	defer func() {
+		if err != nil {
+			for _, conn := range conns {
+				conn.Close()
+			}
+			conns = nil
+		}
+	}()
+
+	if err := params.Mysqld.AcquireGlobalReadLock(ctx); err != nil {
+		return nil, pos, vterrors.Wrap(err, "failed to acquire a global read lock")
+	}
+	lockAcquired := time.Now()
+	defer func() {
+		// The context might be done, the lock must be released anyway.
+		if err := params.Mysqld.ReleaseGlobalReadLock(context.Background()); err != nil {
+			params.Logger.Errorf("unable to release global read lock: %v", err)
+		}
+		params.Logger.Infof("global read lock released after %v", time.Since(lockAcquired))
+	}()
+
+	if pos, err = params.Mysqld.PrimaryPosition(ctx); err != nil {
+		return nil, pos, vterrors.Wrap(err, "failed to fetch position")
+	}
+	for range count {
+		conn, err := params.Mysqld.GetDbaConnection(ctx)
+		if err != nil {
+			return conns, pos, err
+		}
+		conns = append(conns, conn)
+		queries := append(slices.Clone(logicalBackupSessionQueries),
+			"SET TRANSACTION ISOLATION LEVEL REPEATABLE READ",
+			"START TRANSACTION WITH CONSISTENT SNAPSHOT, READ ONLY",
+		)
+		if err := executeLogicalQueries(conn, queries); err != nil {
+			return conns, pos, err
+		}
+	}
+	return conns, pos, nil
+}
+
+// readLogicalSchema reads the schema of the databases into the manifest,
+// and returns the chunks of the tables to back up.
+func readLogicalSchema(ctx context.Context, conn *dbconnpool.DBConnection, bm *LogicalBackupManifest) ([]*logicalChunkJob, error) {
+	qr, err := conn.ExecuteFetch("SHOW DATABASES", -1, false)
+	if err != nil {
+		return nil, vterrors.Wrap(err, "failed to list the databases")
+	}
+	var jobs []*logicalChunkJob
+	for _, row := range qr.Rows {
+		name := row[0].ToString()
+		if slices.Contains(internalDBs, name) {
+			continue
+		}
+		database := &LogicalDatabase{Name: name}
+		if database.CreateStatement, err = showCreate(conn, "DATABASE", sqlescape.EscapeID(name)); err != nil {
+			return nil, err
+		}
+		dbJobs, err := readLogicalTables(ctx, conn, database)
+		if err != nil {
+			return nil, err
+		}
+		bm.Databases = append(bm.Databases, database)
+		jobs = append(jobs, dbJobs...)
+	}
+
+	// Name the data files in the order of the tables.
+	for i, job := range jobs {
+		job.chunk.Name = strconv.Itoa(i)
+	}
+	return jobs, nil
+}
+
+// readLogicalTables reads the tables and views of a database, and returns
+// the chunks of its tables.
+func readLogicalTables(ctx context.Context, conn *dbconnpool.DBConnection, database *LogicalDatabase) ([]*logicalChunkJob, error) {
+	qr, err := conn.ExecuteFetch(fmt.Sprintf("SELECT TABLE_NAME, TABLE_TYPE, TABLE_ROWS FROM information_schema.TABLES WHERE TABLE_SCHEMA = %s ORDER BY TABLE_NAME", sqltypes.EncodeStringSQL(database.Name)), -1, false)
+	if err != nil {
+		return nil, vterrors.Wrapf(err, "failed to list the tables of %v", database.Name)
+	}
+	var jobs []*logicalChunkJob
+	for _, row := range qr.Rows {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		name, tableType := row[0].ToString(), row[1].ToString()
+		qualifiedName := sqlescape.EscapeID(database.Name) + "." + sqlescape.EscapeID(name)
+		switch tableType {
+		case "VIEW":
+			view := &LogicalView{Name: name}
+			if view.CreateStatement, err = showCreate(conn, "VIEW", qualifiedName); err != nil {
+				return nil, err
+			}
+			database.Views = append(database.Views, view)
+		case "BASE TABLE":
+			table := &LogicalTable{Name: name}
+			if table.CreateStatement, err = showCreate(conn, "TABLE", qualifiedName); err != nil {
+				return nil, err
+			}
+			// The row count is an estimate, only used to split the table
+			// into chunks.
+			rows, _ := row[2].ToInt64()
+			wheres, err := readLogicalTableChunks(conn, table, qualifiedName, rows)
+			if err != nil {
+				return nil, err
+			}
+			for _, where := range wheres {
+				chunk := &LogicalChunk{}
+				table.Chunks = append(table.Chunks, chunk)
+				jobs = append(jobs, &logicalChunkJob{database: database, table: table, chunk: chunk, where: where})
+			}
+			database.Tables = append(database.Tables, table)
+		}
+	}
+	return jobs, nil
+}
+
+// readLogicalTableChunks reads the columns of a table, and returns the WHERE
+// clauses of its chunks. A table is split into chunks only if it has more
+// than logicalBackupChunkRows rows and a single-column integer primary key,
+// which is split into ranges of values.
+func readLogicalTableChunks(conn *dbconnpool.DBConnection, table *LogicalTable, qualifiedName string, rows int64) ([]string, error) {
+	qr, err := conn.ExecuteFetch("SHOW COLUMNS FROM "+qualifiedName, -1, false)
+	if err != nil {
+		return nil, vterrors.Wrapf(err, "failed to read the columns of %v", qualifiedName)
+	}
+	// The columns are Field, Type, Null, Key, Default and Extra.
+	var pkColumns []string
+	integerPK := false
+	for _, row := range qr.Rows {
+		if len(row) < 6 {
+			return nil, vterrors.Errorf(vtrpc.Code_INTERNAL, "unexpected SHOW COLUMNS result for %v", qualifiedName)
+		}
+		name, columnType, key, extra := row[0].ToString(), strings.ToLower(row[1].ToString()), row[3].ToString(), strings.ToUpper(row[5].ToString())
+		if key == "PRI" {
+			pkColumns = append(pkColumns, name)
+			integerPK = strings.Contains(columnType, "int") && !strings.Contains(columnType, "point")
+		}
+		if strings.Contains(extra, "GENERATED") {
+			continue
+		}
+		table.Columns = append(table.Columns, name)
+	}
+
+	n := int64(1)
+	if logicalBackupChunkRows > 0 {
+		n = (rows + logicalBackupChunkRows - 1) / logicalBackupChunkRows
+	}
+	if n < 2 || len(pkColumns) != 1 || !integerPK {
+		return []string{""}, nil
+	}
+
+	pk := sqlescape.EscapeID(pkColumns[0])
+	qr, err = conn.ExecuteFetch(fmt.Sprintf("SELECT MIN(%s), MAX(%s) FROM %s", pk, pk, qualifiedName), 1, false)
+	if err != nil {
+		return nil, vterrors.Wrapf(err, "failed to read the primary key range of %v", qualifiedName)
+	}
+	if len(qr.Rows) != 1 || len(qr.Rows[0]) != 2 {
+		return []string{""}, nil
+	}
+	minValue, err := qr.Rows[0][0].ToInt64()
+	if err != nil {
+		// The table is empty, or the unsigned values overflow int64.
+		return []string{""}, nil
+	}
+	maxValue, err := qr.Rows[0][1].ToInt64()
+	if err != nil {
+		return []string{""}, nil
+	}
+	return logicalChunkRanges(pk, minValue, maxValue, n), nil
+}
+
+// logicalChunkRanges returns the WHERE clauses splitting the values of a
+// primary key from minValue to maxValue into at most n ranges. The first
+// and last ranges are open, so that no row is missed.
+func logicalChunkRanges(pk string, minValue, maxValue, n int64) []string {
+	// The arithmetic is unsigned, so that the span of the values does not
+	// overflow.
+	span := uint64(maxValue) - uint64(minValue)
+	width := span/uint64(n) + 1
+	var bounds []int64
+	// width > span/i is i*width > span, without overflowing.
+	for i := uint64(1); i < uint64(n) && width <= span/i; i++ {
+		bounds = append(bounds, int64(uint64(minValue)+i*width))
+	}
+	if len(bounds) == 0 {
+		return []string{""}
+	}
+
+	wheres := make([]string, 0, len(bounds)+1)
+	wheres = append(wheres, fmt.Sprintf("%s < %d", pk, bounds[0]))
+	for i := 1; i < len(bounds); i++ {
+		wheres = append(wheres, fmt.Sprintf("%s >= %d AND %s < %d", pk, bounds[i-1], pk, bounds[i]))
+	}
+	wheres = append(wheres, fmt.Sprintf("%s >= %d", pk, bounds[len(bounds)-1]))
+	return wheres
+}
+
+// backupLogicalChunk writes the INSERT statements of the rows of a chunk to
+// its data file.
+func backupLogicalChunk(ctx context.Context, params BackupParams, bh backupstorage.BackupHandle, conn *dbconnpool.DBConnection, compressionEngine string, job *logicalChunkJob) (finalErr error) {
+	if len(job.table.Columns) == 0 {
+		return nil
+	}
+
+	wc, err := bh.AddFile(ctx, job.chunk.Name, backupstorage.FileSizeUnknown)
+	if err != nil {
+		return vterrors.Wrapf(err, "cannot add file %v", job.chunk.Name)
+	}
+	defer closeFile(wc, job.chunk.Name, params.Logger, &finalErr)
+
+	lw := newLogicalFileWriter(wc)
+	var w io.Writer = lw
+	if compressionEngine != "" {
+		compressor, err := newBuiltinCompressor(compressionEngine, lw, params.Logger)
+		if err != nil {
+			return vterrors.Wrap(err, "can't create compressor")
+		}
+		defer func() {
+			if err := compressor.Close(); err != nil {
+				finalErr = errors.Join(finalErr, vterrors.Wrap(err, "failed to close compressor"))
+			}
+			job.chunk.Size, job.chunk.Hash = lw.n, lw.HashString()
+		}()
+		w = compressor
+	} else {
+		defer func() {
+			job.chunk.Size, job.chunk.Hash = lw.n, lw.HashString()
+		}()
+	}
+	bw := bufio.NewWriterSize(w, 64*1024)
+
+	columns := make([]string, len(job.table.Columns))
+	for i, column := range job.table.Columns {
+		columns[i] = sqlescape.EscapeID(column)
+	}
+	query := fmt.Sprintf("SELECT %s FROM %s.%s", strings.Join(columns, ", "), sqlescape.EscapeID(job.database.Name), sqlescape.EscapeID(job.table.Name))
+	if job.where != "" {
+		query += " WHERE " + job.where
+	}
+	if err := conn.Conn.ExecuteStreamFetch(query); err != nil {
+		return err
+	}
+	defer conn.CloseResult()
+	fields, err := conn.Fields()
+	if err != nil {
+		return err
+	}
+
+	insert := fmt.Sprintf("INSERT INTO %s (%s) VALUES ", sqlescape.EscapeID(job.table.Name), strings.Join(columns, ", "))
+	var statement bytes.Buffer
+	flush := func() error {
+		if statement.Len() == 0 {
+			return nil
+		}
+		if params.IOThrottler != nil {
+			params.IOThrottler.Throttle(ctx)
+		}
+		statement.WriteString(";\n")
+		_, err := bw.Write(statement.Bytes())
+		statement.Reset()
+		return err
+	}
+	for {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		row, err := conn.FetchNext(nil)
+		if err != nil {
+			return err
+		}
+		if row == nil {
+			break
+		}
+		if statement.Len() == 0 {
+			statement.WriteString(insert)
+		} else {
+			statement.WriteByte(',')
+		}
+		encodeLogicalRow(&statement, fields, row)
+		job.chunk.Rows++
+		if statement.Len() >= logicalBackupStatementSize {
+			if err := flush(); err != nil {
+				return err
+			}
+		}
+	}
+	if err := flush(); err != nil {
+		return err
+	}
+	return bw.Flush()
+}
+
+// encodeLogicalRow writes a row as the values of an INSERT statement.
+func encodeLogicalRow(buf *bytes.Buffer, fields []*querypb.Field, row []sqltypes.Value) {
+	buf.WriteByte('(')
+	for i, value := range row {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		if i < len(fields) && fields[i].Type == querypb.Type_GEOMETRY && !value.IsNull() {
+			// Geometries are in an internal binary format, not valid in a
+			// string of the connection character set.
+			buf.WriteString("X'")
+			buf.WriteString(hex.EncodeToString(value.Raw()))
+			buf.WriteByte('\'')
+			continue
+		}
+		value.EncodeSQL(buf)
+	}
+	buf.WriteByte(')')
+}
+
+// logicalFileWriter counts and hashes the bytes written to a file of a
+// backup.
+type logicalFileWriter struct {
+	w     io.Writer
+	crc32 hash.Hash32
+	n     int64
+}
+
+func newLogicalFileWriter(w io.Writer) *logicalFileWriter {
+	return &logicalFileWriter{w: w, crc32: crc32.NewIEEE()}
+}
+
+func (lw *logicalFileWriter) Write(p []byte) (int, error) {
+	n, err := lw.w.Write(p)
+	_, _ = lw.crc32.Write(p[:n])
+	lw.n += int64(n)
+	return n, err
+}
+
+func (lw *logicalFileWriter) HashString() string {
+	return hex.EncodeToString(lw.crc32.Sum(nil))
+}
+
+// ExecuteRestore is part of the RestoreEngine interface.
+func (be *LogicalBackupEngine) ExecuteRestore(ctx context.Context, params RestoreParams, bh backupstorage.BackupHandle) (*BackupManifest, error) {
+	var bm LogicalBackupManifest
+	if err := getBackupManifestInto(ctx, bh, &bm); err != nil {
+		return nil, err
+	}
+	params.Logger.Infof("Restoring logical backup %v, taken from MySQL %v, into %s", bh.Name(), bm.ServerVersion, params.DbName)
+
+	// mark restore as in progress
+	if err := createStateFile(params.Cnf); err != nil {
+		return nil, err
+	}
+
+	// make sure semi-sync is disabled, otherwise we will wait forever for acknowledgements
+	if err := params.Mysqld.SetSemiSyncEnabled(ctx, false, false); err != nil {
+		return nil, vterrors.Wrap(err, "disable semi-sync failed")
+	}
+
+	readOnly, err := params.Mysqld.IsSuperReadOnly(ctx)
+	if err != nil {
+		return nil, vterrors.Wrap(err, "checking if mysqld has super_read_only=enable")
+	}
+	if readOnly {
+		resetFunc, err := params.Mysqld.SetSuperReadOnly(ctx, false)
+		if err != nil {
+			return nil, vterrors.Wrap(err, "unable to disable super-read-only")
+		}
+		if resetFunc != nil {
+			defer func() {
+				if err := resetFunc(); err != nil {
+					params.Logger.Errorf("Not able to set super_read_only to its original value after restore")
+				}
+			}()
+		}
+	}
+
+	if err := cleanupMySQL(ctx, params, false); err != nil {
+		return nil, vterrors.Wrap(err, "error cleaning MySQL")
+	}
+	if err := params.Mysqld.ResetReplication(ctx); err != nil {
+		return nil, vterrors.Wrap(err, "unable to reset replication")
+	}
+
+	var parser *sqlparser.Parser
+	if params.RowFilter != nil {
+		params.Logger.Infof("Logical restore: filtering the rows of the tables")
+		if parser, err = sqlparser.New(sqlparser.Options{}); err != nil {
+			return nil, err
+		}
+	}
+
+	conn, err := openLogicalRestoreConn(ctx, params)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+
+	params.Logger.Infof("Logical restore: creating the databases and tables")
+	var jobs []*logicalChunkJob
+	for _, database := range bm.Databases {
+		if err := executeLogicalQueries(conn, []string{database.CreateStatement, "USE " + sqlescape.EscapeID(database.Name)}); err != nil {
+			return nil, vterrors.Wrapf(err, "failed to create database %v", database.Name)
+		}
+		for _, table := range database.Tables {
+			if err := executeLogicalQueries(conn, []string{table.CreateStatement}); err != nil {
+				return nil, vterrors.Wrapf(err, "failed to create table %v.%v", database.Name, table.Name)
+			}
+			var filter *RestoreTableFilter
+			if params.RowFilter != nil {
+				if filter, err = params.RowFilter.TableFilter(database.Name, table.Name, table.Columns); err != nil {
+					return nil, vterrors.Wrapf(err, "failed to filter the rows of table %v.%v", database.Name, table.Name)
+				}
+			}
+			for _, chunk := range table.Chunks {
+				jobs = append(jobs, &logicalChunkJob{database: database, table: table, chunk: chunk, filter: filter})
+			}
+		}
+	}
+
+	params.Logger.Infof("Logical restore: restoring %v chunks", len(jobs))
+	g, gctx := errgroup.WithContext(ctx)
+	jobsCh := make(chan *logicalChunkJob)
+	for range max(params.Concurrency, 1) {
+		g.Go(func() error {
+			conn, err := openLogicalRestoreConn(gctx, params)
+			if err != nil {
+				return err
+			}
+			defer conn.Close()
+			for job := range jobsCh {
+				if err := restoreLogicalChunk(gctx, params, bh, conn, parser, bm.CompressionEngine, job); err != nil {
+					return vterrors.Wrapf(err, "failed to restore chunk %v of table %v.%v", job.chunk.Name, job.database.Name, job.table.Name)
+				}
+			}
+			return nil
+		})
+	}
+	g.Go(func() error {
+		defer close(jobsCh)
+		for _, job := range jobs {
+			select {
+			case jobsCh <- job:
+			case <-gctx.Done():
+				return gctx.Err()
+			}
+		}
+		return nil
+	})
+	if err := g.Wait(); err != nil {
+		return nil, err
+	}
+
+	params.Logger.Infof("Logical restore: creating the views")
+	for _, database := range bm.Databases {
+		if err := createLogicalViews(conn, database); err != nil {
+			return nil, err
+		}
+	}
+
+	// The restored transactions were not written to the binary logs, the
+	// restored position is the one of the backup. Unless the rows were
+	// filtered: the position is then the one of another shard, that the
+	// restored mysqld must not carry, so it is left empty.
+	if params.RowFilter != nil {
+		bm.Position = replication.Position{}
+	} else if err := params.Mysqld.SetReplicationPosition(ctx, bm.Position); err != nil {
+		return nil, vterrors.Wrap(err, "failed to set the restored position")
+	}
+
+	params.Logger.Infof("Restore completed")
+	return &bm.BackupManifest, nil
+}
+
+// openLogicalRestoreConn opens a connection to restore a logical backup.
+func openLogicalRestoreConn(ctx context.Context, params RestoreParams) (*dbconnpool.DBConnection, error) {
+	conn, err := params.Mysqld.GetDbaConnection(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if err := executeLogicalQueries(conn, append(slices.Clone(logicalBackupSessionQueries), logicalRestoreSessionQueries...)); err != nil {
+		conn.Close()
+		return nil, err
+	}
+	return conn, nil
+}
+
+// restoreLogicalChunk executes the INSERT statements of a data file, with
+// the rows selected by the filter of the job, if it has one.
+func restoreLogicalChunk(ctx context.Context, params RestoreParams, bh backupstorage.BackupHandle, conn *dbconnpool.DBConnection, parser *sqlparser.Parser, compressionEngine string, job *logicalChunkJob) error {
+	if err := executeLogicalQueries(conn, []string{"USE " + sqlescape.EscapeID(job.database.Name)}); err != nil {
+		return err
+	}
+	if job.chunk.Rows == 0 {
+		return nil
+	}
+
+	rc, err := bh.ReadFile(ctx, job.chunk.Name)
+	if err != nil {
+		return vterrors.Wrapf(err, "can't open file %v", job.chunk.Name)
+	}
+	defer rc.Close()
+
+	lr := newLogicalFileReader(rc)
+	var r io.Reader = lr
+	if compressionEngine != "" {
+		decompressor, err := newBuiltinDecompressor(compressionEngine, lr, params.Logger)
+		if err != nil {
+			return vterrors.Wrap(err, "can't create decompressor")
+		}
+		defer decompressor.Close()
+		r = decompressor
+	}
+
+	br := bufio.NewReaderSize(r, 64*1024)
+	for {
+		// The values are escaped, so each line is a statement.
+		statement, err := br.ReadBytes('\n')
+		if len(statement) > 0 {
+			if params.IOThrottler != nil {
+				params.IOThrottler.Throttle(ctx)
+			}
+			if err := ctx.Err(); err != nil {
+				return err
+			}
+			query := strings.TrimSuffix(strings.TrimSuffix(string(statement), "\n"), ";")
+			if job.filter != nil {
+				if query, err = filterLogicalStatement(ctx, parser, query, job.filter); err != nil {
+					return err
+				}
+			}
+			if query != "" {
+				if _, err := conn.ExecuteFetch(query, 0, false); err != nil {
+					return err
+				}
+			}
+		}
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return err
+		}
+	}
+
+	// Drain the file, so that its hash covers all of it.
+	if _, err := io.Copy(io.Discard, lr); err != nil {
+		return err
+	}
+	if hash := lr.HashString(); hash != job.chunk.Hash {
+		return vterrors.Errorf(vtrpc.Code_INTERNAL, "hash mismatch for %v, got %v expected %v", job.chunk.Name, hash, job.chunk.Hash)
+	}
+	return nil
+}
+
+// filterLogicalStatement returns an INSERT statement of a data file with only
+// the rows selected by a filter, or an empty string if none is.
+func filterLogicalStatement(ctx context.Context, parser *sqlparser.Parser, query string, filter *RestoreTableFilter) (string, error) {
+	stmt, err := parser.Parse(query)
+	if err != nil {
+		return "", err
+	}
+	insert, ok := stmt.(*sqlparser.Insert)
+	if !ok {
+		return "", vterrors.Errorf(vtrpc.Code_INTERNAL, "unexpected statement in data file: %v", parser.TruncateForLog(query))
+	}
+	values, ok := insert.Rows.(sqlparser.Values)
+	if !ok {
+		return "", vterrors.Errorf(vtrpc.Code_INTERNAL, "unexpected rows in data file: %v", parser.TruncateForLog(query))
+	}
+	var kept sqlparser.Values
+	for _, tuple := range values {
+		row := make([]sqltypes.Value, len(filter.Columns))
+		for i, column := range filter.Columns {
+			if column >= len(tuple) {
+				return "", vterrors.Errorf(vtrpc.Code_INTERNAL, "missing value in data file: %v", parser.TruncateForLog(query))
+			}
+			if row[i], err = logicalValue(tuple[column]); err != nil {
+				return "", err
+			}
+		}
+		keep, err := filter.Keep(ctx, row)
+		if err != nil {
+			return "", err
+		}
+		if keep {
+			kept = append(kept, tuple)
+		}
+	}
+	if len(kept) == 0 {
+		return "", nil
+	}
+	if len(kept) == len(values) {
+		return query, nil
+	}
+	insert.Rows = kept
+	return sqlparser.String(insert), nil
+}
+
+// logicalValue returns the value of a row of a data file, as written by
+// encodeLogicalRow.
+func logicalValue(expr sqlparser.Expr) (sqltypes.Value, error) {
+	switch expr := expr.(type) {
+	case *sqlparser.NullVal:
+		return sqltypes.NULL, nil
+	case *sqlparser.Literal:
+		return logicalLiteralValue(expr.Type, expr.Val)
+	case *sqlparser.IntroducerExpr:
+		// The binary strings are written as _binary'...'.
+		if lit, ok := expr.Expr.(*sqlparser.Literal); ok && lit.Type == sqlparser.StrVal && strings.EqualFold(expr.CharacterSet, "_binary") {
+			return sqltypes.NewVarBinary(lit.Val), nil
+		}
+	case *sqlparser.UnaryExpr:
+		// Negative numbers are parsed as the negation of a literal.
+		if lit, ok := expr.Expr.(*sqlparser.Literal); ok && expr.Operator == sqlparser.UMinusOp {
+			switch lit.Type {
+			case sqlparser.IntVal, sqlparser.FloatVal, sqlparser.DecimalVal:
+				return logicalLiteralValue(lit.Type, "-"+lit.Val)
+			}
+		}
+	}
+	return sqltypes.Value{}, vterrors.Errorf(vtrpc.Code_INTERNAL, "unexpected value in data file: %v", sqlparser.String(expr))
+}
+
+// logicalLiteralValue returns the value of a literal of a data file. The
+// numbers keep the text they were written with, e.g. the scale of the
+// decimals, and the bit values keep their leading zero bytes.
+func logicalLiteralValue(typ sqlparser.ValType, val string) (sqltypes.Value, error) {
+	switch typ {
+	case sqlparser.IntVal:
+		if _, err := strconv.ParseInt(val, 10, 64); err == nil {
+			return sqltypes.MakeTrusted(sqltypes.Int64, []byte(val)), nil
+		}
+		if _, err := strconv.ParseUint(val, 10, 64); err == nil {
+			return sqltypes.MakeTrusted(sqltypes.Uint64, []byte(val)), nil
+		}
+		return sqltypes.MakeTrusted(sqltypes.Decimal, []byte(val)), nil
+	case sqlparser.FloatVal:
+		return sqltypes.MakeTrusted(sqltypes.Float64, []byte(val)), nil
+	case sqlparser.DecimalVal:
+		return sqltypes.MakeTrusted(sqltypes.Decimal, []byte(val)), nil
+	case sqlparser.StrVal:
+		return sqltypes.NewVarChar(val), nil
+	case sqlparser.HexVal:
+		b, err := hex.DecodeString(val)
+		if err != nil {
+			return sqltypes.Value{}, vterrors.Wrapf(err, "invalid hex value in data file: %v", val)
+		}
+		return sqltypes.MakeTrusted(sqltypes.VarBinary, b), nil
+	case sqlparser.BitNum:
+		bits := strings.TrimPrefix(val, "0b")
+		if len(bits)%8 != 0 {
+			return sqltypes.Value{}, vterrors.Errorf(vtrpc.Code_INTERNAL, "invalid bit value in data file: %v", val)
+		}
+		b := make([]byte, len(bits)/8)
+		for i := range b {
+			n, err := strconv.ParseUint(bits[i*8:i*8+8], 2, 8)
+			if err != nil {
+				return sqltypes.Value{}, vterrors.Wrapf(err, "invalid bit value in data file: %v", val)
+			}
+			b[i] = byte(n)
+		}
+		return sqltypes.MakeTrusted(sqltypes.VarBinary, b), nil
+	}
+	return sqltypes.Value{}, vterrors.Errorf(vtrpc.Code_INTERNAL, "unexpected literal in data file: %v", val)
+}
+
+// createLogicalViews creates the views of a database. A view can select from
+// other views, so the views that fail to be created are retried as long as
+// others are.
+func createLogicalViews(conn *dbconnpool.DBConnection, database *LogicalDatabase) error {
+	if len(database.Views) == 0 {
+		return nil
+	}
+	if err := executeLogicalQueries(conn, []string{"USE " + sqlescape.EscapeID(database.Name)}); err != nil {
+		return err
+	}
+	pending := database.Views
+	for len(pending) > 0 {
+		var failed []*LogicalView
+		var lastErr error
+		for _, view := range pending {
+			if _, err := conn.ExecuteFetch(view.CreateStatement, 0, false); err != nil {
+				failed = append(failed, view)
+				lastErr = vterrors.Wrapf(err, "failed to create view %v.%v", database.Name, view.Name)
+			}
+		}
+		if len(failed) == len(pending) {
+			return lastErr
+		}
+		pending = failed
+	}
+	return nil
+}
+
+// logicalFileReader hashes the bytes read from a file of a backup.
+type logicalFileReader struct {
+	r     io.Reader
+	crc32 hash.Hash32
+}
+
+func newLogicalFileReader(r io.Reader) *logicalFileReader {
+	return &logicalFileReader{r: r, crc32: crc32.NewIEEE()}
+}
+
+func (lr *logicalFileReader) Read(p []byte) (int, error) {
+	n, err := lr.r.Read(p)
+	_, _ = lr.crc32.Write(p[:n])
+	return n, err
+}
+
+func (lr *logicalFileReader) HashString() string {
+	return hex.EncodeToString(lr.crc32.Sum(nil))
+}
+
+// executeLogicalQueries executes queries on a connection, in order.
+func executeLogicalQueries(conn *dbconnpool.DBConnection, queries []string) error {
+	for _, query := range queries {
+		if _, err := conn.ExecuteFetch(query, 0, false); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// showCreate returns the CREATE statement of a database, table or view.
+func showCreate(conn *dbconnpool.DBConnection, kind, name string) (string, error) {
+	qr, err := conn.ExecuteFetch(fmt.Sprintf("SHOW CREATE %s %s", kind, name), 1, false)
+	if err != nil {
+		return "", vterrors.Wrapf(err, "failed to read the CREATE statement of %v", name)
+	}
+	if len(qr.Rows) != 1 || len(qr.Rows[0]) < 2 {
+		return "", vterrors.Errorf(vtrpc.Code_INTERNAL, "unexpected SHOW CREATE %s result for %v", kind, name)
+	}
+	return qr.Rows[0][1].ToString(), nil
+}
+
+// ShouldDrainForBackup satisfies the BackupEngine interface.
+// Logical backups read a consistent snapshot while MySQL is running, so we
+// can control this via a flag.
+func (be *LogicalBackupEngine) ShouldDrainForBackup(req *tabletmanagerdatapb.BackupRequest) bool {
+	return logicalBackupShouldDrain
+}
+
+// ShouldStartMySQLAfterRestore signifies if this backup engine needs to
+// restart MySQL once the restore is completed. Logical restores run on a
+// live MySQL instance, so there is no need to start it.
+func (be *LogicalBackupEngine) ShouldStartMySQLAfterRestore() bool {
+	return false
+}
+
+// Name is part of the BackupEngine interface.
+func (be *LogicalBackupEngine) Name() string { return logicalBackupEngineName }
+
+var _ BackupRestoreEngine = (*LogicalBackupEngine)(nil)
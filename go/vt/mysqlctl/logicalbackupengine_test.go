@@ -0,0 +1,356 @@
+/*
+Copyright 2026 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package mysqlctl
+
+import (
+	"bytes"
+	"context"
+	"path"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"vitess.io/vitess/go/mysql/fakesqldb"
+	"vitess.io/vitess/go/mysql/replication"
+	"vitess.io/vitess/go/sqltypes"
+	"vitess.io/vitess/go/vt/logutil"
+	"vitess.io/vitess/go/vt/mysqlctl/backupstorage"
+	"vitess.io/vitess/go/vt/mysqlctl/filebackupstorage"
+	"vitess.io/vitess/go/vt/sqlparser"
+
+	querypb "vitess.io/vitess/go/vt/proto/query"
+)
+
+func TestLogicalChunkRanges(t *testing.T) {
+	tests := []struct {
+		name     string
+		min, max int64
+		n        int64
+		expected []string
+	}{
+		{
+			name:     "even split",
+			min:      1,
+			max:      10,
+			n:        2,
+			expected: []string{"id < 6", "id >= 6"},
+		},
+		{
+			name:     "more chunks than values",
+			min:      1,
+			max:      2,
+			n:        4,
+			expected: []string{"id < 2", "id >= 2"},
+		},
+		{
+			name:     "single value",
+			min:      5,
+			max:      5,
+			n:        3,
+			expected: []string{""},
+		},
+		{
+			name:     "full range",
+			min:      -1 << 63,
+			max:      1<<63 - 1,
+			n:        2,
+			expected: []string{"id < 0", "id >= 0"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, logicalChunkRanges("id", tt.min, tt.max, tt.n))
+		})
+	}
+}
+
+func TestEncodeLogicalRow(t *testing.T) {
+	fields := []*querypb.Field{
+		{Name: "id", Type: querypb.Type_INT64},
+		{Name: "name", Type: querypb.Type_VARCHAR},
+		{Name: "location", Type: querypb.Type_GEOMETRY},
+		{Name: "note", Type: querypb.Type_TEXT},
+	}
+	var buf bytes.Buffer
+	encodeLogicalRow(&buf, fields, []sqltypes.Value{
+		sqltypes.NewInt64(1),
+		sqltypes.NewVarChar("it's\na line"),
+		sqltypes.MakeTrusted(querypb.Type_GEOMETRY, []byte{0, 1, 0xff}),
+		sqltypes.NULL,
+	})
+	assert.Equal(t, `(1,'it\'s\na line',X'0001ff',null)`, buf.String())
+}
+
+func TestLogicalBackupEngine(t *testing.T) {
+	ctx := context.Background()
+	logger := logutil.NewMemoryLogger()
+
+	oldRoot := filebackupstorage.FileBackupStorageRoot
+	defer func() { filebackupstorage.FileBackupStorageRoot = oldRoot }()
+	filebackupstorage.FileBackupStorageRoot = t.TempDir()
+	oldChunkRows := logicalBackupChunkRows
+	defer func() { logicalBackupChunkRows = oldChunkRows }()
+	logicalBackupChunkRows = 2
+	bs := backupstorage.BackupStorageMap["file"]
+
+	pos, err := replication.DecodePosition("MySQL56/00000000-0000-0000-0000-000000000001:1-10")
+	require.NoError(t, err)
+
+	// Back up a table split in two chunks, and a view.
+	backupDB := fakesqldb.New(t)
+	defer backupDB.Close()
+	backupDB.SetNeverFail(true)
+	backupDB.AddQuery("SHOW DATABASES", sqltypes.MakeTestResult(sqltypes.MakeTestFields("Database", "varchar"), "mysql", "vt_test"))
+	backupDB.AddQuery("SHOW CREATE DATABASE `vt_test`", sqltypes.MakeTestResult(sqltypes.MakeTestFields("Database|Create Database", "varchar|varchar"), "vt_test|CREATE DATABASE `vt_test`"))
+	backupDB.AddQuery("SELECT TABLE_NAME, TABLE_TYPE, TABLE_ROWS FROM information_schema.TABLES WHERE TABLE_SCHEMA = 'vt_test' ORDER BY TABLE_NAME",
+		sqltypes.MakeTestResult(sqltypes.MakeTestFields("TABLE_NAME|TABLE_TYPE|TABLE_ROWS", "varchar|varchar|int64"), "t1|BASE TABLE|3", "v1|VIEW|0"))
+	backupDB.AddQuery("SHOW CREATE TABLE `vt_test`.`t1`", sqltypes.MakeTestResult(sqltypes.MakeTestFields("Table|Create Table", "varchar|varchar"), "t1|CREATE TABLE `t1` (...)"))
+	backupDB.AddQuery("SHOW CREATE VIEW `vt_test`.`v1`", sqltypes.MakeTestResult(sqltypes.MakeTestFields("View|Create View", "varchar|varchar"), "v1|CREATE VIEW `v1` AS SELECT 1"))
+	backupDB.AddQuery("SHOW COLUMNS FROM `vt_test`.`t1`", sqltypes.MakeTestResult(sqltypes.MakeTestFields("Field|Type|Null|Key|Default|Extra", "varchar|varchar|varchar|varchar|varchar|varchar"),
+		"id|bigint|NO|PRI||",
+		"name|varchar(10)|YES|||",
+		"upper_name|varchar(10)|YES|||VIRTUAL GENERATED",
+	))
+	backupDB.AddQuery("SELECT MIN(`id`), MAX(`id`) FROM `vt_test`.`t1`", sqltypes.MakeTestResult(sqltypes.MakeTestFields("min|max", "int64|int64"), "1|10"))
+	dataFields := sqltypes.MakeTestFields("id|name", "int64|varchar")
+	backupDB.AddQuery("SELECT `id`, `name` FROM `vt_test`.`t1` WHERE `id` < 6", sqltypes.MakeTestResult(dataFields, "1|a", "2|b"))
+	backupDB.AddQuery("SELECT `id`, `name` FROM `vt_test`.`t1` WHERE `id` >= 6", sqltypes.MakeTestResult(dataFields, "10|c"))
+
+	backupMysqld := NewFakeMysqlDaemon(backupDB)
+	defer backupMysqld.Close()
+	backupMysqld.SetPrimaryPositionLocked(pos)
+
+	bh, err := bs.StartBackup(ctx, "ks/0", "backup1")
+	require.NoError(t, err)
+	be := &LogicalBackupEngine{}
+	result, err := be.ExecuteBackup(ctx, BackupParams{
+		Logger:      logger,
+		Mysqld:      backupMysqld,
+		Concurrency: 2,
+		BackupTime:  time.Now(),
+		Keyspace:    "ks",
+		Shard:       "0",
+	}, bh)
+	require.NoError(t, err)
+	assert.Equal(t, BackupUsable, result)
+	require.NoError(t, bh.EndBackup(ctx))
+	assert.False(t, backupMysqld.GlobalReadLock)
+	assert.Contains(t, backupDB.QueryLog(), "start transaction with consistent snapshot, read only")
+
+	bhs, err := bs.ListBackups(ctx, "ks/0")
+	require.NoError(t, err)
+	require.Len(t, bhs, 1)
+	var bm LogicalBackupManifest
+	require.NoError(t, getBackupManifestInto(ctx, bhs[0], &bm))
+	assert.Equal(t, logicalBackupEngineName, bm.BackupMethod)
+	assert.Equal(t, pos, bm.Position)
+	assert.Empty(t, bm.MySQLVersion)
+	require.Len(t, bm.Databases, 1)
+	database := bm.Databases[0]
+	assert.Equal(t, "vt_test", database.Name)
+	require.Len(t, database.Tables, 1)
+	assert.Equal(t, []string{"id", "name"}, database.Tables[0].Columns)
+	require.Len(t, database.Tables[0].Chunks, 2)
+	assert.EqualValues(t, 2, database.Tables[0].Chunks[0].Rows)
+	assert.EqualValues(t, 1, database.Tables[0].Chunks[1].Rows)
+	require.Len(t, database.Views, 1)
+	assert.Equal(t, "CREATE VIEW `v1` AS SELECT 1", database.Views[0].CreateStatement)
+
+	// Restore the backup.
+	restored, inserts, queryLog := restoreLogicalBackup(t, be, bhs[0], pos, nil)
+	assert.Equal(t, pos, restored.Position)
+	assert.ElementsMatch(t, []string{
+		"INSERT INTO `t1` (`id`, `name`) VALUES (1,'a'),(2,'b')",
+		"INSERT INTO `t1` (`id`, `name`) VALUES (10,'c')",
+	}, inserts)
+	assert.Contains(t, queryLog, "set session sql_log_bin = 0")
+	assert.Contains(t, queryLog, "create database `vt_test`")
+	assert.Contains(t, queryLog, "create table `t1` (...)")
+	assert.Contains(t, queryLog, "create view `v1` as select 1")
+
+	// Restore the backup into two shards splitting its rows: the restored
+	// mysqld does not get the position of the backup.
+	restored, inserts, queryLog = restoreLogicalBackup(t, be, bhs[0], pos, idRowFilter(func(id int64) bool { return id < 2 }))
+	assert.True(t, restored.Position.IsZero())
+	assert.Equal(t, []string{"insert into t1(id, `name`) values (1, 'a')"}, inserts)
+	assert.Contains(t, queryLog, "create table `t1` (...)")
+	_, inserts, _ = restoreLogicalBackup(t, be, bhs[0], pos, idRowFilter(func(id int64) bool { return id >= 2 }))
+	assert.ElementsMatch(t, []string{
+		"insert into t1(id, `name`) values (2, 'b')",
+		"INSERT INTO `t1` (`id`, `name`) VALUES (10,'c')",
+	}, inserts)
+}
+
+// idRowFilter is a RestoreRowFilter keeping the rows of t1 whose id matches.
+type idRowFilter func(id int64) bool
+
+func (f idRowFilter) TableFilter(database, table string, columns []string) (*RestoreTableFilter, error) {
+	if table != "t1" {
+		return nil, nil
+	}
+	return &RestoreTableFilter{
+		Columns: []int{0},
+		Keep: func(ctx context.Context, values []sqltypes.Value) (bool, error) {
+			id, err := values[0].ToInt64()
+			return f(id), err
+		},
+	}, nil
+}
+
+// restoreLogicalBackup restores a logical backup of a position into a fake
+// mysqld, and returns the manifest, the INSERT statements and the other
+// queries executed. The position is only set if no rows are filtered.
+func restoreLogicalBackup(t *testing.T, be *LogicalBackupEngine, bh backupstorage.BackupHandle, position replication.Position, rowFilter RestoreRowFilter) (*BackupManifest, []string, string) {
+	restoreDB := fakesqldb.New(t)
+	defer restoreDB.Close()
+	restoreDB.SetNeverFail(true)
+	var mu sync.Mutex
+	var inserts []string
+	restoreDB.AddQueryPatternWithCallback("(?is)INSERT INTO .*", &sqltypes.Result{}, func(query string) {
+		mu.Lock()
+		defer mu.Unlock()
+		inserts = append(inserts, query)
+	})
+
+	restoreMysqld := NewFakeMysqlDaemon(restoreDB)
+	defer restoreMysqld.Close()
+	restoreMysqld.FetchSuperQueryMap = map[string]*sqltypes.Result{
+		"SHOW DATABASES": {},
+	}
+	restoreMysqld.ExpectedExecuteSuperQueryList = []string{"FAKE RESET ALL REPLICATION"}
+	if rowFilter == nil {
+		restoreMysqld.ExpectedExecuteSuperQueryList = append(restoreMysqld.ExpectedExecuteSuperQueryList,
+			"FAKE RESET BINARY LOGS AND GTIDS",
+			"FAKE SET GLOBAL gtid_purged",
+		)
+		restoreMysqld.SetReplicationPositionPos = position
+	}
+
+	restored, err := be.ExecuteRestore(context.Background(), RestoreParams{
+		Cnf:         &Mycnf{DataDir: path.Join(t.TempDir(), "data")},
+		Logger:      logutil.NewMemoryLogger(),
+		Mysqld:      restoreMysqld,
+		Concurrency: 2,
+		RowFilter:   rowFilter,
+	}, bh)
+	require.NoError(t, err)
+	require.NoError(t, restoreMysqld.CheckSuperQueryList())
+	return restored, inserts, restoreDB.QueryLog()
+}
+
+func TestFilterLogicalStatement(t *testing.T) {
+	parser := sqlparser.NewTestParser()
+
+	// Encode rows of all kinds of values as backupLogicalChunk does, the
+	// filter must get them back.
+	fields := []*querypb.Field{
+		{Name: "id", Type: querypb.Type_INT64},
+		{Name: "uid", Type: querypb.Type_UINT64},
+		{Name: "name", Type: querypb.Type_VARCHAR},
+		{Name: "bin", Type: querypb.Type_VARBINARY},
+		{Name: "blob", Type: querypb.Type_BLOB},
+		{Name: "dec", Type: querypb.Type_DECIMAL},
+		{Name: "flt", Type: querypb.Type_FLOAT64},
+		{Name: "bits", Type: querypb.Type_BIT},
+		{Name: "dt", Type: querypb.Type_DATETIME},
+		{Name: "location", Type: querypb.Type_GEOMETRY},
+		{Name: "note", Type: querypb.Type_TEXT},
+	}
+	rows := [][]sqltypes.Value{{
+		sqltypes.NewInt64(-5),
+		sqltypes.NewUint64(18446744073709551615),
+		sqltypes.NewVarChar("it's\na line"),
+		sqltypes.NewVarBinary("\x00\xff'\\\n"),
+		sqltypes.MakeTrusted(querypb.Type_BLOB, []byte("blob\x00")),
+		sqltypes.MakeTrusted(querypb.Type_DECIMAL, []byte("-1.50")),
+		sqltypes.NewFloat64(-1.5e-05),
+		sqltypes.MakeTrusted(querypb.Type_BIT, []byte{0x05}),
+		sqltypes.MakeTrusted(querypb.Type_DATETIME, []byte("2026-10-18 12:00:00")),
+		sqltypes.MakeTrusted(querypb.Type_GEOMETRY, []byte{0, 1, 0xff}),
+		sqltypes.NULL,
+	}, {
+		sqltypes.NewInt64(7),
+		sqltypes.NewUint64(1),
+		sqltypes.NewVarChar(""),
+		sqltypes.NewVarBinary(""),
+		sqltypes.MakeTrusted(querypb.Type_BLOB, []byte("")),
+		sqltypes.MakeTrusted(querypb.Type_DECIMAL, []byte("3")),
+		sqltypes.NewFloat64(1e+20),
+		sqltypes.MakeTrusted(querypb.Type_BIT, []byte{0}),
+		sqltypes.NULL,
+		sqltypes.NULL,
+		sqltypes.NewVarChar("note"),
+	}}
+	var buf bytes.Buffer
+	buf.WriteString("INSERT INTO `t1` (`id`, `uid`, `name`, `bin`, `blob`, `dec`, `flt`, `bits`, `dt`, `location`, `note`) VALUES ")
+	for i, row := range rows {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		encodeLogicalRow(&buf, fields, row)
+	}
+	insert := buf.String()
+
+	for column, field := range fields {
+		t.Run(field.Name, func(t *testing.T) {
+			var values []sqltypes.Value
+			filter := &RestoreTableFilter{
+				Columns: []int{column},
+				Keep: func(ctx context.Context, row []sqltypes.Value) (bool, error) {
+					values = append(values, row[0])
+					return len(values) == 1, nil
+				},
+			}
+			query, err := filterLogicalStatement(context.Background(), parser, insert, filter)
+			require.NoError(t, err)
+			require.Len(t, values, len(rows))
+			for i, row := range rows {
+				assert.Equal(t, row[column].IsNull(), values[i].IsNull())
+				assert.Equal(t, row[column].Raw(), values[i].Raw())
+			}
+
+			// The kept row is restored as it was backed up.
+			stmt, err := parser.Parse(query)
+			require.NoError(t, err)
+			kept := stmt.(*sqlparser.Insert).Rows.(sqlparser.Values)
+			require.Len(t, kept, 1)
+			for i, expr := range kept[0] {
+				value, err := logicalValue(expr)
+				require.NoError(t, err)
+				assert.Equal(t, rows[0][i].Raw(), value.Raw(), fields[i].Name)
+			}
+		})
+	}
+
+	keepNegative := &RestoreTableFilter{
+		Columns: []int{0},
+		Keep: func(ctx context.Context, row []sqltypes.Value) (bool, error) {
+			id, err := row[0].ToInt64()
+			return id < 0, err
+		},
+	}
+	query, err := filterLogicalStatement(context.Background(), parser, "INSERT INTO `t1` (`id`) VALUES (1),(2)", keepNegative)
+	require.NoError(t, err)
+	assert.Empty(t, query)
+
+	query, err = filterLogicalStatement(context.Background(), parser, "INSERT INTO `t1` (`id`) VALUES (-1),(-2)", keepNegative)
+	require.NoError(t, err)
+	assert.Equal(t, "INSERT INTO `t1` (`id`) VALUES (-1),(-2)", query)
+}
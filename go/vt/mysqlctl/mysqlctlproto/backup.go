@@ -44,3 +44,20 @@ func BackupHandleToProto(bh backupstorage.BackupHandle) *mysqlctlpb.BackupInfo {
 
 	return bi
 }
+
+// SetBackupInfoDetails sets the engine and status of a BackupInfo from the
+// catalog entry of its backup, which is nil if the backup is incomplete.
+func SetBackupInfoDetails(bi *mysqlctlpb.BackupInfo, entry *mysqlctl.BackupCatalogEntry) {
+	switch {
+	case entry == nil:
+		bi.Status = mysqlctlpb.BackupInfo_INCOMPLETE
+		return
+	case entry.Verification == nil:
+		bi.Status = mysqlctlpb.BackupInfo_COMPLETE
+	case entry.Verification.Success:
+		bi.Status = mysqlctlpb.BackupInfo_VALID
+	default:
+		bi.Status = mysqlctlpb.BackupInfo_INVALID
+	}
+	bi.Engine = entry.BackupMethod
+}
@@ -23,6 +23,7 @@ import (
 
 	"vitess.io/vitess/go/protoutil"
 	"vitess.io/vitess/go/test/utils"
+	"vitess.io/vitess/go/vt/mysqlctl"
 	"vitess.io/vitess/go/vt/mysqlctl/backupstorage"
 
 	mysqlctlpb "vitess.io/vitess/go/vt/proto/mysqlctl"
@@ -110,3 +111,49 @@ func TestBackupHandleToProto(t *testing.T) {
 		})
 	}
 }
+
+func TestSetBackupInfoDetails(t *testing.T) {
+	t.Parallel()
+
+	entry := func(verification *mysqlctl.BackupCatalogVerification) *mysqlctl.BackupCatalogEntry {
+		return &mysqlctl.BackupCatalogEntry{
+			BackupManifest: mysqlctl.BackupManifest{BackupMethod: "builtin"},
+			Verification:   verification,
+		}
+	}
+	tests := []struct {
+		name  string
+		entry *mysqlctl.BackupCatalogEntry
+		want  *mysqlctlpb.BackupInfo
+	}{
+		{
+			name: "incomplete",
+			want: &mysqlctlpb.BackupInfo{Status: mysqlctlpb.BackupInfo_INCOMPLETE},
+		},
+		{
+			name:  "complete",
+			entry: entry(nil),
+			want:  &mysqlctlpb.BackupInfo{Engine: "builtin", Status: mysqlctlpb.BackupInfo_COMPLETE},
+		},
+		{
+			name:  "valid",
+			entry: entry(&mysqlctl.BackupCatalogVerification{Success: true}),
+			want:  &mysqlctlpb.BackupInfo{Engine: "builtin", Status: mysqlctlpb.BackupInfo_VALID},
+		},
+		{
+			name:  "invalid",
+			entry: entry(&mysqlctl.BackupCatalogVerification{}),
+			want:  &mysqlctlpb.BackupInfo{Engine: "builtin", Status: mysqlctlpb.BackupInfo_INVALID},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got := &mysqlctlpb.BackupInfo{}
+			SetBackupInfoDetails(got, tt.entry)
+			utils.MustMatch(t, tt.want, got)
+		})
+	}
+}
@@ -0,0 +1,197 @@
+/*
+Copyright 2026 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package mysqlctl
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"vitess.io/vitess/go/vt/logutil"
+	"vitess.io/vitess/go/vt/mysqlctl/backupstorage"
+	"vitess.io/vitess/go/vt/proto/vtrpc"
+	"vitess.io/vitess/go/vt/vterrors"
+)
+
+// RetentionPolicy defines which backups of a shard PruneBackups keeps. A
+// backup is kept if any of the rules retains it, and so are all the backups
+// a retained incremental backup needs to be restored.
+type RetentionPolicy struct {
+	// KeepFull is the number of most recent full backups to keep. It must
+	// be at least 1, so that the shard can always be restored.
+	KeepFull int
+	// KeepFor, if non-zero, keeps the backups taken less than KeepFor ago.
+	KeepFor time.Duration
+	// KeepRecoveryFrom, if non-zero, keeps the backups needed for a point in
+	// time recovery to any time since KeepRecoveryFrom.
+	KeepRecoveryFrom time.Time
+}
+
+// prunableBackup is a backup PruneBackups considers for removal.
+type prunableBackup struct {
+	name string
+	time time.Time
+	// manifest is nil if the backup has no readable MANIFEST, i.e. if it
+	// failed or is still in progress.
+	manifest *BackupManifest
+}
+
+// PruneBackups removes the backups of a directory that the retention policy
+// does not keep. It never removes a full or incremental backup that a kept
+// incremental backup needs to be restored. With dryRun, nothing is removed.
+// It returns the names of the removed backups, and the reason each of the
+// other backups is kept for.
+func PruneBackups(ctx context.Context, bs backupstorage.BackupStorage, dir string, policy RetentionPolicy, dryRun bool, logger logutil.Logger) (removed []string, kept map[string]string, err error) {
+	if policy.KeepFull < 1 {
+		return nil, nil, vterrors.Errorf(vtrpc.Code_INVALID_ARGUMENT, "at least one full backup must be kept")
+	}
+
+	bhs, err := bs.ListBackups(ctx, dir)
+	if err != nil {
+		return nil, nil, vterrors.Wrap(err, "ListBackups failed")
+	}
+	catalog := loadBackupCatalog(ctx, bs, dir, logger)
+	backups := make([]prunableBackup, 0, len(bhs))
+	kept = make(map[string]string)
+	for _, bh := range bhs {
+		backupTime, _, err := ParseBackupName(dir, bh.Name())
+		if err != nil || backupTime == nil {
+			// Backups that can't be dated are never removed.
+			kept[bh.Name()] = "unknown backup time"
+			continue
+		}
+		b := prunableBackup{name: bh.Name(), time: *backupTime}
+		if b.manifest, err = catalog.GetManifest(ctx, bh); err != nil {
+			logger.Warningf("PruneBackups: possibly incomplete backup %v/%v: %v", dir, bh.Name(), err)
+		}
+		backups = append(backups, b)
+	}
+
+	for name, reason := range retainBackups(time.Now(), backups, policy) {
+		kept[name] = reason
+	}
+	for _, b := range backups {
+		if _, ok := kept[b.name]; ok {
+			continue
+		}
+		removed = append(removed, b.name)
+		if dryRun {
+			logger.Infof("PruneBackups: would remove backup %v/%v", dir, b.name)
+			continue
+		}
+		logger.Infof("PruneBackups: removing backup %v/%v", dir, b.name)
+		if err := RemoveBackup(ctx, bs, dir, b.name, logger); err != nil {
+			return nil, nil, vterrors.Wrapf(err, "couldn't remove backup %v/%v", dir, b.name)
+		}
+	}
+
+	if !dryRun && len(removed) > 0 {
+		// Remove the chunks of deduplicated backups that only the pruned
+		// backups referenced.
+		if _, err := RemoveUnreferencedChunks(ctx, bs, dir); err != nil {
+			return nil, nil, vterrors.Wrapf(err, "couldn't remove unreferenced chunks from %v", dir)
+		}
+	}
+	return removed, kept, nil
+}
+
+// retainBackups returns the names of the backups, given sorted by time, that
+// the retention policy keeps, with the reason why.
+func retainBackups(now time.Time, backups []prunableBackup, policy RetentionPolicy) map[string]string {
+	kept := make(map[string]string)
+	keep := func(b prunableBackup, reason string) {
+		if _, ok := kept[b.name]; !ok {
+			kept[b.name] = reason
+		}
+	}
+
+	var fulls []int
+	lastComplete := -1
+	for i, b := range backups {
+		if b.manifest == nil {
+			continue
+		}
+		lastComplete = i
+		if !b.manifest.Incremental {
+			fulls = append(fulls, i)
+		}
+	}
+
+	// The most recent full backups.
+	for _, i := range fulls[max(0, len(fulls)-policy.KeepFull):] {
+		keep(backups[i], fmt.Sprintf("one of the %d most recent full backups", policy.KeepFull))
+	}
+
+	// The recent backups, and those that may still be in progress.
+	for i, b := range backups {
+		switch {
+		case policy.KeepFor > 0 && now.Sub(b.time) < policy.KeepFor:
+			keep(b, fmt.Sprintf("taken less than %v ago", policy.KeepFor))
+		case b.manifest == nil && i > lastComplete:
+			keep(b, "possibly in progress")
+		}
+	}
+
+	// Point in time recoveries since KeepRecoveryFrom start from the last
+	// full backup taken at or before it, and may use any backup after it.
+	if !policy.KeepRecoveryFrom.IsZero() && len(fulls) > 0 {
+		base := fulls[0]
+		for _, i := range fulls {
+			if backups[i].time.After(policy.KeepRecoveryFrom) {
+				break
+			}
+			base = i
+		}
+		for _, b := range backups[base:] {
+			if b.manifest != nil {
+				keep(b, "needed for point in time recovery since "+FormatRFC3339(policy.KeepRecoveryFrom))
+			}
+		}
+	}
+
+	// A kept incremental backup needs the full backup, and incremental
+	// backups, a restore to its position goes through.
+	manifests := make([]*BackupManifest, 0, len(backups))
+	names := make(map[*BackupManifest]string, len(backups))
+	for _, b := range backups {
+		if b.manifest != nil {
+			manifests = append(manifests, b.manifest)
+			names[b.manifest] = b.name
+		}
+	}
+	for i, b := range backups {
+		if _, ok := kept[b.name]; !ok || b.manifest == nil || !b.manifest.Incremental {
+			continue
+		}
+		reason := "needed to restore incremental backup " + b.name
+		path, err := FindPITRPath(b.manifest.Position.GTIDSet, manifests)
+		if err != nil {
+			// The restore path can't be figured out: keep everything
+			// the incremental backup may depend on.
+			for _, dep := range backups[:i] {
+				if dep.manifest != nil {
+					keep(dep, reason)
+				}
+			}
+			continue
+		}
+		for _, m := range path {
+			keep(prunableBackup{name: names[m]}, reason)
+		}
+	}
+	return kept
+}
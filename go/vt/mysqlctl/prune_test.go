@@ -0,0 +1,149 @@
+/*
+Copyright 2026 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package mysqlctl
+
+import (
+	"context"
+	"encoding/json"
+	"maps"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"vitess.io/vitess/go/mysql/replication"
+	"vitess.io/vitess/go/vt/logutil"
+	"vitess.io/vitess/go/vt/mysqlctl/backupstorage"
+	"vitess.io/vitess/go/vt/mysqlctl/filebackupstorage"
+)
+
+func TestPruneBackups(t *testing.T) {
+	ctx := context.Background()
+	oldRoot := filebackupstorage.FileBackupStorageRoot
+	defer func() { filebackupstorage.FileBackupStorageRoot = oldRoot }()
+	filebackupstorage.FileBackupStorageRoot = t.TempDir()
+
+	bs := backupstorage.BackupStorageMap["file"]
+	dir := "ks/0"
+	pos := func(gtids string) replication.Position {
+		return replication.MustParsePosition(replication.Mysql56FlavorID, "16b1039f-22b6-11ed-b765-0a43f95f28a3:"+gtids)
+	}
+	// addBackup adds a backup, with a MANIFEST unless manifest is nil.
+	addBackup := func(day int, manifest *BackupManifest) string {
+		name := time.Date(2025, 1, day, 0, 0, 0, 0, time.UTC).Format(BackupTimestampFormat) + ".zone1-0000000100"
+		bh, err := bs.StartBackup(ctx, dir, name)
+		require.NoError(t, err)
+		if manifest != nil {
+			wc, err := bh.AddFile(ctx, backupManifestFileName, backupstorage.FileSizeUnknown)
+			require.NoError(t, err)
+			require.NoError(t, json.NewEncoder(wc).Encode(manifest))
+			require.NoError(t, wc.Close())
+		}
+		require.NoError(t, bh.EndBackup(ctx))
+		return name
+	}
+	full1 := addBackup(1, &BackupManifest{Position: pos("1-100")})
+	inc1 := addBackup(2, &BackupManifest{Incremental: true, FromPosition: pos("1-100"), Position: pos("1-200")})
+	inc2 := addBackup(3, &BackupManifest{Incremental: true, FromPosition: pos("1-200"), Position: pos("1-300")})
+	full2 := addBackup(4, &BackupManifest{Position: pos("1-400")})
+	inc3 := addBackup(5, &BackupManifest{Incremental: true, FromPosition: pos("1-400"), Position: pos("1-500")})
+	failed := addBackup(6, nil)
+	full3 := addBackup(7, &BackupManifest{Position: pos("1-700")})
+	inProgress := addBackup(8, nil)
+	all := []string{full1, inc1, inc2, full2, inc3, failed, full3, inProgress}
+
+	list := func() []string {
+		bhs, err := bs.ListBackups(ctx, dir)
+		require.NoError(t, err)
+		var names []string
+		for _, bh := range bhs {
+			names = append(names, bh.Name())
+		}
+		return names
+	}
+
+	_, _, err := PruneBackups(ctx, bs, dir, RetentionPolicy{}, true, logutil.NewMemoryLogger())
+	require.ErrorContains(t, err, "at least one full backup must be kept")
+
+	// Keeping the backups needed for a point in time recovery since the
+	// second day keeps the first full backup, and everything after it but
+	// the failed backup.
+	removed, kept, err := PruneBackups(ctx, bs, dir, RetentionPolicy{KeepFull: 1, KeepRecoveryFrom: time.Date(2025, 1, 2, 12, 0, 0, 0, time.UTC)}, true, logutil.NewMemoryLogger())
+	require.NoError(t, err)
+	assert.Equal(t, []string{failed}, removed)
+	assert.Equal(t, "needed for point in time recovery since 2025-01-02T12:00:00Z", kept[full1])
+	assert.Equal(t, "one of the 1 most recent full backups", kept[full3])
+	assert.Equal(t, "possibly in progress", kept[inProgress])
+
+	// Nothing is removed in a dry run.
+	assert.Equal(t, all, list())
+
+	// Only the last full backup, and the backup in progress, are kept.
+	removed, kept, err = PruneBackups(ctx, bs, dir, RetentionPolicy{KeepFull: 1}, true, logutil.NewMemoryLogger())
+	require.NoError(t, err)
+	assert.Equal(t, []string{full1, inc1, inc2, full2, inc3, failed}, removed)
+	assert.ElementsMatch(t, []string{full3, inProgress}, slices.Collect(maps.Keys(kept)))
+
+	// A recent incremental backup keeps the backups it needs to be
+	// restored, even if they are not recent.
+	removed, kept, err = PruneBackups(ctx, bs, dir, RetentionPolicy{KeepFull: 1, KeepFor: time.Since(time.Date(2025, 1, 2, 12, 0, 0, 0, time.UTC))}, false, logutil.NewMemoryLogger())
+	require.NoError(t, err)
+	assert.Empty(t, removed)
+	assert.Equal(t, "needed to restore incremental backup "+inc2, kept[full1])
+	assert.Equal(t, "needed to restore incremental backup "+inc2, kept[inc1])
+	assert.Equal(t, all, list())
+
+	// Once the incremental backups of the first full backup expire, they
+	// are removed with it.
+	removed, kept, err = PruneBackups(ctx, bs, dir, RetentionPolicy{KeepFull: 2}, false, logutil.NewMemoryLogger())
+	require.NoError(t, err)
+	assert.Equal(t, []string{full1, inc1, inc2, inc3, failed}, removed)
+	assert.Equal(t, []string{full2, full3, inProgress}, list())
+	assert.Len(t, kept, 3)
+}
+
+func TestRetainBackups(t *testing.T) {
+	pos := func(gtids string) replication.Position {
+		return replication.MustParsePosition(replication.Mysql56FlavorID, "16b1039f-22b6-11ed-b765-0a43f95f28a3:"+gtids)
+	}
+	day := func(d int) time.Time {
+		return time.Date(2025, 1, d, 0, 0, 0, 0, time.UTC)
+	}
+	backups := []prunableBackup{
+		{name: "full1", time: day(1), manifest: &BackupManifest{Position: pos("1-100")}},
+		{name: "full2", time: day(2), manifest: &BackupManifest{Position: pos("1-200")}},
+		{name: "inc1", time: day(3), manifest: &BackupManifest{Incremental: true, FromPosition: pos("1-150"), Position: pos("1-300")}},
+		{name: "full3", time: day(4), manifest: &BackupManifest{Position: pos("1-400")}},
+		// This incremental backup can't be restored: there is a gap
+		// between its position and the one of the backups before it.
+		{name: "inc2", time: day(5), manifest: &BackupManifest{Incremental: true, FromPosition: pos("1-450"), Position: pos("1-500")}},
+	}
+
+	// An incremental backup is restored from the latest full backup
+	// before it, not necessarily the one it was taken from.
+	kept := retainBackups(day(10), backups[:4], RetentionPolicy{KeepFull: 1, KeepFor: 7*24*time.Hour + 12*time.Hour})
+	assert.ElementsMatch(t, []string{"full2", "inc1", "full3"}, slices.Collect(maps.Keys(kept)))
+	assert.Equal(t, "needed to restore incremental backup inc1", kept["full2"])
+
+	// When the restore path of an incremental backup can't be found, all
+	// the backups before it are kept.
+	kept = retainBackups(day(10), backups, RetentionPolicy{KeepFull: 1, KeepFor: 5*24*time.Hour + 12*time.Hour})
+	assert.Len(t, kept, 5)
+	assert.Equal(t, "needed to restore incremental backup inc2", kept["full1"])
+}
@@ -0,0 +1,104 @@
+/*
+Copyright 2026 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package s3backupstorage
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/aws-sdk-go-v2/service/s3/types"
+	"github.com/aws/smithy-go"
+
+	"vitess.io/vitess/go/vt/mysqlctl/backupstorage"
+)
+
+// catalogObjectName is the name of the object holding the catalog of a
+// directory.
+const catalogObjectName = "CATALOG"
+
+// ReadCatalog is part of the backupstorage.CatalogStorage interface. The
+// version of a catalog is the ETag of its object.
+func (bs *S3BackupStorage) ReadCatalog(ctx context.Context, dir string) ([]byte, string, error) {
+	c, err := bs.client()
+	if err != nil {
+		return nil, "", err
+	}
+
+	object := objName(backupstorage.CatalogDir, dir, catalogObjectName)
+	out, err := c.GetObject(ctx, &s3.GetObjectInput{
+		Bucket:               &bucket,
+		Key:                  &object,
+		SSECustomerAlgorithm: bs.s3SSE.customerAlg,
+		SSECustomerKey:       bs.s3SSE.customerKey,
+		SSECustomerKeyMD5:    bs.s3SSE.customerMd5,
+	})
+	if err != nil {
+		var noSuchKey *types.NoSuchKey
+		if errors.As(err, &noSuchKey) {
+			return nil, "", nil
+		}
+		return nil, "", err
+	}
+	defer out.Body.Close()
+	data, err := io.ReadAll(out.Body)
+	if err != nil {
+		return nil, "", err
+	}
+	return data, aws.ToString(out.ETag), nil
+}
+
+// WriteCatalog is part of the backupstorage.CatalogStorage interface. It
+// relies on the conditional writes of S3.
+func (bs *S3BackupStorage) WriteCatalog(ctx context.Context, dir string, data []byte, version string) error {
+	c, err := bs.client()
+	if err != nil {
+		return err
+	}
+
+	object := objName(backupstorage.CatalogDir, dir, catalogObjectName)
+	input := &s3.PutObjectInput{
+		Bucket:               &bucket,
+		Key:                  &object,
+		Body:                 bytes.NewReader(data),
+		ServerSideEncryption: bs.s3SSE.awsAlg,
+		SSECustomerAlgorithm: bs.s3SSE.customerAlg,
+		SSECustomerKey:       bs.s3SSE.customerKey,
+		SSECustomerKeyMD5:    bs.s3SSE.customerMd5,
+	}
+	if version == "" {
+		input.IfNoneMatch = aws.String("*")
+	} else {
+		input.IfMatch = aws.String(version)
+	}
+	if _, err := c.PutObject(ctx, input); err != nil {
+		var apiErr smithy.APIError
+		if errors.As(err, &apiErr) {
+			switch apiErr.ErrorCode() {
+			case "PreconditionFailed", "ConditionalRequestConflict":
+				return backupstorage.ErrCatalogConflict
+			}
+		}
+		return err
+	}
+	return nil
+}
+
+var _ backupstorage.CatalogStorage = (*S3BackupStorage)(nil)
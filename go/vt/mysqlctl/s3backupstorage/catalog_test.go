@@ -0,0 +1,60 @@
+/*
+Copyright 2026 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package s3backupstorage
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"vitess.io/vitess/go/vt/logutil"
+	"vitess.io/vitess/go/vt/mysqlctl/backupstorage"
+)
+
+func TestCatalog(t *testing.T) {
+	s := newLocalS3(t)
+	ctx := context.Background()
+	bs := newS3BackupStorage().WithParams(backupstorage.Params{
+		Logger: logutil.NewMemoryLogger(),
+		Stats:  backupstorage.NoParams().Stats,
+	})
+	defer bs.Close()
+	cs := bs.(backupstorage.CatalogStorage)
+	dir := "keyspace/shard"
+
+	// There is no catalog yet.
+	data, version, err := cs.ReadCatalog(ctx, dir)
+	require.NoError(t, err)
+	assert.Nil(t, data)
+	assert.Empty(t, version)
+	require.ErrorIs(t, cs.WriteCatalog(ctx, dir, []byte("v1"), `"some-etag"`), backupstorage.ErrCatalogConflict)
+	require.NoError(t, cs.WriteCatalog(ctx, dir, []byte("v1"), ""))
+	assert.Contains(t, s.objects, ".catalog/keyspace/shard/CATALOG")
+
+	// Only the writer that read the current version replaces the catalog.
+	data, version, err = cs.ReadCatalog(ctx, dir)
+	require.NoError(t, err)
+	assert.Equal(t, "v1", string(data))
+	require.NoError(t, cs.WriteCatalog(ctx, dir, []byte("v2"), version))
+	require.ErrorIs(t, cs.WriteCatalog(ctx, dir, []byte("v2 bis"), version), backupstorage.ErrCatalogConflict)
+	require.ErrorIs(t, cs.WriteCatalog(ctx, dir, []byte("v2 bis"), ""), backupstorage.ErrCatalogConflict)
+	data, _, err = cs.ReadCatalog(ctx, dir)
+	require.NoError(t, err)
+	assert.Equal(t, "v2", string(data))
+}
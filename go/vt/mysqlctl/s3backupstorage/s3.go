@@ -24,6 +24,7 @@ limitations under the License.
 package s3backupstorage
 
 import (
+	"bytes"
 	"context"
 	"crypto/md5"
 	"crypto/tls"
@@ -33,6 +34,7 @@ import (
 	"io"
 	"math"
 	"net/http"
+	"net/url"
 	"os"
 	"sort"
 	"strings"
@@ -97,6 +99,9 @@ var (
 	// minimum part size
 	minPartSize int64
 
+	// resumableUploads makes files be uploaded with checkpointed multipart uploads.
+	resumableUploads bool
+
 	ErrPartSize = errors.New("minimum S3 part size must be between 5MiB and 5GiB")
 )
 
@@ -112,6 +117,7 @@ func registerFlags(fs *pflag.FlagSet) {
 	utils.SetFlagStringVar(fs, &requiredLogLevel, "s3-backup-log-level", "LogOff", "determine the S3 loglevel to use from LogOff, LogDebug, LogDebugWithSigning, LogDebugWithHTTPBody, LogDebugWithRequestRetries, LogDebugWithRequestErrors.")
 	utils.SetFlagStringVar(fs, &sse, "s3-backup-server-side-encryption", "", "server-side encryption algorithm (e.g., AES256, aws:kms, sse_c:/path/to/key/file).")
 	utils.SetFlagInt64Var(fs, &minPartSize, "s3-backup-aws-min-partsize", manager.MinUploadPartSize, "Minimum part size to use, defaults to 5MiB but can be increased due to the dataset size.")
+	utils.SetFlagBoolVar(fs, &resumableUploads, "s3-backup-resumable-uploads", false, "upload the files larger than a part one part at a time, checkpointing the uploaded parts in a sidecar object, so that retrying a failed upload resumes from the last uploaded part.")
 }
 
 func init() {
@@ -145,6 +151,7 @@ func newEndpointResolver() *endpointResolver {
 type iClient interface {
 	manager.UploadAPIClient
 	manager.DownloadAPIClient
+	DeleteObject(ctx context.Context, params *s3.DeleteObjectInput, optFns ...func(*s3.Options)) (*s3.DeleteObjectOutput, error)
 }
 
 type clientWrapper struct {
@@ -160,6 +167,11 @@ type S3BackupHandle struct {
 	readOnly  bool
 	waitGroup sync.WaitGroup
 	errorsbackup.PerFileErrorRecorder
+
+	// uploads maps the files whose resumable upload is not completed to
+	// the ID of their multipart upload.
+	uploadsMu sync.Mutex
+	uploads   map[string]string
 }
 
 // Directory is part of the backupstorage.BackupHandle interface.
@@ -185,6 +197,10 @@ func (bh *S3BackupHandle) AddFile(ctx context.Context, filename string, filesize
 
 	bh.bs.params.Logger.Infof("Using S3 upload part size: %s", humanize.IBytes(uint64(partSizeBytes)))
 
+	if resumableUploads && filesize > partSizeBytes {
+		return bh.newResumableObject(ctx, filename, partSizeBytes)
+	}
+
 	reader, writer := io.Pipe()
 	bh.handleAddFile(ctx, filename, partSizeBytes, reader, func(err error) {
 		reader.CloseWithError(err)
@@ -230,6 +246,21 @@ func (bh *S3BackupHandle) handleAddFile(ctx context.Context, filename string, pa
 	}()
 }
 
+// withSendStats records the duration of every attempt of a request.
+func (bh *S3BackupHandle) withSendStats() func(*s3.Options) {
+	sendStats := bh.bs.params.Stats.Scope(stats.Operation("AWS:Request:Send"))
+	return func(o *s3.Options) {
+		o.APIOptions = append(o.APIOptions, func(stack *middleware.Stack) error {
+			return stack.Finalize.Add(middleware.FinalizeMiddlewareFunc("CompleteAttemptMiddleware", func(ctx context.Context, input middleware.FinalizeInput, next middleware.FinalizeHandler) (middleware.FinalizeOutput, middleware.Metadata, error) {
+				start := time.Now()
+				output, metadata, err := next.HandleFinalize(ctx, input)
+				sendStats.TimedIncrement(time.Since(start))
+				return output, metadata, err
+			}), middleware.Before)
+		})
+	}
+}
+
 // calculateUploadPartSize is a helper to calculate the part size, taking into consideration the minimum part size
 // passed in by an operator.
 func calculateUploadPartSize(filesize int64) (partSizeBytes int64, err error) {
@@ -270,6 +301,9 @@ func (bh *S3BackupHandle) AbortBackup(ctx context.Context) error {
 	if bh.readOnly {
 		return fmt.Errorf("AbortBackup cannot be called on read-only backup")
 	}
+	if err := bh.abortUploads(ctx); err != nil {
+		return err
+	}
 	return bh.bs.RemoveBackup(ctx, bh.dir, bh.name)
 }
 
@@ -296,12 +330,105 @@ func (bh *S3BackupHandle) ReadFile(ctx context.Context, filename string) (io.Rea
 			}), middleware.Before)
 		})
 	})
+	if err != nil {
+		var noSuchKey *types.NoSuchKey
+		if errors.As(err, &noSuchKey) {
+			return nil, fmt.Errorf("%w: %w", backupstorage.ErrFileNotFound, err)
+		}
+		return nil, err
+	}
+	return out.Body, nil
+}
+
+// HasChunk is part of the backupstorage.ChunkHandle interface.
+func (bh *S3BackupHandle) HasChunk(ctx context.Context, name string) (bool, error) {
+	c, err := bh.bs.client()
+	if err != nil {
+		return false, err
+	}
+	object := objName(backupstorage.ChunksDir, bh.dir, name)
+	_, err = c.HeadObject(ctx, &s3.HeadObjectInput{
+		Bucket:               &bucket,
+		Key:                  &object,
+		SSECustomerAlgorithm: bh.bs.s3SSE.customerAlg,
+		SSECustomerKey:       bh.bs.s3SSE.customerKey,
+		SSECustomerKeyMD5:    bh.bs.s3SSE.customerMd5,
+	})
+	if err != nil {
+		var notFound *types.NotFound
+		if errors.As(err, &notFound) {
+			return false, nil
+		}
+		return false, err
+	}
+
+	// Refresh the modification time of the chunk by copying it onto itself,
+	// which S3 only allows along with a change of its metadata.
+	source := (&url.URL{Path: bucket + "/" + object}).EscapedPath()
+	_, err = c.CopyObject(ctx, &s3.CopyObjectInput{
+		Bucket:                         &bucket,
+		Key:                            &object,
+		CopySource:                     &source,
+		MetadataDirective:              types.MetadataDirectiveReplace,
+		Metadata:                       map[string]string{"last-used": time.Now().UTC().Format(time.RFC3339)},
+		ServerSideEncryption:           bh.bs.s3SSE.awsAlg,
+		SSECustomerAlgorithm:           bh.bs.s3SSE.customerAlg,
+		SSECustomerKey:                 bh.bs.s3SSE.customerKey,
+		SSECustomerKeyMD5:              bh.bs.s3SSE.customerMd5,
+		CopySourceSSECustomerAlgorithm: bh.bs.s3SSE.customerAlg,
+		CopySourceSSECustomerKey:       bh.bs.s3SSE.customerKey,
+		CopySourceSSECustomerKeyMD5:    bh.bs.s3SSE.customerMd5,
+	})
+	if err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// AddChunk is part of the backupstorage.ChunkHandle interface.
+func (bh *S3BackupHandle) AddChunk(ctx context.Context, name string, data []byte) error {
+	if bh.readOnly {
+		return fmt.Errorf("AddChunk cannot be called on read-only backup")
+	}
+	c, err := bh.bs.client()
+	if err != nil {
+		return err
+	}
+	// A PutObject is atomic: the chunk is only visible once complete.
+	object := objName(backupstorage.ChunksDir, bh.dir, name)
+	_, err = c.PutObject(ctx, &s3.PutObjectInput{
+		Bucket:               &bucket,
+		Key:                  &object,
+		Body:                 bytes.NewReader(data),
+		ServerSideEncryption: bh.bs.s3SSE.awsAlg,
+		SSECustomerAlgorithm: bh.bs.s3SSE.customerAlg,
+		SSECustomerKey:       bh.bs.s3SSE.customerKey,
+		SSECustomerKeyMD5:    bh.bs.s3SSE.customerMd5,
+	})
+	return err
+}
+
+// ReadChunk is part of the backupstorage.ChunkHandle interface.
+func (bh *S3BackupHandle) ReadChunk(ctx context.Context, name string) (io.ReadCloser, error) {
+	if !bh.readOnly {
+		return nil, fmt.Errorf("ReadChunk cannot be called on read-write backup")
+	}
+	object := objName(backupstorage.ChunksDir, bh.dir, name)
+	out, err := bh.client.GetObject(ctx, &s3.GetObjectInput{
+		Bucket:               &bucket,
+		Key:                  &object,
+		SSECustomerAlgorithm: bh.bs.s3SSE.customerAlg,
+		SSECustomerKey:       bh.bs.s3SSE.customerKey,
+		SSECustomerKeyMD5:    bh.bs.s3SSE.customerMd5,
+	})
 	if err != nil {
 		return nil, err
 	}
 	return out.Body, nil
 }
 
+var _ backupstorage.ChunkHandle = (*S3BackupHandle)(nil)
+
 var _ backupstorage.BackupHandle = (*S3BackupHandle)(nil)
 
 type S3ServerSideEncryption struct {
@@ -491,6 +618,84 @@ func (bs *S3BackupStorage) RemoveBackup(ctx context.Context, dir, name string) e
 	return nil
 }
 
+// ReopenBackup is part of the backupstorage.ReopenStorage interface.
+func (bs *S3BackupStorage) ReopenBackup(ctx context.Context, dir, name string) (backupstorage.BackupHandle, error) {
+	return bs.StartBackup(ctx, dir, name)
+}
+
+// ListChunks is part of the backupstorage.ChunkStorage interface.
+func (bs *S3BackupStorage) ListChunks(ctx context.Context, dir string) ([]backupstorage.ChunkInfo, error) {
+	log.Infof("ListChunks: [s3] dir: %v, bucket: %v", dir, bucket)
+	c, err := bs.client()
+	if err != nil {
+		return nil, err
+	}
+
+	searchPrefix := objName(backupstorage.ChunksDir, dir, "")
+	query := &s3.ListObjectsV2Input{
+		Bucket: &bucket,
+		Prefix: &searchPrefix,
+	}
+
+	var chunks []backupstorage.ChunkInfo
+	for {
+		objs, err := c.ListObjectsV2(ctx, query)
+		if err != nil {
+			return nil, err
+		}
+		for _, obj := range objs.Contents {
+			chunks = append(chunks, backupstorage.ChunkInfo{
+				Name:    strings.TrimPrefix(*obj.Key, searchPrefix),
+				ModTime: aws.ToTime(obj.LastModified),
+			})
+		}
+
+		if objs.NextContinuationToken == nil {
+			break
+		}
+		query.ContinuationToken = objs.NextContinuationToken
+	}
+	return chunks, nil
+}
+
+// RemoveChunk is part of the backupstorage.ChunkStorage interface. The
+// general purpose buckets do not support conditional deletes, so the
+// modification time of the chunk is checked right before deleting it.
+func (bs *S3BackupStorage) RemoveChunk(ctx context.Context, dir string, chunk backupstorage.ChunkInfo) (bool, error) {
+	c, err := bs.client()
+	if err != nil {
+		return false, err
+	}
+
+	object := objName(backupstorage.ChunksDir, dir, chunk.Name)
+	out, err := c.HeadObject(ctx, &s3.HeadObjectInput{
+		Bucket:               &bucket,
+		Key:                  &object,
+		SSECustomerAlgorithm: bs.s3SSE.customerAlg,
+		SSECustomerKey:       bs.s3SSE.customerKey,
+		SSECustomerKeyMD5:    bs.s3SSE.customerMd5,
+	})
+	if err != nil {
+		var notFound *types.NotFound
+		if errors.As(err, &notFound) {
+			return true, nil
+		}
+		return false, err
+	}
+	if aws.ToTime(out.LastModified).After(chunk.ModTime) {
+		return false, nil
+	}
+
+	// Deleting a missing object is not an error in S3.
+	_, err = c.DeleteObject(ctx, &s3.DeleteObjectInput{
+		Bucket: &bucket,
+		Key:    &object,
+	})
+	return err == nil, err
+}
+
+var _ backupstorage.ChunkStorage = (*S3BackupStorage)(nil)
+
 // Close is part of the backupstorage.BackupStorage interface.
 func (bs *S3BackupStorage) Close() error {
 	bs.mu.Lock()
@@ -504,6 +709,8 @@ func (bs *S3BackupStorage) WithParams(params backupstorage.Params) backupstorage
 	return &S3BackupStorage{params: params, transport: bs.transport}
 }
 
+var _ backupstorage.ReopenStorage = (*S3BackupStorage)(nil)
+
 var _ backupstorage.BackupStorage = (*S3BackupStorage)(nil)
 
 // getLogLevel converts the string loglevel to an aws.LogLevelType
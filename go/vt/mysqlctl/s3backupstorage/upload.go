@@ -0,0 +1,239 @@
+/*
+Copyright 2026 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package s3backupstorage
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/aws-sdk-go-v2/service/s3/types"
+	"github.com/aws/smithy-go"
+
+	"vitess.io/vitess/go/vt/log"
+	"vitess.io/vitess/go/vt/mysqlctl/backupstorage"
+)
+
+// s3PartUploader implements backupstorage.PartUploader with an S3 multipart
+// upload. The checkpoint is stored in a sidecar object next to the object
+// being uploaded, so that a failed upload can be resumed.
+type s3PartUploader struct {
+	bh               *S3BackupHandle
+	filename         string
+	object           string
+	checkpointObject string
+	uploadID         string
+}
+
+// newResumableObject starts the multipart upload of a file, or resumes the
+// one a previous attempt to upload the file left checkpointed.
+func (bh *S3BackupHandle) newResumableObject(ctx context.Context, filename string, partSize int64) (backupstorage.ResumableWriter, error) {
+	u := &s3PartUploader{
+		bh:               bh,
+		filename:         filename,
+		object:           objName(bh.dir, bh.name, filename),
+		checkpointObject: objName(bh.dir, bh.name, filename+backupstorage.UploadCheckpointSuffix),
+	}
+
+	checkpoint, err := u.loadCheckpoint(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if checkpoint == nil || checkpoint.PartSize != partSize || checkpoint.UploadID == "" {
+		out, err := bh.client.CreateMultipartUpload(ctx, &s3.CreateMultipartUploadInput{
+			Bucket:               &bucket,
+			Key:                  &u.object,
+			ServerSideEncryption: bh.bs.s3SSE.awsAlg,
+			SSECustomerAlgorithm: bh.bs.s3SSE.customerAlg,
+			SSECustomerKey:       bh.bs.s3SSE.customerKey,
+			SSECustomerKeyMD5:    bh.bs.s3SSE.customerMd5,
+		})
+		if err != nil {
+			return nil, err
+		}
+		checkpoint = &backupstorage.UploadCheckpoint{UploadID: *out.UploadId, PartSize: partSize}
+	} else {
+		bh.bs.params.Logger.Infof("Resuming S3 upload of %v from %v committed parts", filename, len(checkpoint.Parts))
+	}
+	u.uploadID = checkpoint.UploadID
+
+	bh.uploadsMu.Lock()
+	if bh.uploads == nil {
+		bh.uploads = make(map[string]string)
+	}
+	bh.uploads[filename] = u.uploadID
+	bh.uploadsMu.Unlock()
+
+	return backupstorage.NewResumableWriter(ctx, u, checkpoint), nil
+}
+
+// loadCheckpoint returns the checkpoint of a previous upload of the file, or
+// nil if there is none.
+func (u *s3PartUploader) loadCheckpoint(ctx context.Context) (*backupstorage.UploadCheckpoint, error) {
+	out, err := u.bh.client.GetObject(ctx, &s3.GetObjectInput{
+		Bucket:               &bucket,
+		Key:                  &u.checkpointObject,
+		SSECustomerAlgorithm: u.bh.bs.s3SSE.customerAlg,
+		SSECustomerKey:       u.bh.bs.s3SSE.customerKey,
+		SSECustomerKeyMD5:    u.bh.bs.s3SSE.customerMd5,
+	})
+	if err != nil {
+		var noSuchKey *types.NoSuchKey
+		if errors.As(err, &noSuchKey) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	defer out.Body.Close()
+	data, err := io.ReadAll(out.Body)
+	if err != nil {
+		return nil, err
+	}
+	checkpoint, err := backupstorage.ParseUploadCheckpoint(data)
+	if err != nil {
+		// The upload is started over.
+		log.Warningf("Ignoring the upload checkpoint of %v: %v", u.object, err)
+		return nil, nil
+	}
+	return checkpoint, nil
+}
+
+// UploadPart is part of the backupstorage.PartUploader interface.
+func (u *s3PartUploader) UploadPart(ctx context.Context, number int, data []byte) (string, error) {
+	out, err := u.bh.client.UploadPart(ctx, &s3.UploadPartInput{
+		Bucket:               &bucket,
+		Key:                  &u.object,
+		UploadId:             &u.uploadID,
+		PartNumber:           aws.Int32(int32(number)),
+		Body:                 bytes.NewReader(data),
+		SSECustomerAlgorithm: u.bh.bs.s3SSE.customerAlg,
+		SSECustomerKey:       u.bh.bs.s3SSE.customerKey,
+		SSECustomerKeyMD5:    u.bh.bs.s3SSE.customerMd5,
+	}, u.bh.withSendStats())
+	if err != nil {
+		u.forgetExpiredUpload(ctx, err)
+		return "", err
+	}
+	return aws.ToString(out.ETag), nil
+}
+
+// SaveCheckpoint is part of the backupstorage.PartUploader interface.
+func (u *s3PartUploader) SaveCheckpoint(ctx context.Context, checkpoint *backupstorage.UploadCheckpoint) error {
+	data, err := backupstorage.MarshalUploadCheckpoint(checkpoint)
+	if err != nil {
+		return err
+	}
+	// A PutObject is atomic: the checkpoint is never seen partially written.
+	_, err = u.bh.client.PutObject(ctx, &s3.PutObjectInput{
+		Bucket:               &bucket,
+		Key:                  &u.checkpointObject,
+		Body:                 bytes.NewReader(data),
+		ServerSideEncryption: u.bh.bs.s3SSE.awsAlg,
+		SSECustomerAlgorithm: u.bh.bs.s3SSE.customerAlg,
+		SSECustomerKey:       u.bh.bs.s3SSE.customerKey,
+		SSECustomerKeyMD5:    u.bh.bs.s3SSE.customerMd5,
+	})
+	return err
+}
+
+// Complete is part of the backupstorage.PartUploader interface.
+func (u *s3PartUploader) Complete(ctx context.Context, checkpoint *backupstorage.UploadCheckpoint) error {
+	parts := make([]types.CompletedPart, 0, len(checkpoint.Parts))
+	for _, part := range checkpoint.Parts {
+		parts = append(parts, types.CompletedPart{
+			ETag:       aws.String(part.ETag),
+			PartNumber: aws.Int32(int32(part.Number)),
+		})
+	}
+	_, err := u.bh.client.CompleteMultipartUpload(ctx, &s3.CompleteMultipartUploadInput{
+		Bucket:               &bucket,
+		Key:                  &u.object,
+		UploadId:             &u.uploadID,
+		MultipartUpload:      &types.CompletedMultipartUpload{Parts: parts},
+		SSECustomerAlgorithm: u.bh.bs.s3SSE.customerAlg,
+		SSECustomerKey:       u.bh.bs.s3SSE.customerKey,
+		SSECustomerKeyMD5:    u.bh.bs.s3SSE.customerMd5,
+	}, u.bh.withSendStats())
+	if err != nil {
+		u.forgetExpiredUpload(ctx, err)
+		return err
+	}
+
+	u.bh.uploadsMu.Lock()
+	delete(u.bh.uploads, u.filename)
+	u.bh.uploadsMu.Unlock()
+
+	_, err = u.bh.client.DeleteObject(ctx, &s3.DeleteObjectInput{
+		Bucket: &bucket,
+		Key:    &u.checkpointObject,
+	})
+	return err
+}
+
+// Release is part of the backupstorage.PartUploader interface. The upload
+// is kept for a retry to resume it, or for AbortBackup to abort it.
+func (u *s3PartUploader) Release() error {
+	return nil
+}
+
+// forgetExpiredUpload removes the checkpoint of an upload that no longer
+// exists, e.g. because a lifecycle rule aborted it, so that the next attempt
+// starts a new upload instead of failing the same way.
+func (u *s3PartUploader) forgetExpiredUpload(ctx context.Context, err error) {
+	if !isNoSuchUpload(err) {
+		return
+	}
+	if _, err := u.bh.client.DeleteObject(ctx, &s3.DeleteObjectInput{
+		Bucket: &bucket,
+		Key:    &u.checkpointObject,
+	}); err != nil {
+		log.Warningf("Cannot remove the upload checkpoint of %v: %v", u.object, err)
+	}
+}
+
+// abortUploads aborts the multipart uploads that were not completed.
+func (bh *S3BackupHandle) abortUploads(ctx context.Context) error {
+	bh.uploadsMu.Lock()
+	defer bh.uploadsMu.Unlock()
+
+	var errs []error
+	for filename, uploadID := range bh.uploads {
+		object := objName(bh.dir, bh.name, filename)
+		if _, err := bh.client.AbortMultipartUpload(ctx, &s3.AbortMultipartUploadInput{
+			Bucket:   &bucket,
+			Key:      &object,
+			UploadId: aws.String(uploadID),
+		}); err != nil {
+			if !isNoSuchUpload(err) {
+				errs = append(errs, err)
+				continue
+			}
+		}
+		delete(bh.uploads, filename)
+	}
+	return errors.Join(errs...)
+}
+
+// isNoSuchUpload returns whether an error is about a multipart upload that does
+// not exist. Only some operations return it as a *types.NoSuchUpload.
+func isNoSuchUpload(err error) bool {
+	var apiErr smithy.APIError
+	return errors.As(err, &apiErr) && apiErr.ErrorCode() == "NoSuchUpload"
+}
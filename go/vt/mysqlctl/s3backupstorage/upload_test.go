@@ -0,0 +1,344 @@
+/*
+Copyright 2026 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package s3backupstorage
+
+import (
+	"bytes"
+	"context"
+	"crypto/md5"
+	"crypto/rand"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/feature/s3/manager"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"vitess.io/vitess/go/vt/logutil"
+	"vitess.io/vitess/go/vt/mysqlctl/backupstorage"
+)
+
+// localS3 is a minimal S3-compatible server, storing the objects of a single
+// bucket in memory. It serves the path-style requests used to upload and read
+// files, including multipart uploads and conditional writes, and can inject
+// failures.
+type localS3 struct {
+	mu      sync.Mutex
+	objects map[string][]byte
+	// uploads maps the IDs of the multipart uploads in progress to their parts.
+	uploads  map[string]map[int][]byte
+	nextID   int
+	uploaded []int
+
+	// failPart is the number of a part whose next upload fails.
+	failPart int
+}
+
+func newLocalS3(t *testing.T) *localS3 {
+	s := &localS3{
+		objects: make(map[string][]byte),
+		uploads: make(map[string]map[int][]byte),
+	}
+	server := httptest.NewServer(s)
+	t.Cleanup(server.Close)
+
+	// Point the storage to the server.
+	t.Setenv("AWS_ACCESS_KEY_ID", "access-key")
+	t.Setenv("AWS_SECRET_ACCESS_KEY", "secret-key")
+	t.Setenv("AWS_EC2_METADATA_DISABLED", "true")
+	t.Setenv("AWS_CONFIG_FILE", "/nonexistent")
+	t.Setenv("AWS_SHARED_CREDENTIALS_FILE", "/nonexistent")
+	t.Setenv("AWS_CA_BUNDLE", "")
+	oldRegion, oldEndpoint, oldBucket, oldForcePath, oldSSE, oldResumableUploads := region, endpoint, bucket, forcePath, sse, resumableUploads
+	t.Cleanup(func() {
+		region, endpoint, bucket, forcePath, sse, resumableUploads = oldRegion, oldEndpoint, oldBucket, oldForcePath, oldSSE, oldResumableUploads
+	})
+	region = "us-east-1"
+	sse = ""
+	endpoint = server.URL
+	bucket = "backups"
+	forcePath = true
+	resumableUploads = true
+	return s
+}
+
+func (s *localS3) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	bucketName, key, _ := strings.Cut(strings.TrimPrefix(r.URL.Path, "/"), "/")
+	if bucketName != bucket {
+		s.writeError(w, http.StatusNotFound, "NoSuchBucket")
+		return
+	}
+	query := r.URL.Query()
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		s.writeError(w, http.StatusBadRequest, "IncompleteBody")
+		return
+	}
+
+	switch {
+	case key == "" && r.Method == http.MethodHead:
+		// HeadBucket
+	case r.Method == http.MethodGet:
+		data, ok := s.objects[key]
+		if !ok {
+			s.writeError(w, http.StatusNotFound, "NoSuchKey")
+			return
+		}
+		w.Header().Set("ETag", objectETag(data))
+		_, _ = w.Write(data)
+	case r.Method == http.MethodPut && query.Has("uploadId"):
+		parts, ok := s.uploads[query.Get("uploadId")]
+		if !ok {
+			s.writeError(w, http.StatusNotFound, "NoSuchUpload")
+			return
+		}
+		number, _ := strconv.Atoi(query.Get("partNumber"))
+		if number == s.failPart {
+			s.failPart = 0
+			s.writeError(w, http.StatusForbidden, "AccessDenied")
+			return
+		}
+		parts[number] = body
+		s.uploaded = append(s.uploaded, number)
+		w.Header().Set("ETag", fmt.Sprintf("%q", fmt.Sprintf("etag-%d", number)))
+	case r.Method == http.MethodPut:
+		data, exists := s.objects[key]
+		if ifMatch := r.Header.Get("If-Match"); ifMatch != "" && (!exists || ifMatch != objectETag(data)) {
+			s.writeError(w, http.StatusPreconditionFailed, "PreconditionFailed")
+			return
+		}
+		if r.Header.Get("If-None-Match") == "*" && exists {
+			s.writeError(w, http.StatusPreconditionFailed, "PreconditionFailed")
+			return
+		}
+		s.objects[key] = body
+		w.Header().Set("ETag", objectETag(body))
+	case r.Method == http.MethodDelete && query.Has("uploadId"):
+		if _, ok := s.uploads[query.Get("uploadId")]; !ok {
+			s.writeError(w, http.StatusNotFound, "NoSuchUpload")
+			return
+		}
+		delete(s.uploads, query.Get("uploadId"))
+		w.WriteHeader(http.StatusNoContent)
+	case r.Method == http.MethodDelete:
+		delete(s.objects, key)
+		w.WriteHeader(http.StatusNoContent)
+	case r.Method == http.MethodPost && query.Has("uploads"):
+		s.nextID++
+		uploadID := fmt.Sprintf("upload-%d", s.nextID)
+		s.uploads[uploadID] = make(map[int][]byte)
+		s.writeXML(w, struct {
+			XMLName  xml.Name `xml:"InitiateMultipartUploadResult"`
+			Bucket   string
+			Key      string
+			UploadId string
+		}{Bucket: bucket, Key: key, UploadId: uploadID})
+	case r.Method == http.MethodPost && query.Has("uploadId"):
+		parts, ok := s.uploads[query.Get("uploadId")]
+		if !ok {
+			s.writeError(w, http.StatusNotFound, "NoSuchUpload")
+			return
+		}
+		var complete struct {
+			Parts []struct {
+				ETag       string
+				PartNumber int
+			} `xml:"Part"`
+		}
+		if err := xml.Unmarshal(body, &complete); err != nil {
+			s.writeError(w, http.StatusBadRequest, "MalformedXML")
+			return
+		}
+		var data []byte
+		for _, part := range complete.Parts {
+			content, ok := parts[part.PartNumber]
+			if !ok || part.ETag != fmt.Sprintf("%q", fmt.Sprintf("etag-%d", part.PartNumber)) {
+				s.writeError(w, http.StatusBadRequest, "InvalidPart")
+				return
+			}
+			data = append(data, content...)
+		}
+		s.objects[key] = data
+		delete(s.uploads, query.Get("uploadId"))
+		s.writeXML(w, struct {
+			XMLName xml.Name `xml:"CompleteMultipartUploadResult"`
+			Bucket  string
+			Key     string
+			ETag    string
+		}{Bucket: bucket, Key: key, ETag: "etag"})
+	default:
+		s.writeError(w, http.StatusNotImplemented, "NotImplemented")
+	}
+}
+
+// objectETag returns the ETag of an object, the quoted MD5 of its content as
+// for the objects not uploaded in parts.
+func objectETag(data []byte) string {
+	return fmt.Sprintf("%q", fmt.Sprintf("%x", md5.Sum(data)))
+}
+
+func (s *localS3) writeXML(w http.ResponseWriter, v any) {
+	data, err := xml.Marshal(v)
+	if err != nil {
+		s.writeError(w, http.StatusInternalServerError, "InternalError")
+		return
+	}
+	w.Header().Set("Content-Type", "application/xml")
+	_, _ = w.Write(data)
+}
+
+func (s *localS3) writeError(w http.ResponseWriter, status int, code string) {
+	w.Header().Set("Content-Type", "application/xml")
+	w.WriteHeader(status)
+	_, _ = fmt.Fprintf(w, "<Error><Code>%s</Code><Message>%s</Message></Error>", code, code)
+}
+
+// state returns the uploaded parts since the last call, and the number of
+// multipart uploads in progress.
+func (s *localS3) state() (uploaded []int, uploads int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	uploaded, s.uploaded = s.uploaded, nil
+	return uploaded, len(s.uploads)
+}
+
+// writeFile writes data to a file of the backup. If the upload fails, it is
+// closed without being completed.
+func writeFile(t *testing.T, bh backupstorage.BackupHandle, filename string, data []byte) (backupstorage.ResumableWriter, error) {
+	wc, err := bh.AddFile(context.Background(), filename, int64(len(data)))
+	require.NoError(t, err)
+	rw, ok := wc.(backupstorage.ResumableWriter)
+	require.True(t, ok, "AddFile of a large file did not return a ResumableWriter: %T", wc)
+	if _, err := rw.Write(data); err != nil {
+		require.NoError(t, rw.CloseWithError(err))
+		return rw, err
+	}
+	return rw, rw.Close()
+}
+
+func TestResumableUpload(t *testing.T) {
+	s := newLocalS3(t)
+	ctx := context.Background()
+	bs := newS3BackupStorage().WithParams(backupstorage.Params{
+		Logger: logutil.NewMemoryLogger(),
+		Stats:  backupstorage.NoParams().Stats,
+	})
+	defer bs.Close()
+	bh, err := bs.StartBackup(ctx, "keyspace/shard", "cell-0001-2015-01-14-10-00-00")
+	require.NoError(t, err)
+
+	// Three parts and a half.
+	data := make([]byte, 7*manager.DefaultUploadPartSize/2)
+	_, err = rand.Read(data)
+	require.NoError(t, err)
+	checkpointObject := "keyspace/shard/cell-0001-2015-01-14-10-00-00/0" + backupstorage.UploadCheckpointSuffix
+
+	// The upload of the third part fails.
+	s.failPart = 3
+	_, err = writeFile(t, bh, "0", data)
+	require.ErrorContains(t, err, "AccessDenied")
+	uploaded, uploads := s.state()
+	assert.Equal(t, []int{1, 2}, uploaded)
+	assert.Equal(t, 1, uploads)
+	checkpoint, err := backupstorage.ParseUploadCheckpoint(s.objects[checkpointObject])
+	require.NoError(t, err)
+	assert.Len(t, checkpoint.Parts, 2)
+
+	// The retry resumes the same upload from the third part.
+	rw, err := writeFile(t, bh, "0", data)
+	require.NoError(t, err)
+	assert.EqualValues(t, 2*manager.DefaultUploadPartSize, rw.ResumedBytes())
+	uploaded, uploads = s.state()
+	assert.Equal(t, []int{3, 4}, uploaded)
+	assert.Equal(t, 0, uploads)
+	require.NoError(t, bh.EndBackup(ctx))
+	assert.NotContains(t, s.objects, checkpointObject)
+
+	// Read the file back.
+	rbh := &S3BackupHandle{
+		client:   bh.(*S3BackupHandle).client,
+		bs:       bh.(*S3BackupHandle).bs,
+		dir:      bh.Directory(),
+		name:     bh.Name(),
+		readOnly: true,
+	}
+	rc, err := rbh.ReadFile(ctx, "0")
+	require.NoError(t, err)
+	read, err := io.ReadAll(rc)
+	require.NoError(t, err)
+	require.NoError(t, rc.Close())
+	assert.True(t, bytes.Equal(data, read), "the uploaded file differs from the written one")
+}
+
+func TestResumableUploadExpired(t *testing.T) {
+	s := newLocalS3(t)
+	ctx := context.Background()
+	bs := newS3BackupStorage().WithParams(backupstorage.NoParams())
+	defer bs.Close()
+	bh, err := bs.StartBackup(ctx, "keyspace/shard", "cell-0001-2015-01-14-10-00-00")
+	require.NoError(t, err)
+	data := make([]byte, 3*manager.DefaultUploadPartSize/2)
+
+	s.failPart = 2
+	_, err = writeFile(t, bh, "0", data)
+	require.Error(t, err)
+
+	// The upload expires: the next attempt fails, and forgets it.
+	s.mu.Lock()
+	clear(s.uploads)
+	s.mu.Unlock()
+	_, err = writeFile(t, bh, "0", data)
+	require.ErrorContains(t, err, "NoSuchUpload")
+
+	// The attempt after starts a new upload.
+	s.state()
+	_, err = writeFile(t, bh, "0", data)
+	require.NoError(t, err)
+	uploaded, _ := s.state()
+	assert.Equal(t, []int{1, 2}, uploaded)
+	assert.Len(t, s.objects["keyspace/shard/cell-0001-2015-01-14-10-00-00/0"], len(data))
+}
+
+func TestResumableUploadAbort(t *testing.T) {
+	s := newLocalS3(t)
+	ctx := context.Background()
+	bs := newS3BackupStorage().WithParams(backupstorage.NoParams())
+	defer bs.Close()
+	bh, err := bs.StartBackup(ctx, "keyspace/shard", "cell-0001-2015-01-14-10-00-00")
+	require.NoError(t, err)
+
+	s.failPart = 2
+	_, err = writeFile(t, bh, "0", make([]byte, 3*manager.DefaultUploadPartSize/2))
+	require.Error(t, err)
+	_, uploads := s.state()
+	require.Equal(t, 1, uploads)
+
+	// Aborting the backup aborts the uploads left in progress.
+	require.NoError(t, bh.(*S3BackupHandle).abortUploads(ctx))
+	_, uploads = s.state()
+	assert.Equal(t, 0, uploads)
+}
@@ -0,0 +1,317 @@
+/*
+Copyright 2026 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package mysqlctl
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math/rand/v2"
+	"net"
+	"os"
+	"time"
+
+	"vitess.io/vitess/go/mysql/collations"
+	"vitess.io/vitess/go/mysql/replication"
+	"vitess.io/vitess/go/sqlescape"
+	"vitess.io/vitess/go/vt/logutil"
+	"vitess.io/vitess/go/vt/mysqlctl/backupstats"
+	"vitess.io/vitess/go/vt/mysqlctl/backupstorage"
+	"vitess.io/vitess/go/vt/proto/vtrpc"
+	"vitess.io/vitess/go/vt/vterrors"
+)
+
+// backupVerificationFileName is the name of the file, next to the MANIFEST
+// of a backup, that records the result of its last verification.
+const backupVerificationFileName = "VERIFICATION"
+
+// VerifyParams is the struct that holds all params passed to VerifyBackup.
+type VerifyParams struct {
+	Logger logutil.Logger
+	// Keyspace and Shard are used to infer the directory where backups are stored
+	Keyspace string
+	Shard    string
+	// BackupName is the name of the backup to verify.
+	BackupName string
+	// DbName is the name of the managed database / schema
+	DbName string
+	// Concurrency is how many files are restored in parallel.
+	Concurrency int
+	// MysqlPort is the port of the throwaway mysqld the backup is restored
+	// into. If zero, a free port is picked.
+	MysqlPort int
+	// InitDBSQLFile is the path to the .sql file used to initialize the
+	// throwaway mysqld.
+	InitDBSQLFile string
+	// MysqlTimeout is how long to wait for mysqld startup. Defaults to
+	// 5 minutes.
+	MysqlTimeout time.Duration
+	// MysqlShutdownTimeout is how long to wait for mysqld shutdown.
+	// Defaults to DefaultShutdownTimeout.
+	MysqlShutdownTimeout time.Duration
+	CollationEnv         *collations.Environment
+	Stats                backupstats.Stats
+}
+
+// VerificationResult is the result of the verification of a backup. It is
+// recorded as JSON in the VERIFICATION file of the backup.
+type VerificationResult struct {
+	// BackupName is the name of the verified backup.
+	BackupName string
+	// VerifiedAt is the time of the verification, in RFC3339 format.
+	VerifiedAt string
+	// Position is the replication position of the backup MANIFEST.
+	Position string
+	// RestoredPosition is the GTID position the restored mysqld got along
+	// with the data, before the restore sets it from the MANIFEST. It must
+	// be equal to Position.
+	RestoredPosition string `json:",omitempty"`
+	// TablesChecked is the number of tables CHECK TABLE was run on.
+	TablesChecked int
+	// FailedTables lists the tables CHECK TABLE reported as not OK, with
+	// the reported message.
+	FailedTables []string `json:",omitempty"`
+	// Error is set if the backup could not be restored.
+	Error string `json:",omitempty"`
+}
+
+// Success returns whether the backup passed the verification.
+func (r *VerificationResult) Success() bool {
+	return r.Error == "" && len(r.FailedTables) == 0 && r.RestoredPosition == r.Position
+}
+
+// VerifyBackup proves that a backup is restorable: it restores the backup
+// into a temporary data directory with a throwaway mysqld, runs CHECK TABLE
+// on all the tables and compares the restored GTID position with the one of
+// the MANIFEST. The result is recorded alongside the backup in the backup
+// storage, and returned. A failed verification is not an error; an error is
+// returned only if the backup could not be verified at all.
+func VerifyBackup(ctx context.Context, params VerifyParams) (*VerificationResult, error) {
+	if params.Stats == nil {
+		params.Stats = backupstats.NoStats()
+	}
+	if params.MysqlTimeout == 0 {
+		params.MysqlTimeout = 5 * time.Minute
+	}
+	if params.MysqlShutdownTimeout == 0 {
+		params.MysqlShutdownTimeout = DefaultShutdownTimeout
+	}
+
+	bs, err := backupstorage.GetBackupStorage()
+	if err != nil {
+		return nil, err
+	}
+	defer bs.Close()
+	if _, ok := bs.(backupstorage.ReopenStorage); !ok {
+		return nil, vterrors.Errorf(vtrpc.Code_UNIMPLEMENTED, "backup storage %v cannot record the result of a verification", backupstorage.BackupStorageImplementation)
+	}
+
+	backupDir := GetBackupDir(params.Keyspace, params.Shard)
+	bhs, err := bs.ListBackups(ctx, backupDir)
+	if err != nil {
+		return nil, vterrors.Wrap(err, "ListBackups failed")
+	}
+	var bh backupstorage.BackupHandle
+	for _, b := range bhs {
+		if b.Name() == params.BackupName {
+			bh = b
+			break
+		}
+	}
+	if bh == nil {
+		return nil, vterrors.Errorf(vtrpc.Code_NOT_FOUND, "backup %v not found in directory %v", params.BackupName, backupDir)
+	}
+	manifest, err := GetBackupManifest(ctx, bh)
+	if err != nil {
+		return nil, vterrors.Wrapf(err, "backup %v/%v is not complete", backupDir, params.BackupName)
+	}
+	if manifest.Incremental {
+		return nil, vterrors.Errorf(vtrpc.Code_INVALID_ARGUMENT, "backup %v/%v is incremental, only full backups can be verified", backupDir, params.BackupName)
+	}
+
+	result := &VerificationResult{
+		BackupName: params.BackupName,
+		VerifiedAt: FormatRFC3339(time.Now()),
+		Position:   replication.EncodePosition(manifest.Position),
+	}
+	if err := verifyBackup(ctx, params, result); err != nil {
+		params.Logger.Errorf("VerifyBackup: backup %v/%v could not be restored: %v", backupDir, params.BackupName, err)
+		result.Error = err.Error()
+	}
+	if err := writeVerificationResult(ctx, bs, backupDir, result); err != nil {
+		return nil, vterrors.Wrap(err, "failed to record the verification result")
+	}
+	changeBackupCatalog(ctx, bs, backupDir, params.Logger, false, func(catalog *BackupCatalog) {
+		if entry := catalog.Entry(params.BackupName); entry != nil {
+			entry.Verification = newBackupCatalogVerification(result)
+		}
+	})
+	params.Logger.Infof("VerifyBackup: backup %v/%v verified, success: %v", backupDir, params.BackupName, result.Success())
+	return result, nil
+}
+
+// verifyBackup restores the backup into a throwaway mysqld, and checks it.
+func verifyBackup(ctx context.Context, params VerifyParams, result *VerificationResult) error {
+	port := params.MysqlPort
+	if port == 0 {
+		var err error
+		if port, err = freePort(); err != nil {
+			return err
+		}
+	}
+
+	// The UID of this imaginary tablet only makes the data directory unique.
+	uid := rand.Uint32N(1<<31-1) + 1
+	tabletDir := TabletDir(uid)
+	defer func() {
+		if err := os.RemoveAll(tabletDir); err != nil {
+			params.Logger.Warningf("VerifyBackup: failed to remove temporary tablet directory %v: %v", tabletDir, err)
+		}
+	}()
+	mysqld, cnf, err := CreateMysqldAndMycnf(uid, "", port, params.CollationEnv)
+	if err != nil {
+		return fmt.Errorf("failed to initialize mysql config: %v", err)
+	}
+	defer mysqld.Close()
+
+	initCtx, initCancel := context.WithTimeout(ctx, params.MysqlTimeout)
+	defer initCancel()
+	params.Logger.Infof("VerifyBackup: starting a throwaway mysqld in %v", tabletDir)
+	if err := mysqld.Init(initCtx, cnf, params.InitDBSQLFile); err != nil {
+		return fmt.Errorf("failed to initialize mysql data dir and start mysqld: %v", err)
+	}
+	defer func() {
+		// Shut down even if ctx is done, not to leave a mysqld behind.
+		shutdownCtx, shutdownCancel := context.WithTimeout(context.Background(), params.MysqlShutdownTimeout+10*time.Second)
+		defer shutdownCancel()
+		if err := mysqld.Shutdown(shutdownCtx, cnf, true, params.MysqlShutdownTimeout); err != nil {
+			params.Logger.Errorf("VerifyBackup: failed to shutdown mysqld: %v", err)
+		}
+	}()
+
+	// The restore sets the position from the MANIFEST if it differs, the
+	// position to verify is the one read before.
+	var restoredPos replication.Position
+	if _, err := Restore(ctx, RestoreParams{
+		Cnf:                  cnf,
+		Mysqld:               mysqld,
+		Logger:               params.Logger,
+		Concurrency:          params.Concurrency,
+		DeleteBeforeRestore:  true,
+		DbName:               params.DbName,
+		Keyspace:             params.Keyspace,
+		Shard:                params.Shard,
+		Stats:                params.Stats,
+		MysqlShutdownTimeout: params.MysqlShutdownTimeout,
+		BackupName:           params.BackupName,
+		OnRestoredPosition: func(pos replication.Position) {
+			restoredPos = pos
+		},
+	}); err != nil {
+		return err
+	}
+	return checkRestoredBackup(ctx, mysqld, params.Logger, restoredPos, result)
+}
+
+// checkRestoredBackup compares the restored GTID position with the one of the
+// MANIFEST, and runs CHECK TABLE on all the tables of a restored backup.
+func checkRestoredBackup(ctx context.Context, mysqld MysqlDaemon, logger logutil.Logger, restoredPos replication.Position, result *VerificationResult) error {
+	result.RestoredPosition = replication.EncodePosition(restoredPos)
+	if result.RestoredPosition != result.Position {
+		logger.Errorf("VerifyBackup: restored position %v does not match the backup position %v", result.RestoredPosition, result.Position)
+	}
+
+	qr, err := mysqld.FetchSuperQuery(ctx, "SELECT table_schema, table_name FROM information_schema.tables WHERE table_type = 'BASE TABLE' AND table_schema NOT IN ('information_schema', 'mysql', 'performance_schema', 'sys')")
+	if err != nil {
+		return vterrors.Wrap(err, "failed to list the restored tables")
+	}
+	for _, row := range qr.Rows {
+		table := sqlescape.EscapeID(row[0].ToString()) + "." + sqlescape.EscapeID(row[1].ToString())
+		check, err := mysqld.FetchSuperQuery(ctx, "CHECK TABLE "+table)
+		if err != nil {
+			return vterrors.Wrapf(err, "failed to check table %v", table)
+		}
+		result.TablesChecked++
+		// The last row of the Table, Op, Msg_type, Msg_text result is
+		// the status of the table, any other row is a note or an error.
+		if len(check.Rows) == 0 {
+			result.FailedTables = append(result.FailedTables, table+": no status")
+			continue
+		}
+		status := check.Rows[len(check.Rows)-1]
+		if len(status) < 4 || status[2].ToString() != "status" || status[3].ToString() != "OK" {
+			msg := ""
+			if len(status) >= 4 {
+				msg = status[3].ToString()
+			}
+			logger.Errorf("VerifyBackup: CHECK TABLE %v failed: %v", table, msg)
+			result.FailedTables = append(result.FailedTables, table+": "+msg)
+		}
+	}
+	return nil
+}
+
+// writeVerificationResult records the result of a verification in the
+// backup it is about.
+func writeVerificationResult(ctx context.Context, bs backupstorage.BackupStorage, dir string, result *VerificationResult) error {
+	bh, err := bs.(backupstorage.ReopenStorage).ReopenBackup(ctx, dir, result.BackupName)
+	if err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(result, "", "  ")
+	if err != nil {
+		return err
+	}
+	wc, err := bh.AddFile(ctx, backupVerificationFileName, int64(len(data)))
+	if err != nil {
+		return err
+	}
+	if _, err := wc.Write(data); err != nil {
+		wc.Close()
+		return err
+	}
+	if err := wc.Close(); err != nil {
+		return err
+	}
+	return bh.EndBackup(ctx)
+}
+
+// readVerificationResult reads the result of the last verification of a
+// backup.
+func readVerificationResult(ctx context.Context, bh backupstorage.BackupHandle) (*VerificationResult, error) {
+	file, err := bh.ReadFile(ctx, backupVerificationFileName)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	result := &VerificationResult{}
+	if err := json.NewDecoder(file).Decode(result); err != nil {
+		return nil, vterrors.Wrapf(err, "can't decode %v", backupVerificationFileName)
+	}
+	return result, nil
+}
+
+// freePort returns a TCP port that is not in use.
+func freePort() (int, error) {
+	l, err := net.Listen("tcp", "localhost:0")
+	if err != nil {
+		return 0, err
+	}
+	defer l.Close()
+	return l.Addr().(*net.TCPAddr).Port, nil
+}
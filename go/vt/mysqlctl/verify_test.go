@@ -0,0 +1,114 @@
+/*
+Copyright 2026 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package mysqlctl
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"vitess.io/vitess/go/mysql/fakesqldb"
+	"vitess.io/vitess/go/mysql/replication"
+	"vitess.io/vitess/go/sqltypes"
+	"vitess.io/vitess/go/vt/logutil"
+	"vitess.io/vitess/go/vt/mysqlctl/backupstorage"
+	"vitess.io/vitess/go/vt/mysqlctl/filebackupstorage"
+)
+
+func TestCheckRestoredBackup(t *testing.T) {
+	ctx := context.Background()
+	pos := replication.MustParsePosition(replication.Mysql56FlavorID, "16b1039f-22b6-11ed-b765-0a43f95f28a3:1-100")
+	checkFields := sqltypes.MakeTestFields("Table|Op|Msg_type|Msg_text", "varchar|varchar|varchar|varchar")
+
+	mysqld := NewFakeMysqlDaemon(fakesqldb.New(t))
+	defer mysqld.Close()
+	mysqld.FetchSuperQueryMap = map[string]*sqltypes.Result{
+		"SELECT table_schema, table_name FROM information_schema.tables WHERE table_type = 'BASE TABLE' AND table_schema NOT IN ('information_schema', 'mysql', 'performance_schema', 'sys')": sqltypes.MakeTestResult(
+			sqltypes.MakeTestFields("table_schema|table_name", "varchar|varchar"),
+			"vt_ks|t1",
+			"vt_ks|t2",
+		),
+		"CHECK TABLE `vt_ks`.`t1`": sqltypes.MakeTestResult(checkFields, "vt_ks.t1|check|status|OK"),
+		"CHECK TABLE `vt_ks`.`t2`": sqltypes.MakeTestResult(checkFields,
+			"vt_ks.t2|check|warning|InnoDB: The B-tree of index PRIMARY is corrupted.",
+			"vt_ks.t2|check|error|Corrupt",
+		),
+	}
+
+	result := &VerificationResult{Position: replication.EncodePosition(pos)}
+	require.NoError(t, checkRestoredBackup(ctx, mysqld, logutil.NewMemoryLogger(), pos, result))
+	assert.Equal(t, 2, result.TablesChecked)
+	assert.Equal(t, []string{"`vt_ks`.`t2`: Corrupt"}, result.FailedTables)
+	assert.Equal(t, result.Position, result.RestoredPosition)
+	assert.False(t, result.Success())
+
+	// Once the table is repaired, the check succeeds, unless the restored
+	// position is not the one of the backup.
+	mysqld.FetchSuperQueryMap["CHECK TABLE `vt_ks`.`t2`"] = sqltypes.MakeTestResult(checkFields, "vt_ks.t2|check|status|OK")
+	result = &VerificationResult{Position: replication.EncodePosition(pos)}
+	require.NoError(t, checkRestoredBackup(ctx, mysqld, logutil.NewMemoryLogger(), pos, result))
+	assert.Empty(t, result.FailedTables)
+	assert.True(t, result.Success())
+
+	restoredPos := replication.MustParsePosition(replication.Mysql56FlavorID, "16b1039f-22b6-11ed-b765-0a43f95f28a3:1-99")
+	result = &VerificationResult{Position: replication.EncodePosition(pos)}
+	require.NoError(t, checkRestoredBackup(ctx, mysqld, logutil.NewMemoryLogger(), restoredPos, result))
+	assert.Empty(t, result.FailedTables)
+	assert.Equal(t, "MySQL56/16b1039f-22b6-11ed-b765-0a43f95f28a3:1-99", result.RestoredPosition)
+	assert.False(t, result.Success())
+}
+
+func TestWriteVerificationResult(t *testing.T) {
+	ctx := context.Background()
+	oldRoot := filebackupstorage.FileBackupStorageRoot
+	defer func() { filebackupstorage.FileBackupStorageRoot = oldRoot }()
+	filebackupstorage.FileBackupStorageRoot = t.TempDir()
+
+	bs := backupstorage.BackupStorageMap["file"]
+	dir, name := "ks/0", "2025-01-01.000000.zone1-0000000100"
+	bh, err := bs.StartBackup(ctx, dir, name)
+	require.NoError(t, err)
+	require.NoError(t, bh.EndBackup(ctx))
+
+	read := func() *VerificationResult {
+		bhs, err := bs.ListBackups(ctx, dir)
+		require.NoError(t, err)
+		require.Len(t, bhs, 1)
+		rc, err := bhs[0].ReadFile(ctx, backupVerificationFileName)
+		require.NoError(t, err)
+		defer rc.Close()
+		data, err := io.ReadAll(rc)
+		require.NoError(t, err)
+		result := &VerificationResult{}
+		require.NoError(t, json.Unmarshal(data, result))
+		return result
+	}
+
+	result := &VerificationResult{BackupName: name, Position: "MySQL56/16b1039f-22b6-11ed-b765-0a43f95f28a3:1-100", Error: "restore failed"}
+	require.NoError(t, writeVerificationResult(ctx, bs, dir, result))
+	assert.Equal(t, result, read())
+
+	// A new verification replaces the previous result.
+	result = &VerificationResult{BackupName: name, Position: result.Position, RestoredPosition: result.Position, TablesChecked: 3}
+	require.NoError(t, writeVerificationResult(ctx, bs, dir, result))
+	assert.Equal(t, result, read())
+	assert.True(t, read().Success())
+}
@@ -2261,6 +2261,8 @@ func (ty ShowCommandType) ToString() string {
 		return ProcedureCStr
 	case Procedure:
 		return ProcedureStr
+	case Processlist:
+		return ProcesslistStr
 	case StatusGlobal:
 		return StatusGlobalStr
 	case StatusSession:
@@ -2279,6 +2281,10 @@ func (ty ShowCommandType) ToString() string {
 		return VGtidExecGlobalStr
 	case VitessMigrations:
 		return VitessMigrationsStr
+	case VitessProcesslist:
+		return VitessProcesslistStr
+	case VitessQueryStats:
+		return VitessQueryStatsStr
 	case VitessReplicationStatus:
 		return VitessReplicationStatusStr
 	case VitessShards:
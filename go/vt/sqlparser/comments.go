@@ -55,6 +55,8 @@ const (
 	DirectiveConsolidator = "CONSOLIDATOR"
 	// DirectiveWorkloadName specifies the name of the client application workload issuing the query.
 	DirectiveWorkloadName = "WORKLOAD_NAME"
+	// DirectiveMaxReplicaLag sets the maximum replication lag, in seconds, of the replicas a query reads from.
+	DirectiveMaxReplicaLag = "MAX_REPLICA_LAG"
 	// DirectivePriority specifies the priority of a workload. It should be an integer between 0 and MaxPriorityValue,
 	// where 0 is the highest priority, and MaxPriorityValue is the lowest one.
 	DirectivePriority = "PRIORITY"
@@ -569,6 +571,7 @@ type QueryHints struct {
 	ForeignKeyChecks    *bool
 	Priority            string
 	Timeout             *int
+	MaxReplicaLag       *int
 }
 
 func BuildQueryHints(stmt Statement) (qh QueryHints, err error) {
@@ -590,6 +593,7 @@ func BuildQueryHints(stmt Statement) (qh QueryHints, err error) {
 	qh.Workload = getWorkload(directives)
 	qh.ForeignKeyChecks = getForeignKeyChecksState(comment)
 	qh.Timeout = getQueryTimeout(directives)
+	qh.MaxReplicaLag = getMaxReplicaLag(directives)
 
 	return qh, nil
 }
@@ -662,3 +666,17 @@ func getQueryTimeout(directives *CommentDirectives) *int {
 	}
 	return &timeout
 }
+
+// getMaxReplicaLag gets the maximum replication lag from the provided Statement, using DirectiveMaxReplicaLag
+func getMaxReplicaLag(directives *CommentDirectives) *int {
+	lagString, ok := directives.GetString(DirectiveMaxReplicaLag, "")
+	if !ok || lagString == "" {
+		return nil
+	}
+
+	lag, err := strconv.Atoi(lagString)
+	if err != nil || lag < 0 {
+		return nil
+	}
+	return &lag
+}
@@ -698,3 +698,41 @@ func TestQueryTimeout(t *testing.T) {
 		})
 	}
 }
+
+// TestMaxReplicaLag tests the extraction of MAX_REPLICA_LAG from the comments.
+func TestMaxReplicaLag(t *testing.T) {
+	testCases := []struct {
+		query    string
+		expLag   int
+		noMaxLag bool
+	}{{
+		query:    "select * from a_table",
+		noMaxLag: true,
+	}, {
+		query:  "select /*vt+ MAX_REPLICA_LAG=5 */ * from another_table",
+		expLag: 5,
+	}, {
+		query:  "select /*vt+ MAX_REPLICA_LAG=0 */ * from another_table",
+		expLag: 0,
+	}, {
+		query:    "select /*vt+ MAX_REPLICA_LAG=-1 */ * from another_table",
+		noMaxLag: true,
+	}, {
+		query:    "select /*vt+ MAX_REPLICA_LAG=abc */ * from another_table",
+		noMaxLag: true,
+	}}
+
+	parser := NewTestParser()
+	for _, tc := range testCases {
+		t.Run(tc.query, func(t *testing.T) {
+			stmt, err := parser.Parse(tc.query)
+			assert.NoError(t, err)
+			qh, _ := BuildQueryHints(stmt)
+			if tc.noMaxLag {
+				assert.Nil(t, qh.MaxReplicaLag)
+			} else {
+				assert.Equal(t, tc.expLag, *qh.MaxReplicaLag)
+			}
+		})
+	}
+}
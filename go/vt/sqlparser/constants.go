@@ -328,6 +328,7 @@ const (
 	PrivilegeStr               = " privileges"
 	ProcedureCStr              = " procedure code"
 	ProcedureStr               = " procedure status"
+	ProcesslistStr             = " processlist"
 	StatusGlobalStr            = " global status"
 	StatusSessionStr           = " status"
 	TablesStr                  = " tables"
@@ -338,6 +339,8 @@ const (
 	VGtidExecGlobalStr         = " global vgtid_executed"
 	KeyspaceStr                = " keyspaces"
 	VitessMigrationsStr        = " vitess_migrations"
+	VitessProcesslistStr       = " vitess_processlist"
+	VitessQueryStatsStr        = " vitess_query_stats"
 	VitessReplicationStatusStr = " vitess_replication_status"
 	VitessShardsStr            = " vitess_shards"
 	VitessTabletsStr           = " vitess_tablets"
@@ -916,6 +919,7 @@ const (
 	Privilege
 	ProcedureC
 	Procedure
+	Processlist
 	StatusGlobal
 	StatusSession
 	Table
@@ -925,6 +929,8 @@ const (
 	VariableSession
 	VGtidExecGlobal
 	VitessMigrations
+	VitessProcesslist
+	VitessQueryStats
 	VitessReplicationStatus
 	VitessShards
 	VitessTablets
@@ -2299,6 +2299,8 @@ var (
 	}, {
 		input:  "flush no_write_to_binlog slow logs, status, user_resources, relay logs, relay logs for channel s",
 		output: "flush local slow logs, status, user_resources, relay logs, relay logs for channel s",
+	}, {
+		input: "flush vitess_query_stats",
 	}, {
 		input: "show binary logs",
 	}, {
@@ -2404,11 +2406,11 @@ var (
 	}, {
 		input: "show procedure status",
 	}, {
-		input:  "show processlist",
-		output: "show processlist",
+		input: "show processlist",
+	}, {
+		input: "show full processlist",
 	}, {
-		input:  "show full processlist",
-		output: "show processlist",
+		input: "show full processlist where Command != 'Sleep'",
 	}, {
 		input:  "show profile cpu for query 1",
 		output: "show profile",
@@ -2516,6 +2518,14 @@ var (
 		input: "show vitess_replication_status",
 	}, {
 		input: "show vitess_replication_status like '%'",
+	}, {
+		input: "show vitess_processlist",
+	}, {
+		input: "show vitess_processlist where Keyspace = 'ks'",
+	}, {
+		input: "show vitess_query_stats",
+	}, {
+		input: "show vitess_query_stats like '%'",
 	}, {
 		input: "show vitess_shards",
 	}, {
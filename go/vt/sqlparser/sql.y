@@ -392,7 +392,7 @@ func markBindVariable(yylex yyLexer, bvar string) {
 // SHOW tokens
 %token <str> CODE COLLATION COLUMNS DATABASES ENGINES EVENT EXTENDED FIELDS FULL FUNCTION GTID_EXECUTED
 %token <str> KEYSPACES OPEN PLUGINS PRIVILEGES PROCESSLIST SCHEMAS TABLES TRIGGERS USER
-%token <str> VGTID_EXECUTED VITESS_KEYSPACES VITESS_METADATA VITESS_MIGRATIONS VITESS_REPLICATION_STATUS VITESS_SHARDS VITESS_TABLETS VITESS_TARGET VSCHEMA VITESS_THROTTLED_APPS
+%token <str> VGTID_EXECUTED VITESS_KEYSPACES VITESS_METADATA VITESS_MIGRATIONS VITESS_PROCESSLIST VITESS_QUERY_STATS VITESS_REPLICATION_STATUS VITESS_SHARDS VITESS_TABLETS VITESS_TARGET VSCHEMA VITESS_THROTTLED_APPS
 
 // SET tokens
 %token <str> NAMES GLOBAL SESSION ISOLATION LEVEL READ WRITE ONLY REPEATABLE COMMITTED UNCOMMITTED SERIALIZABLE
@@ -4617,6 +4617,14 @@ show_statement:
   {
     $$ = &Show{&ShowBasic{Command: Warnings}}
   }
+| SHOW VITESS_PROCESSLIST like_or_where_opt
+  {
+    $$ = &Show{&ShowBasic{Command: VitessProcesslist, Filter: $3}}
+  }
+| SHOW VITESS_QUERY_STATS like_or_where_opt
+  {
+    $$ = &Show{&ShowBasic{Command: VitessQueryStats, Filter: $3}}
+  }
 | SHOW VITESS_SHARDS like_or_where_opt
   {
     $$ = &Show{&ShowBasic{Command: VitessShards, Filter: $3}}
@@ -4662,7 +4670,7 @@ show_statement:
   }
 | SHOW full_opt PROCESSLIST from_database_opt like_or_where_opt
   {
-    $$ = &Show{&ShowOther{Command: string($3)}}
+    $$ = &Show{&ShowBasic{Command: Processlist, Full: $2, DbName: $4, Filter: $5}}
   }
 | SHOW STORAGE ddl_skip_to_end
   {
@@ -5148,6 +5156,10 @@ flush_option:
   {
     $$ = string($1)
   }
+| VITESS_QUERY_STATS
+  {
+    $$ = string($1)
+  }
 
 local_opt:
   {
@@ -9276,6 +9288,8 @@ non_reserved_keyword:
 | VITESS_METADATA
 | VITESS_MIGRATION
 | VITESS_MIGRATIONS
+| VITESS_PROCESSLIST
+| VITESS_QUERY_STATS
 | VITESS_REPLICATION_STATUS
 | VITESS_SHARDS
 | VITESS_TABLETS
@@ -63,3 +63,7 @@ func (f *FakeSrvTopo) WatchSrvKeyspace(ctx context.Context, cell, keyspace strin
 func (f *FakeSrvTopo) WatchSrvVSchema(ctx context.Context, cell string, callback func(*vschemapb.SrvVSchema, error) bool) {
 	panic("unsupported in FakeSrvTopo")
 }
+
+func (f *FakeSrvTopo) WatchTenantDirectory(ctx context.Context, keyspace, name string, callback func(*vschemapb.TenantDirectory, error) bool) {
+	panic("unsupported in FakeSrvTopo")
+}
@@ -131,3 +131,16 @@ func (ksf keyspaceFilteringServer) WatchSrvVSchema(
 
 	ksf.server.WatchSrvVSchema(ctx, cell, filteringCallback)
 }
+
+func (ksf keyspaceFilteringServer) WatchTenantDirectory(
+	ctx context.Context,
+	keyspace, name string,
+	callback func(*vschemapb.TenantDirectory, error) bool,
+) {
+	if !ksf.selectKeyspaces[keyspace] {
+		callback(nil, topo.NewError(topo.NoNode, keyspace))
+		return
+	}
+
+	ksf.server.WatchTenantDirectory(ctx, keyspace, name, callback)
+}
@@ -92,3 +92,9 @@ func (ros readOnlyServer) WatchSrvKeyspace(ctx context.Context, cell, keyspace s
 func (ros readOnlyServer) WatchSrvVSchema(ctx context.Context, cell string, callback func(*vschemapb.SrvVSchema, error) bool) {
 	ros.underlying.WatchSrvVSchema(ctx, cell, callback)
 }
+
+// WatchTenantDirectory starts watching the content of a directory vindex. It will call the callback when
+// a new value or an error occurs.
+func (ros readOnlyServer) WatchTenantDirectory(ctx context.Context, keyspace, name string, callback func(*vschemapb.TenantDirectory, error) bool) {
+	ros.underlying.WatchTenantDirectory(ctx, keyspace, name, callback)
+}
@@ -74,6 +74,7 @@ type ResilientServer struct {
 
 	*SrvKeyspaceWatcher
 	*SrvVSchemaWatcher
+	*TenantDirectoryWatcher
 	*SrvKeyspaceNamesQuery
 }
 
@@ -85,10 +86,11 @@ func NewResilientServer(ctx context.Context, base *topo.Server, counts *stats.Co
 	}
 
 	return &ResilientServer{
-		topoServer:            base,
-		SrvKeyspaceWatcher:    NewSrvKeyspaceWatcher(ctx, base, counts, srvTopoCacheRefresh, srvTopoCacheTTL),
-		SrvVSchemaWatcher:     NewSrvVSchemaWatcher(ctx, base, counts, srvTopoCacheRefresh, srvTopoCacheTTL),
-		SrvKeyspaceNamesQuery: NewSrvKeyspaceNamesQuery(base, counts, srvTopoCacheRefresh, srvTopoCacheTTL),
+		topoServer:             base,
+		SrvKeyspaceWatcher:     NewSrvKeyspaceWatcher(ctx, base, counts, srvTopoCacheRefresh, srvTopoCacheTTL),
+		SrvVSchemaWatcher:      NewSrvVSchemaWatcher(ctx, base, counts, srvTopoCacheRefresh, srvTopoCacheTTL),
+		TenantDirectoryWatcher: NewTenantDirectoryWatcher(ctx, base, counts, srvTopoCacheRefresh, srvTopoCacheTTL),
+		SrvKeyspaceNamesQuery:  NewSrvKeyspaceNamesQuery(base, counts, srvTopoCacheRefresh, srvTopoCacheTTL),
 	}
 }
 
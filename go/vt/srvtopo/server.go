@@ -51,4 +51,9 @@ type Server interface {
 	// the provided cell.  It will call the callback when
 	// a new value or an error occurs.
 	WatchSrvVSchema(ctx context.Context, cell string, callback func(*vschemapb.SrvVSchema, error) bool)
+
+	// WatchTenantDirectory starts watching the content of a directory
+	// vindex. It will call the callback when a new value or an error occurs.
+	// A directory that does not exist yet is reported as a topo.NoNode error.
+	WatchTenantDirectory(ctx context.Context, keyspace, name string, callback func(*vschemapb.TenantDirectory, error) bool)
 }
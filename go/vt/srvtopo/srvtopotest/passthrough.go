@@ -37,6 +37,9 @@ type PassthroughSrvTopoServer struct {
 
 	WatchedSrvVSchema      *vschemapb.SrvVSchema
 	WatchedSrvVSchemaError error
+
+	TenantDirectory      *vschemapb.TenantDirectory
+	TenantDirectoryError error
 }
 
 // NewPassthroughSrvTopoServer returns a new, unconfigured test PassthroughSrvTopoServer
@@ -67,3 +70,8 @@ func (srv *PassthroughSrvTopoServer) WatchSrvKeyspace(ctx context.Context, cell,
 func (srv *PassthroughSrvTopoServer) WatchSrvVSchema(ctx context.Context, cell string, callback func(*vschemapb.SrvVSchema, error) bool) {
 	callback(srv.WatchedSrvVSchema, srv.WatchedSrvVSchemaError)
 }
+
+// WatchTenantDirectory implements srvtopo.Server
+func (srv *PassthroughSrvTopoServer) WatchTenantDirectory(ctx context.Context, keyspace, name string, callback func(*vschemapb.TenantDirectory, error) bool) {
+	callback(srv.TenantDirectory, srv.TenantDirectoryError)
+}
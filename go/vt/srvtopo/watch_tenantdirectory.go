@@ -0,0 +1,83 @@
+/*
+Copyright 2026 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package srvtopo
+
+import (
+	"context"
+	"time"
+
+	"vitess.io/vitess/go/stats"
+	"vitess.io/vitess/go/vt/topo"
+
+	vschemapb "vitess.io/vitess/go/vt/proto/vschema"
+)
+
+type TenantDirectoryWatcher struct {
+	rw *resilientWatcher
+}
+
+type tenantDirectoryKey struct {
+	keyspace, name string
+}
+
+func (k *tenantDirectoryKey) String() string {
+	return k.keyspace + "." + k.name
+}
+
+func NewTenantDirectoryWatcher(ctx context.Context, topoServer *topo.Server, counts *stats.CountersWithSingleLabel, cacheRefresh, cacheTTL time.Duration) *TenantDirectoryWatcher {
+	watch := func(entry *watchEntry) {
+		key := entry.key.(*tenantDirectoryKey)
+		requestCtx, requestCancel := context.WithCancel(ctx)
+		defer requestCancel()
+
+		current, changes, err := topoServer.WatchTenantDirectory(requestCtx, key.keyspace, key.name)
+		if err != nil {
+			entry.update(ctx, nil, err, true)
+			return
+		}
+
+		entry.update(ctx, current.Value, current.Err, true)
+		if current.Err != nil {
+			return
+		}
+
+		for c := range changes {
+			entry.update(ctx, c.Value, c.Err, false)
+			if c.Err != nil {
+				return
+			}
+		}
+	}
+
+	rw := &resilientWatcher{
+		watcher:              watch,
+		counts:               counts,
+		cacheRefreshInterval: cacheRefresh,
+		cacheTTL:             cacheTTL,
+		entries:              make(map[string]*watchEntry),
+	}
+
+	return &TenantDirectoryWatcher{rw}
+}
+
+func (w *TenantDirectoryWatcher) WatchTenantDirectory(ctx context.Context, keyspace, name string, callback func(*vschemapb.TenantDirectory, error) bool) {
+	entry := w.rw.getEntry(&tenantDirectoryKey{keyspace, name})
+	entry.addListener(ctx, func(v any, err error) bool {
+		td, _ := v.(*vschemapb.TenantDirectory)
+		return callback(td, err)
+	})
+}
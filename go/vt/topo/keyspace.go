@@ -385,6 +385,17 @@ func (ts *Server) DeleteKeyspace(ctx context.Context, keyspace string) error {
 		return err
 	}
 
+	// Delete the content of the directory vindexes of the keyspace.
+	directories, err := ts.GetTenantDirectoryNames(ctx, keyspace)
+	if err != nil {
+		return err
+	}
+	for _, name := range directories {
+		if err := ts.DeleteTenantDirectory(ctx, keyspace, name); err != nil && !IsErrType(err, NoNode) {
+			return err
+		}
+	}
+
 	event.Dispatch(&events.KeyspaceChange{
 		KeyspaceName: keyspace,
 		Keyspace:     nil,
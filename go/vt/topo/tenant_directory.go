@@ -0,0 +1,215 @@
+/*
+Copyright 2026 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package topo
+
+import (
+	"context"
+	"path"
+
+	"vitess.io/vitess/go/vt/log"
+	"vitess.io/vitess/go/vt/vterrors"
+
+	vschemapb "vitess.io/vitess/go/vt/proto/vschema"
+)
+
+// This file contains the utility methods to manage TenantDirectory objects.
+// A TenantDirectory holds the content of a directory vindex, and is stored
+// in the global cell at keyspaces/<keyspace>/directories/<vindex name>.
+
+// TenantDirectoryInfo wraps a vschemapb.TenantDirectory with the keyspace
+// and vindex it belongs to, and the version it was read at.
+type TenantDirectoryInfo struct {
+	Keyspace string
+	Name     string
+	*vschemapb.TenantDirectory
+	version Version
+}
+
+// WatchTenantDirectoryData is returned / streamed by WatchTenantDirectory.
+// The WatchTenantDirectory API guarantees exactly one of Value or Err will be set.
+type WatchTenantDirectoryData struct {
+	Value *vschemapb.TenantDirectory
+	Err   error
+}
+
+func tenantDirectoryPath(keyspace, name string) string {
+	return path.Join(KeyspacesPath, keyspace, TenantDirectoriesPath, name)
+}
+
+// GetTenantDirectory reads the content of a directory vindex from the topo.
+func (ts *Server) GetTenantDirectory(ctx context.Context, keyspace, name string) (*TenantDirectoryInfo, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	data, version, err := ts.globalCell.Get(ctx, tenantDirectoryPath(keyspace, name))
+	if err != nil {
+		return nil, err
+	}
+	td := &vschemapb.TenantDirectory{}
+	if err := td.UnmarshalVT(data); err != nil {
+		return nil, vterrors.Wrapf(err, "bad tenant directory data: %q", data)
+	}
+	return &TenantDirectoryInfo{
+		Keyspace:        keyspace,
+		Name:            name,
+		TenantDirectory: td,
+		version:         version,
+	}, nil
+}
+
+// SaveTenantDirectory saves the content of a directory vindex. If the
+// TenantDirectoryInfo was read from the topo, the save only succeeds if the
+// directory was not modified in the meantime.
+func (ts *Server) SaveTenantDirectory(ctx context.Context, tdi *TenantDirectoryInfo) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	data, err := tdi.TenantDirectory.MarshalVT()
+	if err != nil {
+		return err
+	}
+
+	version, err := ts.globalCell.Update(ctx, tenantDirectoryPath(tdi.Keyspace, tdi.Name), data, tdi.version)
+	if err != nil {
+		return err
+	}
+	tdi.version = version
+	log.Infof("successfully updated tenant directory %s for keyspace %s: %d entries", tdi.Name, tdi.Keyspace, len(tdi.KeyspaceIds))
+	return nil
+}
+
+// UpdateTenantDirectoryFields is a high level helper method to read a
+// directory, update its content, and then write it back. If the write fails
+// due to a version mismatch, it will re-read the directory and retry the
+// update. A directory that does not exist is passed in empty, and created.
+// If the update method returns ErrNoUpdateNeeded, nothing is written.
+func (ts *Server) UpdateTenantDirectoryFields(ctx context.Context, keyspace, name string, update func(*vschemapb.TenantDirectory) error) (*vschemapb.TenantDirectory, error) {
+	for {
+		tdi, err := ts.GetTenantDirectory(ctx, keyspace, name)
+		switch {
+		case err == nil:
+		case IsErrType(err, NoNode):
+			tdi = &TenantDirectoryInfo{
+				Keyspace:        keyspace,
+				Name:            name,
+				TenantDirectory: &vschemapb.TenantDirectory{},
+			}
+		default:
+			return nil, err
+		}
+		if tdi.KeyspaceIds == nil {
+			tdi.KeyspaceIds = make(map[string][]byte)
+		}
+
+		if err := update(tdi.TenantDirectory); err != nil {
+			if IsErrType(err, NoUpdateNeeded) {
+				return tdi.TenantDirectory, nil
+			}
+			return nil, err
+		}
+
+		if err := ts.SaveTenantDirectory(ctx, tdi); !IsErrType(err, BadVersion) {
+			// This includes the 'err=nil' case.
+			if err != nil {
+				return nil, err
+			}
+			return tdi.TenantDirectory, nil
+		}
+	}
+}
+
+// DeleteTenantDirectory deletes the content of a directory vindex.
+func (ts *Server) DeleteTenantDirectory(ctx context.Context, keyspace, name string) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	return ts.globalCell.Delete(ctx, tenantDirectoryPath(keyspace, name), nil)
+}
+
+// GetTenantDirectoryNames returns the names of all the directory vindexes
+// of a keyspace that have content in the topo.
+func (ts *Server) GetTenantDirectoryNames(ctx context.Context, keyspace string) ([]string, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	children, err := ts.globalCell.ListDir(ctx, path.Join(KeyspacesPath, keyspace, TenantDirectoriesPath), false /*full*/)
+	switch {
+	case err == nil:
+		return DirEntriesToStringArray(children), nil
+	case IsErrType(err, NoNode):
+		return nil, nil
+	default:
+		return nil, err
+	}
+}
+
+// WatchTenantDirectory will set a watch on the content of a directory vindex.
+// It has the same contract as Conn.Watch, but it also unpacks the
+// contents into a TenantDirectory object.
+func (ts *Server) WatchTenantDirectory(ctx context.Context, keyspace, name string) (*WatchTenantDirectoryData, <-chan *WatchTenantDirectoryData, error) {
+	ctx, cancel := context.WithCancel(ctx)
+	current, wdChannel, err := ts.globalCell.Watch(ctx, tenantDirectoryPath(keyspace, name))
+	if err != nil {
+		cancel()
+		return nil, nil, err
+	}
+	value := &vschemapb.TenantDirectory{}
+	if err := value.UnmarshalVT(current.Contents); err != nil {
+		// Cancel the watch, drain channel.
+		cancel()
+		for range wdChannel {
+		}
+		return nil, nil, vterrors.Wrapf(err, "error unpacking initial TenantDirectory object")
+	}
+
+	changes := make(chan *WatchTenantDirectoryData, 10)
+
+	// The background routine reads any event from the watch channel,
+	// translates it, and sends it to the caller.
+	// If cancel() is called, the underlying Watch() code will
+	// send an ErrInterrupted and then close the channel. We'll
+	// just propagate that back to our caller.
+	go func() {
+		defer cancel()
+		defer close(changes)
+
+		for wd := range wdChannel {
+			if wd.Err != nil {
+				// Last error value, we're done.
+				// wdChannel will be closed right after
+				// this, no need to do anything.
+				changes <- &WatchTenantDirectoryData{Err: wd.Err}
+				return
+			}
+
+			value := &vschemapb.TenantDirectory{}
+			if err := value.UnmarshalVT(wd.Contents); err != nil {
+				cancel()
+				for range wdChannel {
+				}
+				changes <- &WatchTenantDirectoryData{Err: vterrors.Wrapf(err, "error unpacking TenantDirectory object")}
+				return
+			}
+			changes <- &WatchTenantDirectoryData{Value: value}
+		}
+	}()
+
+	return &WatchTenantDirectoryData{Value: value}, changes, nil
+}
@@ -0,0 +1,78 @@
+/*
+Copyright 2026 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package topo_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"vitess.io/vitess/go/vt/topo"
+	"vitess.io/vitess/go/vt/topo/memorytopo"
+
+	topodatapb "vitess.io/vitess/go/vt/proto/topodata"
+	vschemapb "vitess.io/vitess/go/vt/proto/vschema"
+)
+
+func TestTenantDirectory(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	ts := memorytopo.NewServer(ctx, "zone1")
+	defer ts.Close()
+
+	require.NoError(t, ts.CreateKeyspace(ctx, "ks", &topodatapb.Keyspace{}))
+
+	_, err := ts.GetTenantDirectory(ctx, "ks", "dir")
+	require.True(t, topo.IsErrType(err, topo.NoNode), "unexpected error: %v", err)
+
+	td, err := ts.UpdateTenantDirectoryFields(ctx, "ks", "dir", func(td *vschemapb.TenantDirectory) error {
+		td.KeyspaceIds["t1"] = []byte{0x10}
+		return nil
+	})
+	require.NoError(t, err)
+	assert.Equal(t, map[string][]byte{"t1": {0x10}}, td.KeyspaceIds)
+
+	current, changes, err := ts.WatchTenantDirectory(ctx, "ks", "dir")
+	require.NoError(t, err)
+	assert.Equal(t, map[string][]byte{"t1": {0x10}}, current.Value.KeyspaceIds)
+
+	// Updating a stale copy fails.
+	stale, err := ts.GetTenantDirectory(ctx, "ks", "dir")
+	require.NoError(t, err)
+	tdi, err := ts.GetTenantDirectory(ctx, "ks", "dir")
+	require.NoError(t, err)
+	tdi.KeyspaceIds["t2"] = []byte{0x80}
+	require.NoError(t, ts.SaveTenantDirectory(ctx, tdi))
+	stale.KeyspaceIds["t3"] = []byte{0xc0}
+	err = ts.SaveTenantDirectory(ctx, stale)
+	require.True(t, topo.IsErrType(err, topo.BadVersion), "unexpected error: %v", err)
+
+	wd := <-changes
+	require.NoError(t, wd.Err)
+	assert.Equal(t, map[string][]byte{"t1": {0x10}, "t2": {0x80}}, wd.Value.KeyspaceIds)
+
+	names, err := ts.GetTenantDirectoryNames(ctx, "ks")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"dir"}, names)
+
+	// Deleting the keyspace deletes its directories.
+	require.NoError(t, ts.DeleteKeyspace(ctx, "ks"))
+	_, err = ts.GetTenantDirectory(ctx, "ks", "dir")
+	require.True(t, topo.IsErrType(err, topo.NoNode), "unexpected error: %v", err)
+}
@@ -515,6 +515,15 @@ func (client *gRPCVtctldClient) GetTablets(ctx context.Context, in *vtctldatapb.
 	return client.c.GetTablets(ctx, in, opts...)
 }
 
+// GetTenantDirectory is part of the vtctlservicepb.VtctldClient interface.
+func (client *gRPCVtctldClient) GetTenantDirectory(ctx context.Context, in *vtctldatapb.GetTenantDirectoryRequest, opts ...grpc.CallOption) (*vtctldatapb.GetTenantDirectoryResponse, error) {
+	if client.c == nil {
+		return nil, status.Error(codes.Unavailable, connClosedMsg)
+	}
+
+	return client.c.GetTenantDirectory(ctx, in, opts...)
+}
+
 // GetThrottlerStatus is part of the vtctlservicepb.VtctldClient interface.
 func (client *gRPCVtctldClient) GetThrottlerStatus(ctx context.Context, in *vtctldatapb.GetThrottlerStatusRequest, opts ...grpc.CallOption) (*vtctldatapb.GetThrottlerStatusResponse, error) {
 	if client.c == nil {
@@ -722,6 +731,24 @@ func (client *gRPCVtctldClient) PlannedReparentShard(ctx context.Context, in *vt
 	return client.c.PlannedReparentShard(ctx, in, opts...)
 }
 
+// PruneBackups is part of the vtctlservicepb.VtctldClient interface.
+func (client *gRPCVtctldClient) PruneBackups(ctx context.Context, in *vtctldatapb.PruneBackupsRequest, opts ...grpc.CallOption) (*vtctldatapb.PruneBackupsResponse, error) {
+	if client.c == nil {
+		return nil, status.Error(codes.Unavailable, connClosedMsg)
+	}
+
+	return client.c.PruneBackups(ctx, in, opts...)
+}
+
+// RebuildBackupCatalog is part of the vtctlservicepb.VtctldClient interface.
+func (client *gRPCVtctldClient) RebuildBackupCatalog(ctx context.Context, in *vtctldatapb.RebuildBackupCatalogRequest, opts ...grpc.CallOption) (*vtctldatapb.RebuildBackupCatalogResponse, error) {
+	if client.c == nil {
+		return nil, status.Error(codes.Unavailable, connClosedMsg)
+	}
+
+	return client.c.RebuildBackupCatalog(ctx, in, opts...)
+}
+
 // RebuildKeyspaceGraph is part of the vtctlservicepb.VtctldClient interface.
 func (client *gRPCVtctldClient) RebuildKeyspaceGraph(ctx context.Context, in *vtctldatapb.RebuildKeyspaceGraphRequest, opts ...grpc.CallOption) (*vtctldatapb.RebuildKeyspaceGraphResponse, error) {
 	if client.c == nil {
@@ -1010,6 +1037,15 @@ func (client *gRPCVtctldClient) UpdateCellsAlias(ctx context.Context, in *vtctld
 	return client.c.UpdateCellsAlias(ctx, in, opts...)
 }
 
+// UpdateTenantDirectory is part of the vtctlservicepb.VtctldClient interface.
+func (client *gRPCVtctldClient) UpdateTenantDirectory(ctx context.Context, in *vtctldatapb.UpdateTenantDirectoryRequest, opts ...grpc.CallOption) (*vtctldatapb.UpdateTenantDirectoryResponse, error) {
+	if client.c == nil {
+		return nil, status.Error(codes.Unavailable, connClosedMsg)
+	}
+
+	return client.c.UpdateTenantDirectory(ctx, in, opts...)
+}
+
 // UpdateThrottlerConfig is part of the vtctlservicepb.VtctldClient interface.
 func (client *gRPCVtctldClient) UpdateThrottlerConfig(ctx context.Context, in *vtctldatapb.UpdateThrottlerConfigRequest, opts ...grpc.CallOption) (*vtctldatapb.UpdateThrottlerConfigResponse, error) {
 	if client.c == nil {
@@ -1136,6 +1172,15 @@ func (client *gRPCVtctldClient) ValidateVersionShard(ctx context.Context, in *vt
 	return client.c.ValidateVersionShard(ctx, in, opts...)
 }
 
+// VerifyBackup is part of the vtctlservicepb.VtctldClient interface.
+func (client *gRPCVtctldClient) VerifyBackup(ctx context.Context, in *vtctldatapb.VerifyBackupRequest, opts ...grpc.CallOption) (*vtctldatapb.VerifyBackupResponse, error) {
+	if client.c == nil {
+		return nil, status.Error(codes.Unavailable, connClosedMsg)
+	}
+
+	return client.c.VerifyBackup(ctx, in, opts...)
+}
+
 // WorkflowAddTables is part of the vtctlservicepb.VtctldClient interface.
 func (client *gRPCVtctldClient) WorkflowAddTables(ctx context.Context, in *vtctldatapb.WorkflowAddTablesRequest, opts ...grpc.CallOption) (*vtctldatapb.WorkflowAddTablesResponse, error) {
 	if client.c == nil {
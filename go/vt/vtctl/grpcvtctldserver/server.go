@@ -37,6 +37,7 @@ import (
 	"google.golang.org/grpc"
 
 	"vitess.io/vitess/go/event"
+	"vitess.io/vitess/go/mysql/collations"
 	"vitess.io/vitess/go/netutil"
 	"vitess.io/vitess/go/protoutil"
 	"vitess.io/vitess/go/sets"
@@ -51,6 +52,7 @@ import (
 	"vitess.io/vitess/go/vt/log"
 	"vitess.io/vitess/go/vt/logutil"
 	"vitess.io/vitess/go/vt/mysqlctl"
+	"vitess.io/vitess/go/vt/mysqlctl/backupstats"
 	"vitess.io/vitess/go/vt/mysqlctl/backupstorage"
 	"vitess.io/vitess/go/vt/mysqlctl/mysqlctlproto"
 	"vitess.io/vitess/go/vt/mysqlctl/tmutils"
@@ -67,6 +69,7 @@ import (
 	vtrpcpb "vitess.io/vitess/go/vt/proto/vtrpc"
 	"vitess.io/vitess/go/vt/schema"
 	"vitess.io/vitess/go/vt/schemamanager"
+	"vitess.io/vitess/go/vt/servenv"
 	"vitess.io/vitess/go/vt/sqlparser"
 	"vitess.io/vitess/go/vt/topo"
 	"vitess.io/vitess/go/vt/topo/topoproto"
@@ -766,6 +769,32 @@ func (s *VtctldServer) CheckThrottler(ctx context.Context, req *vtctldatapb.Chec
 	return resp, nil
 }
 
+// GetTenantDirectory is part of the vtctlservicepb.VtctldServer interface.
+func (s *VtctldServer) GetTenantDirectory(ctx context.Context, req *vtctldatapb.GetTenantDirectoryRequest) (resp *vtctldatapb.GetTenantDirectoryResponse, err error) {
+	span, ctx := trace.NewSpan(ctx, "VtctldServer.GetTenantDirectory")
+	defer span.Finish()
+
+	defer panicHandler(&err)
+
+	span.Annotate("keyspace", req.Keyspace)
+	span.Annotate("vindex", req.Vindex)
+
+	if err := s.checkDirectoryVindex(ctx, req.Keyspace, req.Vindex); err != nil {
+		return nil, err
+	}
+
+	tdi, err := s.ts.GetTenantDirectory(ctx, req.Keyspace, req.Vindex)
+	switch {
+	case err == nil:
+		return &vtctldatapb.GetTenantDirectoryResponse{TenantDirectory: tdi.TenantDirectory}, nil
+	case topo.IsErrType(err, topo.NoNode):
+		// The directory was never written to, so it is empty.
+		return &vtctldatapb.GetTenantDirectoryResponse{TenantDirectory: &vschemapb.TenantDirectory{}}, nil
+	default:
+		return nil, err
+	}
+}
+
 // GetThrottlerStatus is part of the vtctlservicepb.VtctldServer interface.
 func (s *VtctldServer) GetThrottlerStatus(ctx context.Context, req *vtctldatapb.GetThrottlerStatusRequest) (resp *vtctldatapb.GetThrottlerStatusResponse, err error) {
 	span, ctx := trace.NewSpan(ctx, "VtctldServer.GetThrottlerStatus")
@@ -1542,6 +1571,14 @@ func (s *VtctldServer) GetBackups(ctx context.Context, req *vtctldatapb.GetBacku
 		totalDetailedBackups = int(req.DetailedLimit)
 	}
 
+	var catalog *mysqlctl.BackupCatalog
+	if req.Detailed {
+		if catalog, err = mysqlctl.ReadBackupCatalog(ctx, bs, bucket); err != nil {
+			// The details are then read from the backups.
+			log.Warningf("GetBackups(%v): %v", bucket, err)
+		}
+	}
+
 	backups := make([]*mysqlctlpb.BackupInfo, 0, totalBackups)
 	backupsToSkip := len(bhs) - totalBackups
 	backupsToSkipDetails := len(bhs) - totalDetailedBackups
@@ -1555,11 +1592,10 @@ func (s *VtctldServer) GetBackups(ctx context.Context, req *vtctldatapb.GetBacku
 		bi.Keyspace = req.Keyspace
 		bi.Shard = req.Shard
 
-		if req.Detailed {
-			if i >= backupsToSkipDetails { // nolint:staticcheck
-				// (TODO:@ajm188) Update backupengine/backupstorage implementations
-				// to get Status info for backups.
-			}
+		if req.Detailed && i >= backupsToSkipDetails {
+			// A backup without a readable MANIFEST is incomplete.
+			entry, _ := catalog.GetEntry(ctx, bh)
+			mysqlctlproto.SetBackupInfoDetails(bi, entry)
 		}
 
 		backups = append(backups, bi)
@@ -3343,6 +3379,78 @@ func (s *VtctldServer) PlannedReparentShard(ctx context.Context, req *vtctldatap
 	return resp, err
 }
 
+// PruneBackups is part of the vtctlservicepb.VtctldServer interface.
+func (s *VtctldServer) PruneBackups(ctx context.Context, req *vtctldatapb.PruneBackupsRequest) (resp *vtctldatapb.PruneBackupsResponse, err error) {
+	span, ctx := trace.NewSpan(ctx, "VtctldServer.PruneBackups")
+	defer span.Finish()
+
+	defer panicHandler(&err)
+
+	bucket := fmt.Sprintf("%v/%v", req.Keyspace, req.Shard)
+
+	span.Annotate("keyspace", req.Keyspace)
+	span.Annotate("shard", req.Shard)
+	span.Annotate("bucket", bucket)
+	span.Annotate("keep_full", req.KeepFull)
+	span.Annotate("dry_run", req.DryRun)
+
+	keepFor, _, err := protoutil.DurationFromProto(req.KeepFor)
+	if err != nil {
+		return nil, vterrors.Wrapf(err, "unable to parse KeepFor into a valid duration")
+	}
+
+	bs, err := backupstorage.GetBackupStorage()
+	if err != nil {
+		return nil, err
+	}
+	defer bs.Close()
+
+	removed, kept, err := mysqlctl.PruneBackups(ctx, bs, bucket, mysqlctl.RetentionPolicy{
+		KeepFull:         int(req.KeepFull),
+		KeepFor:          keepFor,
+		KeepRecoveryFrom: protoutil.TimeFromProto(req.KeepRecoveryFrom).UTC(),
+	}, req.DryRun, logutil.NewConsoleLogger())
+	if err != nil {
+		return nil, err
+	}
+
+	return &vtctldatapb.PruneBackupsResponse{
+		RemovedBackups: removed,
+		KeptBackups:    kept,
+	}, nil
+}
+
+// RebuildBackupCatalog is part of the vtctlservicepb.VtctldServer interface.
+func (s *VtctldServer) RebuildBackupCatalog(ctx context.Context, req *vtctldatapb.RebuildBackupCatalogRequest) (resp *vtctldatapb.RebuildBackupCatalogResponse, err error) {
+	span, ctx := trace.NewSpan(ctx, "VtctldServer.RebuildBackupCatalog")
+	defer span.Finish()
+
+	defer panicHandler(&err)
+
+	bucket := fmt.Sprintf("%v/%v", req.Keyspace, req.Shard)
+
+	span.Annotate("keyspace", req.Keyspace)
+	span.Annotate("shard", req.Shard)
+	span.Annotate("bucket", bucket)
+
+	bs, err := backupstorage.GetBackupStorage()
+	if err != nil {
+		return nil, err
+	}
+	defer bs.Close()
+
+	catalog, err := mysqlctl.RebuildBackupCatalog(ctx, bs, bucket, logutil.NewConsoleLogger())
+	if err != nil {
+		return nil, err
+	}
+
+	resp = &vtctldatapb.RebuildBackupCatalogResponse{}
+	for _, entry := range catalog.Backups {
+		resp.Backups = append(resp.Backups, entry.BackupName)
+	}
+	return resp, nil
+}
+
 // RebuildKeyspaceGraph is part of the vtctlservicepb.VtctldServer interface.
 func (s *VtctldServer) RebuildKeyspaceGraph(ctx context.Context, req *vtctldatapb.RebuildKeyspaceGraphRequest) (resp *vtctldatapb.RebuildKeyspaceGraphResponse, err error) {
 	span, ctx := trace.NewSpan(ctx, "VtctldServer.RebuildKeyspaceGraph")
@@ -3578,10 +3686,17 @@ func (s *VtctldServer) RemoveBackup(ctx context.Context, req *vtctldatapb.Remove
 	}
 	defer bs.Close()
 
-	if err = bs.RemoveBackup(ctx, bucket, req.Name); err != nil {
+	if err = mysqlctl.RemoveBackup(ctx, bs, bucket, req.Name, logutil.NewConsoleLogger()); err != nil {
 		return nil, err
 	}
 
+	// The chunks of deduplicated backups are shared, and only removed once
+	// no backup references them. Failing to remove them is not an error, as
+	// they are removed again the next time a backup is.
+	if _, err := mysqlctl.RemoveUnreferencedChunks(ctx, bs, bucket); err != nil {
+		log.Warningf("RemoveBackup(%v/%v): %v", bucket, req.Name, err)
+	}
+
 	return &vtctldatapb.RemoveBackupResponse{}, nil
 }
 
@@ -4661,6 +4776,71 @@ func (s *VtctldServer) UpdateCellsAlias(ctx context.Context, req *vtctldatapb.Up
 	}, nil
 }
 
+// UpdateTenantDirectory is part of the vtctlservicepb.VtctldServer interface.
+func (s *VtctldServer) UpdateTenantDirectory(ctx context.Context, req *vtctldatapb.UpdateTenantDirectoryRequest) (resp *vtctldatapb.UpdateTenantDirectoryResponse, err error) {
+	span, ctx := trace.NewSpan(ctx, "VtctldServer.UpdateTenantDirectory")
+	defer span.Finish()
+
+	defer panicHandler(&err)
+
+	span.Annotate("keyspace", req.Keyspace)
+	span.Annotate("vindex", req.Vindex)
+	span.Annotate("set", len(req.Set))
+	span.Annotate("remove", len(req.Remove))
+
+	for _, tenant := range req.Remove {
+		if _, ok := req.Set[tenant]; ok {
+			return nil, vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "tenant %s cannot be both set and removed", tenant)
+		}
+	}
+	for tenant, ksid := range req.Set {
+		if len(ksid) == 0 {
+			return nil, vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "empty keyspace id for tenant %s", tenant)
+		}
+	}
+
+	if err := s.checkDirectoryVindex(ctx, req.Keyspace, req.Vindex); err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, topo.RemoteOperationTimeout)
+	defer cancel()
+
+	td, err := s.ts.UpdateTenantDirectoryFields(ctx, req.Keyspace, req.Vindex, func(td *vschemapb.TenantDirectory) error {
+		for tenant, ksid := range req.Set {
+			td.KeyspaceIds[tenant] = ksid
+		}
+		for _, tenant := range req.Remove {
+			delete(td.KeyspaceIds, tenant)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &vtctldatapb.UpdateTenantDirectoryResponse{
+		TenantDirectory: td,
+	}, nil
+}
+
+// checkDirectoryVindex returns an error if the VSchema of the keyspace does
+// not have a directory vindex with the given name.
+func (s *VtctldServer) checkDirectoryVindex(ctx context.Context, keyspace, name string) error {
+	ksvs, err := s.ts.GetVSchema(ctx, keyspace)
+	if err != nil {
+		return err
+	}
+	vindex, ok := ksvs.Vindexes[name]
+	if !ok {
+		return vterrors.Errorf(vtrpcpb.Code_NOT_FOUND, "vindex %s not found in keyspace %s", name, keyspace)
+	}
+	if vindex.Type != "directory" {
+		return vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "vindex %s in keyspace %s is of type %s, not directory", name, keyspace, vindex.Type)
+	}
+	return nil
+}
+
 // Validate is part of the vtctlservicepb.VtctldServer interface.
 func (s *VtctldServer) Validate(ctx context.Context, req *vtctldatapb.ValidateRequest) (resp *vtctldatapb.ValidateResponse, err error) {
 	span, ctx := trace.NewSpan(ctx, "VtctldServer.Validate")
@@ -5602,6 +5782,47 @@ func (s *VtctldServer) VDiffStop(ctx context.Context, req *vtctldatapb.VDiffStop
 	return resp, err
 }
 
+// VerifyBackup is part of the vtctlservicepb.VtctldServer interface.
+func (s *VtctldServer) VerifyBackup(ctx context.Context, req *vtctldatapb.VerifyBackupRequest) (resp *vtctldatapb.VerifyBackupResponse, err error) {
+	span, ctx := trace.NewSpan(ctx, "VtctldServer.VerifyBackup")
+	defer span.Finish()
+
+	defer panicHandler(&err)
+
+	span.Annotate("keyspace", req.Keyspace)
+	span.Annotate("shard", req.Shard)
+	span.Annotate("backup_name", req.Name)
+
+	concurrency := int(req.Concurrency)
+	if concurrency == 0 {
+		concurrency = 4
+	}
+
+	result, err := mysqlctl.VerifyBackup(ctx, mysqlctl.VerifyParams{
+		Logger:       logutil.NewConsoleLogger(),
+		Keyspace:     req.Keyspace,
+		Shard:        req.Shard,
+		BackupName:   req.Name,
+		DbName:       topoproto.VtDbPrefix + req.Keyspace,
+		Concurrency:  concurrency,
+		MysqlPort:    int(req.MysqlPort),
+		CollationEnv: collations.NewEnvironment(servenv.MySQLServerVersion()),
+		Stats:        backupstats.RestoreStats(),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &vtctldatapb.VerifyBackupResponse{
+		Success:          result.Success(),
+		Position:         result.Position,
+		RestoredPosition: result.RestoredPosition,
+		TablesChecked:    int32(result.TablesChecked),
+		FailedTables:     result.FailedTables,
+		Error:            result.Error,
+	}, nil
+}
+
 // WorkflowDelete is part of the vtctlservicepb.VtctldServer interface.
 func (s *VtctldServer) WorkflowDelete(ctx context.Context, req *vtctldatapb.WorkflowDeleteRequest) (resp *vtctldatapb.WorkflowDeleteResponse, err error) {
 	span, ctx := trace.NewSpan(ctx, "VtctldServer.WorkflowDelete")
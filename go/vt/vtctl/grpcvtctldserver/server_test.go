@@ -8510,6 +8510,88 @@ func TestGetVSchema(t *testing.T) {
 	})
 }
 
+func TestTenantDirectory(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	ts := memorytopo.NewServer(ctx, "zone1")
+	vtctld := testutil.NewVtctldServerWithTabletManagerClient(t, ts, nil, func(ts *topo.Server) vtctlservicepb.VtctldServer {
+		return NewVtctldServer(vtenv.NewTestEnv(), ts)
+	})
+
+	err := ts.SaveVSchema(ctx, &topo.KeyspaceVSchemaInfo{
+		Name: "testkeyspace",
+		Keyspace: &vschemapb.Keyspace{
+			Sharded: true,
+			Vindexes: map[string]*vschemapb.Vindex{
+				"dir":  {Type: "directory"},
+				"hash": {Type: "hash"},
+			},
+		},
+	})
+	require.NoError(t, err)
+
+	resp, err := vtctld.GetTenantDirectory(ctx, &vtctldatapb.GetTenantDirectoryRequest{
+		Keyspace: "testkeyspace",
+		Vindex:   "dir",
+	})
+	require.NoError(t, err)
+	utils.MustMatch(t, &vschemapb.TenantDirectory{}, resp.TenantDirectory)
+
+	_, err = vtctld.UpdateTenantDirectory(ctx, &vtctldatapb.UpdateTenantDirectoryRequest{
+		Keyspace: "testkeyspace",
+		Vindex:   "dir",
+		Set:      map[string][]byte{"t1": {0x10}, "t2": {0x80}, "t3": {0xc0}},
+	})
+	require.NoError(t, err)
+
+	updateResp, err := vtctld.UpdateTenantDirectory(ctx, &vtctldatapb.UpdateTenantDirectoryRequest{
+		Keyspace: "testkeyspace",
+		Vindex:   "dir",
+		Set:      map[string][]byte{"t2": {0x90}},
+		Remove:   []string{"t3", "unknown"},
+	})
+	require.NoError(t, err)
+	expected := &vschemapb.TenantDirectory{KeyspaceIds: map[string][]byte{"t1": {0x10}, "t2": {0x90}}}
+	utils.MustMatch(t, expected, updateResp.TenantDirectory)
+
+	resp, err = vtctld.GetTenantDirectory(ctx, &vtctldatapb.GetTenantDirectoryRequest{
+		Keyspace: "testkeyspace",
+		Vindex:   "dir",
+	})
+	require.NoError(t, err)
+	utils.MustMatch(t, expected, resp.TenantDirectory)
+
+	tests := []struct {
+		name string
+		req  *vtctldatapb.UpdateTenantDirectoryRequest
+		err  string
+	}{{
+		name: "set and removed",
+		req:  &vtctldatapb.UpdateTenantDirectoryRequest{Keyspace: "testkeyspace", Vindex: "dir", Set: map[string][]byte{"t1": {0x10}}, Remove: []string{"t1"}},
+		err:  "tenant t1 cannot be both set and removed",
+	}, {
+		name: "empty keyspace id",
+		req:  &vtctldatapb.UpdateTenantDirectoryRequest{Keyspace: "testkeyspace", Vindex: "dir", Set: map[string][]byte{"t1": nil}},
+		err:  "empty keyspace id for tenant t1",
+	}, {
+		name: "unknown vindex",
+		req:  &vtctldatapb.UpdateTenantDirectoryRequest{Keyspace: "testkeyspace", Vindex: "nope", Remove: []string{"t1"}},
+		err:  "vindex nope not found in keyspace testkeyspace",
+	}, {
+		name: "not a directory",
+		req:  &vtctldatapb.UpdateTenantDirectoryRequest{Keyspace: "testkeyspace", Vindex: "hash", Remove: []string{"t1"}},
+		err:  "vindex hash in keyspace testkeyspace is of type hash, not directory",
+	}}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := vtctld.UpdateTenantDirectory(ctx, tt.req)
+			assert.ErrorContains(t, err, tt.err)
+		})
+	}
+}
+
 func TestLaunchSchemaMigration(t *testing.T) {
 	t.Parallel()
 
@@ -9071,6 +9153,59 @@ func TestPlannedReparentShard(t *testing.T) {
 	}
 }
 
+func TestPruneBackups(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	ts := memorytopo.NewServer(ctx)
+	vtctld := testutil.NewVtctldServerWithTabletManagerClient(t, ts, nil, func(ts *topo.Server) vtctlservicepb.VtctldServer {
+		return NewVtctldServer(vtenv.NewTestEnv(), ts)
+	})
+
+	testutil.BackupStorage.Backups = map[string][]string{
+		"testkeyspace/-": {"backup1", "backup2"},
+	}
+
+	t.Run("ok", func(t *testing.T) {
+		// Backups that can't be dated are never removed.
+		resp, err := vtctld.PruneBackups(ctx, &vtctldatapb.PruneBackupsRequest{
+			Keyspace: "testkeyspace",
+			Shard:    "-",
+			KeepFull: 1,
+		})
+		require.NoError(t, err)
+		assert.Empty(t, resp.RemovedBackups)
+		assert.Equal(t, map[string]string{"backup1": "unknown backup time", "backup2": "unknown backup time"}, resp.KeptBackups)
+	})
+
+	t.Run("no full backup kept", func(t *testing.T) {
+		_, err := vtctld.PruneBackups(ctx, &vtctldatapb.PruneBackupsRequest{
+			Keyspace: "testkeyspace",
+			Shard:    "-",
+		})
+		assert.ErrorContains(t, err, "at least one full backup must be kept")
+	})
+}
+
+func TestRebuildBackupCatalog(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	ts := memorytopo.NewServer(ctx)
+	vtctld := testutil.NewVtctldServerWithTabletManagerClient(t, ts, nil, func(ts *topo.Server) vtctlservicepb.VtctldServer {
+		return NewVtctldServer(vtenv.NewTestEnv(), ts)
+	})
+
+	testutil.BackupStorage.Backups = map[string][]string{
+		"testkeyspace/-": {"backup1", "backup2"},
+	}
+
+	// The test backup storage does not store catalogs.
+	_, err := vtctld.RebuildBackupCatalog(ctx, &vtctldatapb.RebuildBackupCatalogRequest{
+		Keyspace: "testkeyspace",
+		Shard:    "-",
+	})
+	assert.ErrorContains(t, err, "does not store backup catalogs")
+}
+
 func TestRebuildKeyspaceGraph(t *testing.T) {
 	t.Parallel()
 
@@ -391,6 +391,11 @@ func (client *localVtctldClient) GetTablets(ctx context.Context, in *vtctldatapb
 	return client.s.GetTablets(ctx, in)
 }
 
+// GetTenantDirectory is part of the vtctlservicepb.VtctldClient interface.
+func (client *localVtctldClient) GetTenantDirectory(ctx context.Context, in *vtctldatapb.GetTenantDirectoryRequest, opts ...grpc.CallOption) (*vtctldatapb.GetTenantDirectoryResponse, error) {
+	return client.s.GetTenantDirectory(ctx, in)
+}
+
 // GetThrottlerStatus is part of the vtctlservicepb.VtctldClient interface.
 func (client *localVtctldClient) GetThrottlerStatus(ctx context.Context, in *vtctldatapb.GetThrottlerStatusRequest, opts ...grpc.CallOption) (*vtctldatapb.GetThrottlerStatusResponse, error) {
 	return client.s.GetThrottlerStatus(ctx, in)
@@ -506,6 +511,16 @@ func (client *localVtctldClient) PlannedReparentShard(ctx context.Context, in *v
 	return client.s.PlannedReparentShard(ctx, in)
 }
 
+// PruneBackups is part of the vtctlservicepb.VtctldClient interface.
+func (client *localVtctldClient) PruneBackups(ctx context.Context, in *vtctldatapb.PruneBackupsRequest, opts ...grpc.CallOption) (*vtctldatapb.PruneBackupsResponse, error) {
+	return client.s.PruneBackups(ctx, in)
+}
+
+// RebuildBackupCatalog is part of the vtctlservicepb.VtctldClient interface.
+func (client *localVtctldClient) RebuildBackupCatalog(ctx context.Context, in *vtctldatapb.RebuildBackupCatalogRequest, opts ...grpc.CallOption) (*vtctldatapb.RebuildBackupCatalogResponse, error) {
+	return client.s.RebuildBackupCatalog(ctx, in)
+}
+
 // RebuildKeyspaceGraph is part of the vtctlservicepb.VtctldClient interface.
 func (client *localVtctldClient) RebuildKeyspaceGraph(ctx context.Context, in *vtctldatapb.RebuildKeyspaceGraphRequest, opts ...grpc.CallOption) (*vtctldatapb.RebuildKeyspaceGraphResponse, error) {
 	return client.s.RebuildKeyspaceGraph(ctx, in)
@@ -712,6 +727,11 @@ func (client *localVtctldClient) UpdateCellsAlias(ctx context.Context, in *vtctl
 	return client.s.UpdateCellsAlias(ctx, in)
 }
 
+// UpdateTenantDirectory is part of the vtctlservicepb.VtctldClient interface.
+func (client *localVtctldClient) UpdateTenantDirectory(ctx context.Context, in *vtctldatapb.UpdateTenantDirectoryRequest, opts ...grpc.CallOption) (*vtctldatapb.UpdateTenantDirectoryResponse, error) {
+	return client.s.UpdateTenantDirectory(ctx, in)
+}
+
 // UpdateThrottlerConfig is part of the vtctlservicepb.VtctldClient interface.
 func (client *localVtctldClient) UpdateThrottlerConfig(ctx context.Context, in *vtctldatapb.UpdateThrottlerConfigRequest, opts ...grpc.CallOption) (*vtctldatapb.UpdateThrottlerConfigResponse, error) {
 	return client.s.UpdateThrottlerConfig(ctx, in)
@@ -782,6 +802,11 @@ func (client *localVtctldClient) ValidateVersionShard(ctx context.Context, in *v
 	return client.s.ValidateVersionShard(ctx, in)
 }
 
+// VerifyBackup is part of the vtctlservicepb.VtctldClient interface.
+func (client *localVtctldClient) VerifyBackup(ctx context.Context, in *vtctldatapb.VerifyBackupRequest, opts ...grpc.CallOption) (*vtctldatapb.VerifyBackupResponse, error) {
+	return client.s.VerifyBackup(ctx, in)
+}
+
 // WorkflowAddTables is part of the vtctlservicepb.VtctldClient interface.
 func (client *localVtctldClient) WorkflowAddTables(ctx context.Context, in *vtctldatapb.WorkflowAddTablesRequest, opts ...grpc.CallOption) (*vtctldatapb.WorkflowAddTablesResponse, error) {
 	return client.s.WorkflowAddTables(ctx, in)
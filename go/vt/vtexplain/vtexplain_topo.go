@@ -117,3 +117,8 @@ func (et *ExplainTopo) WatchSrvKeyspace(ctx context.Context, cell, keyspace stri
 func (et *ExplainTopo) WatchSrvVSchema(ctx context.Context, cell string, callback func(*vschemapb.SrvVSchema, error) bool) {
 	callback(et.getSrvVSchema(), nil)
 }
+
+// WatchTenantDirectory is part of the srvtopo.Server interface.
+func (et *ExplainTopo) WatchTenantDirectory(ctx context.Context, keyspace, name string, callback func(*vschemapb.TenantDirectory, error) bool) {
+	callback(nil, topo.NewError(topo.NoNode, keyspace+"/"+name))
+}
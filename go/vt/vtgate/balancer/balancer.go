@@ -22,6 +22,7 @@ import (
 	"math/rand/v2"
 	"net/http"
 	"sync"
+	"time"
 
 	"vitess.io/vitess/go/vt/discovery"
 	querypb "vitess.io/vitess/go/vt/proto/query"
@@ -92,6 +93,13 @@ type TabletBalancer interface {
 	// for a given query to maintain the desired balanced allocation over multiple executions.
 	Pick(target *querypb.Target, tablets []*discovery.TabletHealth) *discovery.TabletHealth
 
+	// QueryStarted records that a query picked by the balancer is being sent to the tablet.
+	QueryStarted(th *discovery.TabletHealth)
+
+	// QueryFinished records that a query sent to the tablet completed after the given latency,
+	// with the given error if it failed.
+	QueryFinished(th *discovery.TabletHealth, latency time.Duration, err error)
+
 	// DebugHandler provides a summary of tablet balancer state
 	DebugHandler(w http.ResponseWriter, r *http.Request)
 }
@@ -188,6 +196,13 @@ func (b *tabletBalancer) Pick(target *querypb.Target, tablets []*discovery.Table
 	return tablets[0]
 }
 
+// QueryStarted implements the TabletBalancer interface. The allocation of the
+// flows does not depend on the load of the tablets.
+func (b *tabletBalancer) QueryStarted(*discovery.TabletHealth) {}
+
+// QueryFinished implements the TabletBalancer interface.
+func (b *tabletBalancer) QueryFinished(*discovery.TabletHealth, time.Duration, error) {}
+
 // To stick with integer arithmetic, use 1,000,000 as the full load
 const ALLOCATION = 1000000
 
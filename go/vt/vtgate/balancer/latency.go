@@ -0,0 +1,254 @@
+/*
+Copyright 2026 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package balancer
+
+import (
+	"fmt"
+	"maps"
+	"math"
+	"math/rand/v2"
+	"net/http"
+	"slices"
+	"sync"
+	"time"
+
+	"vitess.io/vitess/go/vt/discovery"
+	"vitess.io/vitess/go/vt/topo/topoproto"
+	"vitess.io/vitess/go/vt/vterrors"
+
+	querypb "vitess.io/vitess/go/vt/proto/query"
+	vtrpcpb "vitess.io/vitess/go/vt/proto/vtrpc"
+)
+
+/*
+
+The latencyBalancer picks tablets based on the load they are observed to be under
+rather than on the topology, in order to steer queries away from a replica that is
+slower than its peers, e.g. because of a noisy neighbor or a degraded disk.
+
+For each tablet, the balancer keeps an exponentially weighted moving average (EWMA) of
+the latency of the queries the vtgate sent to it, and the number of queries currently
+in flight. The cost of a tablet is its EWMA latency multiplied by its number of in-flight
+queries plus one, so that a fast tablet that is already busy is not piled onto.
+Streaming calls, e.g. StreamExecute or VStream, last as long as their caller wants:
+the gateway picks their tablet with the balancer, but does not account them.
+
+For each query, the balancer uses the "power of two choices": it samples two distinct
+tablets at random and picks the one with the lowest cost. This avoids the herding of
+always picking the cheapest tablet, while still moving most of the load away from the
+slow ones.
+
+Since tablets in remote cells are naturally slower to reach, the balancer prefers the
+local cell without having to be told about the topology.
+
+A query that fails because of the tablet, e.g. because it is unavailable or overloaded,
+counts as a slow one so that the tablet is avoided. The latency of the other failed
+queries does not tell how the tablet performs, and is ignored.
+
+A tablet that has not received a query for a while has its estimate forgotten, so that
+a tablet that was slow once gets probed again instead of being avoided forever, and
+the tablets removed from the topology are eventually dropped.
+
+*/
+
+const (
+	// ewmaWeight is the weight of a new latency sample in the EWMA.
+	ewmaWeight = 0.3
+
+	// latencyStaleAfter is how long after its last sample the EWMA of a tablet
+	// is forgotten.
+	latencyStaleAfter = 30 * time.Second
+
+	// errorLatency is the minimum latency sample of a query that failed
+	// because of the tablet.
+	errorLatency = time.Second
+)
+
+// NewLatencyBalancer returns a TabletBalancer that prefers the tablets with
+// the lowest observed latency and number of in-flight queries.
+func NewLatencyBalancer() TabletBalancer {
+	return &latencyBalancer{
+		tablets: map[string]*tabletLoad{},
+		now:     time.Now,
+	}
+}
+
+type latencyBalancer struct {
+	// mu protects the tablets map and the tabletLoad it holds
+	mu sync.Mutex
+
+	// tablets is the observed load of each tablet, indexed by tablet alias
+	tablets map[string]*tabletLoad
+
+	// now returns the current time, and is overridden in tests
+	now func() time.Time
+
+	// lastPrune is when the stale tablets were last dropped
+	lastPrune time.Time
+}
+
+type tabletLoad struct {
+	// EWMA of the latency of the queries sent to the tablet
+	Latency time.Duration
+
+	// Number of queries currently sent to the tablet
+	InFlight int
+
+	// Total number of queries sent to the tablet since its estimate was
+	// last forgotten
+	Queries int64
+
+	// Time of the last latency sample
+	LastSample time.Time
+}
+
+// cost returns the cost of sending a query to the tablet, the lower the better.
+// A tablet without a recent latency sample has no cost, so that it gets probed,
+// unless a query is already in flight to it: it is then avoided until that
+// query tells how the tablet performs.
+func (l *tabletLoad) cost(now time.Time) float64 {
+	if l == nil {
+		return 0
+	}
+	if now.Sub(l.LastSample) > latencyStaleAfter {
+		if l.InFlight > 0 {
+			return math.MaxFloat64
+		}
+		return 0
+	}
+	return float64(l.Latency) * float64(l.InFlight+1)
+}
+
+// Pick implements the TabletBalancer interface.
+//
+// It samples two distinct tablets at random and returns the one with the
+// lowest cost.
+func (b *latencyBalancer) Pick(_ *querypb.Target, tablets []*discovery.TabletHealth) *discovery.TabletHealth {
+	numTablets := len(tablets)
+	if numTablets == 0 {
+		return nil
+	}
+	if numTablets == 1 {
+		return tablets[0]
+	}
+
+	i := rand.IntN(numTablets)
+	j := rand.IntN(numTablets - 1)
+	if j >= i {
+		j++
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	now := b.now()
+	if b.tablets[tabletKey(tablets[j])].cost(now) < b.tablets[tabletKey(tablets[i])].cost(now) {
+		return tablets[j]
+	}
+	return tablets[i]
+}
+
+// QueryStarted implements the TabletBalancer interface.
+func (b *latencyBalancer) QueryStarted(th *discovery.TabletHealth) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	load := b.load(th)
+	load.InFlight++
+	load.Queries++
+}
+
+// QueryFinished implements the TabletBalancer interface.
+func (b *latencyBalancer) QueryFinished(th *discovery.TabletHealth, latency time.Duration, err error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	load := b.load(th)
+	if load.InFlight > 0 {
+		load.InFlight--
+	}
+
+	now := b.now()
+	defer b.pruneStale(now)
+	if err != nil {
+		if !isTabletError(err) {
+			return
+		}
+		latency = max(latency, errorLatency)
+	}
+	if now.Sub(load.LastSample) > latencyStaleAfter {
+		load.Latency = latency
+	} else {
+		load.Latency += time.Duration(ewmaWeight * float64(latency-load.Latency))
+	}
+	load.LastSample = now
+}
+
+// isTabletError returns true if the query failed because of the tablet
+// rather than because of the query itself.
+func isTabletError(err error) bool {
+	switch vterrors.Code(err) {
+	case vtrpcpb.Code_UNAVAILABLE, vtrpcpb.Code_RESOURCE_EXHAUSTED, vtrpcpb.Code_DEADLINE_EXCEEDED, vtrpcpb.Code_FAILED_PRECONDITION:
+		return true
+	}
+	return false
+}
+
+// pruneStale drops the tablets whose estimate is stale and that have no
+// query in flight, at most once per latencyStaleAfter. They cost the same as
+// the tablets never seen, and are likely removed from the topology. It must
+// be called with mu held.
+func (b *latencyBalancer) pruneStale(now time.Time) {
+	if now.Sub(b.lastPrune) < latencyStaleAfter {
+		return
+	}
+	b.lastPrune = now
+	maps.DeleteFunc(b.tablets, func(_ string, load *tabletLoad) bool {
+		return load.InFlight == 0 && now.Sub(load.LastSample) > latencyStaleAfter
+	})
+}
+
+// load returns the load of the tablet, creating it if needed. It must be
+// called with mu held.
+func (b *latencyBalancer) load(th *discovery.TabletHealth) *tabletLoad {
+	key := tabletKey(th)
+	load, ok := b.tablets[key]
+	if !ok {
+		load = &tabletLoad{}
+		b.tablets[key] = load
+	}
+	return load
+}
+
+func tabletKey(th *discovery.TabletHealth) string {
+	return topoproto.TabletAliasString(th.Tablet.Alias)
+}
+
+// DebugHandler implements the TabletBalancer interface.
+func (b *latencyBalancer) DebugHandler(w http.ResponseWriter, _ *http.Request) {
+	w.Header().Set("Content-Type", "text/plain")
+	fmt.Fprintf(w, "Mode: latency\r\n")
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	now := b.now()
+	for _, key := range slices.Sorted(maps.Keys(b.tablets)) {
+		load := b.tablets[key]
+		stale := ""
+		if now.Sub(load.LastSample) > latencyStaleAfter {
+			stale = " (stale)"
+		}
+		fmt.Fprintf(w, "%s: latency %v%s, in flight %d, queries %d\r\n", key, load.Latency, stale, load.InFlight, load.Queries)
+	}
+}
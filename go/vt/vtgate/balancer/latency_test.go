@@ -0,0 +1,192 @@
+/*
+Copyright 2026 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package balancer
+
+import (
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"vitess.io/vitess/go/vt/discovery"
+	"vitess.io/vitess/go/vt/vterrors"
+
+	querypb "vitess.io/vitess/go/vt/proto/query"
+	topodatapb "vitess.io/vitess/go/vt/proto/topodata"
+	vtrpcpb "vitess.io/vitess/go/vt/proto/vtrpc"
+)
+
+func newTestLatencyBalancer(now *time.Time) *latencyBalancer {
+	b := NewLatencyBalancer().(*latencyBalancer)
+	b.now = func() time.Time { return *now }
+	return b
+}
+
+func TestLatencyPick(t *testing.T) {
+	target := &querypb.Target{Keyspace: "k", Shard: "s", TabletType: topodatapb.TabletType_REPLICA}
+	now := time.Now()
+	b := newTestLatencyBalancer(&now)
+
+	assert.Nil(t, b.Pick(target, nil))
+
+	single := []*discovery.TabletHealth{createTestTablet("a")}
+	assert.Equal(t, single[0], b.Pick(target, single))
+
+	fast := createTestTablet("a")
+	slow := createTestTablet("a")
+	tablets := []*discovery.TabletHealth{fast, slow}
+	b.QueryStarted(fast)
+	b.QueryFinished(fast, time.Millisecond, nil)
+	b.QueryStarted(slow)
+	b.QueryFinished(slow, 100*time.Millisecond, nil)
+
+	// with two tablets, both are always sampled and the fast one always wins
+	for range 100 {
+		assert.Equal(t, fast, b.Pick(target, tablets))
+	}
+
+	// the fast tablet is avoided once it is busy enough
+	for range 200 {
+		b.QueryStarted(fast)
+	}
+	assert.Equal(t, slow, b.Pick(target, tablets))
+	for range 200 {
+		b.QueryFinished(fast, time.Millisecond, nil)
+	}
+	assert.Equal(t, fast, b.Pick(target, tablets))
+
+	// once its estimate is stale, the slow tablet is probed again
+	now = now.Add(latencyStaleAfter + time.Second)
+	b.QueryStarted(fast)
+	b.QueryFinished(fast, time.Millisecond, nil)
+	assert.Equal(t, slow, b.Pick(target, tablets))
+
+	// but not while the probe is in flight
+	b.QueryStarted(slow)
+	assert.Equal(t, fast, b.Pick(target, tablets))
+	b.QueryFinished(slow, 2*time.Millisecond, nil)
+	assert.Equal(t, 2*time.Millisecond, b.tablets[tabletKey(slow)].Latency)
+}
+
+func TestLatencyPickSpreadsLoad(t *testing.T) {
+	target := &querypb.Target{Keyspace: "k", Shard: "s", TabletType: topodatapb.TabletType_REPLICA}
+	now := time.Now()
+	b := newTestLatencyBalancer(&now)
+
+	tablets := []*discovery.TabletHealth{
+		createTestTablet("a"),
+		createTestTablet("a"),
+		createTestTablet("a"),
+		createTestTablet("a"),
+	}
+	degraded := tablets[3]
+	for _, th := range tablets {
+		latency := 10 * time.Millisecond
+		if th == degraded {
+			latency = 200 * time.Millisecond
+		}
+		b.QueryStarted(th)
+		b.QueryFinished(th, latency, nil)
+	}
+
+	const numPicks = 10000
+	picks := map[*discovery.TabletHealth]int{}
+	for range numPicks {
+		picks[b.Pick(target, tablets)]++
+	}
+
+	// the degraded tablet always loses against the tablet it is sampled with
+	assert.Zero(t, picks[degraded])
+	for _, th := range tablets[:3] {
+		assert.Greater(t, picks[th], numPicks/6, "tablet %s", tabletKey(th))
+	}
+}
+
+func TestLatencyEWMA(t *testing.T) {
+	now := time.Now()
+	b := newTestLatencyBalancer(&now)
+	th := createTestTablet("a")
+
+	b.QueryStarted(th)
+	b.QueryFinished(th, 100*time.Millisecond, nil)
+	assert.Equal(t, 100*time.Millisecond, b.tablets[tabletKey(th)].Latency)
+
+	b.QueryStarted(th)
+	b.QueryFinished(th, 200*time.Millisecond, nil)
+	assert.Equal(t, 130*time.Millisecond, b.tablets[tabletKey(th)].Latency)
+	assert.Zero(t, b.tablets[tabletKey(th)].InFlight)
+	assert.EqualValues(t, 2, b.tablets[tabletKey(th)].Queries)
+}
+
+func TestLatencyErrors(t *testing.T) {
+	now := time.Now()
+	b := newTestLatencyBalancer(&now)
+	th := createTestTablet("a")
+
+	b.QueryStarted(th)
+	b.QueryFinished(th, 10*time.Millisecond, nil)
+
+	// the latency of a query failing on its own is ignored
+	b.QueryStarted(th)
+	b.QueryFinished(th, time.Millisecond, vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "syntax error"))
+	assert.Equal(t, 10*time.Millisecond, b.tablets[tabletKey(th)].Latency)
+	assert.Zero(t, b.tablets[tabletKey(th)].InFlight)
+
+	// a query failing fast because of the tablet is recorded as a slow one
+	b.QueryStarted(th)
+	b.QueryFinished(th, time.Millisecond, vterrors.Errorf(vtrpcpb.Code_UNAVAILABLE, "tablet is not serving"))
+	assert.Equal(t, 10*time.Millisecond+time.Duration(ewmaWeight*float64(errorLatency-10*time.Millisecond)), b.tablets[tabletKey(th)].Latency)
+}
+
+func TestLatencyPruneStale(t *testing.T) {
+	now := time.Now()
+	b := newTestLatencyBalancer(&now)
+	removed := createTestTablet("a")
+	busy := createTestTablet("a")
+	active := createTestTablet("a")
+
+	b.QueryStarted(removed)
+	b.QueryFinished(removed, time.Millisecond, nil)
+	b.QueryStarted(busy)
+	b.QueryFinished(busy, time.Millisecond, nil)
+	b.QueryStarted(busy)
+	require.Len(t, b.tablets, 2)
+
+	// once their estimate is stale, the tablets without queries in flight are dropped
+	now = now.Add(latencyStaleAfter + time.Second)
+	b.QueryStarted(active)
+	b.QueryFinished(active, time.Millisecond, nil)
+	assert.NotContains(t, b.tablets, tabletKey(removed))
+	assert.Contains(t, b.tablets, tabletKey(busy))
+	assert.Contains(t, b.tablets, tabletKey(active))
+}
+
+func TestLatencyDebugHandler(t *testing.T) {
+	now := time.Now()
+	b := newTestLatencyBalancer(&now)
+	th := createTestTablet("a")
+	b.QueryStarted(th)
+	b.QueryFinished(th, 5*time.Millisecond, nil)
+	b.QueryStarted(th)
+
+	w := httptest.NewRecorder()
+	b.DebugHandler(w, httptest.NewRequest("GET", "/debug/balancer", nil))
+	require.Contains(t, w.Body.String(), "Mode: latency")
+	require.Contains(t, w.Body.String(), tabletKey(th)+": latency 5ms, in flight 1, queries 2")
+}
@@ -796,7 +796,7 @@ func (cached *Plan) CachedSize(alloc bool) int64 {
 	}
 	size := int64(0)
 	if alloc {
-		size += int64(224)
+		size += int64(320)
 	}
 	// field Original string
 	size += hack.RuntimeAllocSize(int64(len(cached.Original)))
@@ -822,6 +822,26 @@ func (cached *Plan) CachedSize(alloc bool) int64 {
 	}
 	// field QueryHints vitess.io/vitess/go/vt/sqlparser.QueryHints
 	size += cached.QueryHints.CachedSize(false)
+	// field Key vitess.io/vitess/go/vt/vtgate/engine.PlanKey
+	size += cached.Key.CachedSize(false)
+	return size
+}
+func (cached *PlanKey) CachedSize(alloc bool) int64 {
+	if cached == nil {
+		return int64(0)
+	}
+	size := int64(0)
+	if alloc {
+		size += int64(80)
+	}
+	// field CurrentKeyspace string
+	size += hack.RuntimeAllocSize(int64(len(cached.CurrentKeyspace)))
+	// field Destination string
+	size += hack.RuntimeAllocSize(int64(len(cached.Destination)))
+	// field Query string
+	size += hack.RuntimeAllocSize(int64(len(cached.Query)))
+	// field SetVarComment string
+	size += hack.RuntimeAllocSize(int64(len(cached.SetVarComment)))
 	return size
 }
 func (cached *PlanSwitcher) CachedSize(alloc bool) int64 {
@@ -1217,6 +1237,16 @@ func (cached *ShowExec) CachedSize(alloc bool) int64 {
 	size += cached.ShowFilter.CachedSize(true)
 	return size
 }
+func (cached *ShowProcesslist) CachedSize(alloc bool) int64 {
+	if cached == nil {
+		return int64(0)
+	}
+	size := int64(0)
+	if alloc {
+		size += int64(8)
+	}
+	return size
+}
 func (cached *SimpleProjection) CachedSize(alloc bool) int64 {
 	if cached == nil {
 		return int64(0)
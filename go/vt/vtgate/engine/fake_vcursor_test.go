@@ -148,6 +148,14 @@ func (t *noopVCursor) ThrottleApp(ctx context.Context, throttleAppRule *topodata
 	panic("implement me")
 }
 
+func (t *noopVCursor) ResetQueryStats() {
+	panic("implement me")
+}
+
+func (t *noopVCursor) ShowProcesslist(ctx context.Context, full, shards bool) (*sqltypes.Result, error) {
+	panic("implement me")
+}
+
 func (t *noopVCursor) ShowExec(ctx context.Context, command sqlparser.ShowCommandType, filter *sqlparser.ShowFilter) (*sqltypes.Result, error) {
 	panic("implement me")
 }
@@ -321,6 +329,9 @@ func (t *noopVCursor) SetClientFoundRows(context.Context, bool) error {
 func (t *noopVCursor) SetQueryTimeout(maxExecutionTime int64) {
 }
 
+func (t *noopVCursor) SetMaxReplicaLag(maxReplicaLag int64) {
+}
+
 func (t *noopVCursor) SetSkipQueryPlanCache(context.Context, bool) error {
 	panic("implement me")
 }
@@ -49,7 +49,9 @@ type (
 		TablesUsed   []string                // TablesUsed enumerates the tables this query accesses.
 		QueryHints   sqlparser.QueryHints    // QueryHints stores any SET_VAR hints that influenced plan generation.
 		ParamsCount  uint16                  // ParamsCount is the total number of bind parameters (?) in the query.
+		Prepared     bool                    // Prepared is true if the plan was built for a prepared statement.
 		Optimized    atomic.Bool             // Prepared queries need to be optimized before the first execution
+		Key          PlanKey                 // Key is the plan cache key of the plan, to replan it after a restart.
 
 		ExecCount    uint64 // ExecCount is how many times this plan has been executed.
 		ExecTime     uint64 // ExecTime is the total accumulated execution time in nanoseconds.
@@ -174,6 +176,8 @@ func getPlanType(p Primitive) PlanType {
 			return PlanTopoOp
 		}
 		return PlanLocal
+	case *FlushQueryStats, *ShowProcesslist:
+		return PlanLocal
 	case *Set:
 		return getPlanTypeForSet(prim)
 	case *VExplain:
@@ -232,7 +236,7 @@ func getPlanTypeFromRoutingParams(rp *RoutingParameters) PlanType {
 			return PlanLookup
 		}
 		return PlanPassthrough
-	case Equal, IN, Between, MultiEqual, SubShard, ByDestination:
+	case Equal, IN, Between, MultiEqual, SubShard, Range, ByDestination:
 		if rp.Vindex != nil && rp.Vindex.NeedsVCursor() {
 			return PlanLookup
 		}
@@ -22,6 +22,7 @@ import (
 
 	"vitess.io/vitess/go/mysql/collations"
 	"vitess.io/vitess/go/sqltypes"
+	"vitess.io/vitess/go/trace"
 	"vitess.io/vitess/go/vt/key"
 	"vitess.io/vitess/go/vt/sqlparser"
 	"vitess.io/vitess/go/vt/srvtopo"
@@ -116,6 +117,11 @@ type (
 		SetExec(ctx context.Context, name string, value string) error
 		// ThrottleApp sets a ThrottlerappRule in topo
 		ThrottleApp(ctx context.Context, throttleAppRule *topodatapb.ThrottledAppRule) error
+		// ResetQueryStats drops the per-digest query statistics of the executor.
+		ResetQueryStats()
+		// ShowProcesslist lists the MySQL connections of vtgate. If shards is set, it lists their
+		// shard sessions with the queries they are running on the vttablets.
+		ShowProcesslist(ctx context.Context, full, shards bool) (*sqltypes.Result, error)
 
 		// CanUseSetVar returns true if system_settings can use SET_VAR hint.
 		CanUseSetVar() bool
@@ -231,6 +237,10 @@ type (
 		// SetQueryTimeout sets the query timeout
 		SetQueryTimeout(queryTimeout int64)
 
+		// SetMaxReplicaLag sets the maximum replication lag, in seconds, of
+		// the replicas the session reads from
+		SetMaxReplicaLag(maxReplicaLag int64)
+
 		// InTransaction returns true if the session has already opened transaction or
 		// will start a transaction on the query execution.
 		InTransaction() bool
@@ -274,6 +284,23 @@ type (
 	noFields struct{}
 )
 
+// NewPrimitiveSpan starts a span for the execution of the primitive. Only
+// traced queries get a span, describing the primitive is not free.
+func NewPrimitiveSpan(ctx context.Context, primitive Primitive) (trace.Span, context.Context) {
+	if _, ok := trace.FromContext(ctx); !ok {
+		return trace.NoopSpan{}, ctx
+	}
+	desc := primitive.description()
+	span, ctx := trace.NewSpan(ctx, "Primitive."+desc.OperatorType)
+	if desc.Variant != "" {
+		span.Annotate("variant", desc.Variant)
+	}
+	if desc.Keyspace != nil {
+		span.Annotate("keyspace", desc.Keyspace.Name)
+	}
+	return span, ctx
+}
+
 // Find will return the first Primitive that matches the evaluate function. If no match is found, nil will be returned
 func Find(isMatch Match, start Primitive) Primitive {
 	if isMatch(start) {
@@ -707,6 +707,50 @@ func TestSelectLike(t *testing.T) {
 
 }
 
+func TestSelectRange(t *testing.T) {
+	vindex, _ := vindexes.CreateVindex("numeric", "", nil)
+	sel := NewRoute(
+		Range,
+		&vindexes.Keyspace{
+			Name:    "ks",
+			Sharded: true,
+		},
+		"dummy_select",
+		"dummy_select_field",
+	)
+	sel.Vindex = vindex
+	sel.Values = []evalengine.Expr{
+		evalengine.NewLiteralInt(16),
+		evalengine.NullExpr,
+	}
+	vc := &loggingVCursor{
+		shards:       []string{"-20", "20-"},
+		shardForKsid: []string{"-20", "20-"},
+		results:      []*sqltypes.Result{defaultSelectResult},
+	}
+	result, err := sel.TryExecute(context.Background(), vc, map[string]*querypb.BindVariable{}, false)
+	require.NoError(t, err)
+	vc.ExpectLog(t, []string{
+		`ResolveDestinations ks [] Destinations:DestinationKeyRange(0000000000000010-)`,
+		`ExecuteMultiShard ks.-20: dummy_select {} ks.20-: dummy_select {} false false`,
+	})
+	expectResult(t, result, defaultSelectResult)
+
+	vc.Rewind()
+	sel.Values = []evalengine.Expr{
+		evalengine.NewLiteralInt(16),
+		evalengine.NewLiteralInt(31),
+	}
+	vc.shardForKsid = []string{"-20"}
+	result, err = wrapStreamExecute(sel, vc, map[string]*querypb.BindVariable{}, false)
+	require.NoError(t, err)
+	vc.ExpectLog(t, []string{
+		`ResolveDestinations ks [] Destinations:DestinationKeyRange(0000000000000010-0000000000000020)`,
+		`StreamExecuteMulti dummy_select ks.-20: {} `,
+	})
+	expectResult(t, result, defaultSelectResult)
+}
+
 func TestSelectNext(t *testing.T) {
 	sel := NewRoute(
 		Next,
@@ -59,6 +59,10 @@ const (
 	MultiEqual
 	// SubShard is for when we are missing one or more columns from a composite vindex
 	SubShard
+	// Range is for routing a statement to the shards overlapping a key range.
+	// Requires: A RangeVindex, and start and end Value. A NULL Value leaves
+	// that side of the range unbounded.
+	Range
 	// Scatter is for routing a scattered statement.
 	Scatter
 	// Next is for fetching from a sequence.
@@ -90,6 +94,7 @@ var opName = map[Opcode]string{
 	None:          "None",
 	ByDestination: "ByDestination",
 	SubShard:      "SubShard",
+	Range:         "Range",
 }
 
 // MarshalJSON serializes the Opcode as a JSON string.
@@ -169,6 +174,14 @@ func (rp *RoutingParameters) findRoute(ctx context.Context, vcursor VCursor, bin
 			// Only SingleColumn vindex supported.
 			return nil, nil, vterrors.VT13001("between supported on SingleColumn vindex only")
 		}
+	case Range:
+		switch rp.Vindex.(type) {
+		case vindexes.RangeVindex:
+			return rp.keyRange(ctx, vcursor, bindVars)
+		default:
+			// Only RangeVindex supported.
+			return nil, nil, vterrors.VT13001("range supported on RangeVindex only")
+		}
 	case MultiEqual:
 		switch rp.Vindex.(type) {
 		case vindexes.MultiColumn:
@@ -421,6 +434,23 @@ func (rp *RoutingParameters) between(ctx context.Context, vcursor VCursor, bindV
 	return rss, shardVars(bindVars, values), nil
 }
 
+func (rp *RoutingParameters) keyRange(ctx context.Context, vcursor VCursor, bindVars map[string]*querypb.BindVariable) ([]*srvtopo.ResolvedShard, []map[string]*querypb.BindVariable, error) {
+	env := evalengine.NewExpressionEnv(ctx, bindVars, vcursor)
+	start, err := env.Evaluate(rp.Values[0])
+	if err != nil {
+		return nil, nil, err
+	}
+	end, err := env.Evaluate(rp.Values[1])
+	if err != nil {
+		return nil, nil, err
+	}
+	kr, err := rp.Vindex.(vindexes.RangeVindex).MapRange(ctx, vcursor, start.Value(vcursor.ConnCollation()), end.Value(vcursor.ConnCollation()))
+	if err != nil {
+		return nil, nil, err
+	}
+	return rp.byDestination(ctx, vcursor, bindVars, key.DestinationKeyRange{KeyRange: kr})
+}
+
 func (rp *RoutingParameters) multiEqual(ctx context.Context, vcursor VCursor, bindVars map[string]*querypb.BindVariable) ([]*srvtopo.ResolvedShard, []map[string]*querypb.BindVariable, error) {
 	env := evalengine.NewExpressionEnv(ctx, bindVars, vcursor)
 	value, err := env.Evaluate(rp.Values[0])
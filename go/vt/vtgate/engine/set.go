@@ -476,6 +476,15 @@ func (svss *SysVarSetAware) Execute(ctx context.Context, vcursor VCursor, env *e
 			return err
 		}
 		vcursor.Session().SetQueryTimeout(queryTimeout)
+	case sysvars.MaxReplicaLag.Name:
+		maxReplicaLag, err := svss.evalAsInt64(env, vcursor)
+		if err != nil {
+			return err
+		}
+		if maxReplicaLag < 0 {
+			return vterrors.NewErrorf(vtrpcpb.Code_INVALID_ARGUMENT, vterrors.WrongValueForVar, "variable 'max_replica_lag' can't be set to the value of '%d'", maxReplicaLag)
+		}
+		vcursor.Session().SetMaxReplicaLag(maxReplicaLag)
 	case sysvars.SessionEnableSystemSettings.Name:
 		err = svss.setBoolSysVar(ctx, env, vcursor.Session().SetSessionEnableSystemSettings)
 	case sysvars.Charset.Name, sysvars.Names.Name:
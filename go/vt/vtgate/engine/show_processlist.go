@@ -0,0 +1,79 @@
+/*
+Copyright 2026 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package engine
+
+import (
+	"context"
+
+	"vitess.io/vitess/go/sqltypes"
+	querypb "vitess.io/vitess/go/vt/proto/query"
+)
+
+var _ Primitive = (*ShowProcesslist)(nil)
+
+// ShowProcesslist lists the MySQL connections of vtgate, for SHOW [FULL]
+// PROCESSLIST and SHOW VITESS_PROCESSLIST.
+type ShowProcesslist struct {
+	noInputs
+	noTxNeeded
+
+	// Full lists the queries without truncating them.
+	Full bool
+	// Shards lists the shard sessions of the connections, with the queries
+	// they are running on the vttablets.
+	Shards bool
+}
+
+func (s *ShowProcesslist) description() PrimitiveDescription {
+	other := map[string]any{}
+	if s.Full {
+		other["Full"] = true
+	}
+	variant := ""
+	if s.Shards {
+		variant = "Shards"
+	}
+	return PrimitiveDescription{
+		OperatorType: "ShowProcesslist",
+		Variant:      variant,
+		Other:        other,
+	}
+}
+
+// TryExecute implements the Primitive interface
+func (s *ShowProcesslist) TryExecute(ctx context.Context, vcursor VCursor, bindVars map[string]*querypb.BindVariable, wantfields bool) (*sqltypes.Result, error) {
+	return vcursor.ShowProcesslist(ctx, s.Full, s.Shards)
+}
+
+// TryStreamExecute implements the Primitive interface
+func (s *ShowProcesslist) TryStreamExecute(ctx context.Context, vcursor VCursor, bindVars map[string]*querypb.BindVariable, wantfields bool, callback func(*sqltypes.Result) error) error {
+	qr, err := s.TryExecute(ctx, vcursor, bindVars, wantfields)
+	if err != nil {
+		return err
+	}
+	return callback(qr)
+}
+
+// GetFields implements the Primitive interface
+func (s *ShowProcesslist) GetFields(ctx context.Context, vcursor VCursor, bindVars map[string]*querypb.BindVariable) (*sqltypes.Result, error) {
+	qr, err := s.TryExecute(ctx, vcursor, bindVars, true)
+	if err != nil {
+		return nil, err
+	}
+	qr.Rows = nil
+	return qr, nil
+}
@@ -23,6 +23,7 @@ import (
 
 	"github.com/stretchr/testify/require"
 
+	"vitess.io/vitess/go/mysql/collations"
 	"vitess.io/vitess/go/vt/vtgate/evalengine"
 
 	"vitess.io/vitess/go/sqltypes"
@@ -267,6 +268,25 @@ func TestFieldOrder(t *testing.T) {
 	}
 }
 
+func TestVindexFuncUUID(t *testing.T) {
+	vindex, err := vindexes.CreateVindex("uuid_ordered", "uuid_ordered", nil)
+	require.NoError(t, err)
+	vf := &VindexFunc{
+		Fields: sqltypes.MakeTestFields("id|hex_keyspace_id", "varbinary|varbinary"),
+		Cols:   []int{0, 4},
+		Opcode: VindexMap,
+		Vindex: vindex.(vindexes.SingleColumn),
+		Value:  evalengine.NewLiteralString([]byte("01JCP0F86PFCY9YBJABDP7V3MZ"), collations.SystemCollation),
+	}
+	got, err := vf.TryExecute(context.Background(), &noopVCursor{}, nil, false)
+	require.NoError(t, err)
+	want := sqltypes.MakeTestResult(
+		vf.Fields,
+		"01JCP0F86PFCY9YBJABDP7V3MZ|01932c07a0d67b3c9f2e4a5b6c7d8e9f",
+	)
+	require.Equal(t, want, got)
+}
+
 func testVindexFunc(v vindexes.SingleColumn) *VindexFunc {
 	return &VindexFunc{
 		Fields: sqltypes.MakeTestFields("id|keyspace_id|hex(keyspace_id)|range_start|range_end", "varbinary|varbinary|varbinary|varbinary|varbinary"),
@@ -23,7 +23,9 @@ import (
 	"fmt"
 	"io"
 	"net/http"
+	"regexp"
 	"sort"
+	"strconv"
 	"strings"
 	"sync"
 	"sync/atomic"
@@ -41,6 +43,7 @@ import (
 	"vitess.io/vitess/go/streamlog"
 	"vitess.io/vitess/go/trace"
 	"vitess.io/vitess/go/vt/callerid"
+	"vitess.io/vitess/go/vt/concurrency"
 	"vitess.io/vitess/go/vt/key"
 	"vitess.io/vitess/go/vt/log"
 	"vitess.io/vitess/go/vt/logutil"
@@ -64,6 +67,8 @@ import (
 	"vitess.io/vitess/go/vt/vtgate/logstats"
 	"vitess.io/vitess/go/vt/vtgate/planbuilder"
 	"vitess.io/vitess/go/vt/vtgate/planbuilder/plancontext"
+	"vitess.io/vitess/go/vt/vtgate/processlist"
+	"vitess.io/vitess/go/vt/vtgate/querystats"
 	"vitess.io/vitess/go/vt/vtgate/vindexes"
 	"vitess.io/vitess/go/vt/vtgate/vschemaacl"
 	"vitess.io/vitess/go/vt/vtgate/vtgateservice"
@@ -113,6 +118,9 @@ type (
 		AllowScatter        bool
 		WarmingReadsPercent int
 		QueryLogToFile      string
+		// QueryStatsMaxDigests is the number of normalized queries to keep
+		// statistics for in SHOW VITESS_QUERY_STATS. 0 disables them.
+		QueryStatsMaxDigests int
 	}
 
 	Executor struct {
@@ -130,6 +138,7 @@ type (
 		mu           sync.Mutex
 		vschema      *vindexes.VSchema
 		vschemaStats *VSchemaStats
+		processList  processlist.Lister
 
 		plans *PlanCache
 		epoch atomic.Uint32
@@ -140,6 +149,9 @@ type (
 		// queryLogger is passed in for logging from this vtgate executor.
 		queryLogger *streamlog.StreamLogger[*logstats.LogStats]
 
+		// queryStats aggregates the executions per normalized query.
+		queryStats *querystats.Stats
+
 		warmingReadsChannel chan bool
 
 		vConfig   econtext.VCursorConfig
@@ -194,6 +206,7 @@ func NewExecutor(
 		plans:               plans,
 		warmingReadsChannel: make(chan bool, warmingReadsConcurrency),
 		ddlConfig:           ddlConfig,
+		queryStats:          querystats.New(eConfig.QueryStatsMaxDigests),
 	}
 	// setting the vcursor config.
 	e.initVConfig(warnOnShardedOnly, pv)
@@ -203,11 +216,12 @@ func NewExecutor(
 
 	// we subscribe to update from the VSchemaManager
 	e.vm = &VSchemaManager{
-		subscriber: e.SaveVSchema,
-		serv:       serv,
-		cell:       cell,
-		schema:     e.schemaTracker,
-		parser:     env.Parser(),
+		subscriber:  e.SaveVSchema,
+		serv:        serv,
+		cell:        cell,
+		schema:      e.schemaTracker,
+		parser:      env.Parser(),
+		directories: newTenantDirectories(ctx, serv),
 	}
 	serv.WatchSrvVSchema(ctx, cell, e.vm.VSchemaUpdate)
 
@@ -253,6 +267,7 @@ func (e *Executor) Execute(
 	defer span.Finish()
 
 	logStats := logstats.NewLogStats(ctx, method, sql, safeSession.GetSessionUUID(), bindVars, streamlog.GetQueryLogConfig())
+	logStats.QueryAttributes = econtext.QueryAttributesFromContext(ctx)
 	stmtType, result, err := e.execute(ctx, mysqlCtx, safeSession, sql, bindVars, prepared, logStats)
 	logStats.Error = err
 	if result == nil {
@@ -316,10 +331,13 @@ func (e *Executor) StreamExecute(
 	defer span.Finish()
 
 	logStats := logstats.NewLogStats(ctx, method, sql, safeSession.GetSessionUUID(), bindVars, streamlog.GetQueryLogConfig())
+	logStats.QueryAttributes = econtext.QueryAttributesFromContext(ctx)
 	srr := &streaminResultReceiver{callback: callback}
 	var err error
+	var executedPlan *engine.Plan
 
 	resultHandler := func(ctx context.Context, plan *engine.Plan, vc *econtext.VCursorImpl, bindVars map[string]*querypb.BindVariable, execStart time.Time) error {
+		executedPlan = plan
 		var seenResults atomic.Bool
 		var resultMu sync.Mutex
 		result := &sqltypes.Result{}
@@ -400,6 +418,9 @@ func (e *Executor) StreamExecute(
 	err = e.newExecute(ctx, mysqlCtx, safeSession, sql, bindVars, false, logStats, resultHandler, srr.storeResultStats)
 
 	logStats.Error = err
+	if executedPlan != nil {
+		e.recordQueryStats(executedPlan, logStats, uint64(srr.rowsReturned), srr.rowsAffected, err)
+	}
 	saveSessionStats(safeSession, srr.stmtType, srr.rowsAffected, srr.rowsReturned, err)
 	if srr.rowsReturned > warnMemoryRows {
 		warnings.Add("ResultsExceeded", 1)
@@ -488,6 +509,8 @@ func (e *Executor) addNeededBindVars(vcursor *econtext.VCursorImpl, bindVarNeeds
 			bindVars[key] = sqltypes.BoolBindVariable(session.Autocommit)
 		case sysvars.QueryTimeout.Name:
 			bindVars[key] = sqltypes.Int64BindVariable(session.GetQueryTimeout())
+		case sysvars.MaxReplicaLag.Name:
+			bindVars[key] = sqltypes.Int64BindVariable(session.GetMaxReplicaLag())
 		case sysvars.ClientFoundRows.Name:
 			var v bool
 			ifOptionsExist(session, func(options *querypb.ExecuteOptions) {
@@ -1116,6 +1139,15 @@ func (e *Executor) fetchOrCreatePlan(
 		return nil, nil, nil, vterrors.VT13001("vschema not initialized")
 	}
 
+	span, ctx := trace.NewSpan(ctx, "executor.fetchOrCreatePlan")
+	defer func() {
+		span.Annotate("cached_plan", logStats.CachedPlan)
+		if plan != nil {
+			span.Annotate("plan_type", plan.Type.String())
+		}
+		span.Finish()
+	}()
+
 	query, comments := sqlparser.SplitMarginComments(queryString)
 	vcursor, _ = e.newVCursor(safeSession, comments, logStats)
 
@@ -1131,7 +1163,7 @@ func (e *Executor) fetchOrCreatePlan(
 	}
 
 	if plan == nil {
-		plan, logStats.CachedPlan, stmt, err = e.getCachedOrBuildPlan(ctx, vcursor, query, bindVars, setVarComment, parameterize, planKey, false)
+		plan, logStats.CachedPlan, stmt, err = e.getCachedOrBuildPlan(ctx, vcursor, query, bindVars, setVarComment, parameterize, preparedPlan, planKey, false)
 		if err != nil && preparedPlan && isExecutePath {
 			// The baseline plan failed to build, try to build an optimized plan
 			plan, err = e.tryOptimizedPlan(ctx, vcursor, bindVars, query, setVarComment, parameterize, planKey, plan, err)
@@ -1143,7 +1175,7 @@ func (e *Executor) fetchOrCreatePlan(
 
 	if shouldOptimizePlan(preparedPlan, isExecutePath, plan) {
 		vcursor.SetBindVars(bindVars)
-		optimizedPlan, _, _, err := e.getCachedOrBuildPlan(ctx, vcursor, query, bindVars, setVarComment, parameterize, planKey, true)
+		optimizedPlan, _, _, err := e.getCachedOrBuildPlan(ctx, vcursor, query, bindVars, setVarComment, parameterize, preparedPlan, planKey, true)
 		if err == nil {
 			if sp, ok := optimizedPlan.Instructions.(*engine.PlanSwitcher); ok {
 				sp.Baseline = plan.Instructions
@@ -1156,6 +1188,9 @@ func (e *Executor) fetchOrCreatePlan(
 
 	// Apply query hints
 	e.applyQueryHints(vcursor, plan)
+	if err := e.applyQueryAttributes(ctx, vcursor, plan); err != nil {
+		return nil, nil, nil, err
+	}
 
 	logStats.SQL = comments.Leading + plan.Original + comments.Trailing
 	logStats.BindVariables = sqltypes.CopyBindVariables(bindVars)
@@ -1179,7 +1214,7 @@ func (e *Executor) tryOptimizedPlan(
 	prevErr error,
 ) (*engine.Plan, error) {
 	vcursor.SetBindVars(bindVars)
-	sPlan, _, _, err := e.getCachedOrBuildPlan(ctx, vcursor, baseQuery, bindVars, setVarComment, parameterize, planKey, true)
+	sPlan, _, _, err := e.getCachedOrBuildPlan(ctx, vcursor, baseQuery, bindVars, setVarComment, parameterize, true, planKey, true)
 	if err == nil {
 		if sp, ok := sPlan.Instructions.(*engine.PlanSwitcher); ok {
 			sp.BaselineErr = prevErr
@@ -1212,6 +1247,28 @@ func (e *Executor) applyQueryHints(vcursor *econtext.VCursorImpl, plan *engine.P
 	vcursor.SetWorkloadName(qh.Workload)
 	vcursor.SetPriority(qh.Priority)
 	vcursor.SetExecQueryTimeout(qh.Timeout)
+	vcursor.SetExecMaxReplicaLag(qh.MaxReplicaLag)
+}
+
+// applyQueryAttributes applies the WORKLOAD_NAME and PRIORITY query
+// attributes sent by the client, unless the query sets them with a
+// directive.
+func (e *Executor) applyQueryAttributes(ctx context.Context, vcursor *econtext.VCursorImpl, plan *engine.Plan) error {
+	attributes := econtext.QueryAttributesFromContext(ctx)
+	if len(attributes) == 0 {
+		return nil
+	}
+	if workload, ok := econtext.QueryAttribute(attributes, sqlparser.DirectiveWorkloadName); ok && plan.QueryHints.Workload == "" {
+		vcursor.SetWorkloadName(workload)
+	}
+	if priority, ok := econtext.QueryAttribute(attributes, sqlparser.DirectivePriority); ok && plan.QueryHints.Priority == "" {
+		intPriority, err := strconv.Atoi(priority)
+		if err != nil || intPriority < 0 || intPriority > sqlparser.MaxPriorityValue {
+			return sqlparser.ErrInvalidPriority
+		}
+		vcursor.SetPriority(priority)
+	}
+	return nil
 }
 
 func (e *Executor) getCachedOrBuildPlan(
@@ -1221,6 +1278,7 @@ func (e *Executor) getCachedOrBuildPlan(
 	bindVars map[string]*querypb.BindVariable,
 	setVarComment string,
 	parameterize bool,
+	preparedPlan bool,
 	planKey engine.PlanKey,
 	ignoreCache bool,
 ) (plan *engine.Plan, cached bool, stmt sqlparser.Statement, err error) {
@@ -1246,7 +1304,6 @@ func (e *Executor) getCachedOrBuildPlan(
 	vcursor.SetForeignKeyCheckState(qh.ForeignKeyChecks)
 
 	paramsCount := uint16(0)
-	preparedPlan := planKey.Query != ""
 	if preparedPlan {
 		// We need to count the number of arguments in the statement before we plan the query.
 		// Planning could add additional arguments to the statement.
@@ -1279,12 +1336,17 @@ func (e *Executor) getCachedOrBuildPlan(
 
 	planCachable := sqlparser.CachePlan(stmt) && vcursor.CachePlan()
 	if planCachable && !ignoreCache {
-		if !preparedPlan {
+		if planKey.Query == "" {
 			// build Plan key
 			planKey = buildPlanKey(ctx, vcursor, query, setVarComment)
 		}
 		plan, cached, err = e.plans.GetOrLoad(planKey.Hash(), e.epoch.Load(), func() (*engine.Plan, error) {
-			return e.buildStatement(ctx, vcursor, query, stmt, reservedVars, bindVarNeeds, qh, paramsCount)
+			plan, err := e.buildStatement(ctx, vcursor, query, stmt, reservedVars, bindVarNeeds, qh, paramsCount)
+			if err == nil {
+				plan.Key = planKey
+				plan.Prepared = preparedPlan
+			}
+			return plan, err
 		})
 		return plan, cached, stmt, err
 	}
@@ -1418,6 +1480,147 @@ func (e *Executor) updateQueryStats(queryType, planType, tabletType string, shar
 	}
 }
 
+// recordQueryStats adds an execution of the plan to the statistics of its
+// normalized query.
+func (e *Executor) recordQueryStats(plan *engine.Plan, logStats *logstats.LogStats, rowsReturned, rowsAffected uint64, err error) {
+	e.queryStats.Record(&querystats.Execution{
+		Keyspace:     plan.Key.CurrentKeyspace,
+		Query:        plan.Original,
+		PlanType:     plan.Type.String(),
+		Latency:      time.Since(logStats.StartTime),
+		RowsReturned: rowsReturned,
+		RowsAffected: rowsAffected,
+		ShardQueries: logStats.ShardQueries,
+		Failed:       err != nil,
+	})
+}
+
+// ShowQueryStats returns the statistics of the normalized queries, for
+// SHOW VITESS_QUERY_STATS.
+func (e *Executor) ShowQueryStats(filter *sqlparser.ShowFilter) (*sqltypes.Result, error) {
+	var queryRegexp *regexp.Regexp
+	if filter != nil && filter.Like != "" {
+		queryRegexp = sqlparser.LikeToRegexp(filter.Like)
+	}
+
+	rows := [][]sqltypes.Value{}
+	for _, digest := range e.queryStats.Digests() {
+		if queryRegexp != nil && !queryRegexp.MatchString(digest.Query) {
+			continue
+		}
+		rows = append(rows, digest.Row())
+	}
+	return &sqltypes.Result{
+		Fields: querystats.Fields(),
+		Rows:   rows,
+	}, nil
+}
+
+// ResetQueryStats drops the statistics of the normalized queries, for
+// FLUSH VITESS_QUERY_STATS.
+func (e *Executor) ResetQueryStats() {
+	e.queryStats.Reset()
+}
+
+// SetProcessList sets the MySQL connections listed by SHOW PROCESSLIST.
+func (e *Executor) SetProcessList(processList processlist.Lister) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.processList = processList
+}
+
+// processes returns the MySQL connections of vtgate the caller can list:
+// all of them for the users of --processlist-authorized-users, only the ones
+// of its MySQL user otherwise.
+func (e *Executor) processes(ctx context.Context) []*processlist.Process {
+	e.mu.Lock()
+	processList := e.processList
+	e.mu.Unlock()
+	if processList == nil {
+		return nil
+	}
+	processes := processList.Processes()
+	if processlist.Authorized(callerid.ImmediateCallerIDFromContext(ctx), processlistAuthorizedUsers) {
+		return processes
+	}
+	return processlist.OfUser(processes, callerid.EffectiveCallerIDFromContext(ctx).GetPrincipal())
+}
+
+// ShowProcesslist lists the MySQL connections of vtgate, for SHOW [FULL]
+// PROCESSLIST.
+func (e *Executor) ShowProcesslist(ctx context.Context, full bool) (*sqltypes.Result, error) {
+	return &sqltypes.Result{
+		Fields: processlist.Fields(),
+		Rows:   processlist.Rows(e.processes(ctx), full),
+	}, nil
+}
+
+// ShowVitessProcesslist lists the shard sessions of the MySQL connections of
+// vtgate, for SHOW VITESS_PROCESSLIST. The queries they are running are
+// fetched from the vttablets the shard sessions are on.
+func (e *Executor) ShowVitessProcesslist(ctx context.Context) (*sqltypes.Result, error) {
+	processes := e.processes(ctx)
+
+	type tablet struct {
+		alias  *topodatapb.TabletAlias
+		target *querypb.Target
+	}
+	tablets := make(map[string]tablet)
+	for _, p := range processes {
+		for _, ss := range p.ShardSessions {
+			if ss.TabletAlias == nil {
+				continue
+			}
+			tablets[topoproto.TabletAliasString(ss.TabletAlias)] = tablet{alias: ss.TabletAlias, target: ss.GetTarget()}
+		}
+	}
+
+	var (
+		mu           sync.Mutex
+		wg           sync.WaitGroup
+		allErrors    concurrency.AllErrorRecorder
+		shardQueries = make(map[processlist.ShardQueryKey]processlist.ShardQuery)
+	)
+	for aliasStr, tablet := range tablets {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			qs, err := e.scatterConn.gateway.QueryServiceByAlias(ctx, tablet.alias, tablet.target)
+			if err != nil {
+				allErrors.RecordError(err)
+				return
+			}
+			qr, err := qs.Execute(ctx, tablet.target, "show vitess_processlist", nil, 0, 0, nil)
+			if err != nil {
+				allErrors.RecordError(err)
+				return
+			}
+			mu.Lock()
+			defer mu.Unlock()
+			for _, row := range qr.Named().Rows {
+				id := row.AsInt64("stateful_id", 0)
+				if id == 0 {
+					continue
+				}
+				key := processlist.ShardQueryKey{TabletAlias: aliasStr, ID: id}
+				shardQueries[key] = processlist.ShardQuery{
+					Query: row.AsString("query", ""),
+					Time:  time.Duration(row.AsInt64("time", 0)) * time.Second,
+				}
+			}
+		}()
+	}
+	wg.Wait()
+	if err := allErrors.AggrError(vterrors.Aggregate); err != nil {
+		return nil, err
+	}
+
+	return &sqltypes.Result{
+		Fields: processlist.VitessFields(),
+		Rows:   processlist.VitessRows(processes, shardQueries),
+	}, nil
+}
+
 // VSchemaStats returns the loaded vschema stats.
 func (e *Executor) VSchemaStats() *VSchemaStats {
 	e.mu.Lock()
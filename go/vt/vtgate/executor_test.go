@@ -57,9 +57,11 @@ import (
 	vtrpcpb "vitess.io/vitess/go/vt/proto/vtrpc"
 	"vitess.io/vitess/go/vt/sqlparser"
 	"vitess.io/vitess/go/vt/topo"
+	"vitess.io/vitess/go/vt/topo/topoproto"
 	"vitess.io/vitess/go/vt/vtgate/buffer"
 	"vitess.io/vitess/go/vt/vtgate/engine"
 	"vitess.io/vitess/go/vt/vtgate/logstats"
+	"vitess.io/vitess/go/vt/vtgate/processlist"
 	"vitess.io/vitess/go/vt/vtgate/vindexes"
 	"vitess.io/vitess/go/vt/vtgate/vschemaacl"
 	"vitess.io/vitess/go/vt/vtgate/vtgateservice"
@@ -1796,12 +1798,14 @@ func TestGetPlanNormalized(t *testing.T) {
 
 	var logStats5 *logstats.LogStats
 	plan3, logStats5 = getPlanCached(t, ctx, r, unshardedvc.SafeSession, query1, makeComments(" /* comment 5 */"), map[string]*querypb.BindVariable{}, false)
-	assert.Equal(t, plan1, plan3)
+	// The plan is the same, but cached under the key of the keyspace.
+	assert.Equal(t, plan1.Instructions, plan3.Instructions)
+	assert.Equal(t, KsTestUnsharded, plan3.Key.CurrentKeyspace)
 	wantSQL = normalized + " /* comment 5 */"
 	assert.Equal(t, wantSQL, logStats5.SQL)
 
 	plan4, _ := getPlanCached(t, ctx, r, unshardedvc.SafeSession, query1, makeComments(" /* comment 6 */"), map[string]*querypb.BindVariable{}, false)
-	assert.Equal(t, plan1, plan4)
+	assert.Same(t, plan3, plan4)
 	assertCacheContains(t, r, emptyvc, normalized)
 	assertCacheContains(t, r, unshardedvc, normalized)
 }
@@ -1842,6 +1846,58 @@ func TestGetPlanPriority(t *testing.T) {
 
 }
 
+func TestGetPlanQueryAttributes(t *testing.T) {
+	testCases := []struct {
+		name             string
+		sql              string
+		attributes       map[string]*querypb.BindVariable
+		expectedWorkload string
+		expectedPriority string
+		expectedError    error
+	}{{
+		name: "attributes",
+		sql:  "select * from music_user_map",
+		attributes: map[string]*querypb.BindVariable{
+			"workload_name": sqltypes.StringBindVariable("reporting"),
+			"PRIORITY":      sqltypes.StringBindVariable("10"),
+		},
+		expectedWorkload: "reporting",
+		expectedPriority: "10",
+	}, {
+		name: "directives take precedence",
+		sql:  "select /*vt+ WORKLOAD_NAME=batch PRIORITY=20 */ * from music_user_map",
+		attributes: map[string]*querypb.BindVariable{
+			"WORKLOAD_NAME": sqltypes.StringBindVariable("reporting"),
+			"PRIORITY":      sqltypes.StringBindVariable("10"),
+		},
+		expectedWorkload: "batch",
+		expectedPriority: "20",
+	}, {
+		name:          "invalid priority",
+		sql:           "select * from music_user_map",
+		attributes:    map[string]*querypb.BindVariable{"PRIORITY": sqltypes.Int64BindVariable(1000)},
+		expectedError: sqlparser.ErrInvalidPriority,
+	}}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			r, _, _, _, ctx := createExecutorEnvWithConfig(t, createExecutorConfigWithNormalizer())
+			session := econtext.NewSafeSession(&vtgatepb.Session{TargetString: "@unknown", Options: &querypb.ExecuteOptions{}})
+			logStats := logstats.NewLogStats(ctx, "Test", "", "", nil, streamlog.NewQueryLogConfigForTest())
+
+			ctx = econtext.WithQueryAttributes(ctx, testCase.attributes)
+			_, _, _, err := r.fetchOrCreatePlan(ctx, session, testCase.sql, map[string]*querypb.BindVariable{}, r.config.Normalize, false, logStats, true)
+			if testCase.expectedError != nil {
+				assert.ErrorIs(t, err, testCase.expectedError)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, testCase.expectedWorkload, session.Options.WorkloadName)
+			assert.Equal(t, testCase.expectedPriority, session.Options.Priority)
+		})
+	}
+}
+
 func TestPassthroughDDL(t *testing.T) {
 	executor, sbc1, sbc2, _, ctx := createExecutorEnvWithConfig(t, createExecutorConfigWithNormalizer())
 	session := &vtgatepb.Session{
@@ -2701,6 +2757,192 @@ func TestExecutorShowVitessMigrations(t *testing.T) {
 	assert.Contains(t, sbc2.StringQueries(), "show vitess_migrations")
 }
 
+func TestExecutorQueryStats(t *testing.T) {
+	eConfig := createExecutorConfigWithNormalizer()
+	eConfig.QueryStatsMaxDigests = 10
+	executor, _, _, sbclookup, ctx := createExecutorEnvWithConfig(t, eConfig)
+	session := econtext.NewSafeSession(&vtgatepb.Session{TargetString: KsTestUnsharded})
+
+	for _, query := range []string{
+		"select id from music_user_map where id = 1",
+		"select id from music_user_map where id = 2",
+		"update music_user_map set id = 3 where id = 2",
+	} {
+		_, err := executorExecSession(ctx, executor, session, query, nil)
+		require.NoError(t, err)
+	}
+	sbclookup.MustFailCodes[vtrpcpb.Code_INVALID_ARGUMENT] = 1
+	_, err := executorExecSession(ctx, executor, session, "select id from music_user_map where id = 4", nil)
+	require.Error(t, err)
+	_, err = executorStream(ctx, executor, "select id from TestUnsharded.music_user_map where id = 5")
+	require.NoError(t, err)
+
+	qr, err := executorExecSession(ctx, executor, session, "show vitess_query_stats like 'select%'", nil)
+	require.NoError(t, err)
+	require.Len(t, qr.Rows, 2)
+	row := qr.Named().Rows[0]
+	assert.Equal(t, KsTestUnsharded, row.AsString("Keyspace", ""))
+	assert.Equal(t, "select id from music_user_map where id = :id /* INT64 */", row.AsString("Query", ""))
+	assert.Len(t, row.AsString("Digest", ""), 32)
+	assert.Equal(t, "Passthrough", row.AsString("PlanType", ""))
+	assert.EqualValues(t, 3, row.AsUint64("Calls", 0))
+	assert.EqualValues(t, 1, row.AsUint64("Errors", 0))
+	assert.EqualValues(t, 2, row.AsUint64("RowsReturned", 0))
+	assert.EqualValues(t, 3, row.AsUint64("ShardQueries", 0))
+	assert.LessOrEqual(t, row.AsFloat64("MinLatency", 0), row.AsFloat64("P99Latency", 0))
+	assert.LessOrEqual(t, row.AsFloat64("P99Latency", 0), row.AsFloat64("MaxLatency", 0))
+	// The streaming query has no current keyspace.
+	row = qr.Named().Rows[1]
+	assert.Equal(t, "", row.AsString("Keyspace", ""))
+	assert.Equal(t, "select id from TestUnsharded.music_user_map where id = :id /* INT64 */", row.AsString("Query", ""))
+	assert.EqualValues(t, 1, row.AsUint64("Calls", 0))
+
+	qr, err = executorExecSession(ctx, executor, session, "show vitess_query_stats where RowsAffected > 0", nil)
+	require.NoError(t, err)
+	require.Len(t, qr.Rows, 1)
+	assert.Equal(t, "update music_user_map set id = :id /* INT64 */ where id = :id1 /* INT64 */", qr.Named().Row().AsString("Query", ""))
+
+	_, err = executorExecSession(ctx, executor, session, "flush vitess_query_stats", nil)
+	require.NoError(t, err)
+	qr, err = executorExecSession(ctx, executor, session, "show vitess_query_stats where Query like 'select%'", nil)
+	require.NoError(t, err)
+	assert.Empty(t, qr.Rows)
+}
+
+type fakeProcessList []*processlist.Process
+
+func (f fakeProcessList) Processes() []*processlist.Process {
+	return f
+}
+
+func TestExecutorShowProcesslist(t *testing.T) {
+	executor, sbc1, _, _, ctx := createExecutorEnv(t)
+	session := econtext.NewSafeSession(&vtgatepb.Session{TargetString: "TestExecutor"})
+
+	qr, err := executorExecSession(ctx, executor, session, "show processlist", nil)
+	require.NoError(t, err)
+	assert.Empty(t, qr.Rows)
+
+	executor.SetProcessList(fakeProcessList{{
+		ID:      1,
+		User:    "user1",
+		Host:    "localhost:1234",
+		DB:      "TestExecutor",
+		Command: "Query",
+		Time:    2 * time.Second,
+		Query:   "update user set name = 'foo' where id = 1",
+		ShardSessions: []*vtgatepb.Session_ShardSession{{
+			Target:        &querypb.Target{Keyspace: "TestExecutor", Shard: "-20", TabletType: topodatapb.TabletType_PRIMARY},
+			TransactionId: 5,
+			TabletAlias:   sbc1.Tablet().Alias,
+		}},
+	}, {
+		ID:      2,
+		User:    "user2",
+		Host:    "localhost:5678",
+		Command: "Sleep",
+		Time:    time.Minute,
+	}})
+
+	// Users only list their own connections.
+	userCtx := callerid.NewContext(ctx, callerid.NewEffectiveCallerID("user2", "", ""), &querypb.VTGateCallerID{Username: "user2"})
+	qr, err = executorExecSession(userCtx, executor, session, "show processlist", nil)
+	require.NoError(t, err)
+	require.Len(t, qr.Rows, 1)
+	assert.EqualValues(t, 2, qr.Named().Row().AsUint64("Id", 0))
+	qr, err = executorExecSession(userCtx, executor, session, "show vitess_processlist", nil)
+	require.NoError(t, err)
+	require.Len(t, qr.Rows, 1)
+	assert.Empty(t, sbc1.StringQueries())
+
+	// Unless they are authorized to list the connections of all users.
+	defer func(users []string) { processlistAuthorizedUsers = users }(processlistAuthorizedUsers)
+	processlistAuthorizedUsers = []string{"admin"}
+	ctx = callerid.NewContext(ctx, nil, &querypb.VTGateCallerID{Username: "admin"})
+
+	qr, err = executorExecSession(ctx, executor, session, "show processlist", nil)
+	require.NoError(t, err)
+	require.Len(t, qr.Rows, 2)
+	row := qr.Named().Rows[0]
+	assert.EqualValues(t, 1, row.AsUint64("Id", 0))
+	assert.Equal(t, "user1", row.AsString("User", ""))
+	assert.Equal(t, "Query", row.AsString("Command", ""))
+	assert.EqualValues(t, 2, row.AsInt64("Time", 0))
+	assert.Equal(t, "executing", row.AsString("State", ""))
+	assert.Equal(t, "update user set name = 'foo' where id = 1", row.AsString("Info", ""))
+	assert.Equal(t, "TestExecutor/-20@primary", row.AsString("ShardSessions", ""))
+	row = qr.Named().Rows[1]
+	assert.Equal(t, "Sleep", row.AsString("Command", ""))
+	assert.Equal(t, "", row.AsString("State", ""))
+	assert.True(t, row["Info"].IsNull())
+
+	qr, err = executorExecSession(ctx, executor, session, "show full processlist where Command != 'Sleep'", nil)
+	require.NoError(t, err)
+	require.Len(t, qr.Rows, 1)
+	assert.EqualValues(t, 1, qr.Named().Row().AsUint64("Id", 0))
+
+	sbc1.SetResults([]*sqltypes.Result{sqltypes.MakeTestResult(
+		sqltypes.MakeTestFields("type|connection_id|stateful_id|time|query", "varchar|int64|int64|int64|varchar"),
+		"Stateful|10|5|1|update `user` set `name` = 'foo' where id = 1",
+		"Stateless|11|0|0|select 1 from dual",
+	)})
+	qr, err = executorExecSession(ctx, executor, session, "show vitess_processlist", nil)
+	require.NoError(t, err)
+	assert.Contains(t, sbc1.StringQueries(), "show vitess_processlist")
+	require.Len(t, qr.Rows, 2)
+	row = qr.Named().Rows[0]
+	assert.EqualValues(t, 1, row.AsUint64("Id", 0))
+	assert.Equal(t, "TestExecutor", row.AsString("Keyspace", ""))
+	assert.Equal(t, "-20", row.AsString("Shard", ""))
+	assert.Equal(t, "primary", row.AsString("TabletType", ""))
+	assert.Equal(t, topoproto.TabletAliasString(sbc1.Tablet().Alias), row.AsString("TabletAlias", ""))
+	assert.EqualValues(t, 5, row.AsInt64("TransactionID", 0))
+	assert.Equal(t, "update `user` set `name` = 'foo' where id = 1", row.AsString("ShardQuery", ""))
+	assert.EqualValues(t, 1, row.AsInt64("ShardQueryTime", 0))
+	row = qr.Named().Rows[1]
+	assert.EqualValues(t, 2, row.AsUint64("Id", 0))
+	assert.True(t, row["Keyspace"].IsNull())
+	assert.True(t, row["ShardQuery"].IsNull())
+
+	sbc1.MustFailCodes[vtrpcpb.Code_UNAVAILABLE] = 1
+	_, err = executorExecSession(ctx, executor, session, "show vitess_processlist where Keyspace = 'TestExecutor'", nil)
+	require.ErrorContains(t, err, "UNAVAILABLE")
+
+	// The queries are fetched once from each tablet the shard sessions are
+	// on, rather than from any tablet of their shard.
+	shardSession := func(id int64, alias *topodatapb.TabletAlias) []*vtgatepb.Session_ShardSession {
+		return []*vtgatepb.Session_ShardSession{{
+			Target:        &querypb.Target{Keyspace: "TestExecutor", Shard: "-20", TabletType: topodatapb.TabletType_PRIMARY},
+			TransactionId: id,
+			TabletAlias:   alias,
+		}}
+	}
+	executor.SetProcessList(fakeProcessList{
+		{ID: 1, Command: "Query", ShardSessions: shardSession(5, sbc1.Tablet().Alias)},
+		{ID: 2, Command: "Query", ShardSessions: shardSession(6, sbc1.Tablet().Alias)},
+	})
+	sbc1.Queries = nil
+	sbc1.SetResults([]*sqltypes.Result{sqltypes.MakeTestResult(
+		sqltypes.MakeTestFields("type|connection_id|stateful_id|time|query", "varchar|int64|int64|int64|varchar"),
+		"Stateful|10|5|1|select 5",
+		"Stateful|11|6|2|select 6",
+	)})
+	qr, err = executorExecSession(ctx, executor, session, "show vitess_processlist", nil)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"show vitess_processlist"}, sbc1.StringQueries())
+	require.Len(t, qr.Rows, 2)
+	assert.Equal(t, "select 5", qr.Named().Rows[0].AsString("ShardQuery", ""))
+	assert.Equal(t, "select 6", qr.Named().Rows[1].AsString("ShardQuery", ""))
+
+	executor.SetProcessList(fakeProcessList{
+		{ID: 1, Command: "Query", ShardSessions: shardSession(5, &topodatapb.TabletAlias{Cell: "aa", Uid: 999})},
+	})
+	sbc1.Queries = nil
+	_, err = executorExecSession(ctx, executor, session, "show vitess_processlist", nil)
+	require.ErrorContains(t, err, "aa-0000000999 not found")
+	assert.Empty(t, sbc1.StringQueries())
+}
+
 func TestExecutorDescHash(t *testing.T) {
 	executor, _, _, _, ctx := createExecutorEnv(t)
 	session := econtext.NewSafeSession(&vtgatepb.Session{TargetString: "TestExecutor"})
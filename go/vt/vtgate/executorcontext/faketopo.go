@@ -66,3 +66,7 @@ func (f *FakeTopoServer) WatchSrvKeyspace(ctx context.Context, cell, keyspace st
 // a new value or an error occurs.
 func (f *FakeTopoServer) WatchSrvVSchema(ctx context.Context, cell string, callback func(*vschemapb.SrvVSchema, error) bool) {
 }
+
+// WatchTenantDirectory starts watching the content of a directory vindex.
+func (f *FakeTopoServer) WatchTenantDirectory(ctx context.Context, keyspace, name string, callback func(*vschemapb.TenantDirectory, error) bool) {
+}
@@ -0,0 +1,37 @@
+/*
+Copyright 2026 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package executorcontext
+
+import (
+	"context"
+	"time"
+)
+
+type maxReplicaLagKey struct{}
+
+// WithMaxReplicaLag returns a context bounding the replication lag of the
+// replicas the queries run with it are sent to.
+func WithMaxReplicaLag(ctx context.Context, maxReplicaLag time.Duration) context.Context {
+	return context.WithValue(ctx, maxReplicaLagKey{}, maxReplicaLag)
+}
+
+// MaxReplicaLagFromContext returns the bound on the replication lag of the
+// replicas carried by the context, or zero if there is none.
+func MaxReplicaLagFromContext(ctx context.Context) time.Duration {
+	maxReplicaLag, _ := ctx.Value(maxReplicaLagKey{}).(time.Duration)
+	return maxReplicaLag
+}
@@ -0,0 +1,54 @@
+/*
+Copyright 2026 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package executorcontext
+
+import (
+	"context"
+	"strings"
+
+	querypb "vitess.io/vitess/go/vt/proto/query"
+)
+
+type queryAttributesKey struct{}
+
+// WithQueryAttributes returns a context carrying the query attributes the
+// client sent along with the query, over the MySQL protocol.
+func WithQueryAttributes(ctx context.Context, attributes map[string]*querypb.BindVariable) context.Context {
+	if len(attributes) == 0 {
+		return ctx
+	}
+	return context.WithValue(ctx, queryAttributesKey{}, attributes)
+}
+
+// QueryAttributesFromContext returns the query attributes carried by the
+// context, or nil.
+func QueryAttributesFromContext(ctx context.Context) map[string]*querypb.BindVariable {
+	attributes, _ := ctx.Value(queryAttributesKey{}).(map[string]*querypb.BindVariable)
+	return attributes
+}
+
+// QueryAttribute returns the value of the query attribute with the given
+// name. Like query directives, names are case-insensitive. NULL values are
+// reported as missing.
+func QueryAttribute(attributes map[string]*querypb.BindVariable, name string) (string, bool) {
+	for attrName, attr := range attributes {
+		if strings.EqualFold(attrName, name) && attr.GetType() != querypb.Type_NULL_TYPE {
+			return string(attr.GetValue()), true
+		}
+	}
+	return "", false
+}
@@ -262,6 +262,22 @@ func (session *SafeSession) GetQueryTimeout() int64 {
 	return session.QueryTimeout
 }
 
+// SetMaxReplicaLag sets the maximum replication lag, in seconds, of the
+// replicas the session reads from
+func (session *SafeSession) SetMaxReplicaLag(maxReplicaLag int64) {
+	session.mu.Lock()
+	defer session.mu.Unlock()
+	session.MaxReplicaLag = maxReplicaLag
+}
+
+// GetMaxReplicaLag gets the maximum replication lag, in seconds, of the
+// replicas the session reads from
+func (session *SafeSession) GetMaxReplicaLag() int64 {
+	session.mu.Lock()
+	defer session.mu.Unlock()
+	return session.MaxReplicaLag
+}
+
 // SavePoints returns the save points of the session. It's safe to use concurrently
 func (session *SafeSession) SavePoints() []string {
 	session.mu.Lock()
@@ -108,7 +108,11 @@ type (
 		ShowShards(ctx context.Context, filter *sqlparser.ShowFilter, destTabletType topodatapb.TabletType) (*sqltypes.Result, error)
 		ShowTablets(filter *sqlparser.ShowFilter) (*sqltypes.Result, error)
 		ShowVitessMetadata(ctx context.Context, filter *sqlparser.ShowFilter) (*sqltypes.Result, error)
+		ShowQueryStats(filter *sqlparser.ShowFilter) (*sqltypes.Result, error)
+		ShowProcesslist(ctx context.Context, full bool) (*sqltypes.Result, error)
+		ShowVitessProcesslist(ctx context.Context) (*sqltypes.Result, error)
 		SetVitessMetadata(ctx context.Context, name, value string) error
+		ResetQueryStats()
 
 		// TODO: remove when resolver is gone
 		VSchema() *vindexes.VSchema
@@ -173,6 +177,7 @@ type (
 		vm                  VSchemaOperator
 		semTable            *semantics.SemTable
 		queryTimeout        time.Duration
+		maxReplicaLag       time.Duration
 
 		warnings []*querypb.QueryWarning // any warnings that are accumulated during the planning phase are stored here
 
@@ -708,6 +713,8 @@ func (vc *VCursorImpl) TargetString() string {
 const MaxBufferingRetries = 3
 
 func (vc *VCursorImpl) ExecutePrimitive(ctx context.Context, primitive engine.Primitive, bindVars map[string]*querypb.BindVariable, wantfields bool) (*sqltypes.Result, error) {
+	span, ctx := engine.NewPrimitiveSpan(ctx, primitive)
+	defer span.Finish()
 	for try := 0; try < MaxBufferingRetries; try++ {
 		res, err := primitive.TryExecute(ctx, vc, bindVars, wantfields)
 		if err != nil && vterrors.RootCause(err) == buffer.ShardMissingError {
@@ -746,6 +753,8 @@ func (vc *VCursorImpl) logShardsQueried(primitive engine.Primitive, shardsNb int
 }
 
 func (vc *VCursorImpl) ExecutePrimitiveStandalone(ctx context.Context, primitive engine.Primitive, bindVars map[string]*querypb.BindVariable, wantfields bool) (*sqltypes.Result, error) {
+	span, ctx := engine.NewPrimitiveSpan(ctx, primitive)
+	defer span.Finish()
 	// clone the VCursorImpl with a new session.
 	newVC := vc.cloneWithAutocommitSession()
 	for try := 0; try < MaxBufferingRetries; try++ {
@@ -779,6 +788,8 @@ func (vc *VCursorImpl) wrapCallback(callback func(*sqltypes.Result) error, primi
 }
 
 func (vc *VCursorImpl) StreamExecutePrimitive(ctx context.Context, primitive engine.Primitive, bindVars map[string]*querypb.BindVariable, wantfields bool, callback func(*sqltypes.Result) error) error {
+	span, ctx := engine.NewPrimitiveSpan(ctx, primitive)
+	defer span.Finish()
 	callback = vc.wrapCallback(callback, primitive)
 
 	for try := 0; try < MaxBufferingRetries; try++ {
@@ -792,6 +803,8 @@ func (vc *VCursorImpl) StreamExecutePrimitive(ctx context.Context, primitive eng
 }
 
 func (vc *VCursorImpl) StreamExecutePrimitiveStandalone(ctx context.Context, primitive engine.Primitive, bindVars map[string]*querypb.BindVariable, wantfields bool, callback func(result *sqltypes.Result) error) error {
+	span, ctx := engine.NewPrimitiveSpan(ctx, primitive)
+	defer span.Finish()
 	callback = vc.wrapCallback(callback, primitive)
 
 	// clone the VCursorImpl with a new session.
@@ -1152,6 +1165,11 @@ func (vc *VCursorImpl) SetQueryTimeout(maxExecutionTime int64) {
 	vc.SafeSession.QueryTimeout = maxExecutionTime
 }
 
+// SetMaxReplicaLag implements the SessionActions interface
+func (vc *VCursorImpl) SetMaxReplicaLag(maxReplicaLag int64) {
+	vc.SafeSession.SetMaxReplicaLag(maxReplicaLag)
+}
+
 // SetClientFoundRows implements the SessionActions interface
 func (vc *VCursorImpl) SetClientFoundRows(_ context.Context, clientFoundRows bool) error {
 	vc.SafeSession.GetOrCreateOptions().ClientFoundRows = clientFoundRows
@@ -1218,6 +1236,17 @@ func (vc *VCursorImpl) SetExecQueryTimeout(timeout *int) {
 	}
 }
 
+// SetExecMaxReplicaLag sets the maximum replication lag of the replicas the
+// query reads from: the one given by the query directive if non-nil,
+// otherwise the one of the session.
+func (vc *VCursorImpl) SetExecMaxReplicaLag(maxReplicaLag *int) {
+	lag := vc.SafeSession.GetMaxReplicaLag()
+	if maxReplicaLag != nil {
+		lag = int64(*maxReplicaLag)
+	}
+	vc.maxReplicaLag = time.Duration(lag) * time.Second
+}
+
 // getQueryTimeout returns timeout based on the priority
 // session setting > global default specified by a flag.
 func (vc *VCursorImpl) getQueryTimeout() int {
@@ -1500,6 +1529,8 @@ func (vc *VCursorImpl) ShowExec(ctx context.Context, command sqlparser.ShowComma
 		return vc.executor.ShowTablets(filter)
 	case sqlparser.VitessVariables:
 		return vc.executor.ShowVitessMetadata(ctx, filter)
+	case sqlparser.VitessQueryStats:
+		return vc.executor.ShowQueryStats(filter)
 	default:
 		return nil, vterrors.Errorf(vtrpcpb.Code_INTERNAL, "bug: unexpected show command: %v", command)
 	}
@@ -1517,6 +1548,17 @@ func (vc *VCursorImpl) SetExec(ctx context.Context, name string, value string) e
 	return vc.executor.SetVitessMetadata(ctx, name, value)
 }
 
+func (vc *VCursorImpl) ResetQueryStats() {
+	vc.executor.ResetQueryStats()
+}
+
+func (vc *VCursorImpl) ShowProcesslist(ctx context.Context, full, shards bool) (*sqltypes.Result, error) {
+	if shards {
+		return vc.executor.ShowVitessProcesslist(ctx)
+	}
+	return vc.executor.ShowProcesslist(ctx, full)
+}
+
 func (vc *VCursorImpl) ThrottleApp(ctx context.Context, throttledAppRule *topodatapb.ThrottledAppRule) (err error) {
 	if throttledAppRule == nil {
 		return vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "ThrottleApp: nil rule")
@@ -1657,6 +1699,15 @@ func (vc *VCursorImpl) GetContextWithTimeOut(ctx context.Context) (context.Conte
 	return context.WithTimeout(ctx, vc.queryTimeout)
 }
 
+// GetContextWithMaxReplicaLag returns a context bounding the replication lag
+// of the replicas the query reads from, if the query or the session sets one.
+func (vc *VCursorImpl) GetContextWithMaxReplicaLag(ctx context.Context) context.Context {
+	if vc.maxReplicaLag == 0 {
+		return ctx
+	}
+	return WithMaxReplicaLag(ctx, vc.maxReplicaLag)
+}
+
 func (vc *VCursorImpl) IgnoreMaxMemoryRows() bool {
 	return vc.ignoreMaxMemoryRows
 }
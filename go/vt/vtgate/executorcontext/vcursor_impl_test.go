@@ -388,11 +388,31 @@ func (f fakeExecutor) ShowVitessMetadata(ctx context.Context, filter *sqlparser.
 	panic("implement me")
 }
 
+func (f fakeExecutor) ShowQueryStats(filter *sqlparser.ShowFilter) (*sqltypes.Result, error) {
+	// TODO implement me
+	panic("implement me")
+}
+
+func (f fakeExecutor) ShowProcesslist(ctx context.Context, full bool) (*sqltypes.Result, error) {
+	// TODO implement me
+	panic("implement me")
+}
+
+func (f fakeExecutor) ShowVitessProcesslist(ctx context.Context) (*sqltypes.Result, error) {
+	// TODO implement me
+	panic("implement me")
+}
+
 func (f fakeExecutor) SetVitessMetadata(ctx context.Context, name, value string) error {
 	// TODO implement me
 	panic("implement me")
 }
 
+func (f fakeExecutor) ResetQueryStats() {
+	// TODO implement me
+	panic("implement me")
+}
+
 func (f fakeExecutor) ParseDestinationTarget(targetString string) (string, topodatapb.TabletType, key.ShardDestination, error) {
 	// TODO implement me
 	panic("implement me")
@@ -41,6 +41,7 @@ type LogStats struct {
 	StmtType                string
 	SQL                     string
 	BindVariables           map[string]*querypb.BindVariable
+	QueryAttributes         map[string]*querypb.BindVariable // QueryAttributes are the query attributes sent by the client
 	StartTime               time.Time
 	EndTime                 time.Time
 	ShardQueries            uint64
@@ -199,6 +200,12 @@ func (stats *LogStats) Logf(w io.Writer, params url.Values) error {
 	log.String(stats.MirrorTargetErrorStr())
 	log.Key("EmitReason")
 	log.String(emitReason)
+	log.Key("QueryAttributes")
+	if stats.Config.RedactDebugUIQueries {
+		log.Redacted()
+	} else {
+		log.BindVariables(stats.QueryAttributes, fullBindParams)
+	}
 
 	return log.Flush(w)
 }
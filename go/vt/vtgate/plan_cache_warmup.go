@@ -0,0 +1,136 @@
+/*
+Copyright 2026 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vtgate
+
+import (
+	"cmp"
+	"context"
+	"slices"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"vitess.io/vitess/go/cache/warmup"
+	"vitess.io/vitess/go/streamlog"
+	"vitess.io/vitess/go/vt/sqlparser"
+	"vitess.io/vitess/go/vt/topo/topoproto"
+	"vitess.io/vitess/go/vt/vterrors"
+	"vitess.io/vitess/go/vt/vtgate/engine"
+	econtext "vitess.io/vitess/go/vt/vtgate/executorcontext"
+	"vitess.io/vitess/go/vt/vtgate/logstats"
+
+	querypb "vitess.io/vitess/go/vt/proto/query"
+	vtgatepb "vitess.io/vitess/go/vt/proto/vtgate"
+	vtrpcpb "vitess.io/vitess/go/vt/proto/vtrpc"
+)
+
+// planCacheEntry is an entry of the plan cache snapshot.
+type planCacheEntry struct {
+	engine.PlanKey
+	// Prepared is true if the plan was built for a prepared statement.
+	Prepared bool `json:",omitempty"`
+}
+
+// planCacheKeys returns the entries of the cached plans that can be
+// replanned, the most executed plans first, and at most maxEntries of them
+// if it is positive.
+func (e *Executor) planCacheKeys(maxEntries int) []planCacheEntry {
+	type cachedPlan struct {
+		entry planCacheEntry
+		count uint64
+	}
+	var plans []cachedPlan
+	e.ForEachPlan(func(plan *engine.Plan) bool {
+		if plan.Key.Query != "" {
+			plans = append(plans, cachedPlan{
+				entry: planCacheEntry{PlanKey: plan.Key, Prepared: plan.Prepared},
+				count: atomic.LoadUint64(&plan.ExecCount),
+			})
+		}
+		return true
+	})
+	slices.SortStableFunc(plans, func(a, b cachedPlan) int {
+		return cmp.Compare(b.count, a.count)
+	})
+	if maxEntries > 0 && len(plans) > maxEntries {
+		plans = plans[:maxEntries]
+	}
+	entries := make([]planCacheEntry, 0, len(plans))
+	for _, plan := range plans {
+		entries = append(entries, plan.entry)
+	}
+	return entries
+}
+
+// WarmUpPlanCache replans the queries of the plan cache snapshot, so the
+// cache does not start empty after a restart. It waits for the vschema to
+// be loaded, within the time allowed for the warm-up. It returns the number
+// of queries that were replanned.
+func (e *Executor) WarmUpPlanCache(ctx context.Context, config warmup.Config) int {
+	if !config.Enabled() {
+		return 0
+	}
+	if config.MaxDuration > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, config.MaxDuration)
+		defer cancel()
+	}
+	for e.VSchema() == nil {
+		select {
+		case <-ctx.Done():
+			return 0
+		case <-time.After(100 * time.Millisecond):
+		}
+	}
+	return warmup.WarmUp(ctx, config, e.replan)
+}
+
+// replan plans the query of the entry, in a session targeting the same
+// keyspace and tablet type, and caches the plan under its key.
+func (e *Executor) replan(ctx context.Context, entry planCacheEntry) error {
+	key := entry.PlanKey
+	// The plans of queries targeting shards depend on how the destination
+	// was resolved, they are not replanned.
+	if key.Destination != "" {
+		return vterrors.Errorf(vtrpcpb.Code_FAILED_PRECONDITION, "cannot replan a query targeting a shard")
+	}
+	if key.Collation != e.vConfig.Collation {
+		return vterrors.Errorf(vtrpcpb.Code_FAILED_PRECONDITION, "cannot replan a query of another collation")
+	}
+	// The arguments vtgate provides, e.g. for LAST_INSERT_ID() or the user
+	// defined variables, cannot be told apart from the ones of the client
+	// once the query is normalized.
+	if strings.Contains(key.Query, ":__") {
+		return vterrors.Errorf(vtrpcpb.Code_FAILED_PRECONDITION, "cannot replan a query with vtgate provided arguments")
+	}
+
+	safeSession := econtext.NewSafeSession(&vtgatepb.Session{
+		TargetString: key.CurrentKeyspace + "@" + topoproto.TabletTypeLString(key.TabletType),
+		Autocommit:   true,
+	})
+	logStats := logstats.NewLogStats(ctx, "PlanCacheWarmup", key.Query, "", nil, streamlog.GetQueryLogConfig())
+	vcursor, err := e.newVCursor(safeSession, sqlparser.MarginComments{}, logStats)
+	if err != nil {
+		return err
+	}
+	// The queries of the snapshot are known not to be one-offs.
+	e.plans.Admit(key.Hash())
+	// The query of the key is already normalized: it is planned as is, and
+	// cached under the same key.
+	_, _, _, err = e.getCachedOrBuildPlan(ctx, vcursor, key.Query, make(map[string]*querypb.BindVariable), key.SetVarComment, false, entry.Prepared, key, false)
+	return err
+}
@@ -0,0 +1,108 @@
+/*
+Copyright 2026 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vtgate
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"vitess.io/vitess/go/cache/theine"
+	"vitess.io/vitess/go/cache/warmup"
+	"vitess.io/vitess/go/streamlog"
+	"vitess.io/vitess/go/vt/vtgate/engine"
+	econtext "vitess.io/vitess/go/vt/vtgate/executorcontext"
+	"vitess.io/vitess/go/vt/vtgate/logstats"
+
+	querypb "vitess.io/vitess/go/vt/proto/query"
+	vtgatepb "vitess.io/vitess/go/vt/proto/vtgate"
+)
+
+func TestPlanCacheWarmup(t *testing.T) {
+	config := warmup.Config{File: filepath.Join(t.TempDir(), "plans")}
+
+	executor, _, _, _, ctx := createExecutorEnvWithConfig(t, createExecutorConfigWithNormalizer())
+	// Use a doorkeeper, like in production: the plans are only cached the
+	// second time their query is seen.
+	executor.plans.Close()
+	executor.plans = theine.NewStore[PlanCacheKey, *engine.Plan](queryPlanCacheMemory, true)
+
+	queries := []struct {
+		target string
+		sql    string
+		count  int
+	}{
+		{target: "@primary", sql: "select * from music_user_map where id = 1", count: 5},
+		{target: KsTestUnsharded + "@replica", sql: "select * from music_user_map where id = 2 and c = 'foo'", count: 4},
+		{target: "@primary", sql: "update user set a = 1 where id = 1", count: 3},
+		// Queries targeting a shard, or using arguments provided by vtgate,
+		// are not replanned.
+		{target: KsTestUnsharded + ":0@primary", sql: "select 1 from dual", count: 2},
+		{target: "@primary", sql: "select last_insert_id() from user where id = 1", count: 2},
+	}
+	for _, query := range queries {
+		for range query.count {
+			session := econtext.NewSafeSession(&vtgatepb.Session{TargetString: query.target})
+			_, err := executorExecSession(ctx, executor, session, query.sql, nil)
+			require.NoError(t, err)
+		}
+	}
+	// A prepared statement is planned, but never executed.
+	for range 2 {
+		session := econtext.NewSafeSession(&vtgatepb.Session{TargetString: "@primary"})
+		_, _, err := executor.Prepare(ctx, "TestPrepare", session, "select * from music_user_map where id = ?")
+		require.NoError(t, err)
+	}
+	var keys []planCacheEntry
+	assert.Eventually(t, func() bool {
+		keys = executor.planCacheKeys(0)
+		return len(keys) == len(queries)+1
+	}, 5*time.Second, 10*time.Millisecond)
+	assert.Equal(t, "select * from music_user_map where id = :id /* INT64 */", keys[0].Query)
+	assert.Equal(t, keys[:3], executor.planCacheKeys(3))
+	prepared := keys[len(keys)-1]
+	assert.True(t, prepared.Prepared)
+	require.NoError(t, warmup.Save(config.File, keys))
+
+	executor.ClearPlans()
+	assert.Empty(t, executor.planCacheKeys(0))
+	assert.Equal(t, 4, executor.WarmUpPlanCache(ctx, config))
+	assert.Eventually(t, func() bool {
+		return len(executor.planCacheKeys(0)) == 4
+	}, 5*time.Second, 10*time.Millisecond)
+	assert.ElementsMatch(t, append(keys[:3:3], prepared), executor.planCacheKeys(0))
+
+	// Only the plans of the prepared statements count their parameters.
+	executor.ForEachPlan(func(plan *engine.Plan) bool {
+		if plan.Prepared {
+			assert.EqualValues(t, 1, plan.ParamsCount, plan.Original)
+		} else {
+			assert.Zero(t, plan.ParamsCount, plan.Original)
+		}
+		return true
+	})
+
+	// The warmed up plans are used.
+	session := econtext.NewSafeSession(&vtgatepb.Session{TargetString: "@primary"})
+	logStats := logstats.NewLogStats(ctx, "Test", "", "", nil, streamlog.NewQueryLogConfigForTest())
+	_, _, _, err := executor.fetchOrCreatePlan(ctx, session, "select * from music_user_map where id = 3", map[string]*querypb.BindVariable{}, true, false, logStats, true)
+	require.NoError(t, err)
+	assert.True(t, logStats.CachedPlan)
+}
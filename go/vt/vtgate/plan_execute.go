@@ -142,6 +142,8 @@ func (e *Executor) newExecute(
 		// set the overall query timeout if it is not already set
 		ctx, cancel = vcursor.GetContextWithTimeOut(ctx)
 		defer cancel()
+		// bound the replication lag of the replicas the query reads from, if requested
+		ctx = vcursor.GetContextWithMaxReplicaLag(ctx)
 
 		// If we have previously issued a VT15001 error, we block any new queries on this session until we receive a ROLLBACK or "show warnings".
 		if shouldBlockQueries(plan, safeSession) {
@@ -412,6 +414,7 @@ func (e *Executor) setLogStats(logStats *logstats.LogStats, plan *engine.Plan, v
 	logStats.TabletType = vcursor.TabletType().String()
 	errCount := e.logExecutionEnd(logStats, execStart, plan, vcursor, err, qr)
 	plan.AddStats(1, time.Since(logStats.StartTime), logStats.ShardQueries, logStats.RowsAffected, logStats.RowsReturned, errCount)
+	e.recordQueryStats(plan, logStats, logStats.RowsReturned, logStats.RowsAffected, err)
 }
 
 func (e *Executor) logExecutionEnd(logStats *logstats.LogStats, execStart time.Time, plan *engine.Plan, vcursor *econtext.VCursorImpl, err error, qr *sqltypes.Result) uint64 {
@@ -19,7 +19,9 @@ package planbuilder
 import (
 	"context"
 	"fmt"
+	"slices"
 	"sort"
+	"strings"
 
 	"vitess.io/vitess/go/sqltypes"
 	"vitess.io/vitess/go/test/vschemawrapper"
@@ -351,6 +353,13 @@ func buildFlushPlan(stmt *sqlparser.Flush, vschema plancontext.VSchema) (*planRe
 }
 
 func buildFlushOptions(stmt *sqlparser.Flush, vschema plancontext.VSchema) (*planResult, error) {
+	if slices.ContainsFunc(stmt.FlushOptions, isFlushQueryStats) {
+		if len(stmt.FlushOptions) > 1 {
+			return nil, vterrors.VT12001("FLUSH VITESS_QUERY_STATS with other options")
+		}
+		return newPlanResult(&engine.FlushQueryStats{}), nil
+	}
+
 	if !stmt.IsLocal && vschema.TabletType() != topodatapb.TabletType_PRIMARY {
 		return nil, vterrors.VT09012("FLUSH", vschema.TabletType().String())
 	}
@@ -373,6 +382,10 @@ func buildFlushOptions(stmt *sqlparser.Flush, vschema plancontext.VSchema) (*pla
 	}), nil
 }
 
+func isFlushQueryStats(option string) bool {
+	return strings.EqualFold(option, "vitess_query_stats")
+}
+
 func buildFlushTables(stmt *sqlparser.Flush, vschema plancontext.VSchema) (*planResult, error) {
 	if !stmt.IsLocal && vschema.TabletType() != topodatapb.TabletType_PRIMARY {
 		return nil, vterrors.VT09012("FLUSH", vschema.TabletType().String())
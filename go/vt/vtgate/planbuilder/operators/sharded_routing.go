@@ -284,7 +284,9 @@ func (tr *ShardedRouting) planComparison(ctx *plancontext.PlanningContext, cmp *
 	case sqlparser.LikeOp:
 		found := tr.planLikeOp(ctx, cmp)
 		return nil, found
-
+	case sqlparser.LessThanOp, sqlparser.LessEqualOp, sqlparser.GreaterThanOp, sqlparser.GreaterEqualOp:
+		found := tr.planRangeOp(ctx, cmp)
+		return nil, found
 	}
 	return nil, false
 }
@@ -363,6 +365,91 @@ func (tr *ShardedRouting) planLikeOp(ctx *plancontext.PlanningContext, node *sql
 	return tr.haveMatchingVindex(ctx, node, vdValue, column, val, selectEqual, vdx)
 }
 
+// planRangeOp plans a '<', '<=', '>' or '>=' comparison against a column with a RangeVindex.
+// Strict comparisons are planned as inclusive ones, which can at worst add a shard to the route.
+// When both sides of the range show up in separate predicates, they are combined into a single option.
+func (tr *ShardedRouting) planRangeOp(ctx *plancontext.PlanningContext, cmp *sqlparser.ComparisonExpr) bool {
+	column, ok := cmp.Left.(*sqlparser.ColName)
+	vdValue := cmp.Right
+	op := cmp.Operator
+	if !ok {
+		column, ok = cmp.Right.(*sqlparser.ColName)
+		if !ok {
+			// either the LHS or RHS have to be a column to be useful for the vindex
+			return false
+		}
+		vdValue = cmp.Left
+		op, _ = op.SwitchSides()
+	}
+	val := makeEvalEngineExpr(ctx, vdValue)
+	if val == nil {
+		return false
+	}
+
+	// side is the index of the range bound this predicate sets: 0 for start and 1 for end.
+	side := 0
+	if op == sqlparser.LessThanOp || op == sqlparser.LessEqualOp {
+		side = 1
+	}
+
+	newVindexFound := false
+	for _, v := range tr.VindexPreds {
+		if !ctx.SemTable.DirectDeps(column).IsSolvedBy(v.TableID) {
+			continue
+		}
+		if _, ok := v.ColVindex.Vindex.(vindexes.RangeVindex); !ok || !column.Name.Equal(v.ColVindex.Columns[0]) {
+			continue
+		}
+
+		var combined []*VindexOption
+		for _, option := range v.Options {
+			if option.OpCode != engine.Range || !isOpenRangeBound(option.ValueExprs[side]) {
+				continue
+			}
+			combined = append(combined, rangeOption(v.ColVindex, option, side, vdValue, val, cmp))
+		}
+
+		unbounded := &sqlparser.NullVal{}
+		open := &VindexOption{
+			Values:      []evalengine.Expr{evalengine.NullExpr, evalengine.NullExpr},
+			ValueExprs:  []sqlparser.Expr{unbounded, unbounded},
+			OpCode:      engine.Range,
+			FoundVindex: v.ColVindex.Vindex,
+		}
+		v.Options = append(v.Options, rangeOption(v.ColVindex, open, side, vdValue, val, cmp))
+		v.Options = append(v.Options, combined...)
+		newVindexFound = true
+	}
+	return newVindexFound
+}
+
+// rangeOption returns a copy of the given range option, with the bound at side set to value.
+func rangeOption(
+	colVindex *vindexes.ColumnVindex,
+	orig *VindexOption,
+	side int,
+	valueExpr sqlparser.Expr,
+	value evalengine.Expr,
+	node sqlparser.Expr,
+) *VindexOption {
+	option := copyOption(orig)
+	option.Values[side] = value
+	option.ValueExprs[side] = valueExpr
+	option.Predicates = append(option.Predicates, node)
+	option.Ready = true
+	option.Cost = costFor(colVindex, engine.Range)
+	if isOpenRangeBound(option.ValueExprs[0]) || isOpenRangeBound(option.ValueExprs[1]) {
+		// prefer ranges that are bounded on both sides
+		option.Cost.VindexCost++
+	}
+	return option
+}
+
+func isOpenRangeBound(expr sqlparser.Expr) bool {
+	_, ok := expr.(*sqlparser.NullVal)
+	return ok
+}
+
 func (tr *ShardedRouting) Cost() int {
 	switch tr.RouteOpCode {
 	case engine.EqualUnique:
@@ -375,6 +462,8 @@ func (tr *ShardedRouting) Cost() int {
 		return 10
 	case engine.MultiEqual:
 		return 10
+	case engine.Range:
+		return 15
 	case engine.Scatter:
 		return 20
 	default:
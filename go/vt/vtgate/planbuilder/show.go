@@ -35,7 +35,10 @@ import (
 	"vitess.io/vitess/go/vt/vterrors"
 	"vitess.io/vitess/go/vt/vtgate/engine"
 	popcode "vitess.io/vitess/go/vt/vtgate/engine/opcode"
+	"vitess.io/vitess/go/vt/vtgate/evalengine"
 	"vitess.io/vitess/go/vt/vtgate/planbuilder/plancontext"
+	"vitess.io/vitess/go/vt/vtgate/processlist"
+	"vitess.io/vitess/go/vt/vtgate/querystats"
 	"vitess.io/vitess/go/vt/vtgate/vindexes"
 )
 
@@ -123,6 +126,10 @@ func buildShowBasicPlan(show *sqlparser.ShowBasic, vschema plancontext.VSchema)
 			Command:    show.Command,
 			ShowFilter: show.Filter,
 		}, nil
+	case sqlparser.VitessQueryStats:
+		return buildShowQueryStatsPlan(show, vschema)
+	case sqlparser.Processlist, sqlparser.VitessProcesslist:
+		return buildShowProcesslistPlan(show, vschema)
 	case sqlparser.VitessTarget:
 		return buildShowTargetPlan(vschema)
 	case sqlparser.VschemaTables:
@@ -136,6 +143,65 @@ func buildShowBasicPlan(show *sqlparser.ShowBasic, vschema plancontext.VSchema)
 
 }
 
+// buildShowQueryStatsPlan serves `SHOW VITESS_QUERY_STATS ...` queries. The
+// WHERE clause, if any, filters the statistics returned by the executor.
+func buildShowQueryStatsPlan(show *sqlparser.ShowBasic, vschema plancontext.VSchema) (engine.Primitive, error) {
+	if show.Filter == nil || show.Filter.Filter == nil {
+		return &engine.ShowExec{
+			Command:    show.Command,
+			ShowFilter: show.Filter,
+		}, nil
+	}
+	return buildShowWherePlan(&engine.ShowExec{Command: show.Command}, show.Filter.Filter, querystats.ColumnIndex, vschema)
+}
+
+// buildShowProcesslistPlan serves `SHOW [FULL] PROCESSLIST ...` and
+// `SHOW VITESS_PROCESSLIST ...` queries, which list the MySQL connections
+// of this vtgate.
+func buildShowProcesslistPlan(show *sqlparser.ShowBasic, vschema plancontext.VSchema) (engine.Primitive, error) {
+	if show.DbName.NotEmpty() {
+		return nil, vterrors.VT12001("SHOW PROCESSLIST with FROM")
+	}
+	if show.Filter != nil && show.Filter.Like != "" {
+		return nil, vterrors.VT12001("LIKE filter on SHOW PROCESSLIST")
+	}
+
+	shards := show.Command == sqlparser.VitessProcesslist
+	prim := &engine.ShowProcesslist{Full: show.Full, Shards: shards}
+	if show.Filter == nil || show.Filter.Filter == nil {
+		return prim, nil
+	}
+	columnIndex := processlist.ColumnIndex
+	if shards {
+		columnIndex = processlist.VitessColumnIndex
+	}
+	return buildShowWherePlan(prim, show.Filter.Filter, columnIndex, vschema)
+}
+
+// buildShowWherePlan filters the rows of a SHOW command computed by vtgate
+// with its WHERE clause. columnIndex resolves the columns of the command.
+func buildShowWherePlan(input engine.Primitive, where sqlparser.Expr, columnIndex func(string) int, vschema plancontext.VSchema) (engine.Primitive, error) {
+	predicate, err := evalengine.Translate(where, &evalengine.Config{
+		ResolveColumn: func(col *sqlparser.ColName) (int, error) {
+			idx := columnIndex(col.Name.String())
+			if idx < 0 || col.Qualifier.NonEmpty() {
+				return 0, vterrors.VT03019(sqlparser.String(col))
+			}
+			return idx, nil
+		},
+		Collation:   vschema.ConnCollation(),
+		Environment: vschema.Environment(),
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &engine.Filter{
+		Input:        input,
+		Predicate:    predicate,
+		ASTPredicate: where,
+	}, nil
+}
+
 func buildShowTargetPlan(vschema plancontext.VSchema) (engine.Primitive, error) {
 	rows := [][]sqltypes.Value{buildVarCharRow(vschema.TargetString())}
 	return engine.NewRowsPrimitive(rows,
@@ -23,6 +23,7 @@ import (
 	"os"
 	"os/signal"
 	"regexp"
+	"sort"
 	"strings"
 	"sync"
 	"sync/atomic"
@@ -48,6 +49,8 @@ import (
 	"vitess.io/vitess/go/vt/utils"
 	"vitess.io/vitess/go/vt/vtenv"
 	"vitess.io/vitess/go/vt/vterrors"
+	econtext "vitess.io/vitess/go/vt/vtgate/executorcontext"
+	"vitess.io/vitess/go/vt/vtgate/processlist"
 	"vitess.io/vitess/go/vt/vttls"
 )
 
@@ -80,6 +83,8 @@ var (
 
 	mysqlServerFlushDelay = 100 * time.Millisecond
 	mysqlServerMultiQuery = false
+
+	mysqlServerCompressionAlgorithms []string
 )
 
 func registerPluginFlags(fs *pflag.FlagSet) {
@@ -87,7 +92,7 @@ func registerPluginFlags(fs *pflag.FlagSet) {
 	utils.SetFlagStringVar(fs, &mysqlServerBindAddress, "mysql-server-bind-address", mysqlServerBindAddress, "Binds on this address when listening to MySQL binary protocol. Useful to restrict listening to 'localhost' only for instance.")
 	utils.SetFlagStringVar(fs, &mysqlServerSocketPath, "mysql-server-socket-path", mysqlServerSocketPath, "This option specifies the Unix socket file to use when listening for local connections. By default it will be empty and it won't listen to a unix socket")
 	utils.SetFlagStringVar(fs, &mysqlTCPVersion, "mysql-tcp-version", mysqlTCPVersion, "Select tcp, tcp4, or tcp6 to control the socket type.")
-	utils.SetFlagStringVar(fs, &mysqlAuthServerImpl, "mysql-auth-server-impl", mysqlAuthServerImpl, "Which auth server implementation to use. Options: none, ldap, clientcert, static, vault.")
+	utils.SetFlagStringVar(fs, &mysqlAuthServerImpl, "mysql-auth-server-impl", mysqlAuthServerImpl, "Which auth server implementation to use. Options: none, ldap, clientcert, static, vault, jwt.")
 	utils.SetFlagBoolVar(fs, &mysqlAllowClearTextWithoutTLS, "mysql-allow-clear-text-without-tls", mysqlAllowClearTextWithoutTLS, "If set, the server will allow the use of a clear text password over non-SSL connections.")
 	utils.SetFlagBoolVar(fs, &mysqlProxyProtocol, "proxy-protocol", mysqlProxyProtocol, "Enable HAProxy PROXY protocol on MySQL listener socket")
 	utils.SetFlagBoolVar(fs, &mysqlServerRequireSecureTransport, "mysql-server-require-secure-transport", mysqlServerRequireSecureTransport, "Reject insecure connections but only if mysql-server-ssl-cert and mysql-server-ssl-key are provided")
@@ -107,6 +112,7 @@ func registerPluginFlags(fs *pflag.FlagSet) {
 	utils.SetFlagStringVar(fs, &mysqlDefaultWorkloadName, "mysql-default-workload", mysqlDefaultWorkloadName, "Default session workload (OLTP, OLAP, DBA)")
 	fs.BoolVar(&mysqlDrainOnTerm, "mysql-server-drain-onterm", mysqlDrainOnTerm, "If set, the server waits for --onterm-timeout for already connected clients to complete their in flight work")
 	utils.SetFlagBoolVar(fs, &mysqlServerMultiQuery, "mysql-server-multi-query-protocol", mysqlServerMultiQuery, "If set, the server will use the new implementation of handling queries where-in multiple queries are sent together.")
+	utils.SetFlagStringSliceVar(fs, &mysqlServerCompressionAlgorithms, "mysql-server-compression-algorithms", mysqlServerCompressionAlgorithms, "Compression algorithms clients can use on the MySQL protocol TCP listener. Options: zlib, zstd, uncompressed. Compression is disabled by default.")
 }
 
 // vtgateHandler implements the Listener interface.
@@ -118,9 +124,76 @@ type vtgateHandler struct {
 	vtg         *VTGate
 	connections map[uint32]*mysql.Conn
 
+	// processes maps the connection ids to what the connections are doing,
+	// for SHOW PROCESSLIST. It is not guarded by mu, so that the queries
+	// do not contend on it.
+	processes sync.Map
+
 	busyConnections atomic.Int32
 }
 
+// process is what a connection is doing, for SHOW PROCESSLIST.
+type process struct {
+	id   uint32
+	host string
+
+	mu            sync.Mutex
+	user          string
+	db            string
+	command       string
+	query         string
+	since         time.Time
+	shardSessions []*vtgatepb.Session_ShardSession
+}
+
+// start records that the connection started running the query.
+func (p *process) start(command, query string) {
+	if p == nil {
+		return
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.command = command
+	p.query = query
+	p.since = time.Now()
+}
+
+// idle records that the connection is waiting for a command, in the given
+// session.
+func (p *process) idle(user string, session *vtgatepb.Session) {
+	if p == nil {
+		return
+	}
+	var shardSessions []*vtgatepb.Session_ShardSession
+	for _, ss := range session.GetShardSessions() {
+		shardSessions = append(shardSessions, ss.CloneVT())
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.user = user
+	p.db = session.GetTargetString()
+	p.command = "Sleep"
+	p.query = ""
+	p.since = time.Now()
+	p.shardSessions = shardSessions
+}
+
+func (p *process) snapshot(now time.Time) *processlist.Process {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return &processlist.Process{
+		ID:            p.id,
+		User:          p.user,
+		Host:          p.host,
+		DB:            p.db,
+		Command:       p.command,
+		Time:          now.Sub(p.since),
+		Query:         p.query,
+		ShardSessions: p.shardSessions,
+	}
+}
+
 func newVtgateHandler(vtg *VTGate) *vtgateHandler {
 	return &vtgateHandler{
 		vtg:         vtg,
@@ -132,6 +205,51 @@ func (vh *vtgateHandler) NewConnection(c *mysql.Conn) {
 	vh.mu.Lock()
 	defer vh.mu.Unlock()
 	vh.connections[c.ConnectionID] = c
+	vh.processes.Store(c.ConnectionID, &process{
+		id:      c.ConnectionID,
+		host:    c.RemoteAddr().String(),
+		command: "Connect",
+		since:   time.Now(),
+	})
+}
+
+// ConnectionReady is part of the mysql.Handler interface.
+func (vh *vtgateHandler) ConnectionReady(c *mysql.Conn) {
+	vh.process(c).idle(c.User, vh.session(c))
+}
+
+// process returns what the connection is doing, or nil if the connection
+// is unknown.
+func (vh *vtgateHandler) process(c *mysql.Conn) *process {
+	p, ok := vh.processes.Load(c.ConnectionID)
+	if !ok {
+		return nil
+	}
+	return p.(*process)
+}
+
+// startCommand records that the connection runs the query, until the
+// returned function is called.
+func (vh *vtgateHandler) startCommand(c *mysql.Conn, command, query string) func() {
+	p := vh.process(c)
+	p.start(command, query)
+	return func() {
+		p.idle(c.User, vh.session(c))
+	}
+}
+
+// Processes returns the connections to the listener, by connection id.
+func (vh *vtgateHandler) Processes() []*processlist.Process {
+	now := time.Now()
+	var processes []*processlist.Process
+	vh.processes.Range(func(_, p any) bool {
+		processes = append(processes, p.(*process).snapshot(now))
+		return true
+	})
+	sort.Slice(processes, func(i, j int) bool {
+		return processes[i].ID < processes[j].ID
+	})
+	return processes
 }
 
 func (vh *vtgateHandler) numConnections() int {
@@ -150,6 +268,18 @@ func (vh *vtgateHandler) ComResetConnection(c *mysql.Conn) {
 	if err != nil {
 		log.Errorf("Error happened in transaction rollback: %v", err)
 	}
+	vh.process(c).idle(c.User, session)
+}
+
+// ComChangeUser releases the session of the previous user, and starts a
+// new one. The caller id is built from c.User and c.UserData for every
+// query, so the new user is used from the next query on.
+func (vh *vtgateHandler) ComChangeUser(c *mysql.Conn) {
+	vh.ComResetConnection(c)
+	c.ClientData = nil
+	session := vh.session(c)
+	fillInTxStatusFlags(c, session)
+	vh.process(c).idle(c.User, session)
 }
 
 func (vh *vtgateHandler) ConnectionClosed(c *mysql.Conn) {
@@ -158,6 +288,7 @@ func (vh *vtgateHandler) ConnectionClosed(c *mysql.Conn) {
 		vh.mu.Lock()
 		delete(vh.connections, c.ConnectionID)
 		vh.mu.Unlock()
+		vh.processes.Delete(c.ConnectionID)
 	}()
 
 	var ctx context.Context
@@ -178,15 +309,27 @@ func (vh *vtgateHandler) ConnectionClosed(c *mysql.Conn) {
 // Regexp to extract parent span id over the sql query
 var r = regexp.MustCompile(`/\*VT_SPAN_CONTEXT=(.*)\*/`)
 
+// queryAttributeSpanContext is the query attribute that carries the parent
+// span id, for clients that send it as a query attribute instead of a comment.
+const queryAttributeSpanContext = "VT_SPAN_CONTEXT"
+
 // this function is here to make this logic easy to test by decoupling the logic from the `trace.NewSpan` and `trace.NewFromString` functions
-func startSpanTestable(ctx context.Context, query, label string,
+func startSpanTestable(ctx context.Context, query, label string, attributes map[string]*querypb.BindVariable,
 	newSpan func(context.Context, string) (trace.Span, context.Context),
 	newSpanFromString func(context.Context, string, string) (trace.Span, context.Context, error)) (trace.Span, context.Context, error) {
 	_, comments := sqlparser.SplitMarginComments(query)
 	match := r.FindStringSubmatch(comments.Leading)
+	if len(match) == 0 {
+		if spanContext, ok := econtext.QueryAttribute(attributes, queryAttributeSpanContext); ok {
+			match = []string{"", spanContext}
+		}
+	}
 	span, ctx := getSpan(ctx, match, newSpan, label, newSpanFromString)
 
 	trace.AnnotateSQL(span, sqlparser.Preview(query))
+	for name, value := range attributes {
+		span.Annotate("query_attribute."+name, string(value.GetValue()))
+	}
 
 	return span, ctx, nil
 }
@@ -205,8 +348,8 @@ func getSpan(ctx context.Context, match []string, newSpan func(context.Context,
 	return span, ctx
 }
 
-func startSpan(ctx context.Context, query, label string) (trace.Span, context.Context, error) {
-	return startSpanTestable(ctx, query, label, trace.NewSpan, trace.NewFromString)
+func startSpan(ctx context.Context, query, label string, attributes map[string]*querypb.BindVariable) (trace.Span, context.Context, error) {
+	return startSpanTestable(ctx, query, label, attributes, trace.NewSpan, trace.NewFromString)
 }
 
 func (vh *vtgateHandler) ComQuery(c *mysql.Conn, query string, callback func(*sqltypes.Result) error) error {
@@ -215,6 +358,7 @@ func (vh *vtgateHandler) ComQuery(c *mysql.Conn, query string, callback func(*sq
 		c.MarkForClose()
 		return sqlerror.NewSQLError(sqlerror.ERServerShutdown, sqlerror.SSNetError, "Server shutdown in progress")
 	}
+	defer vh.startCommand(c, "Query", query)()
 
 	ctx, cancel := context.WithCancel(context.Background())
 	c.UpdateCancelCtx(cancel)
@@ -224,13 +368,14 @@ func (vh *vtgateHandler) ComQuery(c *mysql.Conn, query string, callback func(*sq
 		defer cancel()
 	}
 
-	span, ctx, err := startSpan(ctx, query, "vtgateHandler.ComQuery")
+	span, ctx, err := startSpan(ctx, query, "vtgateHandler.ComQuery", c.QueryAttributes)
 	if err != nil {
 		return vterrors.Wrap(err, "failed to extract span")
 	}
 	defer span.Finish()
 
 	ctx = callinfo.MysqlCallInfo(ctx, c)
+	ctx = econtext.WithQueryAttributes(ctx, c.QueryAttributes)
 
 	// Fill in the ImmediateCallerID with the UserData returned by
 	// the AuthServer plugin for that user. If nothing was
@@ -277,17 +422,19 @@ func (vh *vtgateHandler) ComQueryMulti(c *mysql.Conn, sql string, callback func(
 		c.MarkForClose()
 		return sqlerror.NewSQLError(sqlerror.ERServerShutdown, sqlerror.SSNetError, "Server shutdown in progress")
 	}
+	defer vh.startCommand(c, "Query", sql)()
 
 	ctx, cancel := context.WithCancel(context.Background())
 	c.UpdateCancelCtx(cancel)
 
-	span, ctx, err := startSpan(ctx, sql, "vtgateHandler.ComQueryMulti")
+	span, ctx, err := startSpan(ctx, sql, "vtgateHandler.ComQueryMulti", c.QueryAttributes)
 	if err != nil {
 		return vterrors.Wrap(err, "failed to extract span")
 	}
 	defer span.Finish()
 
 	ctx = callinfo.MysqlCallInfo(ctx, c)
+	ctx = econtext.WithQueryAttributes(ctx, c.QueryAttributes)
 
 	// Fill in the ImmediateCallerID with the UserData returned by
 	// the AuthServer plugin for that user. If nothing was
@@ -368,6 +515,8 @@ func fillInTxStatusFlags(c *mysql.Conn, session *vtgatepb.Session) {
 
 // ComPrepare is the handler for command prepare.
 func (vh *vtgateHandler) ComPrepare(c *mysql.Conn, query string) ([]*querypb.Field, uint16, error) {
+	defer vh.startCommand(c, "Prepare", query)()
+
 	var ctx context.Context
 	var cancel context.CancelFunc
 	if mysqlQueryTimeout != 0 {
@@ -410,6 +559,8 @@ func (vh *vtgateHandler) ComPrepare(c *mysql.Conn, query string) ([]*querypb.Fie
 }
 
 func (vh *vtgateHandler) ComStmtExecute(c *mysql.Conn, prepare *mysql.PrepareData, callback func(*sqltypes.Result) error) error {
+	defer vh.startCommand(c, "Execute", prepare.PrepareStmt)()
+
 	ctx, cancel := context.WithCancel(context.Background())
 	c.UpdateCancelCtx(cancel)
 
@@ -418,7 +569,14 @@ func (vh *vtgateHandler) ComStmtExecute(c *mysql.Conn, prepare *mysql.PrepareDat
 		defer cancel()
 	}
 
+	span, ctx, err := startSpan(ctx, prepare.PrepareStmt, "vtgateHandler.ComStmtExecute", c.QueryAttributes)
+	if err != nil {
+		return vterrors.Wrap(err, "failed to extract span")
+	}
+	defer span.Finish()
+
 	ctx = callinfo.MysqlCallInfo(ctx, c)
+	ctx = econtext.WithQueryAttributes(ctx, c.QueryAttributes)
 
 	// Fill in the ImmediateCallerID with the UserData returned by
 	// the AuthServer plugin for that user. If nothing was
@@ -607,10 +765,15 @@ func initMySQLProtocol(vtgate *VTGate) *mysqlServer {
 		log.Exitf("-mysql-tcp-version must be one of [tcp, tcp4, tcp6]")
 	}
 
+	compressionCapabilities, err := mysql.CompressionCapabilities(mysqlServerCompressionAlgorithms)
+	if err != nil {
+		log.Exitf("-mysql-server-compression-algorithms: %v", err)
+	}
+
 	// Create a Listener.
-	var err error
 	srv := &mysqlServer{}
 	srv.vtgateHandle = newVtgateHandler(vtgate)
+	vtgate.executor.SetProcessList(srv.vtgateHandle)
 	if mysqlServerPort >= 0 {
 		srv.tcpListener, err = mysql.NewListener(
 			mysqlTCPVersion,
@@ -637,6 +800,7 @@ func initMySQLProtocol(vtgate *VTGate) *mysqlServer {
 			_ = initTLSConfig(context.Background(), srv, mysqlSslCert, mysqlSslKey, mysqlSslCa, mysqlSslCrl, mysqlSslServerCA, mysqlServerRequireSecureTransport, tlsVersion)
 		}
 		srv.tcpListener.AllowClearTextWithoutTLS.Store(mysqlAllowClearTextWithoutTLS)
+		srv.tcpListener.CompressionCapabilities.Store(compressionCapabilities)
 		// Check for the connection threshold
 		if mysqlSlowConnectWarnThreshold != 0 {
 			log.Infof("setting mysql slow connection threshold to %v", mysqlSlowConnectWarnThreshold)
@@ -234,30 +234,44 @@ func newSpanFail(t *testing.T) func(ctx context.Context, label string) (trace.Sp
 }
 
 func TestNoSpanContextPassed(t *testing.T) {
-	_, _, err := startSpanTestable(context.Background(), "sql without comments", "someLabel", newSpanOK, newFromStringFail(t))
+	_, _, err := startSpanTestable(context.Background(), "sql without comments", "someLabel", nil, newSpanOK, newFromStringFail(t))
 	assert.NoError(t, err)
 }
 
 func TestSpanContextNoPassedInButExistsInString(t *testing.T) {
-	_, _, err := startSpanTestable(context.Background(), "SELECT * FROM SOMETABLE WHERE COL = \"/*VT_SPAN_CONTEXT=123*/", "someLabel", newSpanOK, newFromStringFail(t))
+	_, _, err := startSpanTestable(context.Background(), "SELECT * FROM SOMETABLE WHERE COL = \"/*VT_SPAN_CONTEXT=123*/", "someLabel", nil, newSpanOK, newFromStringFail(t))
 	assert.NoError(t, err)
 }
 
 func TestSpanContextPassedIn(t *testing.T) {
-	_, _, err := startSpanTestable(context.Background(), "/*VT_SPAN_CONTEXT=123*/SQL QUERY", "someLabel", newSpanFail(t), newFromStringOK)
+	_, _, err := startSpanTestable(context.Background(), "/*VT_SPAN_CONTEXT=123*/SQL QUERY", "someLabel", nil, newSpanFail(t), newFromStringOK)
 	assert.NoError(t, err)
 }
 
 func TestSpanContextPassedInEvenAroundOtherComments(t *testing.T) {
-	_, _, err := startSpanTestable(context.Background(), "/*VT_SPAN_CONTEXT=123*/SELECT /*vt+ SCATTER_ERRORS_AS_WARNINGS */ col1, col2 FROM TABLE ", "someLabel",
+	_, _, err := startSpanTestable(context.Background(), "/*VT_SPAN_CONTEXT=123*/SELECT /*vt+ SCATTER_ERRORS_AS_WARNINGS */ col1, col2 FROM TABLE ", "someLabel", nil,
 		newSpanFail(t),
 		newFromStringExpect(t, "123"))
 	assert.NoError(t, err)
 }
 
+func TestSpanContextPassedInQueryAttribute(t *testing.T) {
+	attributes := map[string]*querypb.BindVariable{"vt_span_context": sqltypes.StringBindVariable("123")}
+	_, _, err := startSpanTestable(context.Background(), "SQL QUERY", "someLabel", attributes,
+		newSpanFail(t),
+		newFromStringExpect(t, "123"))
+	assert.NoError(t, err)
+
+	// The comment takes precedence.
+	_, _, err = startSpanTestable(context.Background(), "/*VT_SPAN_CONTEXT=456*/SQL QUERY", "someLabel", attributes,
+		newSpanFail(t),
+		newFromStringExpect(t, "456"))
+	assert.NoError(t, err)
+}
+
 func TestSpanContextNotParsable(t *testing.T) {
 	hasRun := false
-	_, _, err := startSpanTestable(context.Background(), "/*VT_SPAN_CONTEXT=123*/SQL QUERY", "someLabel",
+	_, _, err := startSpanTestable(context.Background(), "/*VT_SPAN_CONTEXT=123*/SQL QUERY", "someLabel", nil,
 		func(c context.Context, s string) (trace.Span, context.Context) {
 			hasRun = true
 			return trace.NoopSpan{}, context.Background()
@@ -904,3 +918,100 @@ func TestGracefulShutdownWithTransaction(t *testing.T) {
 
 	require.True(t, mysqlConn.IsMarkedForClose())
 }
+
+func TestComChangeUser(t *testing.T) {
+	executor, _, _, _, _ := createExecutorEnv(t)
+
+	vh := newVtgateHandler(&VTGate{executor: executor, timings: timings, rowsReturned: rowsReturned, rowsAffected: rowsAffected, queryTextCharsProcessed: queryTextCharsProcessed})
+	th := &testHandler{}
+	listener, err := mysql.NewListener("tcp", "127.0.0.1:", mysql.NewAuthServerNone(), th, 0, 0, false, false, 0, 0, false)
+	require.NoError(t, err)
+	defer listener.Close()
+
+	mysqlConn := mysql.GetTestServerConn(listener)
+	mysqlConn.ConnectionID = 1
+	mysqlConn.UserData = &mysql.StaticUserData{}
+	vh.connections[1] = mysqlConn
+
+	err = vh.ComQuery(mysqlConn, "BEGIN", func(result *sqltypes.Result) error {
+		return nil
+	})
+	require.NoError(t, err)
+	session := vh.session(mysqlConn)
+	require.True(t, session.InTransaction)
+	require.EqualValues(t, 1, vh.busyConnections.Load())
+
+	// The transaction is rolled back, and the new user gets a new session.
+	vh.ComChangeUser(mysqlConn)
+	assert.EqualValues(t, 0, vh.busyConnections.Load())
+	newSession := vh.session(mysqlConn)
+	assert.False(t, newSession.InTransaction)
+	assert.NotEqual(t, session.SessionUUID, newSession.SessionUUID)
+	assert.Zero(t, mysqlConn.StatusFlags&mysql.ServerStatusInTrans)
+	assert.NotZero(t, mysqlConn.StatusFlags&mysql.ServerStatusAutocommit)
+}
+
+func TestProcesses(t *testing.T) {
+	executor, _, _, _, _ := createExecutorEnv(t)
+
+	vh := newVtgateHandler(&VTGate{executor: executor, timings: timings, rowsReturned: rowsReturned, rowsAffected: rowsAffected, queryTextCharsProcessed: queryTextCharsProcessed})
+	executor.SetProcessList(vh)
+	th := &testHandler{}
+	listener, err := mysql.NewListener("tcp", "127.0.0.1:", mysql.NewAuthServerNone(), th, 0, 0, false, false, 0, 0, false)
+	require.NoError(t, err)
+	defer listener.Close()
+
+	mysqlConn := mysql.GetTestServerConn(listener)
+	mysqlConn.ConnectionID = 1
+	mysqlConn.User = "user1"
+	mysqlConn.UserData = &mysql.StaticUserData{}
+	vh.NewConnection(mysqlConn)
+	vh.ConnectionReady(mysqlConn)
+
+	processes := vh.Processes()
+	require.Len(t, processes, 1)
+	assert.EqualValues(t, 1, processes[0].ID)
+	assert.Equal(t, "user1", processes[0].User)
+	assert.Equal(t, "Sleep", processes[0].Command)
+	assert.Empty(t, processes[0].Query)
+
+	// The connection running SHOW PROCESSLIST lists itself.
+	qr := &sqltypes.Result{}
+	err = vh.ComQuery(mysqlConn, "show full processlist", func(result *sqltypes.Result) error {
+		if result.Fields != nil {
+			qr.Fields = result.Fields
+		}
+		qr.Rows = append(qr.Rows, result.Rows...)
+		return nil
+	})
+	require.NoError(t, err)
+	require.Len(t, qr.Rows, 1)
+	row := qr.Named().Row()
+	assert.Equal(t, "Query", row.AsString("Command", ""))
+	assert.Equal(t, "show full processlist", row.AsString("Info", ""))
+
+	err = vh.ComQuery(mysqlConn, "begin", func(result *sqltypes.Result) error {
+		return nil
+	})
+	require.NoError(t, err)
+	err = vh.ComQuery(mysqlConn, "insert into user(id, name) values (1, 'foo')", func(result *sqltypes.Result) error {
+		return nil
+	})
+	require.NoError(t, err)
+	processes = vh.Processes()
+	require.Len(t, processes, 1)
+	assert.Equal(t, "Sleep", processes[0].Command)
+	assert.NotEmpty(t, processes[0].ShardSessions)
+
+	// COM_CHANGE_USER switches c.User before calling the handler, the
+	// connection is then listed for the new user.
+	mysqlConn.User = "user2"
+	vh.ComChangeUser(mysqlConn)
+	processes = vh.Processes()
+	require.Len(t, processes, 1)
+	assert.Equal(t, "user2", processes[0].User)
+	assert.Empty(t, processes[0].ShardSessions)
+
+	vh.ConnectionClosed(mysqlConn)
+	assert.Empty(t, vh.Processes())
+}
@@ -0,0 +1,280 @@
+/*
+Copyright 2026 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package processlist lists the client connections of vtgate, for SHOW
+// PROCESSLIST and SHOW VITESS_PROCESSLIST.
+package processlist
+
+import (
+	"strings"
+	"time"
+
+	"vitess.io/vitess/go/mysql/collations"
+	"vitess.io/vitess/go/sqltypes"
+	querypb "vitess.io/vitess/go/vt/proto/query"
+	vtgatepb "vitess.io/vitess/go/vt/proto/vtgate"
+	"vitess.io/vitess/go/vt/topo/topoproto"
+)
+
+// truncatedQueryLength is the length of the queries listed by SHOW
+// PROCESSLIST without FULL, as in MySQL.
+const truncatedQueryLength = 100
+
+// fields are the columns of SHOW PROCESSLIST. They are the ones of MySQL,
+// plus the shard sessions of the connection.
+var fields = []*querypb.Field{
+	uint64Field("Id"),
+	varCharField("User"),
+	varCharField("Host"),
+	varCharField("db"),
+	varCharField("Command"),
+	int64Field("Time"),
+	varCharField("State"),
+	varCharField("Info"),
+	varCharField("ShardSessions"),
+}
+
+// vitessFields are the columns of SHOW VITESS_PROCESSLIST, which has a row
+// per shard session of the connections.
+var vitessFields = []*querypb.Field{
+	uint64Field("Id"),
+	varCharField("User"),
+	varCharField("Host"),
+	varCharField("db"),
+	varCharField("Command"),
+	int64Field("Time"),
+	varCharField("Info"),
+	varCharField("Keyspace"),
+	varCharField("Shard"),
+	varCharField("TabletType"),
+	varCharField("TabletAlias"),
+	int64Field("TransactionID"),
+	int64Field("ReservedID"),
+	varCharField("ShardQuery"),
+	int64Field("ShardQueryTime"),
+}
+
+func varCharField(name string) *querypb.Field {
+	return &querypb.Field{Name: name, Type: sqltypes.VarChar, Charset: uint32(collations.SystemCollation.Collation)}
+}
+
+func int64Field(name string) *querypb.Field {
+	return &querypb.Field{Name: name, Type: sqltypes.Int64, Charset: collations.CollationBinaryID, Flags: uint32(querypb.MySqlFlag_NUM_FLAG)}
+}
+
+func uint64Field(name string) *querypb.Field {
+	return &querypb.Field{Name: name, Type: sqltypes.Uint64, Charset: collations.CollationBinaryID, Flags: uint32(querypb.MySqlFlag_NUM_FLAG | querypb.MySqlFlag_UNSIGNED_FLAG)}
+}
+
+func cloneFields(fields []*querypb.Field) []*querypb.Field {
+	clones := make([]*querypb.Field, len(fields))
+	for i, field := range fields {
+		clones[i] = field.CloneVT()
+	}
+	return clones
+}
+
+func columnIndex(fields []*querypb.Field, name string) int {
+	for i, field := range fields {
+		if strings.EqualFold(field.Name, name) {
+			return i
+		}
+	}
+	return -1
+}
+
+// Fields returns the columns of the rows returned by Rows.
+func Fields() []*querypb.Field {
+	return cloneFields(fields)
+}
+
+// ColumnIndex returns the index of the named column in Fields, or -1.
+func ColumnIndex(name string) int {
+	return columnIndex(fields, name)
+}
+
+// VitessFields returns the columns of the rows returned by VitessRows.
+func VitessFields() []*querypb.Field {
+	return cloneFields(vitessFields)
+}
+
+// VitessColumnIndex returns the index of the named column in VitessFields,
+// or -1.
+func VitessColumnIndex(name string) int {
+	return columnIndex(vitessFields, name)
+}
+
+// Process is a client connection of vtgate.
+type Process struct {
+	ID   uint32
+	User string
+	Host string
+	DB   string
+	// Command is what the connection is doing, e.g. Query or Sleep.
+	Command string
+	// Time is how long the connection has been doing it.
+	Time time.Duration
+	// Query is the query being run by the connection, if any.
+	Query string
+	// ShardSessions are the transactions and reserved connections of the
+	// connection on the vttablets.
+	ShardSessions []*vtgatepb.Session_ShardSession
+}
+
+// Lister lists the client connections of vtgate, by connection id.
+type Lister interface {
+	Processes() []*Process
+}
+
+// Authorized returns true if the caller can list the connections of all
+// the users: it is one of the authorized users, or they contain '%'.
+func Authorized(caller *querypb.VTGateCallerID, authorizedUsers []string) bool {
+	username := caller.GetUsername()
+	for _, user := range authorizedUsers {
+		if user == "%" || (user != "" && user == username) {
+			return true
+		}
+	}
+	return false
+}
+
+// OfUser returns the processes of the MySQL user.
+func OfUser(processes []*Process, user string) []*Process {
+	var owned []*Process
+	for _, p := range processes {
+		if p.User == user {
+			owned = append(owned, p)
+		}
+	}
+	return owned
+}
+
+// ShardQueryKey identifies a transaction or reserved connection on a vttablet.
+type ShardQueryKey struct {
+	TabletAlias string
+	ID          int64
+}
+
+// ShardQuery is a query running on a vttablet, as listed by its SHOW
+// VITESS_PROCESSLIST.
+type ShardQuery struct {
+	Query string
+	Time  time.Duration
+}
+
+func (p *Process) state() string {
+	if p.Command == "Sleep" {
+		return ""
+	}
+	return "executing"
+}
+
+func (p *Process) info(full bool) sqltypes.Value {
+	if p.Query == "" {
+		return sqltypes.NULL
+	}
+	if !full && len(p.Query) > truncatedQueryLength {
+		return sqltypes.NewVarChar(p.Query[:truncatedQueryLength])
+	}
+	return sqltypes.NewVarChar(p.Query)
+}
+
+func (p *Process) shardSessions() string {
+	targets := make([]string, 0, len(p.ShardSessions))
+	for _, ss := range p.ShardSessions {
+		target := ss.GetTarget()
+		targets = append(targets, target.GetKeyspace()+"/"+target.GetShard()+"@"+topoproto.TabletTypeLString(target.GetTabletType()))
+	}
+	return strings.Join(targets, ",")
+}
+
+// Rows returns the processes as rows of SHOW PROCESSLIST. The queries are
+// truncated unless full is set.
+func Rows(processes []*Process, full bool) [][]sqltypes.Value {
+	rows := make([][]sqltypes.Value, 0, len(processes))
+	for _, p := range processes {
+		rows = append(rows, []sqltypes.Value{
+			sqltypes.NewUint64(uint64(p.ID)),
+			sqltypes.NewVarChar(p.User),
+			sqltypes.NewVarChar(p.Host),
+			sqltypes.NewVarChar(p.DB),
+			sqltypes.NewVarChar(p.Command),
+			sqltypes.NewInt64(int64(p.Time.Seconds())),
+			sqltypes.NewVarChar(p.state()),
+			p.info(full),
+			sqltypes.NewVarChar(p.shardSessions()),
+		})
+	}
+	return rows
+}
+
+// VitessRows returns the processes as rows of SHOW VITESS_PROCESSLIST: a row
+// per shard session, with the query it is running from shardQueries, or a
+// single row for the processes without shard sessions.
+func VitessRows(processes []*Process, shardQueries map[ShardQueryKey]ShardQuery) [][]sqltypes.Value {
+	var rows [][]sqltypes.Value
+	for _, p := range processes {
+		process := []sqltypes.Value{
+			sqltypes.NewUint64(uint64(p.ID)),
+			sqltypes.NewVarChar(p.User),
+			sqltypes.NewVarChar(p.Host),
+			sqltypes.NewVarChar(p.DB),
+			sqltypes.NewVarChar(p.Command),
+			sqltypes.NewInt64(int64(p.Time.Seconds())),
+			p.info(true),
+		}
+		if len(p.ShardSessions) == 0 {
+			rows = append(rows, append(process, sqltypes.NULL, sqltypes.NULL, sqltypes.NULL, sqltypes.NULL, sqltypes.NULL, sqltypes.NULL, sqltypes.NULL, sqltypes.NULL))
+			continue
+		}
+		for _, ss := range p.ShardSessions {
+			target := ss.GetTarget()
+			alias := sqltypes.NULL
+			if ss.TabletAlias != nil {
+				alias = sqltypes.NewVarChar(topoproto.TabletAliasString(ss.TabletAlias))
+			}
+			row := append(process[:len(process):len(process)],
+				sqltypes.NewVarChar(target.GetKeyspace()),
+				sqltypes.NewVarChar(target.GetShard()),
+				sqltypes.NewVarChar(topoproto.TabletTypeLString(target.GetTabletType())),
+				alias,
+				sqltypes.NewInt64(ss.TransactionId),
+				sqltypes.NewInt64(ss.ReservedId),
+			)
+			if query, ok := shardQueries[ShardQueryKeyOf(ss)]; ok {
+				row = append(row, sqltypes.NewVarChar(query.Query), sqltypes.NewInt64(int64(query.Time.Seconds())))
+			} else {
+				row = append(row, sqltypes.NULL, sqltypes.NULL)
+			}
+			rows = append(rows, row)
+		}
+	}
+	return rows
+}
+
+// ShardQueryKeyOf returns the key of the queries of the shard session. The
+// transaction and the reserved connection of a shard session share the same
+// id on the vttablet.
+func ShardQueryKeyOf(ss *vtgatepb.Session_ShardSession) ShardQueryKey {
+	id := ss.TransactionId
+	if id == 0 {
+		id = ss.ReservedId
+	}
+	return ShardQueryKey{
+		TabletAlias: topoproto.TabletAliasString(ss.TabletAlias),
+		ID:          id,
+	}
+}
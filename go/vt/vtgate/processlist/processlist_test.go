@@ -0,0 +1,124 @@
+/*
+Copyright 2026 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package processlist
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"vitess.io/vitess/go/sqltypes"
+	querypb "vitess.io/vitess/go/vt/proto/query"
+	topodatapb "vitess.io/vitess/go/vt/proto/topodata"
+	vtgatepb "vitess.io/vitess/go/vt/proto/vtgate"
+)
+
+func TestRows(t *testing.T) {
+	long := "select " + strings.Repeat("a", 200)
+	processes := []*Process{{
+		ID:      1,
+		User:    "user",
+		Host:    "localhost:1",
+		DB:      "ks",
+		Command: "Query",
+		Time:    1500 * time.Millisecond,
+		Query:   long,
+		ShardSessions: []*vtgatepb.Session_ShardSession{
+			{Target: &querypb.Target{Keyspace: "ks", Shard: "-80", TabletType: topodatapb.TabletType_PRIMARY}},
+			{Target: &querypb.Target{Keyspace: "ks", Shard: "80-", TabletType: topodatapb.TabletType_REPLICA}},
+		},
+	}, {
+		ID:      2,
+		Command: "Sleep",
+	}}
+
+	rows := Rows(processes, false)
+	require.Len(t, rows, 2)
+	require.Len(t, rows[0], len(Fields()))
+	assert.Equal(t, sqltypes.NewUint64(1), rows[0][ColumnIndex("id")])
+	assert.Equal(t, sqltypes.NewInt64(1), rows[0][ColumnIndex("Time")])
+	assert.Equal(t, "executing", rows[0][ColumnIndex("State")].ToString())
+	assert.Equal(t, long[:truncatedQueryLength], rows[0][ColumnIndex("Info")].ToString())
+	assert.Equal(t, "ks/-80@primary,ks/80-@replica", rows[0][ColumnIndex("ShardSessions")].ToString())
+	assert.Equal(t, "", rows[1][ColumnIndex("State")].ToString())
+	assert.True(t, rows[1][ColumnIndex("Info")].IsNull())
+
+	rows = Rows(processes, true)
+	assert.Equal(t, long, rows[0][ColumnIndex("Info")].ToString())
+
+	assert.Equal(t, -1, ColumnIndex("Keyspace"))
+}
+
+func TestVitessRows(t *testing.T) {
+	processes := []*Process{{
+		ID:      1,
+		Command: "Query",
+		Query:   "select 1",
+		ShardSessions: []*vtgatepb.Session_ShardSession{{
+			Target:        &querypb.Target{Keyspace: "ks", Shard: "-80", TabletType: topodatapb.TabletType_PRIMARY},
+			TransactionId: 10,
+			ReservedId:    10,
+			TabletAlias:   &topodatapb.TabletAlias{Cell: "zone1", Uid: 100},
+		}, {
+			Target:     &querypb.Target{Keyspace: "ks", Shard: "80-", TabletType: topodatapb.TabletType_PRIMARY},
+			ReservedId: 20,
+		}},
+	}, {
+		ID:      2,
+		Command: "Sleep",
+	}}
+	shardQueries := map[ShardQueryKey]ShardQuery{
+		ShardQueryKeyOf(processes[0].ShardSessions[0]): {Query: "select 1 from t", Time: 3 * time.Second},
+	}
+
+	rows := VitessRows(processes, shardQueries)
+	require.Len(t, rows, 3)
+	for _, row := range rows {
+		require.Len(t, row, len(VitessFields()))
+	}
+	assert.Equal(t, "-80", rows[0][VitessColumnIndex("Shard")].ToString())
+	assert.Equal(t, "zone1-0000000100", rows[0][VitessColumnIndex("TabletAlias")].ToString())
+	assert.Equal(t, "select 1 from t", rows[0][VitessColumnIndex("ShardQuery")].ToString())
+	assert.Equal(t, sqltypes.NewInt64(3), rows[0][VitessColumnIndex("ShardQueryTime")])
+	assert.Equal(t, "80-", rows[1][VitessColumnIndex("Shard")].ToString())
+	assert.True(t, rows[1][VitessColumnIndex("TabletAlias")].IsNull())
+	assert.Equal(t, sqltypes.NewInt64(20), rows[1][VitessColumnIndex("ReservedID")])
+	assert.True(t, rows[1][VitessColumnIndex("ShardQuery")].IsNull())
+	assert.Equal(t, sqltypes.NewUint64(2), rows[2][VitessColumnIndex("Id")])
+	assert.True(t, rows[2][VitessColumnIndex("Keyspace")].IsNull())
+}
+
+func TestAuthorized(t *testing.T) {
+	alice := &querypb.VTGateCallerID{Username: "alice"}
+	assert.False(t, Authorized(alice, nil))
+	assert.False(t, Authorized(alice, []string{"bob"}))
+	assert.True(t, Authorized(alice, []string{"bob", "alice"}))
+	assert.True(t, Authorized(alice, []string{"%"}))
+	assert.False(t, Authorized(nil, []string{""}))
+}
+
+func TestOfUser(t *testing.T) {
+	processes := []*Process{{ID: 1, User: "alice"}, {ID: 2, User: "bob"}, {ID: 3, User: "alice"}}
+	owned := OfUser(processes, "alice")
+	require.Len(t, owned, 2)
+	assert.EqualValues(t, 1, owned[0].ID)
+	assert.EqualValues(t, 3, owned[1].ID)
+	assert.Empty(t, OfUser(processes, "carol"))
+}
@@ -0,0 +1,323 @@
+/*
+Copyright 2026 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package querystats aggregates the statistics of the queries executed by
+// vtgate per digest, i.e. per normalized query and keyspace.
+package querystats
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"math"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"vitess.io/vitess/go/mysql/collations"
+	"vitess.io/vitess/go/sqltypes"
+	querypb "vitess.io/vitess/go/vt/proto/query"
+)
+
+const (
+	// The latencies are counted in exponential buckets, from 10µs up to
+	// about 7 minutes, each 25% larger than the previous one.
+	minLatencyBucket   = 10 * time.Microsecond
+	latencyBucketRatio = 1.25
+	latencyBuckets     = 80
+)
+
+// latencyBounds are the upper bounds of the latency buckets.
+var latencyBounds = func() [latencyBuckets]time.Duration {
+	var bounds [latencyBuckets]time.Duration
+	for i := range bounds {
+		bounds[i] = time.Duration(float64(minLatencyBucket) * math.Pow(latencyBucketRatio, float64(i)))
+	}
+	return bounds
+}()
+
+// fields are the columns of SHOW VITESS_QUERY_STATS.
+var fields = []*querypb.Field{
+	varCharField("Keyspace"),
+	varCharField("Digest"),
+	varCharField("Query"),
+	varCharField("PlanType"),
+	uint64Field("Calls"),
+	uint64Field("Errors"),
+	float64Field("TotalLatency"),
+	float64Field("MinLatency"),
+	float64Field("MaxLatency"),
+	float64Field("P99Latency"),
+	uint64Field("RowsReturned"),
+	uint64Field("RowsAffected"),
+	uint64Field("ShardQueries"),
+	varCharField("FirstSeen"),
+	varCharField("LastSeen"),
+}
+
+func varCharField(name string) *querypb.Field {
+	return &querypb.Field{Name: name, Type: sqltypes.VarChar, Charset: uint32(collations.SystemCollation.Collation)}
+}
+
+func uint64Field(name string) *querypb.Field {
+	return &querypb.Field{Name: name, Type: sqltypes.Uint64, Charset: collations.CollationBinaryID, Flags: uint32(querypb.MySqlFlag_NUM_FLAG | querypb.MySqlFlag_UNSIGNED_FLAG)}
+}
+
+func float64Field(name string) *querypb.Field {
+	return &querypb.Field{Name: name, Type: sqltypes.Float64, Charset: collations.CollationBinaryID, Flags: uint32(querypb.MySqlFlag_NUM_FLAG)}
+}
+
+// Fields returns the columns of the rows returned by Digest.Row.
+func Fields() []*querypb.Field {
+	clones := make([]*querypb.Field, len(fields))
+	for i, field := range fields {
+		clones[i] = field.CloneVT()
+	}
+	return clones
+}
+
+// ColumnIndex returns the index of the named column in Fields, or -1.
+func ColumnIndex(name string) int {
+	for i, field := range fields {
+		if strings.EqualFold(field.Name, name) {
+			return i
+		}
+	}
+	return -1
+}
+
+// Execution is a query execution to add to the statistics.
+type Execution struct {
+	Keyspace     string
+	Query        string
+	PlanType     string
+	Latency      time.Duration
+	RowsReturned uint64
+	RowsAffected uint64
+	ShardQueries uint64
+	Failed       bool
+}
+
+// Digest is the statistics of the executions of a normalized query in a
+// keyspace.
+type Digest struct {
+	Keyspace string
+	// Query is the normalized query. It is empty for the digest aggregating
+	// the queries that did not fit in the statistics.
+	Query string
+	// Digest is a hash of the normalized query.
+	Digest string
+	// PlanType is the type of the last plan that executed the query.
+	PlanType string
+
+	Calls        uint64
+	Errors       uint64
+	TotalLatency time.Duration
+	MinLatency   time.Duration
+	MaxLatency   time.Duration
+	RowsReturned uint64
+	RowsAffected uint64
+	ShardQueries uint64
+	FirstSeen    time.Time
+	LastSeen     time.Time
+
+	latencies [latencyBuckets]uint64
+}
+
+// Percentile returns an estimate of the given percentile, between 0 and 1,
+// of the latencies of the query.
+func (d *Digest) Percentile(p float64) time.Duration {
+	if d.Calls == 0 {
+		return 0
+	}
+	rank := uint64(math.Ceil(p * float64(d.Calls)))
+	var seen uint64
+	for i, count := range d.latencies {
+		seen += count
+		if seen >= rank {
+			return min(latencyBounds[i], d.MaxLatency)
+		}
+	}
+	return d.MaxLatency
+}
+
+// Row returns the statistics of the digest as a row of SHOW
+// VITESS_QUERY_STATS. The latencies are in seconds.
+func (d *Digest) Row() []sqltypes.Value {
+	query, digest := sqltypes.NULL, sqltypes.NULL
+	if d.Query != "" {
+		query, digest = sqltypes.NewVarChar(d.Query), sqltypes.NewVarChar(d.Digest)
+	}
+	return []sqltypes.Value{
+		sqltypes.NewVarChar(d.Keyspace),
+		digest,
+		query,
+		sqltypes.NewVarChar(d.PlanType),
+		sqltypes.NewUint64(d.Calls),
+		sqltypes.NewUint64(d.Errors),
+		sqltypes.NewFloat64(d.TotalLatency.Seconds()),
+		sqltypes.NewFloat64(d.MinLatency.Seconds()),
+		sqltypes.NewFloat64(d.MaxLatency.Seconds()),
+		sqltypes.NewFloat64(d.Percentile(0.99).Seconds()),
+		sqltypes.NewUint64(d.RowsReturned),
+		sqltypes.NewUint64(d.RowsAffected),
+		sqltypes.NewUint64(d.ShardQueries),
+		sqltypes.NewVarChar(d.FirstSeen.UTC().Format(time.RFC3339)),
+		sqltypes.NewVarChar(d.LastSeen.UTC().Format(time.RFC3339)),
+	}
+}
+
+func (d *Digest) add(exec *Execution, now time.Time) {
+	if d.Calls == 0 || exec.Latency < d.MinLatency {
+		d.MinLatency = exec.Latency
+	}
+	if exec.Latency > d.MaxLatency {
+		d.MaxLatency = exec.Latency
+	}
+	if d.FirstSeen.IsZero() {
+		d.FirstSeen = now
+	}
+	d.LastSeen = now
+	d.Calls++
+	if exec.Failed {
+		d.Errors++
+	}
+	d.PlanType = exec.PlanType
+	d.TotalLatency += exec.Latency
+	d.RowsReturned += exec.RowsReturned
+	d.RowsAffected += exec.RowsAffected
+	d.ShardQueries += exec.ShardQueries
+
+	bucket := sort.Search(latencyBuckets-1, func(i int) bool { return latencyBounds[i] >= exec.Latency })
+	d.latencies[bucket]++
+}
+
+type digestKey struct {
+	keyspace string
+	query    string
+}
+
+type digest struct {
+	mu sync.Mutex
+	Digest
+}
+
+// Stats aggregates query executions per digest. At most maxDigests digests
+// are tracked: the executions of the other queries are aggregated in a
+// single digest with an empty query. A nil *Stats ignores the executions.
+type Stats struct {
+	maxDigests int
+
+	mu       sync.RWMutex
+	digests  map[digestKey]*digest
+	overflow *digest
+}
+
+// New returns a Stats tracking up to maxDigests digests, or nil if
+// maxDigests is not positive.
+func New(maxDigests int) *Stats {
+	if maxDigests <= 0 {
+		return nil
+	}
+	return &Stats{
+		maxDigests: maxDigests,
+		digests:    make(map[digestKey]*digest),
+	}
+}
+
+// Record adds a query execution to the statistics.
+func (s *Stats) Record(exec *Execution) {
+	if s == nil {
+		return
+	}
+	key := digestKey{keyspace: exec.Keyspace, query: exec.Query}
+
+	s.mu.RLock()
+	d, ok := s.digests[key]
+	s.mu.RUnlock()
+	if !ok {
+		d = s.newDigest(key)
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.add(exec, time.Now())
+}
+
+func (s *Stats) newDigest(key digestKey) *digest {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if d, ok := s.digests[key]; ok {
+		return d
+	}
+	if len(s.digests) >= s.maxDigests {
+		if s.overflow == nil {
+			s.overflow = &digest{}
+		}
+		return s.overflow
+	}
+
+	hash := sha256.Sum256([]byte(key.query))
+	d := &digest{Digest: Digest{
+		Keyspace: key.keyspace,
+		Query:    key.query,
+		Digest:   hex.EncodeToString(hash[:16]),
+	}}
+	s.digests[key] = d
+	return d
+}
+
+// Digests returns a copy of the statistics of all the digests, from the
+// largest total latency to the smallest.
+func (s *Stats) Digests() []Digest {
+	if s == nil {
+		return nil
+	}
+	s.mu.RLock()
+	all := make([]*digest, 0, len(s.digests)+1)
+	for _, d := range s.digests {
+		all = append(all, d)
+	}
+	if s.overflow != nil {
+		all = append(all, s.overflow)
+	}
+	s.mu.RUnlock()
+
+	digests := make([]Digest, 0, len(all))
+	for _, d := range all {
+		d.mu.Lock()
+		digests = append(digests, d.Digest)
+		d.mu.Unlock()
+	}
+	sort.Slice(digests, func(i, j int) bool {
+		if digests[i].TotalLatency != digests[j].TotalLatency {
+			return digests[i].TotalLatency > digests[j].TotalLatency
+		}
+		return digests[i].Query < digests[j].Query
+	})
+	return digests
+}
+
+// Reset drops the statistics of all the digests.
+func (s *Stats) Reset() {
+	if s == nil {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.digests = make(map[digestKey]*digest)
+	s.overflow = nil
+}
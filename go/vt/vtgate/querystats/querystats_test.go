@@ -0,0 +1,129 @@
+/*
+Copyright 2026 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package querystats
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRecord(t *testing.T) {
+	s := New(10)
+	for i := 1; i <= 100; i++ {
+		s.Record(&Execution{
+			Keyspace:     "ks",
+			Query:        "select * from t where id = :id",
+			PlanType:     "Passthrough",
+			Latency:      time.Duration(i) * time.Millisecond,
+			RowsReturned: 1,
+			ShardQueries: 1,
+			Failed:       i%10 == 0,
+		})
+	}
+	s.Record(&Execution{Keyspace: "ks", Query: "delete from t", PlanType: "Scatter", Latency: time.Second, RowsAffected: 5, ShardQueries: 4})
+	s.Record(&Execution{Keyspace: "other", Query: "delete from t", PlanType: "Passthrough", Latency: time.Millisecond, RowsAffected: 1, ShardQueries: 1})
+
+	digests := s.Digests()
+	require.Len(t, digests, 3)
+
+	d := digests[0]
+	assert.Equal(t, "ks", d.Keyspace)
+	assert.Equal(t, "select * from t where id = :id", d.Query)
+	assert.Len(t, d.Digest, 32)
+	assert.Equal(t, "Passthrough", d.PlanType)
+	assert.EqualValues(t, 100, d.Calls)
+	assert.EqualValues(t, 10, d.Errors)
+	assert.Equal(t, 5050*time.Millisecond, d.TotalLatency)
+	assert.Equal(t, time.Millisecond, d.MinLatency)
+	assert.Equal(t, 100*time.Millisecond, d.MaxLatency)
+	assert.EqualValues(t, 100, d.RowsReturned)
+	assert.EqualValues(t, 100, d.ShardQueries)
+	assert.False(t, d.FirstSeen.After(d.LastSeen))
+	// The percentiles are estimated within a bucket, i.e. 25%.
+	assert.InDelta(t, 99*time.Millisecond, d.Percentile(0.99), float64(25*time.Millisecond))
+	assert.InDelta(t, 50*time.Millisecond, d.Percentile(0.5), float64(13*time.Millisecond))
+	assert.Equal(t, d.MaxLatency, d.Percentile(1))
+
+	assert.Equal(t, "delete from t", digests[1].Query)
+	assert.Equal(t, "ks", digests[1].Keyspace)
+	assert.EqualValues(t, 5, digests[1].RowsAffected)
+	assert.Equal(t, time.Second, digests[1].Percentile(0.99))
+	assert.Equal(t, "other", digests[2].Keyspace)
+	assert.Equal(t, digests[1].Digest, digests[2].Digest)
+}
+
+func TestOverflow(t *testing.T) {
+	s := New(2)
+	for _, query := range []string{"select 1", "select 2", "select 3", "select 4"} {
+		s.Record(&Execution{Keyspace: "ks", Query: query, Latency: time.Millisecond})
+	}
+	s.Record(&Execution{Keyspace: "ks", Query: "select 1", Latency: 2 * time.Millisecond})
+
+	digests := s.Digests()
+	require.Len(t, digests, 3)
+	assert.Equal(t, "select 1", digests[0].Query)
+	assert.EqualValues(t, 2, digests[0].Calls)
+	assert.Empty(t, digests[1].Query)
+	assert.Empty(t, digests[1].Digest)
+	assert.EqualValues(t, 2, digests[1].Calls)
+	assert.Equal(t, "select 2", digests[2].Query)
+}
+
+func TestReset(t *testing.T) {
+	s := New(1)
+	s.Record(&Execution{Keyspace: "ks", Query: "select 1"})
+	s.Record(&Execution{Keyspace: "ks", Query: "select 2"})
+	require.Len(t, s.Digests(), 2)
+
+	s.Reset()
+	assert.Empty(t, s.Digests())
+	s.Record(&Execution{Keyspace: "ks", Query: "select 2"})
+	digests := s.Digests()
+	require.Len(t, digests, 1)
+	assert.Equal(t, "select 2", digests[0].Query)
+}
+
+func TestDisabled(t *testing.T) {
+	s := New(0)
+	assert.Nil(t, s)
+	s.Record(&Execution{Keyspace: "ks", Query: "select 1"})
+	s.Reset()
+	assert.Empty(t, s.Digests())
+}
+
+func TestConcurrentRecord(t *testing.T) {
+	s := New(10)
+	var wg sync.WaitGroup
+	for range 10 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for range 1000 {
+				s.Record(&Execution{Keyspace: "ks", Query: "select 1", Latency: time.Millisecond})
+			}
+		}()
+	}
+	wg.Wait()
+
+	digests := s.Digests()
+	require.Len(t, digests, 1)
+	assert.EqualValues(t, 10000, digests[0].Calls)
+}
@@ -345,6 +345,30 @@ func (sct *sandboxTopo) WatchSrvVSchema(ctx context.Context, cell string, callba
 	}()
 }
 
+// WatchTenantDirectory is part of the srvtopo.Server interface.
+func (sct *sandboxTopo) WatchTenantDirectory(ctx context.Context, keyspace, name string, callback func(*vschemapb.TenantDirectory, error) bool) {
+	if sct.topoServer == nil {
+		callback(nil, topo.NewError(topo.NoNode, keyspace+"/"+name))
+		return
+	}
+
+	current, updateChan, err := sct.topoServer.WatchTenantDirectory(ctx, keyspace, name)
+	if err != nil {
+		callback(nil, err)
+		return
+	}
+	if !callback(current.Value, nil) {
+		return
+	}
+	go func() {
+		for update := range updateChan {
+			if !callback(update.Value, update.Err) || update.Err != nil {
+				return
+			}
+		}
+	}()
+}
+
 func sandboxDialer(ctx context.Context, tablet *topodatapb.Tablet, failFast grpcclient.FailFast) (queryservice.QueryService, error) {
 	sand := getSandbox(tablet.Keyspace)
 	sand.sandmu.Lock()
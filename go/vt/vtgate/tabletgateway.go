@@ -24,6 +24,7 @@ import (
 	"runtime/debug"
 	"slices"
 	"sort"
+	"strings"
 	"sync"
 	"sync/atomic"
 	"time"
@@ -31,6 +32,7 @@ import (
 	"github.com/spf13/pflag"
 
 	"vitess.io/vitess/go/mysql/collations"
+	"vitess.io/vitess/go/stats"
 	"vitess.io/vitess/go/vt/discovery"
 	"vitess.io/vitess/go/vt/log"
 	"vitess.io/vitess/go/vt/logutil"
@@ -41,6 +43,7 @@ import (
 	"vitess.io/vitess/go/vt/vterrors"
 	"vitess.io/vitess/go/vt/vtgate/balancer"
 	"vitess.io/vitess/go/vt/vtgate/buffer"
+	econtext "vitess.io/vitess/go/vt/vtgate/executorcontext"
 	"vitess.io/vitess/go/vt/vttablet/queryservice"
 
 	querypb "vitess.io/vitess/go/vt/proto/query"
@@ -48,6 +51,13 @@ import (
 	vtrpcpb "vitess.io/vitess/go/vt/proto/vtrpc"
 )
 
+const (
+	// balancerModeCell spreads the load according to the cells of the vtgates and tablets.
+	balancerModeCell = "cell"
+	// balancerModeLatency prefers the tablets with the lowest observed latency and in-flight queries.
+	balancerModeLatency = "latency"
+)
+
 var (
 	_ discovery.HealthCheck = (*discovery.HealthCheckImpl)(nil)
 	// CellsToWatch is the list of cells the healthcheck operates over. If it is empty, only the local cell is watched
@@ -61,6 +71,16 @@ var (
 	balancerEnabled     bool
 	balancerVtgateCells []string
 	balancerKeyspaces   []string
+	balancerMode        = balancerModeCell
+
+	// maxReplicaLagFallbackToPrimary sends the reads to the primary when no
+	// replica is within the max_replica_lag of the session.
+	maxReplicaLagFallbackToPrimary = true
+
+	maxReplicaLagFallbacks = stats.NewCountersWithMultiLabels(
+		"MaxReplicaLagFallbacks",
+		"Reads for which no replica was within the requested maximum replication lag, by fallback",
+		[]string{"Keyspace", "ShardName", "DbType", "Fallback"})
 
 	logCollations = logutil.NewThrottledLogger("CollationInconsistent", 1*time.Minute)
 )
@@ -73,6 +93,8 @@ func init() {
 		fs.BoolVar(&balancerEnabled, "enable-balancer", false, "Enable the tablet balancer to evenly spread query load for a given tablet type")
 		fs.StringSliceVar(&balancerVtgateCells, "balancer-vtgate-cells", []string{}, "When in balanced mode, a comma-separated list of cells that contain vtgates (required)")
 		fs.StringSliceVar(&balancerKeyspaces, "balancer-keyspaces", []string{}, "When in balanced mode, a comma-separated list of keyspaces for which to use the balancer (optional)")
+		fs.StringVar(&balancerMode, "balancer-mode", balancerMode, "When in balanced mode, how to pick tablets: 'cell' to spread the load according to the cells of the vtgates and tablets, or 'latency' to prefer the tablets with the lowest observed latency and in-flight queries")
+		fs.BoolVar(&maxReplicaLagFallbackToPrimary, "max-replica-lag-fallback-to-primary", maxReplicaLagFallbackToPrimary, "When no replica is within the max_replica_lag of a session, send its reads to the primary instead of failing them")
 	})
 }
 
@@ -167,10 +189,17 @@ func (gw *TabletGateway) setupBuffering(ctx context.Context) {
 }
 
 func (gw *TabletGateway) setupBalancer(ctx context.Context) {
-	if len(balancerVtgateCells) == 0 {
-		log.Exitf("balancer-vtgate-cells is required for balanced mode")
+	switch balancerMode {
+	case balancerModeCell:
+		if len(balancerVtgateCells) == 0 {
+			log.Exitf("balancer-vtgate-cells is required for balanced mode")
+		}
+		gw.balancer = balancer.NewTabletBalancer(gw.localCell, balancerVtgateCells)
+	case balancerModeLatency:
+		gw.balancer = balancer.NewLatencyBalancer()
+	default:
+		log.Exitf("unknown balancer-mode %q, expected %q or %q", balancerMode, balancerModeCell, balancerModeLatency)
 	}
-	gw.balancer = balancer.NewTabletBalancer(gw.localCell, balancerVtgateCells)
 }
 
 // QueryServiceByAlias satisfies the Gateway interface
@@ -279,7 +308,7 @@ func (gw *TabletGateway) DebugBalancerHandler(w http.ResponseWriter, r *http.Req
 // withRetry also adds shard information to errors returned from the inner QueryService, so
 // withShardError should not be combined with withRetry.
 func (gw *TabletGateway) withRetry(ctx context.Context, target *querypb.Target, _ queryservice.QueryService,
-	_ string, inTransaction bool, inner func(ctx context.Context, target *querypb.Target, conn queryservice.QueryService) (bool, error)) error {
+	name string, inTransaction bool, inner func(ctx context.Context, target *querypb.Target, conn queryservice.QueryService) (bool, error)) error {
 
 	// for transactions, we connect to a specific tablet instead of letting gateway choose one
 	if inTransaction && target.TabletType != topodatapb.TabletType_PRIMARY {
@@ -328,7 +357,14 @@ func (gw *TabletGateway) withRetry(ctx context.Context, target *querypb.Target,
 			}
 		}
 
+		tabletTarget := target
 		tablets := gw.hc.GetHealthyTabletStats(target)
+		if maxLag := econtext.MaxReplicaLagFromContext(ctx); maxLag > 0 && target.TabletType != topodatapb.TabletType_PRIMARY && len(tablets) > 0 {
+			tabletTarget, tablets, err = gw.withinReplicaLag(target, tablets, maxLag, !startsShardSession(name))
+			if err != nil {
+				break
+			}
+		}
 		if len(tablets) == 0 {
 			// if we have a keyspace event watcher, check if the reason why our primary is not available is that it's currently being resharded
 			// or if a reparent operation is in progress.
@@ -355,7 +391,7 @@ func (gw *TabletGateway) withRetry(ctx context.Context, target *querypb.Target,
 			}
 
 			// fail fast if there is no tablet
-			err = vterrors.Errorf(vtrpcpb.Code_UNAVAILABLE, "no healthy tablet available for '%s'", target.String())
+			err = vterrors.Errorf(vtrpcpb.Code_UNAVAILABLE, "no healthy tablet available for '%s'", tabletTarget.String())
 			break
 		}
 
@@ -374,7 +410,7 @@ func (gw *TabletGateway) withRetry(ctx context.Context, target *querypb.Target,
 				})
 			}
 
-			th = gw.balancer.Pick(target, tablets)
+			th = gw.balancer.Pick(tabletTarget, tablets)
 
 		} else {
 			gw.shuffleTablets(gw.localCell, tablets)
@@ -408,8 +444,17 @@ func (gw *TabletGateway) withRetry(ctx context.Context, target *querypb.Target,
 
 		startTime := time.Now()
 		var canRetry bool
-		canRetry, err = inner(ctx, target, th.Conn)
-		gw.updateStats(target, startTime, err)
+		// The duration of a stream is up to its caller, it is not the
+		// latency of the tablet.
+		accountQuery := useBalancer && !isStreaming(name)
+		if accountQuery {
+			gw.balancer.QueryStarted(th)
+		}
+		canRetry, err = inner(ctx, tabletTarget, th.Conn)
+		if accountQuery {
+			gw.balancer.QueryFinished(th, time.Since(startTime), err)
+		}
+		gw.updateStats(tabletTarget, startTime, err)
 		if canRetry {
 			invalidTablets[topoproto.TabletAliasString(tabletLastUsed.Alias)] = true
 			continue
@@ -419,6 +464,41 @@ func (gw *TabletGateway) withRetry(ctx context.Context, target *querypb.Target,
 	return NewShardError(err, target)
 }
 
+// startsShardSession returns true if the call of the query service begins a
+// transaction or reserves a connection. vtgate keeps the tablet of the call
+// in a shard session of the target, which must thus not be changed.
+func startsShardSession(name string) bool {
+	return strings.HasPrefix(name, "Begin") || strings.HasPrefix(name, "Reserve")
+}
+
+// isStreaming returns true if the call of the query service streams its
+// results, e.g. StreamExecute, MessageStream or VStream.
+func isStreaming(name string) bool {
+	return strings.Contains(name, "Stream")
+}
+
+// withinReplicaLag returns the replicas of the target whose replication lag
+// is within maxLag. If there are none, it returns the primary of the shard
+// and its target instead, or fails if falling back to the primary is
+// disabled or not allowed by the caller.
+func (gw *TabletGateway) withinReplicaLag(target *querypb.Target, tablets []*discovery.TabletHealth, maxLag time.Duration, allowFallback bool) (*querypb.Target, []*discovery.TabletHealth, error) {
+	tablets = slices.DeleteFunc(tablets, func(th *discovery.TabletHealth) bool {
+		return th.Stats == nil || time.Duration(th.Stats.ReplicationLagSeconds)*time.Second > maxLag
+	})
+	if len(tablets) > 0 {
+		return target, tablets, nil
+	}
+
+	if !maxReplicaLagFallbackToPrimary || !allowFallback {
+		maxReplicaLagFallbacks.Add([]string{target.Keyspace, target.Shard, topoproto.TabletTypeLString(target.TabletType), "Error"}, 1)
+		return nil, nil, vterrors.Errorf(vtrpcpb.Code_UNAVAILABLE, "no healthy tablet with replication lag within %v available for '%s'", maxLag, target.String())
+	}
+	maxReplicaLagFallbacks.Add([]string{target.Keyspace, target.Shard, topoproto.TabletTypeLString(target.TabletType), "Primary"}, 1)
+	primary := target.CloneVT()
+	primary.TabletType = topodatapb.TabletType_PRIMARY
+	return primary, gw.hc.GetHealthyTabletStats(primary), nil
+}
+
 // withShardError adds shard information to errors returned from the inner QueryService.
 func (gw *TabletGateway) withShardError(ctx context.Context, target *querypb.Target, conn queryservice.QueryService,
 	_ string, _ bool, inner func(ctx context.Context, target *querypb.Target, conn queryservice.QueryService) (bool, error)) error {
@@ -19,8 +19,12 @@ package vtgate
 import (
 	"context"
 	"fmt"
+	"net/http/httptest"
+	"regexp"
+	"strconv"
 	"strings"
 	"testing"
+	"time"
 
 	econtext "vitess.io/vitess/go/vt/vtgate/executorcontext"
 
@@ -401,3 +405,120 @@ func TestWithRetry(t *testing.T) {
 		})
 	}
 }
+
+func TestTabletGatewayLatencyBalancer(t *testing.T) {
+	ctx := utils.LeakCheckContext(t)
+
+	balancerEnabled = true
+	balancerMode = balancerModeLatency
+	defer func() {
+		balancerEnabled = false
+		balancerMode = balancerModeCell
+	}()
+
+	keyspace := "ks"
+	shard := "0"
+	host := "1.1.1.1"
+	target := &querypb.Target{
+		Keyspace:   keyspace,
+		Shard:      shard,
+		TabletType: topodatapb.TabletType_REPLICA,
+	}
+	hc := discovery.NewFakeHealthCheck(nil)
+	ts := &econtext.FakeTopoServer{}
+	tg := NewTabletGateway(ctx, hc, ts, "cell")
+	defer tg.Close(ctx)
+
+	sc1 := hc.AddTestTablet("cell", host, 1001, keyspace, shard, topodatapb.TabletType_REPLICA, true, 10, nil)
+	sc2 := hc.AddTestTablet("cell", host, 1002, keyspace, shard, topodatapb.TabletType_REPLICA, true, 10, nil)
+	for range 10 {
+		_, err := tg.Execute(ctx, target, "query", nil, 0, 0, nil)
+		require.NoError(t, err)
+	}
+	assert.EqualValues(t, 10, sc1.ExecCount.Load()+sc2.ExecCount.Load())
+
+	// the streams are sent to the tablets the balancer picks, but they are
+	// not accounted as queries
+	for range 5 {
+		err := tg.StreamExecute(ctx, target, "query", nil, 0, 0, nil, func(*sqltypes.Result) error { return nil })
+		require.NoError(t, err)
+	}
+	assert.EqualValues(t, 15, sc1.ExecCount.Load()+sc2.ExecCount.Load())
+
+	// the queries are reported to the balancer
+	w := httptest.NewRecorder()
+	tg.DebugBalancerHandler(w, httptest.NewRequest("GET", "/debug/balancer", nil))
+	assert.Contains(t, w.Body.String(), "Mode: latency")
+	assert.Contains(t, w.Body.String(), "in flight 0")
+	var queries int
+	for _, match := range regexp.MustCompile(`queries (\d+)`).FindAllStringSubmatch(w.Body.String(), -1) {
+		n, err := strconv.Atoi(match[1])
+		require.NoError(t, err)
+		queries += n
+	}
+	assert.Equal(t, 10, queries)
+}
+
+func TestTabletGatewayMaxReplicaLag(t *testing.T) {
+	ctx := utils.LeakCheckContext(t)
+
+	keyspace := "ks"
+	shard := "0"
+	host := "1.1.1.1"
+	target := &querypb.Target{
+		Keyspace:   keyspace,
+		Shard:      shard,
+		TabletType: topodatapb.TabletType_REPLICA,
+	}
+	hc := discovery.NewFakeHealthCheck(nil)
+	ts := &econtext.FakeTopoServer{}
+	tg := NewTabletGateway(ctx, hc, ts, "cell")
+	defer tg.Close(ctx)
+
+	primary := hc.AddTestTablet("cell", host, 1001, keyspace, shard, topodatapb.TabletType_PRIMARY, true, 10, nil)
+	lagging := hc.AddTestTablet("cell", host, 1002, keyspace, shard, topodatapb.TabletType_REPLICA, true, 10, nil)
+	caughtUp := hc.AddTestTablet("cell", host, 1003, keyspace, shard, topodatapb.TabletType_REPLICA, true, 10, nil)
+	for _, th := range hc.GetHealthyTabletStats(target) {
+		if th.Tablet.PortMap["vt"] == 1002 {
+			th.Stats.ReplicationLagSeconds = 30
+		} else {
+			th.Stats.ReplicationLagSeconds = 1
+		}
+	}
+
+	// only the replica within the lag is used
+	lagCtx := econtext.WithMaxReplicaLag(ctx, 5*time.Second)
+	for range 10 {
+		_, err := tg.Execute(lagCtx, target, "query", nil, 0, 0, nil)
+		require.NoError(t, err)
+	}
+	assert.EqualValues(t, 0, lagging.ExecCount.Load())
+	assert.EqualValues(t, 10, caughtUp.ExecCount.Load())
+	assert.EqualValues(t, 0, primary.ExecCount.Load())
+
+	// no replica is within the lag, the primary is used
+	lagCtx = econtext.WithMaxReplicaLag(ctx, 500*time.Millisecond)
+	fallbacks := maxReplicaLagFallbacks.Counts()["ks.0.replica.Primary"]
+	_, err := tg.Execute(lagCtx, target, "query", nil, 0, 0, nil)
+	require.NoError(t, err)
+	assert.EqualValues(t, 1, primary.ExecCount.Load())
+	assert.EqualValues(t, fallbacks+1, maxReplicaLagFallbacks.Counts()["ks.0.replica.Primary"])
+
+	// transactions and reserved connections are not moved to the primary, their
+	// shard sessions keep the replica target
+	_, err = tg.Begin(lagCtx, target, nil)
+	verifyContainsError(t, err, "no healthy tablet with replication lag within 500ms available", vtrpcpb.Code_UNAVAILABLE)
+	_, _, err = tg.ReserveExecute(lagCtx, target, nil, "query", nil, 0, nil)
+	verifyContainsError(t, err, "no healthy tablet with replication lag within 500ms available", vtrpcpb.Code_UNAVAILABLE)
+	assert.EqualValues(t, 0, primary.BeginCount.Load())
+	assert.EqualValues(t, 0, primary.ReserveCount.Load())
+
+	// no replica is within the lag and falling back to the primary is disabled
+	maxReplicaLagFallbackToPrimary = false
+	defer func() {
+		maxReplicaLagFallbackToPrimary = true
+	}()
+	_, err = tg.Execute(lagCtx, target, "query", nil, 0, 0, nil)
+	verifyContainsError(t, err, "no healthy tablet with replication lag within 500ms available", vtrpcpb.Code_UNAVAILABLE)
+	assert.EqualValues(t, 1, primary.ExecCount.Load())
+}
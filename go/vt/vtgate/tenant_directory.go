@@ -0,0 +1,139 @@
+/*
+Copyright 2026 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vtgate
+
+import (
+	"context"
+	"sync"
+
+	"vitess.io/vitess/go/vt/log"
+	"vitess.io/vitess/go/vt/srvtopo"
+	"vitess.io/vitess/go/vt/topo"
+	"vitess.io/vitess/go/vt/vtgate/vindexes"
+
+	vschemapb "vitess.io/vitess/go/vt/proto/vschema"
+)
+
+// tenantDirectories keeps the directory vindexes of the current VSchema
+// in sync with their content in the topo. There is one watch per directory
+// vindex, which lives as long as the vindex is part of the VSchema.
+type tenantDirectories struct {
+	ctx  context.Context
+	serv srvtopo.Server
+
+	mu      sync.Mutex
+	entries map[string]*tenantDirectory
+}
+
+// tenantDirectory is the state of a single watched directory.
+type tenantDirectory struct {
+	keyspace, name string
+
+	ctx    context.Context
+	cancel context.CancelFunc
+
+	// The fields below are protected by tenantDirectories.mu.
+
+	// vindex is the instance of the directory vindex in the current VSchema.
+	vindex *vindexes.Directory
+	// content is the latest content received from the topo, nil if the
+	// directory does not exist. It is only valid once loaded is set.
+	content *vschemapb.TenantDirectory
+	loaded  bool
+}
+
+func newTenantDirectories(ctx context.Context, serv srvtopo.Server) *tenantDirectories {
+	return &tenantDirectories{
+		ctx:     ctx,
+		serv:    serv,
+		entries: make(map[string]*tenantDirectory),
+	}
+}
+
+// update is called with every new VSchema before it is published. It loads
+// the known content into the new directory vindexes, starts watching the
+// directories that were added and stops watching the ones that are gone.
+// Watches that start here deliver their initial content before update returns.
+func (td *tenantDirectories) update(vschema *vindexes.VSchema) {
+	type directoryVindex struct {
+		keyspace, name string
+		vindex         *vindexes.Directory
+	}
+	found := make(map[string]directoryVindex)
+	for ksName, ks := range vschema.Keyspaces {
+		for name, vindex := range ks.Vindexes {
+			if dir, ok := vindex.(*vindexes.Directory); ok {
+				found[ksName+"."+name] = directoryVindex{keyspace: ksName, name: name, vindex: dir}
+			}
+		}
+	}
+
+	var started []*tenantDirectory
+	td.mu.Lock()
+	for key, entry := range td.entries {
+		if _, ok := found[key]; !ok {
+			entry.cancel()
+			delete(td.entries, key)
+		}
+	}
+	for key, dir := range found {
+		entry, ok := td.entries[key]
+		if !ok {
+			entry = &tenantDirectory{keyspace: dir.keyspace, name: dir.name}
+			entry.ctx, entry.cancel = context.WithCancel(td.ctx)
+			td.entries[key] = entry
+			started = append(started, entry)
+		}
+		entry.vindex = dir.vindex
+		if entry.loaded {
+			dir.vindex.SetTenantDirectory(entry.content)
+		}
+	}
+	td.mu.Unlock()
+
+	// The initial callback of a watch is synchronous, so the watches
+	// have to be started without holding the lock.
+	for _, entry := range started {
+		td.serv.WatchTenantDirectory(entry.ctx, entry.keyspace, entry.name, func(content *vschemapb.TenantDirectory, err error) bool {
+			return td.onUpdate(entry, content, err)
+		})
+	}
+}
+
+func (td *tenantDirectories) onUpdate(entry *tenantDirectory, content *vschemapb.TenantDirectory, err error) bool {
+	if entry.ctx.Err() != nil {
+		// The directory is not part of the VSchema anymore.
+		return false
+	}
+	switch {
+	case err == nil:
+	case topo.IsErrType(err, topo.NoNode):
+		// The directory does not exist (yet), so it is empty.
+		content = nil
+	default:
+		// Keep whatever we had before.
+		log.Warningf("error watching tenant directory %s.%s: %v", entry.keyspace, entry.name, err)
+		return true
+	}
+
+	td.mu.Lock()
+	defer td.mu.Unlock()
+	entry.content = content
+	entry.loaded = true
+	entry.vindex.SetTenantDirectory(content)
+	return true
+}
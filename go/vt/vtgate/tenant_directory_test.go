@@ -0,0 +1,104 @@
+/*
+Copyright 2026 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vtgate
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"vitess.io/vitess/go/sqltypes"
+	"vitess.io/vitess/go/stats"
+	"vitess.io/vitess/go/vt/key"
+	"vitess.io/vitess/go/vt/sqlparser"
+	"vitess.io/vitess/go/vt/srvtopo"
+	"vitess.io/vitess/go/vt/topo/memorytopo"
+	"vitess.io/vitess/go/vt/vtgate/vindexes"
+
+	vschemapb "vitess.io/vitess/go/vt/proto/vschema"
+)
+
+func TestTenantDirectories(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	ts := memorytopo.NewServer(ctx, "cell1")
+	defer ts.Close()
+	counts := stats.NewCountersWithSingleLabel("", "Resilient srvtopo server operations", "type")
+	rs := srvtopo.NewResilientServer(ctx, ts, counts)
+
+	_, err := ts.UpdateTenantDirectoryFields(ctx, "ks", "dir", func(td *vschemapb.TenantDirectory) error {
+		td.KeyspaceIds["acme"] = []byte{0x10}
+		return nil
+	})
+	require.NoError(t, err)
+
+	srvVSchema := &vschemapb.SrvVSchema{
+		Keyspaces: map[string]*vschemapb.Keyspace{
+			"ks": {
+				Sharded: true,
+				Vindexes: map[string]*vschemapb.Vindex{
+					"dir": {Type: "directory"},
+				},
+			},
+		},
+	}
+	mapAcme := func(dir *vindexes.Directory) key.ShardDestination {
+		dests, err := dir.Map(ctx, nil, []sqltypes.Value{sqltypes.NewVarChar("acme")})
+		require.NoError(t, err)
+		return dests[0]
+	}
+
+	directories := newTenantDirectories(ctx, rs)
+
+	// The content is loaded before update returns.
+	vschema := vindexes.BuildVSchema(srvVSchema, sqlparser.NewTestParser())
+	directories.update(vschema)
+	dir := vschema.Keyspaces["ks"].Vindexes["dir"].(*vindexes.Directory)
+	assert.Equal(t, key.DestinationKeyspaceID([]byte{0x10}), mapAcme(dir))
+
+	// Changes in the topo are picked up by the current vindex.
+	_, err = ts.UpdateTenantDirectoryFields(ctx, "ks", "dir", func(td *vschemapb.TenantDirectory) error {
+		td.KeyspaceIds["acme"] = []byte{0x80}
+		return nil
+	})
+	require.NoError(t, err)
+	require.Eventually(t, func() bool {
+		return key.DestinationKeyspaceID([]byte{0x80}).String() == mapAcme(dir).String()
+	}, 10*time.Second, 10*time.Millisecond)
+
+	// A new VSchema gets the current content right away.
+	vschema = vindexes.BuildVSchema(srvVSchema, sqlparser.NewTestParser())
+	directories.update(vschema)
+	dir = vschema.Keyspaces["ks"].Vindexes["dir"].(*vindexes.Directory)
+	assert.Equal(t, key.DestinationKeyspaceID([]byte{0x80}), mapAcme(dir))
+
+	// Deleting the directory empties the vindex.
+	require.NoError(t, ts.DeleteTenantDirectory(ctx, "ks", "dir"))
+	require.Eventually(t, func() bool {
+		return key.DestinationNone{}.String() == mapAcme(dir).String()
+	}, 10*time.Second, 10*time.Millisecond)
+
+	// Removing the vindex from the VSchema stops the watch.
+	delete(srvVSchema.Keyspaces["ks"].Vindexes, "dir")
+	directories.update(vindexes.BuildVSchema(srvVSchema, sqlparser.NewTestParser()))
+	directories.mu.Lock()
+	assert.Empty(t, directories.entries)
+	directories.mu.Unlock()
+}
@@ -23,6 +23,8 @@ import (
 
 	"vitess.io/vitess/go/sqltypes"
 	"vitess.io/vitess/go/vt/key"
+
+	topodatapb "vitess.io/vitess/go/vt/proto/topodata"
 )
 
 var (
@@ -31,6 +33,7 @@ var (
 	_ Hashing         = (*Binary)(nil)
 	_ ParamValidating = (*Binary)(nil)
 	_ Sequential      = (*Binary)(nil)
+	_ RangeVindex     = (*Binary)(nil)
 )
 
 // Binary is a vindex that converts binary bits to a keyspace id.
@@ -123,6 +126,44 @@ func (vind *Binary) RangeMap(ctx context.Context, vcursor VCursor, startId sqlty
 	return out, nil
 }
 
+// MapRange implements the RangeVindex interface. Only binary values compare
+// like their keyspace ids: a bound of any other type, e.g. an integer whose
+// text sorts differently than its value, leaves its side of the range
+// unbounded.
+func (vind *Binary) MapRange(ctx context.Context, vcursor VCursor, start sqltypes.Value, end sqltypes.Value) (*topodatapb.KeyRange, error) {
+	kr := &topodatapb.KeyRange{}
+	if isBinaryRangeBound(start) {
+		startKsId, err := vind.Hash(start)
+		if err != nil {
+			return nil, err
+		}
+		kr.Start = startKsId
+	}
+	if isBinaryRangeBound(end) {
+		endKsId, err := vind.Hash(end)
+		if err != nil {
+			return nil, err
+		}
+		// The key range end is exclusive. Appending a byte gives a keyspace id
+		// that sorts after the last id, so that its shard is always included.
+		endKsId = key.Normalize(endKsId)
+		kr.End = make([]byte, len(endKsId)+1)
+		copy(kr.End, endKsId)
+		kr.End[len(endKsId)] = 0x01
+	}
+	return kr, nil
+}
+
+// isBinaryRangeBound returns true if a bound of a range of binary values can
+// be mapped to a keyspace id.
+func isBinaryRangeBound(v sqltypes.Value) bool {
+	switch v.Type() {
+	case sqltypes.VarBinary, sqltypes.Binary:
+		return true
+	}
+	return false
+}
+
 // UnknownParams implements the ParamValidating interface.
 func (vind *Binary) UnknownParams() []string {
 	return vind.unknownParams
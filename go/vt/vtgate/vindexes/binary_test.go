@@ -161,3 +161,39 @@ func TestBinaryRangeMap(t *testing.T) {
 	assert.Equal(t, want, got[0].String())
 
 }
+
+func TestBinaryMapRange(t *testing.T) {
+	tests := []struct {
+		start, end sqltypes.Value
+		want       string
+	}{{
+		start: sqltypes.NewVarBinary("\x10"),
+		end:   sqltypes.NewVarBinary("\x40"),
+		want:  "10-4001",
+	}, {
+		start: sqltypes.NULL,
+		end:   sqltypes.NewVarBinary("\x40\x00"),
+		want:  "-4001",
+	}, {
+		start: sqltypes.NewVarBinary("\x80"),
+		end:   sqltypes.NULL,
+		want:  "80-",
+	}, {
+		// The text of integers does not sort like their values, e.g. 10
+		// sorts before 5, so they can't bound the range.
+		start: sqltypes.NewInt64(5),
+		end:   sqltypes.NewVarBinary("\x40"),
+		want:  "-4001",
+	}, {
+		start: sqltypes.NewVarChar("a"),
+		end:   sqltypes.NewInt64(10),
+		want:  "-",
+	}}
+	for _, tc := range tests {
+		t.Run(tc.want, func(t *testing.T) {
+			got, err := binOnlyVindex.(RangeVindex).MapRange(context.Background(), nil, tc.start, tc.end)
+			require.NoError(t, err)
+			assert.Equal(t, tc.want, key.KeyRangeString(got))
+		})
+	}
+}
@@ -0,0 +1,140 @@
+/*
+Copyright 2026 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vindexes
+
+import (
+	"bytes"
+	"context"
+	"sync/atomic"
+
+	"vitess.io/vitess/go/sqltypes"
+	"vitess.io/vitess/go/vt/key"
+	"vitess.io/vitess/go/vt/vterrors"
+
+	vschemapb "vitess.io/vitess/go/vt/proto/vschema"
+	vtrpcpb "vitess.io/vitess/go/vt/proto/vtrpc"
+)
+
+var (
+	_ SingleColumn    = (*Directory)(nil)
+	_ ParamValidating = (*Directory)(nil)
+)
+
+// Directory defines a vindex that maps tenant ids to keyspace ids through
+// an explicit directory. The directory is stored in the topo next to the
+// VSchema, and vtgate keeps it up to date through SetTenantDirectory.
+// It's Unique. Tenants that are not in the directory map to no shard.
+type Directory struct {
+	name          string
+	unknownParams []string
+	directory     atomic.Pointer[vschemapb.TenantDirectory]
+}
+
+// newDirectory creates a new Directory.
+func newDirectory(name string, m map[string]string) (Vindex, error) {
+	return &Directory{
+		name:          name,
+		unknownParams: FindUnknownParams(m, nil),
+	}, nil
+}
+
+// String returns the name of the vindex.
+func (vind *Directory) String() string {
+	return vind.name
+}
+
+// Cost returns the cost of this index as 1.
+func (vind *Directory) Cost() int {
+	return 1
+}
+
+// IsUnique returns true since the Vindex is unique.
+func (vind *Directory) IsUnique() bool {
+	return true
+}
+
+// NeedsVCursor returns true because the directory is only loaded
+// by vtgate, so it cannot be used by vreplication.
+func (vind *Directory) NeedsVCursor() bool {
+	return true
+}
+
+// SetTenantDirectory replaces the content of the directory.
+// A nil directory is treated as an empty one.
+func (vind *Directory) SetTenantDirectory(td *vschemapb.TenantDirectory) {
+	if td == nil {
+		td = &vschemapb.TenantDirectory{}
+	}
+	vind.directory.Store(td)
+}
+
+// Map can map ids to key.ShardDestination objects.
+func (vind *Directory) Map(ctx context.Context, vcursor VCursor, ids []sqltypes.Value) ([]key.ShardDestination, error) {
+	td, err := vind.load()
+	if err != nil {
+		return nil, err
+	}
+	out := make([]key.ShardDestination, 0, len(ids))
+	for _, id := range ids {
+		ksid, ok := lookupTenant(td, id)
+		if !ok {
+			out = append(out, key.DestinationNone{})
+			continue
+		}
+		out = append(out, key.DestinationKeyspaceID(ksid))
+	}
+	return out, nil
+}
+
+// Verify returns true if ids maps to ksids.
+func (vind *Directory) Verify(ctx context.Context, vcursor VCursor, ids []sqltypes.Value, ksids [][]byte) ([]bool, error) {
+	td, err := vind.load()
+	if err != nil {
+		return nil, err
+	}
+	out := make([]bool, 0, len(ids))
+	for i, id := range ids {
+		ksid, ok := lookupTenant(td, id)
+		out = append(out, ok && bytes.Equal(ksid, ksids[i]))
+	}
+	return out, nil
+}
+
+// UnknownParams implements the ParamValidating interface.
+func (vind *Directory) UnknownParams() []string {
+	return vind.unknownParams
+}
+
+func (vind *Directory) load() (*vschemapb.TenantDirectory, error) {
+	td := vind.directory.Load()
+	if td == nil {
+		return nil, vterrors.Errorf(vtrpcpb.Code_UNAVAILABLE, "tenant directory for vindex %s is not loaded yet", vind.name)
+	}
+	return td, nil
+}
+
+func lookupTenant(td *vschemapb.TenantDirectory, id sqltypes.Value) ([]byte, bool) {
+	if id.IsNull() {
+		return nil, false
+	}
+	ksid, ok := td.KeyspaceIds[id.ToString()]
+	return ksid, ok
+}
+
+func init() {
+	Register("directory", newDirectory)
+}
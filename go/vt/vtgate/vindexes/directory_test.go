@@ -0,0 +1,109 @@
+/*
+Copyright 2026 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vindexes
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"vitess.io/vitess/go/sqltypes"
+	"vitess.io/vitess/go/vt/key"
+
+	vschemapb "vitess.io/vitess/go/vt/proto/vschema"
+)
+
+func directoryCreateVindexTestCase(
+	testName string,
+	vindexParams map[string]string,
+	expectErr error,
+	expectUnknownParams []string,
+) createVindexTestCase {
+	return createVindexTestCase{
+		testName: testName,
+
+		vindexType:   "directory",
+		vindexName:   "directory",
+		vindexParams: vindexParams,
+
+		expectCost:          1,
+		expectErr:           expectErr,
+		expectIsUnique:      true,
+		expectNeedsVCursor:  true,
+		expectString:        "directory",
+		expectUnknownParams: expectUnknownParams,
+	}
+}
+
+func TestDirectoryCreateVindex(t *testing.T) {
+	cases := []createVindexTestCase{
+		directoryCreateVindexTestCase(
+			"no params",
+			nil,
+			nil,
+			nil,
+		),
+		directoryCreateVindexTestCase(
+			"unknown params",
+			map[string]string{"hello": "world"},
+			nil,
+			[]string{"hello"},
+		),
+	}
+
+	testCreateVindexes(t, cases)
+}
+
+func TestDirectoryMap(t *testing.T) {
+	vindex, err := CreateVindex("directory", "dir", nil)
+	require.NoError(t, err)
+	dir := vindex.(*Directory)
+
+	ids := []sqltypes.Value{
+		sqltypes.NewInt64(1),
+		sqltypes.NewVarChar("acme"),
+		sqltypes.NewInt64(3),
+		sqltypes.NULL,
+	}
+	_, err = dir.Map(context.Background(), nil, ids)
+	require.EqualError(t, err, "tenant directory for vindex dir is not loaded yet")
+
+	dir.SetTenantDirectory(&vschemapb.TenantDirectory{KeyspaceIds: map[string][]byte{
+		"1":    {0x10},
+		"acme": {0x80, 0x01},
+	}})
+	got, err := dir.Map(context.Background(), nil, ids)
+	require.NoError(t, err)
+	assert.Equal(t, []key.ShardDestination{
+		key.DestinationKeyspaceID([]byte{0x10}),
+		key.DestinationKeyspaceID([]byte{0x80, 0x01}),
+		key.DestinationNone{},
+		key.DestinationNone{},
+	}, got)
+
+	verified, err := dir.Verify(context.Background(), nil, ids[:3], [][]byte{{0x10}, {0x80}, {0x10}})
+	require.NoError(t, err)
+	assert.Equal(t, []bool{true, false, false}, verified)
+
+	// An empty directory is loaded, but maps nothing.
+	dir.SetTenantDirectory(nil)
+	got, err = dir.Map(context.Background(), nil, ids[:1])
+	require.NoError(t, err)
+	assert.Equal(t, []key.ShardDestination{key.DestinationNone{}}, got)
+}
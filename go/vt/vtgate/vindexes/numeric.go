@@ -21,9 +21,12 @@ import (
 	"context"
 	"encoding/binary"
 	"fmt"
+	"math"
 
 	"vitess.io/vitess/go/sqltypes"
 	"vitess.io/vitess/go/vt/key"
+
+	topodatapb "vitess.io/vitess/go/vt/proto/topodata"
 )
 
 var (
@@ -32,6 +35,7 @@ var (
 	_ Hashing         = (*Numeric)(nil)
 	_ ParamValidating = (*Numeric)(nil)
 	_ Sequential      = (*Numeric)(nil)
+	_ RangeVindex     = (*Numeric)(nil)
 )
 
 // Numeric defines a bit-pattern mapping of a uint64 to the KeyspaceId.
@@ -123,6 +127,22 @@ func (vind *Numeric) RangeMap(ctx context.Context, vcursor VCursor, startId sqlt
 	return out, nil
 }
 
+// MapRange implements the RangeVindex interface. A bound that cannot be
+// hashed, like a negative or fractional number, leaves its side of the range
+// unbounded.
+func (vind *Numeric) MapRange(ctx context.Context, vcursor VCursor, start sqltypes.Value, end sqltypes.Value) (*topodatapb.KeyRange, error) {
+	kr := &topodatapb.KeyRange{}
+	if num, err := start.ToCastUint64(); err == nil {
+		kr.Start = key.Uint64Key(num).Bytes()
+	}
+	// The key range end is exclusive, so it has to be right after the keyspace id
+	// of the last id. If there is no such keyspace id, the range stays unbounded.
+	if num, err := end.ToCastUint64(); err == nil && num != math.MaxUint64 {
+		kr.End = key.Uint64Key(num + 1).Bytes()
+	}
+	return kr, nil
+}
+
 // UnknownParams implements the ParamValidating interface.
 func (vind *Numeric) UnknownParams() []string {
 	return vind.unknownParams
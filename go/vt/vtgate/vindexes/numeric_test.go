@@ -18,6 +18,7 @@ package vindexes
 
 import (
 	"context"
+	"math"
 	"reflect"
 	"testing"
 
@@ -146,3 +147,43 @@ func TestNumericReverseMapBadData(t *testing.T) {
 		t.Errorf("numeric.Map: %v, want %v", err, want)
 	}
 }
+
+func TestNumericMapRange(t *testing.T) {
+	tests := []struct {
+		name       string
+		start, end sqltypes.Value
+		want       string
+	}{{
+		name:  "bounded",
+		start: sqltypes.NewInt64(1),
+		end:   sqltypes.NewInt64(0x7fffffffffffffff),
+		want:  "0000000000000001-8000000000000000",
+	}, {
+		name:  "no end",
+		start: sqltypes.NewInt64(0x40),
+		end:   sqltypes.NULL,
+		want:  "0000000000000040-",
+	}, {
+		name:  "max end",
+		start: sqltypes.NewUint64(0x8000000000000000),
+		end:   sqltypes.NewUint64(math.MaxUint64),
+		want:  "8000000000000000-",
+	}, {
+		name:  "negative start",
+		start: sqltypes.NewInt64(-5),
+		end:   sqltypes.NewInt64(5),
+		want:  "-0000000000000006",
+	}, {
+		name:  "not an integer",
+		start: sqltypes.NewFloat64(1.5),
+		end:   sqltypes.NewInt64(5),
+		want:  "-0000000000000006",
+	}}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := numeric.(RangeVindex).MapRange(context.Background(), nil, tc.start, tc.end)
+			require.NoError(t, err)
+			require.Equal(t, tc.want, key.KeyRangeString(got))
+		})
+	}
+}
@@ -0,0 +1,292 @@
+/*
+Copyright 2026 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vindexes
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+
+	"github.com/google/uuid"
+
+	"vitess.io/vitess/go/sqltypes"
+	"vitess.io/vitess/go/vt/key"
+
+	topodatapb "vitess.io/vitess/go/vt/proto/topodata"
+)
+
+var (
+	_ SingleColumn    = (*UUIDHash)(nil)
+	_ Hashing         = (*UUIDHash)(nil)
+	_ ParamValidating = (*UUIDHash)(nil)
+	_ SingleColumn    = (*UUIDOrdered)(nil)
+	_ Hashing         = (*UUIDOrdered)(nil)
+	_ ParamValidating = (*UUIDOrdered)(nil)
+	_ Sequential      = (*UUIDOrdered)(nil)
+	_ RangeVindex     = (*UUIDOrdered)(nil)
+)
+
+// The uuid vindexes accept the following formats, and normalize them to
+// the 16 bytes of the UUID:
+//   - 16 bytes binary, as stored in a BINARY(16) column
+//   - xxxxxxxx-xxxx-xxxx-xxxx-xxxxxxxxxxxx, optionally in braces or with a urn:uuid: prefix
+//   - xxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxx
+//   - a 26 characters ULID in Crockford's base32
+//
+// Hex digits and ULID characters are case insensitive.
+
+// uuidTimestampLen is the length of the millisecond timestamp prefix
+// of UUIDv7 and ULID values.
+const uuidTimestampLen = 6
+
+// UUIDHash defines a vindex that hashes the random bits of a UUID or ULID to
+// a KeyspaceId, which are the 80 bits that follow the timestamp prefix of
+// UUIDv7 and ULID values. Those bits are random for UUIDv4 as well.
+// It's Unique. Values that cannot be parsed map to no shard.
+type UUIDHash struct {
+	name          string
+	unknownParams []string
+}
+
+// newUUIDHash creates a new UUIDHash.
+func newUUIDHash(name string, m map[string]string) (Vindex, error) {
+	return &UUIDHash{
+		name:          name,
+		unknownParams: FindUnknownParams(m, nil),
+	}, nil
+}
+
+// String returns the name of the vindex.
+func (vind *UUIDHash) String() string {
+	return vind.name
+}
+
+// Cost returns the cost of this index as 1.
+func (vind *UUIDHash) Cost() int {
+	return 1
+}
+
+// IsUnique returns true since the Vindex is unique.
+func (vind *UUIDHash) IsUnique() bool {
+	return true
+}
+
+// NeedsVCursor satisfies the Vindex interface.
+func (vind *UUIDHash) NeedsVCursor() bool {
+	return false
+}
+
+// Map can map ids to key.ShardDestination objects.
+func (vind *UUIDHash) Map(ctx context.Context, vcursor VCursor, ids []sqltypes.Value) ([]key.ShardDestination, error) {
+	return mapUUIDs(vind, ids), nil
+}
+
+// Verify returns true if ids maps to ksids.
+func (vind *UUIDHash) Verify(ctx context.Context, vcursor VCursor, ids []sqltypes.Value, ksids [][]byte) ([]bool, error) {
+	return verifyUUIDs(vind, ids, ksids), nil
+}
+
+func (vind *UUIDHash) Hash(id sqltypes.Value) ([]byte, error) {
+	u, err := parseUUID(id)
+	if err != nil {
+		return nil, err
+	}
+	return vXXHash(u[uuidTimestampLen:]), nil
+}
+
+// UnknownParams implements the ParamValidating interface.
+func (vind *UUIDHash) UnknownParams() []string {
+	return vind.unknownParams
+}
+
+// UUIDOrdered defines a vindex that uses the normalized bytes of a UUID or
+// ULID as KeyspaceId. For UUIDv7 and ULID values, the keyspace ids start with
+// the timestamp, so rows are sharded by time and ranges of ids map to
+// ranges of shards.
+// It's Unique. Values that cannot be parsed map to no shard.
+type UUIDOrdered struct {
+	name          string
+	unknownParams []string
+}
+
+// newUUIDOrdered creates a new UUIDOrdered.
+func newUUIDOrdered(name string, m map[string]string) (Vindex, error) {
+	return &UUIDOrdered{
+		name:          name,
+		unknownParams: FindUnknownParams(m, nil),
+	}, nil
+}
+
+// String returns the name of the vindex.
+func (vind *UUIDOrdered) String() string {
+	return vind.name
+}
+
+// Cost returns the cost of this index as 1.
+func (vind *UUIDOrdered) Cost() int {
+	return 1
+}
+
+// IsUnique returns true since the Vindex is unique.
+func (vind *UUIDOrdered) IsUnique() bool {
+	return true
+}
+
+// NeedsVCursor satisfies the Vindex interface.
+func (vind *UUIDOrdered) NeedsVCursor() bool {
+	return false
+}
+
+// Map can map ids to key.ShardDestination objects.
+func (vind *UUIDOrdered) Map(ctx context.Context, vcursor VCursor, ids []sqltypes.Value) ([]key.ShardDestination, error) {
+	return mapUUIDs(vind, ids), nil
+}
+
+// Verify returns true if ids maps to ksids.
+func (vind *UUIDOrdered) Verify(ctx context.Context, vcursor VCursor, ids []sqltypes.Value, ksids [][]byte) ([]bool, error) {
+	return verifyUUIDs(vind, ids, ksids), nil
+}
+
+func (vind *UUIDOrdered) Hash(id sqltypes.Value) ([]byte, error) {
+	u, err := parseUUID(id)
+	if err != nil {
+		return nil, err
+	}
+	return u[:], nil
+}
+
+// RangeMap implements Between.
+func (vind *UUIDOrdered) RangeMap(ctx context.Context, vcursor VCursor, startId sqltypes.Value, endId sqltypes.Value) ([]key.ShardDestination, error) {
+	kr, err := vind.MapRange(ctx, vcursor, startId, endId)
+	if err != nil {
+		return nil, err
+	}
+	return []key.ShardDestination{key.DestinationKeyRange{KeyRange: kr}}, nil
+}
+
+// MapRange implements the RangeVindex interface. MySQL compares the bounds
+// with the raw values of the column, not with the UUIDs they decode to, so
+// only the bounds holding the 16 bytes of a UUID, which sort like the keyspace
+// ids, bound the range. Any other bound, e.g. a textual UUID or a ULID, leaves
+// its side of the range unbounded.
+func (vind *UUIDOrdered) MapRange(ctx context.Context, vcursor VCursor, start sqltypes.Value, end sqltypes.Value) (*topodatapb.KeyRange, error) {
+	kr := &topodatapb.KeyRange{}
+	if u, ok := rawUUID(start); ok {
+		kr.Start = u
+	}
+	// The key range end is exclusive, so it has to be right after the
+	// keyspace id of the last id. A trailing zero would be insignificant.
+	if u, ok := rawUUID(end); ok {
+		kr.End = append(u, 0x01)
+	}
+	return kr, nil
+}
+
+// UnknownParams implements the ParamValidating interface.
+func (vind *UUIDOrdered) UnknownParams() []string {
+	return vind.unknownParams
+}
+
+func mapUUIDs(vind Hashing, ids []sqltypes.Value) []key.ShardDestination {
+	out := make([]key.ShardDestination, 0, len(ids))
+	for _, id := range ids {
+		ksid, err := vind.Hash(id)
+		if err != nil {
+			out = append(out, key.DestinationNone{})
+			continue
+		}
+		out = append(out, key.DestinationKeyspaceID(ksid))
+	}
+	return out
+}
+
+func verifyUUIDs(vind Hashing, ids []sqltypes.Value, ksids [][]byte) []bool {
+	out := make([]bool, 0, len(ids))
+	for i, id := range ids {
+		ksid, err := vind.Hash(id)
+		out = append(out, err == nil && bytes.Equal(ksid, ksids[i]))
+	}
+	return out
+}
+
+// parseUUID normalizes the textual and binary representations of a UUID or ULID.
+func parseUUID(id sqltypes.Value) (u uuid.UUID, err error) {
+	if id.IsNull() || !(id.IsText() || id.IsBinary()) {
+		return u, fmt.Errorf("cannot parse UUID from %v", id)
+	}
+	raw := id.Raw()
+	switch {
+	case len(raw) == 16 && id.IsBinary():
+		// Only binary values hold the raw bytes of a UUID. A text of 16
+		// characters is not a valid UUID.
+		copy(u[:], raw)
+		return u, nil
+	case len(raw) == 26:
+		return parseULID(raw)
+	}
+	return uuid.ParseBytes(raw)
+}
+
+// rawUUID returns a copy of the bytes of a binary value holding the 16 bytes
+// of a UUID.
+func rawUUID(id sqltypes.Value) ([]byte, bool) {
+	if !id.IsBinary() || len(id.Raw()) != len(uuid.UUID{}) {
+		return nil, false
+	}
+	return bytes.Clone(id.Raw()), true
+}
+
+// crockford maps the characters of Crockford's base32 alphabet to their value.
+var crockford = func() (m [256]byte) {
+	for i := range m {
+		m[i] = 0xff
+	}
+	for i, c := range "0123456789ABCDEFGHJKMNPQRSTVWXYZ" {
+		m[c] = byte(i)
+		if c >= 'A' {
+			m[c+'a'-'A'] = byte(i)
+		}
+	}
+	return m
+}()
+
+// parseULID decodes the 26 characters of a ULID, which encode 128 bits
+// in 130 bits. The first character can therefore not be above 7.
+func parseULID(s []byte) (u uuid.UUID, err error) {
+	if crockford[s[0]] > 7 {
+		return u, fmt.Errorf("invalid ULID %q", s)
+	}
+	var hi, lo uint64
+	for _, c := range s {
+		v := crockford[c]
+		if v == 0xff {
+			return u, fmt.Errorf("invalid ULID %q", s)
+		}
+		hi = hi<<5 | lo>>59
+		lo = lo<<5 | uint64(v)
+	}
+	for i := 0; i < 8; i++ {
+		u[i] = byte(hi >> (56 - 8*i))
+		u[8+i] = byte(lo >> (56 - 8*i))
+	}
+	return u, nil
+}
+
+func init() {
+	Register("uuid_hash", newUUIDHash)
+	Register("uuid_ordered", newUUIDOrdered)
+}
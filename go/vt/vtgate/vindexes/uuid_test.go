@@ -0,0 +1,189 @@
+/*
+Copyright 2026 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vindexes
+
+import (
+	"context"
+	"encoding/hex"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"vitess.io/vitess/go/sqltypes"
+	"vitess.io/vitess/go/vt/key"
+)
+
+var uuidHash, uuidOrdered SingleColumn
+
+func init() {
+	hv, err := CreateVindex("uuid_hash", "uuid_hash", nil)
+	if err != nil {
+		panic(err)
+	}
+	uuidHash = hv.(SingleColumn)
+	ov, err := CreateVindex("uuid_ordered", "uuid_ordered", nil)
+	if err != nil {
+		panic(err)
+	}
+	uuidOrdered = ov.(SingleColumn)
+}
+
+func TestUUIDCreateVindex(t *testing.T) {
+	var cases []createVindexTestCase
+	for _, vindexType := range []string{"uuid_hash", "uuid_ordered"} {
+		cases = append(cases, createVindexTestCase{
+			testName:            vindexType + " no params",
+			vindexType:          vindexType,
+			vindexName:          vindexType,
+			expectCost:          1,
+			expectIsUnique:      true,
+			expectString:        vindexType,
+			expectUnknownParams: nil,
+		}, createVindexTestCase{
+			testName:            vindexType + " unknown params",
+			vindexType:          vindexType,
+			vindexName:          vindexType,
+			vindexParams:        map[string]string{"hello": "world"},
+			expectCost:          1,
+			expectIsUnique:      true,
+			expectString:        vindexType,
+			expectUnknownParams: []string{"hello"},
+		})
+	}
+
+	testCreateVindexes(t, cases)
+}
+
+// uuidv7 is 01932c07-a0d6-7b3c-9f2e-4a5b6c7d8e9f. Its ULID form is 01JCP0F86PFCY9YBJABDP7V3MZ.
+var uuidv7 = []byte{0x01, 0x93, 0x2c, 0x07, 0xa0, 0xd6, 0x7b, 0x3c, 0x9f, 0x2e, 0x4a, 0x5b, 0x6c, 0x7d, 0x8e, 0x9f}
+
+func TestUUIDOrderedMap(t *testing.T) {
+	ids := []sqltypes.Value{
+		sqltypes.NewVarChar("01932c07-a0d6-7b3c-9f2e-4a5b6c7d8e9f"),
+		sqltypes.NewVarChar("01932C07-A0D6-7B3C-9F2E-4A5B6C7D8E9F"),
+		sqltypes.NewVarChar("{01932c07-a0d6-7b3c-9f2e-4a5b6c7d8e9f}"),
+		sqltypes.NewVarChar("urn:uuid:01932c07-a0d6-7b3c-9f2e-4a5b6c7d8e9f"),
+		sqltypes.NewVarChar("01932c07a0d67b3c9f2e4a5b6c7d8e9f"),
+		sqltypes.MakeTrusted(sqltypes.Binary, uuidv7),
+		sqltypes.NewVarChar("01JCP0F86PFCY9YBJABDP7V3MZ"),
+		sqltypes.NewVarChar("01jcp0f86pfcy9ybjabdp7v3mz"),
+	}
+	got, err := uuidOrdered.Map(context.Background(), nil, ids)
+	require.NoError(t, err)
+	for i, dest := range got {
+		assert.Equal(t, key.DestinationKeyspaceID(uuidv7), dest, "id %v", ids[i])
+	}
+
+	got, err = uuidOrdered.Map(context.Background(), nil, []sqltypes.Value{
+		sqltypes.NULL,
+		sqltypes.NewInt64(1),
+		sqltypes.NewVarChar("not a uuid"),
+		sqltypes.NewVarChar("01932c07-a0d6-7b3c-9f2e-4a5b6c7d8e9g"),
+		sqltypes.NewVarChar("81JCP0F86PFCY9YBJABDP7V3MZ"),
+		sqltypes.NewVarChar("01JCP0F86PFCY9YBJABDP7V3MU"),
+		sqltypes.NewVarChar(string(uuidv7)),
+	})
+	require.NoError(t, err)
+	for _, dest := range got {
+		assert.Equal(t, key.DestinationNone{}, dest)
+	}
+}
+
+func TestUUIDHashMap(t *testing.T) {
+	got, err := uuidHash.Map(context.Background(), nil, []sqltypes.Value{
+		sqltypes.NewVarChar("01932c07-a0d6-7b3c-9f2e-4a5b6c7d8e9f"),
+		sqltypes.MakeTrusted(sqltypes.Binary, uuidv7),
+		sqltypes.NewVarChar("01JCP0F86PFCY9YBJABDP7V3MZ"),
+		// Same random bits, another timestamp.
+		sqltypes.NewVarChar("01932c08-0000-7b3c-9f2e-4a5b6c7d8e9f"),
+		sqltypes.NewVarChar("not a uuid"),
+	})
+	require.NoError(t, err)
+	want := key.DestinationKeyspaceID(vXXHash(uuidv7[6:]))
+	assert.Equal(t, []key.ShardDestination{want, want, want, want, key.DestinationNone{}}, got)
+}
+
+func TestUUIDVerify(t *testing.T) {
+	ids := []sqltypes.Value{
+		sqltypes.NewVarChar("01932c07-a0d6-7b3c-9f2e-4a5b6c7d8e9f"),
+		sqltypes.NewVarChar("01932c07-a0d6-7b3c-9f2e-4a5b6c7d8e9f"),
+		sqltypes.NewVarChar("not a uuid"),
+	}
+
+	got, err := uuidOrdered.Verify(context.Background(), nil, ids, [][]byte{uuidv7, vXXHash(uuidv7[6:]), nil})
+	require.NoError(t, err)
+	assert.Equal(t, []bool{true, false, false}, got)
+
+	got, err = uuidHash.Verify(context.Background(), nil, ids, [][]byte{uuidv7, vXXHash(uuidv7[6:]), nil})
+	require.NoError(t, err)
+	assert.Equal(t, []bool{false, true, false}, got)
+}
+
+// uuidBytes decodes the hexadecimal bytes of a UUID.
+func uuidBytes(s string) []byte {
+	b, err := hex.DecodeString(s)
+	if err != nil {
+		panic(err)
+	}
+	return b
+}
+
+func TestUUIDOrderedMapRange(t *testing.T) {
+	tests := []struct {
+		name       string
+		start, end sqltypes.Value
+		want       string
+	}{{
+		name:  "binary",
+		start: sqltypes.MakeTrusted(sqltypes.VarBinary, uuidBytes("01932c07000070008000000000000000")),
+		end:   sqltypes.MakeTrusted(sqltypes.Binary, uuidBytes("01932c08000070008000000000000000")),
+		want:  "01932c07000070008000000000000000-01932c0800007000800000000000000001",
+	}, {
+		name:  "text",
+		start: sqltypes.NewVarChar("01932c07-0000-7000-8000-000000000000"),
+		end:   sqltypes.NewVarChar("01932c08-0000-7000-8000-000000000000"),
+		want:  "-",
+	}, {
+		name:  "ulid and binary",
+		start: sqltypes.NewVarChar("01JCP0F86PFCY9YBJABDP7V3MZ"),
+		end:   sqltypes.MakeTrusted(sqltypes.VarBinary, uuidv7),
+		want:  "-" + hex.EncodeToString(uuidv7) + "01",
+	}, {
+		name:  "binary and hex text",
+		start: sqltypes.MakeTrusted(sqltypes.VarBinary, uuidv7),
+		end:   sqltypes.NewVarChar(hex.EncodeToString(uuidv7)),
+		want:  hex.EncodeToString(uuidv7) + "-",
+	}, {
+		name:  "binary text",
+		start: sqltypes.MakeTrusted(sqltypes.VarBinary, []byte("01932c07-0000-7000-8000-000000000000")),
+		end:   sqltypes.NULL,
+		want:  "-",
+	}, {
+		name:  "not a uuid",
+		start: sqltypes.NewVarChar("nope"),
+		end:   sqltypes.MakeTrusted(sqltypes.VarBinary, uuidv7),
+		want:  "-" + hex.EncodeToString(uuidv7) + "01",
+	}}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := uuidOrdered.(RangeVindex).MapRange(context.Background(), nil, tc.start, tc.end)
+			require.NoError(t, err)
+			require.Equal(t, tc.want, key.KeyRangeString(got))
+		})
+	}
+}
@@ -29,6 +29,7 @@ import (
 	"vitess.io/vitess/go/vt/vterrors"
 
 	querypb "vitess.io/vitess/go/vt/proto/query"
+	topodatapb "vitess.io/vitess/go/vt/proto/topodata"
 	vtgatepb "vitess.io/vitess/go/vt/proto/vtgate"
 	vtrpcpb "vitess.io/vitess/go/vt/proto/vtrpc"
 )
@@ -137,6 +138,16 @@ type (
 		RangeMap(ctx context.Context, vcursor VCursor, startId sqltypes.Value, endId sqltypes.Value) ([]key.ShardDestination, error)
 	}
 
+	// A RangeVindex is an optional interface for vindexes that preserve the order
+	// of their ids in the keyspace ids they produce. It maps an inclusive range of
+	// ids to the key range that contains all of their keyspace ids. A NULL start or
+	// end value leaves that side of the range unbounded. It's being used to reduce
+	// the fan out for '<', '<=', '>' and '>=' expressions.
+	RangeVindex interface {
+		SingleColumn
+		MapRange(ctx context.Context, vcursor VCursor, start sqltypes.Value, end sqltypes.Value) (*topodatapb.KeyRange, error)
+	}
+
 	// A Prefixable vindex is one that maps the prefix of a id to a keyspace range
 	// instead of a single keyspace id. It's being used to reduced the fan out for
 	// 'LIKE' expressions.
@@ -49,6 +49,7 @@ type VSchemaManager struct {
 	subscriber        func(vschema *vindexes.VSchema, stats *VSchemaStats)
 	schema            SchemaInfo
 	parser            *sqlparser.Parser
+	directories       *tenantDirectories
 }
 
 // SchemaInfo is an interface to schema tracker.
@@ -196,6 +197,9 @@ func (vm *VSchemaManager) Rebuild() {
 // buildAndEnhanceVSchema builds a new VSchema and uses information from the schema tracker to update it
 func (vm *VSchemaManager) buildAndEnhanceVSchema(v *vschemapb.SrvVSchema) *vindexes.VSchema {
 	vschema := vindexes.BuildVSchema(v, vm.parser)
+	if vm.directories != nil {
+		vm.directories.update(vschema)
+	}
 	if vm.schema != nil {
 		vm.updateFromSchema(vschema)
 		// We mark the keyspaces that have foreign key management in Vitess and have cyclic foreign keys
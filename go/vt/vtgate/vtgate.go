@@ -32,6 +32,7 @@ import (
 	"github.com/spf13/viper"
 
 	"vitess.io/vitess/go/acl"
+	"vitess.io/vitess/go/cache/warmup"
 	"vitess.io/vitess/go/mysql/sqlerror"
 	"vitess.io/vitess/go/sqltypes"
 	"vitess.io/vitess/go/stats"
@@ -75,6 +76,11 @@ var (
 
 	// plan cache related flag
 	queryPlanCacheMemory int64 = 32 * 1024 * 1024 // 32mb
+	queryPlanCacheWarmup       = warmup.Config{
+		Interval:    time.Minute,
+		MaxEntries:  10000,
+		MaxDuration: 30 * time.Second,
+	}
 
 	maxMemoryRows   = 300000
 	warnMemoryRows  = 30000
@@ -159,12 +165,17 @@ var (
 	queryLogToFile string
 	// queryLogBufferSize controls how many query logs will be buffered before dropping them if logging is not fast enough
 	queryLogBufferSize = 10
+	// queryStatsMaxDigests controls how many normalized queries SHOW VITESS_QUERY_STATS reports
+	queryStatsMaxDigests = 1000
 
 	messageStreamGracePeriod = 30 * time.Second
 
 	// allowKillStmt to allow execution of kill statement.
 	allowKillStmt bool
 
+	// processlistAuthorizedUsers can list the connections of all the users.
+	processlistAuthorizedUsers []string
+
 	warmingReadsPercent      = 0
 	warmingReadsQueryTimeout = 5 * time.Second
 	warmingReadsConcurrency  = 500
@@ -177,6 +188,10 @@ func registerFlags(fs *pflag.FlagSet) {
 	fs.IntVar(&truncateErrorLen, "truncate-error-len", truncateErrorLen, "truncate errors sent to client if they are longer than this value (0 means do not truncate)")
 	utils.SetFlagIntVar(fs, &streamBufferSize, "stream-buffer-size", streamBufferSize, "the number of bytes sent from vtgate for each stream call. It's recommended to keep this value in sync with vttablet's query-server-config-stream-buffer-size.")
 	utils.SetFlagInt64Var(fs, &queryPlanCacheMemory, "gate-query-cache-memory", queryPlanCacheMemory, "gate server query cache size in bytes, maximum amount of memory to be cached. vtgate analyzes every incoming query and generate a query plan, these plans are being cached in a lru cache. This config controls the capacity of the lru cache.")
+	fs.StringVar(&queryPlanCacheWarmup.File, "gate-query-cache-warmup-file", queryPlanCacheWarmup.File, "local file the queries of the query cache are periodically saved to, and replanned from at startup before accepting MySQL connections, so the cache does not start empty after a restart. Disabled if empty.")
+	fs.DurationVar(&queryPlanCacheWarmup.Interval, "gate-query-cache-warmup-interval", queryPlanCacheWarmup.Interval, "how often the queries of the query cache are saved to the warm-up file.")
+	fs.IntVar(&queryPlanCacheWarmup.MaxEntries, "gate-query-cache-warmup-max-entries", queryPlanCacheWarmup.MaxEntries, "maximum number of queries saved to, and replanned from, the query cache warm-up file. The most executed queries are kept.")
+	fs.DurationVar(&queryPlanCacheWarmup.MaxDuration, "gate-query-cache-warmup-max-duration", queryPlanCacheWarmup.MaxDuration, "maximum time spent replanning the queries of the query cache warm-up file at startup.")
 	utils.SetFlagIntVar(fs, &maxMemoryRows, "max-memory-rows", maxMemoryRows, "Maximum number of rows that will be held in memory for intermediate results as well as the final result.")
 	utils.SetFlagIntVar(fs, &warnMemoryRows, "warn-memory-rows", warnMemoryRows, "Warning threshold for in-memory results. A row count higher than this amount will cause the VtGateWarnings.ResultsExceeded counter to be incremented.")
 	utils.SetFlagStringVar(fs, &defaultDDLStrategy, "ddl-strategy", defaultDDLStrategy, "Set default strategy for DDL statements. Override with @@ddl_strategy session variable")
@@ -198,10 +213,12 @@ func registerFlags(fs *pflag.FlagSet) {
 	fs.IntVar(&queryTimeout, "query-timeout", queryTimeout, "Sets the default query timeout (in ms). Can be overridden by session variable (query_timeout) or comment directive (QUERY_TIMEOUT_MS)")
 	utils.SetFlagStringVar(fs, &queryLogToFile, "log-queries-to-file", queryLogToFile, "Enable query logging to the specified file")
 	fs.IntVar(&queryLogBufferSize, "querylog-buffer-size", queryLogBufferSize, "Maximum number of buffered query logs before throttling log output")
+	fs.IntVar(&queryStatsMaxDigests, "query-stats-max-digests", queryStatsMaxDigests, "Maximum number of normalized queries to keep statistics for in SHOW VITESS_QUERY_STATS. The executions of other queries are aggregated in a single row. 0 disables the statistics.")
 	utils.SetFlagDurationVar(fs, &messageStreamGracePeriod, "message-stream-grace-period", messageStreamGracePeriod, "the amount of time to give for a vttablet to resume if it ends a message stream, usually because of a reparent.")
 	fs.BoolVar(&enableViews, "enable-views", enableViews, "Enable views support in vtgate.")
 	fs.BoolVar(&enableUdfs, "track-udfs", enableUdfs, "Track UDFs in vtgate.")
 	fs.BoolVar(&allowKillStmt, "allow-kill-statement", allowKillStmt, "Allows the execution of kill statement")
+	fs.StringSliceVar(&processlistAuthorizedUsers, "processlist-authorized-users", processlistAuthorizedUsers, "List of users authorized to list the connections of all users with SHOW PROCESSLIST and SHOW VITESS_PROCESSLIST, or '%' to allow all users. The other users only list their own connections.")
 	fs.IntVar(&warmingReadsPercent, "warming-reads-percent", 0, "Percentage of reads on the primary to forward to replicas. Useful for keeping buffer pools warm")
 	fs.IntVar(&warmingReadsConcurrency, "warming-reads-concurrency", 500, "Number of concurrent warming reads allowed")
 	fs.DurationVar(&warmingReadsQueryTimeout, "warming-reads-query-timeout", 5*time.Second, "Timeout of warming read queries")
@@ -376,11 +393,12 @@ func Init(
 	plans := DefaultPlanCache()
 
 	eConfig := ExecutorConfig{
-		Normalize:           normalizeQueries,
-		StreamSize:          streamBufferSize,
-		AllowScatter:        !noScatter,
-		WarmingReadsPercent: warmingReadsPercent,
-		QueryLogToFile:      queryLogToFile,
+		Normalize:            normalizeQueries,
+		StreamSize:           streamBufferSize,
+		AllowScatter:         !noScatter,
+		WarmingReadsPercent:  warmingReadsPercent,
+		QueryLogToFile:       queryLogToFile,
+		QueryStatsMaxDigests: queryStatsMaxDigests,
 	}
 
 	executor := NewExecutor(ctx, env, serv, cell, resolver, eConfig, warnShardedOnly, plans, si, pv, dynamicConfig)
@@ -394,6 +412,8 @@ func Init(
 		st.RegisterSignalReceiver(executor.vm.Rebuild)
 	}
 
+	planSnapshotter := warmup.NewSnapshotter(queryPlanCacheWarmup, executor.planCacheKeys)
+
 	vtgateInst := newVTGate(executor, resolver, vsm, tc, gw)
 	_ = stats.NewRates("QPSByOperation", stats.CounterForDimension(vtgateInst.timings, "Operation"), 15, 1*time.Minute)
 	_ = stats.NewRates("QPSByKeyspace", stats.CounterForDimension(vtgateInst.timings, "Keyspace"), 15, 1*time.Minute)
@@ -412,6 +432,9 @@ func Init(
 			st.Start()
 		}
 		tr.Start()
+		// Warm the plan cache up before accepting MySQL connections.
+		executor.WarmUpPlanCache(ctx, queryPlanCacheWarmup)
+		planSnapshotter.Start()
 		srv := initMySQLProtocol(vtgateInst)
 		if srv != nil {
 			servenv.OnTermSync(srv.shutdownMysqlProtocolAndDrain)
@@ -423,6 +446,7 @@ func Init(
 			st.Stop()
 		}
 		tr.Stop()
+		planSnapshotter.Stop()
 	})
 	vtgateInst.registerDebugHealthHandler()
 	vtgateInst.registerDebugEnvHandler()
@@ -34,6 +34,13 @@ var customRule1 = `[
 						"OnAbsent": false,
 						"Operator": ""
 					}]
+				},
+				{
+					"Name": "r2",
+					"Description": "limit selects on table t",
+					"TableNames": ["t"],
+					"Action": "RATE_LIMIT",
+					"MaxQPS": 100
 				}
 			]`
 
@@ -63,4 +70,13 @@ func TestFileCustomRule(t *testing.T) {
 	if qr == nil {
 		t.Fatalf("Expect custom rule r1 to be found, but got nothing, qrs=%v", qrs)
 	}
+	qr = qrs.Find("r2")
+	if qr == nil {
+		t.Fatalf("Expect custom rule r2 to be found, but got nothing, qrs=%v", qrs)
+	}
+	want := rules.NewRateLimitQueryRule("limit selects on table t", "r2", 100, 0)
+	want.AddTableCond("t")
+	if !qr.Equal(want) {
+		t.Errorf("Expect custom rule r2 to rate limit, got %v", qr)
+	}
 }
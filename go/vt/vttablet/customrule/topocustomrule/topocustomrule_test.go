@@ -46,6 +46,24 @@ var customRule2 = `
     "Description": "disallow insert on table test",
     "TableNames" : ["test"],
     "Query" : "(insert)|(INSERT)"
+  },
+  {
+    "Name": "r3",
+    "Description": "limit concurrent selects on table test",
+    "TableNames" : ["test"],
+    "Query" : "select.*",
+    "Action": "CONCURRENCY_LIMIT",
+    "MaxConcurrency": 10
+  },
+  {
+    "Name": "r4",
+    "Description": "force the index of the selects on table test",
+    "TableNames" : ["test"],
+    "Action": "REWRITE",
+    "Rewrite": {
+      "IndexHints": [{"Table": "test", "Type": "FORCE", "Indexes": ["idx_a"]}],
+      "MaxExecutionTime": 1000
+    }
   }
 ]`
 
@@ -0,0 +1,140 @@
+/*
+Copyright 2026 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tabletmanager
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"vitess.io/vitess/go/vt/log"
+	"vitess.io/vitess/go/vt/mysqlctl"
+	"vitess.io/vitess/go/vt/topo/topoproto"
+	"vitess.io/vitess/go/vt/vttablet/tabletserver/throttle"
+	"vitess.io/vitess/go/vt/vttablet/tabletserver/throttle/base"
+	"vitess.io/vitess/go/vt/vttablet/tabletserver/throttle/throttlerapp"
+
+	tabletmanagerdatapb "vitess.io/vitess/go/vt/proto/tabletmanagerdata"
+	topodatapb "vitess.io/vitess/go/vt/proto/topodata"
+)
+
+// backupThrottleCheckInterval is how long a throttler check is trusted for,
+// and how long the I/O waits before checking again once throttled.
+const backupThrottleCheckInterval = 250 * time.Millisecond
+
+// backupThrottler implements mysqlctl.IOThrottler with the tablet throttler:
+// the backups and restores slow down while the replication lag, or whichever
+// metric the throttler is configured with, is too high.
+//
+// The throttler of a tablet only runs while the tablet serves. Online backups
+// check it with the self scope. Offline backups and restores run while the
+// tablet does not serve, so they check the throttler of the primary of the
+// shard instead, with the shard scope.
+type backupThrottler struct {
+	tm *TabletManager
+
+	mu      sync.Mutex
+	okTill  time.Time
+	primary *topodatapb.Tablet
+}
+
+var _ mysqlctl.IOThrottler = (*backupThrottler)(nil)
+
+func newBackupThrottler(tm *TabletManager) *backupThrottler {
+	return &backupThrottler{tm: tm}
+}
+
+// Throttle is part of the mysqlctl.IOThrottler interface.
+func (t *backupThrottler) Throttle(ctx context.Context) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	for time.Now().After(t.okTill) {
+		if t.check(ctx) {
+			t.okTill = time.Now().Add(backupThrottleCheckInterval)
+			return
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(backupThrottleCheckInterval):
+		}
+	}
+}
+
+// check returns whether the throttler lets the backup proceed. The backup
+// proceeds if the throttler can't be checked, rather than stall.
+func (t *backupThrottler) check(ctx context.Context) bool {
+	qsc := t.tm.QueryServiceControl
+	if qsc == nil {
+		return true
+	}
+	if qsc.IsServing() {
+		checkResult := qsc.CheckThrottler(ctx, throttlerapp.BackupName.String(), &throttle.CheckFlags{
+			Scope:                 base.SelfScope,
+			SkipRequestHeartbeats: true,
+			OKIfNotExists:         true,
+		})
+		return checkResult == nil || checkResult.IsOK()
+	}
+	if t.tm.TopoServer == nil || t.tm.tmc == nil {
+		return true
+	}
+
+	primary, err := t.primaryTablet(ctx)
+	if err != nil {
+		log.Warningf("Backup throttler cannot find the primary of the shard: %v", err)
+		return true
+	}
+	if primary == nil || topoproto.TabletAliasEqual(primary.Alias, t.tm.tabletAlias) {
+		return true
+	}
+	resp, err := t.tm.tmc.CheckThrottler(ctx, primary, &tabletmanagerdatapb.CheckThrottlerRequest{
+		AppName:       throttlerapp.BackupName.String(),
+		Scope:         string(base.ShardScope),
+		OkIfNotExists: true,
+	})
+	if err != nil {
+		log.Warningf("Backup throttler cannot check the throttler of primary %v: %v", topoproto.TabletAliasString(primary.Alias), err)
+		// The primary may have changed.
+		t.primary = nil
+		return true
+	}
+	return resp.ResponseCode == tabletmanagerdatapb.CheckThrottlerResponseCode_OK
+}
+
+// primaryTablet returns the primary of the shard of the tablet, or nil if the
+// shard has none.
+func (t *backupThrottler) primaryTablet(ctx context.Context) (*topodatapb.Tablet, error) {
+	if t.primary != nil {
+		return t.primary, nil
+	}
+	tablet := t.tm.Tablet()
+	si, err := t.tm.TopoServer.GetShard(ctx, tablet.Keyspace, tablet.Shard)
+	if err != nil {
+		return nil, err
+	}
+	if si.PrimaryAlias == nil {
+		return nil, nil
+	}
+	ti, err := t.tm.TopoServer.GetTablet(ctx, si.PrimaryAlias)
+	if err != nil {
+		return nil, err
+	}
+	t.primary = ti.Tablet
+	return t.primary, nil
+}
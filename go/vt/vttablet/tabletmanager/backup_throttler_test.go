@@ -0,0 +1,144 @@
+/*
+Copyright 2026 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tabletmanager
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"vitess.io/vitess/go/vt/topo"
+	"vitess.io/vitess/go/vt/topo/memorytopo"
+	"vitess.io/vitess/go/vt/topo/topoproto"
+	"vitess.io/vitess/go/vt/vttablet/tabletserver/throttle"
+	"vitess.io/vitess/go/vt/vttablet/tabletservermock"
+	"vitess.io/vitess/go/vt/vttablet/tmclient"
+
+	tabletmanagerdatapb "vitess.io/vitess/go/vt/proto/tabletmanagerdata"
+	topodatapb "vitess.io/vitess/go/vt/proto/topodata"
+)
+
+// throttlerController is a tabletserver.Controller whose throttler rejects
+// the checks while throttled is set.
+type throttlerController struct {
+	*tabletservermock.Controller
+	throttled atomic.Bool
+}
+
+func (c *throttlerController) CheckThrottler(ctx context.Context, appName string, flags *throttle.CheckFlags) *throttle.CheckResult {
+	if c.throttled.Load() {
+		return &throttle.CheckResult{ResponseCode: tabletmanagerdatapb.CheckThrottlerResponseCode_THRESHOLD_EXCEEDED}
+	}
+	return &throttle.CheckResult{ResponseCode: tabletmanagerdatapb.CheckThrottlerResponseCode_OK}
+}
+
+// throttlerTMClient answers the throttler checks of other tablets, rejecting
+// them while throttled is set.
+type throttlerTMClient struct {
+	tmclient.TabletManagerClient
+	throttled atomic.Bool
+
+	mu       sync.Mutex
+	checked  []*topodatapb.TabletAlias
+	requests []*tabletmanagerdatapb.CheckThrottlerRequest
+}
+
+func (c *throttlerTMClient) CheckThrottler(ctx context.Context, tablet *topodatapb.Tablet, req *tabletmanagerdatapb.CheckThrottlerRequest) (*tabletmanagerdatapb.CheckThrottlerResponse, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.checked = append(c.checked, tablet.Alias)
+	c.requests = append(c.requests, req)
+	if c.throttled.Load() {
+		return &tabletmanagerdatapb.CheckThrottlerResponse{ResponseCode: tabletmanagerdatapb.CheckThrottlerResponseCode_THRESHOLD_EXCEEDED}, nil
+	}
+	return &tabletmanagerdatapb.CheckThrottlerResponse{ResponseCode: tabletmanagerdatapb.CheckThrottlerResponseCode_OK}, nil
+}
+
+// assertThrottles checks that Throttle blocks until unthrottle is called.
+func assertThrottles(t *testing.T, throttler *backupThrottler, unthrottle func()) {
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		throttler.Throttle(context.Background())
+	}()
+	select {
+	case <-done:
+		t.Fatal("Throttle returned while throttled")
+	case <-time.After(3 * backupThrottleCheckInterval):
+	}
+	unthrottle()
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("Throttle did not return once unthrottled")
+	}
+}
+
+func TestBackupThrottler(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	ts := memorytopo.NewServer(ctx, "cell1")
+	defer ts.Close()
+
+	primary := newTestTablet(t, 1, "ks", "0", nil)
+	primary.Type = topodatapb.TabletType_PRIMARY
+	require.NoError(t, ts.CreateKeyspace(ctx, "ks", &topodatapb.Keyspace{}))
+	require.NoError(t, ts.CreateShard(ctx, "ks", "0"))
+	require.NoError(t, ts.CreateTablet(ctx, primary))
+	_, err := ts.UpdateShardFields(ctx, "ks", "0", func(si *topo.ShardInfo) error {
+		si.PrimaryAlias = primary.Alias
+		return nil
+	})
+	require.NoError(t, err)
+
+	tablet := newTestTablet(t, 2, "ks", "0", nil)
+	qsc := &throttlerController{Controller: tabletservermock.NewController()}
+	tmc := &throttlerTMClient{}
+	tm := &TabletManager{
+		BatchCtx:            ctx,
+		TopoServer:          ts,
+		QueryServiceControl: qsc,
+		tmc:                 tmc,
+		tabletAlias:         tablet.Alias,
+	}
+	tm.tmState = newTMState(tm, tablet)
+
+	// A serving tablet, e.g. during an online backup, checks its own
+	// throttler.
+	qsc.SetQueryServiceEnabledForTests(true)
+	qsc.throttled.Store(true)
+	assertThrottles(t, newBackupThrottler(tm), func() { qsc.throttled.Store(false) })
+	assert.Empty(t, tmc.checked)
+
+	// A tablet that does not serve, e.g. during an offline backup or a
+	// restore, checks the throttler of the primary, with the shard scope.
+	qsc.SetQueryServiceEnabledForTests(false)
+	qsc.throttled.Store(true)
+	tmc.throttled.Store(true)
+	assertThrottles(t, newBackupThrottler(tm), func() { tmc.throttled.Store(false) })
+	tmc.mu.Lock()
+	defer tmc.mu.Unlock()
+	require.NotEmpty(t, tmc.checked)
+	assert.Equal(t, "cell1-0000000001", topoproto.TabletAliasString(tmc.checked[0]))
+	assert.Equal(t, "shard", tmc.requests[0].Scope)
+	assert.Equal(t, "backup", tmc.requests[0].AppName)
+}
@@ -0,0 +1,191 @@
+/*
+Copyright 2026 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tabletmanager
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/spf13/pflag"
+
+	"vitess.io/vitess/go/stats"
+	"vitess.io/vitess/go/vt/log"
+	"vitess.io/vitess/go/vt/logutil"
+	"vitess.io/vitess/go/vt/mysqlctl"
+	"vitess.io/vitess/go/vt/mysqlctl/backupstats"
+	"vitess.io/vitess/go/vt/mysqlctl/backupstorage"
+	"vitess.io/vitess/go/vt/servenv"
+	"vitess.io/vitess/go/vt/topo/topoproto"
+
+	topodatapb "vitess.io/vitess/go/vt/proto/topodata"
+)
+
+var (
+	// binlogArchiveInterval is how often closed binary logs are archived. Zero disables archiving.
+	binlogArchiveInterval time.Duration
+
+	statsBinlogArchiveFiles  = stats.NewCounter("BinlogArchiveFiles", "Number of binary logs archived to the backup storage")
+	statsBinlogArchiveErrors = stats.NewCounter("BinlogArchiveErrors", "Number of failed binary log archiving runs")
+	statsBinlogArchiveLag    = stats.NewGauge("BinlogArchiveLagSeconds", "Seconds between the last transaction of the closed binary logs and the latest transaction that can be recovered from the backups of the shard")
+)
+
+// closedBinlog is the last closed binary log, and the time of its last transaction. It spares
+// reading the binary log at every run.
+type closedBinlog struct {
+	name          string
+	lastTimestamp time.Time
+}
+
+func registerBinlogArchiveFlags(fs *pflag.FlagSet) {
+	fs.DurationVar(&binlogArchiveInterval, "binlog-archive-interval", binlogArchiveInterval, "if set, continuously archive the closed binary logs to the backup storage, as incremental backups of one binary log each, checking for new binary logs at this interval. Enable it on one tablet per shard.")
+}
+
+func init() {
+	servenv.OnParseFor("vttablet", registerBinlogArchiveFlags)
+}
+
+func (tm *TabletManager) startBinlogArchive() {
+	if binlogArchiveInterval <= 0 {
+		return
+	}
+	tm.mutex.Lock()
+	defer tm.mutex.Unlock()
+	tm._binlogArchiveDone = make(chan struct{})
+	ctx, cancel := context.WithCancel(context.Background())
+	tm._binlogArchiveCancel = cancel
+
+	go tm.binlogArchiveLoop(ctx, binlogArchiveInterval, tm._binlogArchiveDone)
+}
+
+func (tm *TabletManager) stopBinlogArchive() {
+	var doneChan <-chan struct{}
+
+	tm.mutex.Lock()
+	if tm._binlogArchiveCancel != nil {
+		tm._binlogArchiveCancel()
+	}
+	doneChan = tm._binlogArchiveDone
+	tm.mutex.Unlock()
+
+	// If the binlog archive loop was running, wait for it to fully stop.
+	if doneChan != nil {
+		<-doneChan
+	}
+}
+
+// binlogArchiveLoop archives the closed binary logs at every interval, until ctx is done.
+func (tm *TabletManager) binlogArchiveLoop(ctx context.Context, interval time.Duration, done chan<- struct{}) {
+	defer close(done)
+
+	var lastClosed closedBinlog
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+		if err := tm.archiveBinlogs(ctx, &lastClosed); err != nil {
+			statsBinlogArchiveErrors.Add(1)
+			log.Errorf("Failed to archive binary logs: %v", err)
+		}
+	}
+}
+
+// archiveBinlogs archives the closed binary logs that are not yet in the backup storage, and
+// updates the archive lag of the shard.
+func (tm *TabletManager) archiveBinlogs(ctx context.Context, lastClosed *closedBinlog) error {
+	if tm.Cnf == nil {
+		return fmt.Errorf("cannot archive binary logs without my.cnf, please restart vttablet with a my.cnf file specified")
+	}
+	// Archiving holds the backup guard for the whole run, so that no backup starts meanwhile.
+	if err := tm.beginBackup(backupModeBinlogArchive); err != nil {
+		// Binary logs are archived again once the backup is over.
+		return nil
+	}
+	defer tm.endBackup(backupModeBinlogArchive)
+	tablet := tm.Tablet()
+	if tablet.Type == topodatapb.TabletType_RESTORE {
+		// Binary logs are archived again once the restore is over.
+		return nil
+	}
+
+	logger := logutil.NewConsoleLogger()
+	archived, err := mysqlctl.ArchiveBinlogs(ctx, mysqlctl.BackupParams{
+		Cnf:                  tm.Cnf,
+		Mysqld:               tm.MysqlDaemon,
+		Logger:               logger,
+		Concurrency:          1,
+		HookExtraEnv:         tm.hookExtraEnv(),
+		TopoServer:           tm.TopoServer,
+		Keyspace:             tablet.Keyspace,
+		Shard:                tablet.Shard,
+		TabletAlias:          topoproto.TabletAliasString(tablet.Alias),
+		Stats:                backupstats.BackupStats(),
+		MysqlShutdownTimeout: mysqlShutdownTimeout,
+	})
+	statsBinlogArchiveFiles.Add(int64(archived))
+	// The lag is updated even if archiving failed, so that it grows with the binary logs left to
+	// archive.
+	if lagErr := tm.updateBinlogArchiveLag(ctx, tablet, lastClosed, logger); lagErr != nil {
+		log.Errorf("Failed to update the binary log archive lag: %v", lagErr)
+	}
+	return err
+}
+
+// updateBinlogArchiveLag sets the archive lag of the shard, from the last transaction of the
+// closed binary logs and the latest recovery point of the shard. The binary log MySQL writes to is
+// not archived yet, so its transactions don't count.
+func (tm *TabletManager) updateBinlogArchiveLag(ctx context.Context, tablet *topodatapb.Tablet, lastClosed *closedBinlog, logger logutil.Logger) error {
+	binlogs, err := tm.MysqlDaemon.GetBinaryLogs(ctx)
+	if err != nil {
+		return err
+	}
+	if len(binlogs) > 1 {
+		closed := binlogs[:len(binlogs)-1]
+		if name := closed[len(closed)-1]; name != lastClosed.name {
+			lastTimestamp, err := mysqlctl.BinlogsLastTimestamp(ctx, tm.Cnf, tm.MysqlDaemon, closed)
+			if err != nil {
+				return err
+			}
+			*lastClosed = closedBinlog{name: name, lastTimestamp: lastTimestamp}
+		}
+	}
+
+	bs, err := backupstorage.GetBackupStorage()
+	if err != nil {
+		return err
+	}
+	defer bs.Close()
+	recoveryPoint, err := mysqlctl.LatestRecoveryPoint(ctx, bs, tablet.Keyspace, tablet.Shard, logger)
+	if err != nil {
+		return err
+	}
+	statsBinlogArchiveLag.Set(int64(binlogArchiveLag(lastClosed.lastTimestamp, recoveryPoint).Seconds()))
+	return nil
+}
+
+// binlogArchiveLag returns how far the recovery point is behind the last transaction of the
+// closed binary logs. It is zero once all of them are archived, even if the shard is idle.
+func binlogArchiveLag(lastTransaction, recoveryPoint time.Time) time.Duration {
+	if lastTransaction.IsZero() || !lastTransaction.After(recoveryPoint) {
+		return 0
+	}
+	return lastTransaction.Sub(recoveryPoint)
+}
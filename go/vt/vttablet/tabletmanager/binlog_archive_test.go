@@ -0,0 +1,80 @@
+/*
+Copyright 2026 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tabletmanager
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"vitess.io/vitess/go/vt/mysqlctl"
+)
+
+func TestBinlogArchiveLoop(t *testing.T) {
+	oldInterval := binlogArchiveInterval
+	defer func() { binlogArchiveInterval = oldInterval }()
+
+	// Archiving is disabled by default.
+	tm := &TabletManager{}
+	tm.startBinlogArchive()
+	assert.Nil(t, tm._binlogArchiveDone)
+	tm.stopBinlogArchive()
+
+	// Failed runs are counted, and don't stop the loop. Without my.cnf, all
+	// runs fail.
+	binlogArchiveInterval = 10 * time.Millisecond
+	errors := statsBinlogArchiveErrors.Get()
+	tm.startBinlogArchive()
+	assert.Eventually(t, func() bool {
+		return statsBinlogArchiveErrors.Get() >= errors+2
+	}, 5*time.Second, 10*time.Millisecond)
+	tm.stopBinlogArchive()
+	select {
+	case <-tm._binlogArchiveDone:
+	default:
+		t.Fatal("binlog archive loop is still running")
+	}
+}
+
+func TestArchiveBinlogsDuringBackup(t *testing.T) {
+	tm := &TabletManager{Cnf: &mysqlctl.Mycnf{}}
+	require.NoError(t, tm.beginBackup(backupModeOnline))
+
+	// Binary logs are not archived while a backup runs.
+	errors := statsBinlogArchiveErrors.Get()
+	require.NoError(t, tm.archiveBinlogs(context.Background(), &closedBinlog{}))
+	assert.Equal(t, errors, statsBinlogArchiveErrors.Get())
+	assert.True(t, tm.IsBackupRunning())
+
+	tm.endBackup(backupModeOnline)
+	assert.False(t, tm.IsBackupRunning())
+}
+
+func TestBinlogArchiveLag(t *testing.T) {
+	recoveryPoint := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	// Without closed binary logs, or once they are all archived, there is no lag, however long
+	// ago the last transaction was.
+	assert.Zero(t, binlogArchiveLag(time.Time{}, recoveryPoint))
+	assert.Zero(t, binlogArchiveLag(recoveryPoint, recoveryPoint))
+	assert.Zero(t, binlogArchiveLag(recoveryPoint.Add(-time.Hour), recoveryPoint))
+
+	assert.Equal(t, 90*time.Second, binlogArchiveLag(recoveryPoint.Add(90*time.Second), recoveryPoint))
+}
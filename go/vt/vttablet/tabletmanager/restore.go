@@ -29,6 +29,7 @@ import (
 	"vitess.io/vitess/go/stats"
 
 	"vitess.io/vitess/go/vt/hook"
+	"vitess.io/vitess/go/vt/key"
 	"vitess.io/vitess/go/vt/log"
 	"vitess.io/vitess/go/vt/logutil"
 	"vitess.io/vitess/go/vt/mysqlctl"
@@ -49,6 +50,7 @@ var (
 	restoreFromBackup               bool
 	restoreFromBackupAllowedEngines []string
 	restoreFromBackupTsStr          string
+	restoreFromShard                string
 	restoreConcurrency              = 4
 	waitForBackupInterval           time.Duration
 
@@ -60,6 +62,7 @@ func registerRestoreFlags(fs *pflag.FlagSet) {
 	utils.SetFlagBoolVar(fs, &restoreFromBackup, "restore-from-backup", restoreFromBackup, "(init restore parameter) will check BackupStorage for a recent backup at startup and start there")
 	fs.StringSliceVar(&restoreFromBackupAllowedEngines, "restore-from-backup-allowed-engines", restoreFromBackupAllowedEngines, "(init restore parameter) if set, only backups taken with the specified engines are eligible to be restored")
 	utils.SetFlagStringVar(fs, &restoreFromBackupTsStr, "restore-from-backup-ts", restoreFromBackupTsStr, "(init restore parameter) if set, restore the latest backup taken at or before this timestamp. Example: '2021-04-29.133050'")
+	fs.StringVar(&restoreFromShard, "restore-from-shard", restoreFromShard, "(init restore parameter) if set, restore a logical backup of this shard of the keyspace, keeping only the rows in the key range of the tablet, e.g. to restore into split shards. The shard must cover the key range of the tablet, merges are not supported")
 	utils.SetFlagIntVar(fs, &restoreConcurrency, "restore-concurrency", restoreConcurrency, "(init restore parameter) how many concurrent files to restore at once")
 	utils.SetFlagDurationVar(fs, &waitForBackupInterval, "wait-for-backup-interval", waitForBackupInterval, "(init restore parameter) if this is greater than 0, instead of starting up empty when no backups are found, keep checking at this interval for a backup to appear")
 }
@@ -200,6 +203,24 @@ func (tm *TabletManager) restoreDataLocked(ctx context.Context, logger logutil.L
 		Stats:                backupstats.RestoreStats(),
 		MysqlShutdownTimeout: mysqlShutdownTimeout,
 		AllowedBackupEngines: request.AllowedBackupEngines,
+		IOThrottler:          newBackupThrottler(tm),
+	}
+	// Restoring the backup of another shard only keeps the rows in the key
+	// range of the tablet, which only logical backups can do. A restore reads
+	// a single backup, so the shard must cover the whole key range of the
+	// tablet: a merge of shards can't be restored.
+	fromOtherShard := restoreFromShard != "" && restoreFromShard != tablet.Shard
+	if fromOtherShard {
+		rowFilter, err := newKeyRangeRowFilter(ctx, tm.TopoServer, tm.Env.Parser(), keyspace, params.DbName, restoreFromShard, tablet.KeyRange)
+		if err != nil {
+			return vterrors.Wrapf(err, "cannot restore shard %v into shard %v", restoreFromShard, tablet.Shard)
+		}
+		params.Shard = restoreFromShard
+		params.RowFilter = rowFilter
+		if len(params.AllowedBackupEngines) == 0 {
+			params.AllowedBackupEngines = []string{(&mysqlctl.LogicalBackupEngine{}).Name()}
+		}
+		log.Infof("Restoring the rows of key range %v of a backup of shard %v", key.KeyRangeString(tablet.KeyRange), restoreFromShard)
 	}
 	restoreToTimestamp := protoutil.TimeFromProto(request.RestoreToTimestamp).UTC()
 	if request.RestoreToPos != "" && !restoreToTimestamp.IsZero() {
@@ -278,6 +299,11 @@ func (tm *TabletManager) restoreDataLocked(ctx context.Context, logger logutil.L
 			if err := tm.disableReplication(context.Background()); err != nil {
 				return err
 			}
+		} else if fromOtherShard {
+			// The restored data is at no position of the shard: the restore
+			// left the GTID position empty, rather than the one of the
+			// other shard, and the tablet can't replicate from it.
+			params.Logger.Infof("Restore: not starting replication, the backup is from shard %v", restoreFromShard)
 		} else if keyspaceInfo.KeyspaceType == topodatapb.KeyspaceType_NORMAL {
 			// Reconnect to primary only for "NORMAL" keyspaces
 			params.Logger.Infof("Restore: starting replication at position %v", pos)
@@ -0,0 +1,118 @@
+/*
+Copyright 2026 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tabletmanager
+
+import (
+	"context"
+
+	"vitess.io/vitess/go/sqltypes"
+	"vitess.io/vitess/go/vt/key"
+	"vitess.io/vitess/go/vt/mysqlctl"
+	"vitess.io/vitess/go/vt/sqlparser"
+	"vitess.io/vitess/go/vt/topo"
+	"vitess.io/vitess/go/vt/vterrors"
+	"vitess.io/vitess/go/vt/vtgate/vindexes"
+
+	topodatapb "vitess.io/vitess/go/vt/proto/topodata"
+	vtrpcpb "vitess.io/vitess/go/vt/proto/vtrpc"
+)
+
+// keyRangeRowFilter restores the rows of the tables of a keyspace whose
+// keyspace id, as mapped by the primary vindex of their table, is in a key
+// range. The rows of the reference and sequence tables, and of the other
+// databases, are all restored.
+type keyRangeRowFilter struct {
+	dbName   string
+	keyspace *vindexes.KeyspaceSchema
+	keyRange *topodatapb.KeyRange
+}
+
+var _ mysqlctl.RestoreRowFilter = (*keyRangeRowFilter)(nil)
+
+// newKeyRangeRowFilter returns the filter of the rows of a key range, read
+// from the vschema of the keyspace, to restore a backup of a shard. A
+// restore reads a single backup, so the shard must cover the whole key
+// range: a merge of shards can't be restored.
+func newKeyRangeRowFilter(ctx context.Context, ts *topo.Server, parser *sqlparser.Parser, keyspace, dbName, shard string, keyRange *topodatapb.KeyRange) (*keyRangeRowFilter, error) {
+	_, shardKeyRange, err := topo.ValidateShardName(shard)
+	if err != nil {
+		return nil, err
+	}
+	if !key.KeyRangeContainsKeyRange(shardKeyRange, keyRange) {
+		return nil, vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "shard %v does not cover key range %v, restoring a merge of shards is not supported", shard, key.KeyRangeString(keyRange))
+	}
+	vschema, err := ts.GetVSchema(ctx, keyspace)
+	if err != nil {
+		return nil, err
+	}
+	ks, err := vindexes.BuildKeyspaceSchema(vschema.Keyspace, keyspace, parser)
+	if err != nil {
+		return nil, err
+	}
+	if !ks.Keyspace.Sharded {
+		return nil, vterrors.Errorf(vtrpcpb.Code_FAILED_PRECONDITION, "keyspace %v is not sharded", keyspace)
+	}
+	return &keyRangeRowFilter{dbName: dbName, keyspace: ks, keyRange: keyRange}, nil
+}
+
+// TableFilter is part of the mysqlctl.RestoreRowFilter interface.
+func (f *keyRangeRowFilter) TableFilter(database, table string, columns []string) (*mysqlctl.RestoreTableFilter, error) {
+	if database != f.dbName {
+		return nil, nil
+	}
+	t := f.keyspace.Tables[table]
+	if t == nil {
+		return nil, vterrors.Errorf(vtrpcpb.Code_FAILED_PRECONDITION, "table %v is not in the vschema of keyspace %v", table, f.keyspace.Keyspace.Name)
+	}
+	if t.Type == vindexes.TypeReference || t.Type == vindexes.TypeSequence {
+		return nil, nil
+	}
+	if len(t.ColumnVindexes) == 0 {
+		return nil, vterrors.Errorf(vtrpcpb.Code_FAILED_PRECONDITION, "table %v has no primary vindex", table)
+	}
+	primary := t.ColumnVindexes[0]
+	if primary.Vindex.NeedsVCursor() {
+		return nil, vterrors.Errorf(vtrpcpb.Code_FAILED_PRECONDITION, "primary vindex %v of table %v needs to query the database", primary.Name, table)
+	}
+	indexes := make([]int, len(primary.Columns))
+	for i, column := range primary.Columns {
+		indexes[i] = -1
+		for j, name := range columns {
+			if column.EqualString(name) {
+				indexes[i] = j
+				break
+			}
+		}
+		if indexes[i] == -1 {
+			return nil, vterrors.Errorf(vtrpcpb.Code_FAILED_PRECONDITION, "column %v of the primary vindex of table %v is not in the backup", column.String(), table)
+		}
+	}
+	return &mysqlctl.RestoreTableFilter{
+		Columns: indexes,
+		Keep: func(ctx context.Context, values []sqltypes.Value) (bool, error) {
+			destinations, err := vindexes.Map(ctx, primary.Vindex, nil, [][]sqltypes.Value{values})
+			if err != nil {
+				return false, err
+			}
+			ksid, ok := destinations[0].(key.DestinationKeyspaceID)
+			if !ok {
+				return false, vterrors.Errorf(vtrpcpb.Code_FAILED_PRECONDITION, "cannot map a row of table %v to a keyspace id: %v", table, destinations[0])
+			}
+			return key.KeyRangeContains(f.keyRange, ksid), nil
+		},
+	}, nil
+}
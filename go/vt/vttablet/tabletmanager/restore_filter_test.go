@@ -0,0 +1,102 @@
+/*
+Copyright 2026 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tabletmanager
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"vitess.io/vitess/go/sqltypes"
+	"vitess.io/vitess/go/vt/key"
+	"vitess.io/vitess/go/vt/sqlparser"
+	"vitess.io/vitess/go/vt/topo"
+	"vitess.io/vitess/go/vt/topo/memorytopo"
+
+	vschemapb "vitess.io/vitess/go/vt/proto/vschema"
+)
+
+func TestKeyRangeRowFilter(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	ts := memorytopo.NewServer(ctx, "cell1")
+	defer ts.Close()
+
+	require.NoError(t, ts.SaveVSchema(ctx, &topo.KeyspaceVSchemaInfo{
+		Name: "ks",
+		Keyspace: &vschemapb.Keyspace{
+			Sharded: true,
+			Vindexes: map[string]*vschemapb.Vindex{
+				"hash": {Type: "hash"},
+			},
+			Tables: map[string]*vschemapb.Table{
+				"t1": {ColumnVindexes: []*vschemapb.ColumnVindex{{Column: "id", Name: "hash"}}},
+				"r1": {Type: "reference"},
+			},
+		},
+	}))
+
+	// The hash vindex maps 1, 2 and 3 into -80, and 4 into 80-.
+	keyRanges, err := key.ParseShardingSpec("-80-")
+	require.NoError(t, err)
+	for i, expected := range [][]int64{{1, 2, 3}, {4}} {
+		f, err := newKeyRangeRowFilter(ctx, ts, sqlparser.NewTestParser(), "ks", "vt_ks", "0", keyRanges[i])
+		require.NoError(t, err)
+
+		filter, err := f.TableFilter("vt_ks", "t1", []string{"name", "id"})
+		require.NoError(t, err)
+		require.NotNil(t, filter)
+		assert.Equal(t, []int{1}, filter.Columns)
+		var kept []int64
+		for id := int64(1); id <= 4; id++ {
+			keep, err := filter.Keep(ctx, []sqltypes.Value{sqltypes.NewInt64(id)})
+			require.NoError(t, err)
+			if keep {
+				kept = append(kept, id)
+			}
+		}
+		assert.Equal(t, expected, kept)
+
+		// The reference tables, and the other databases, are restored whole.
+		filter, err = f.TableFilter("vt_ks", "r1", []string{"id"})
+		require.NoError(t, err)
+		assert.Nil(t, filter)
+		filter, err = f.TableFilter("_vt", "t1", []string{"id"})
+		require.NoError(t, err)
+		assert.Nil(t, filter)
+
+		_, err = f.TableFilter("vt_ks", "t2", []string{"id"})
+		assert.ErrorContains(t, err, "table t2 is not in the vschema of keyspace ks")
+		_, err = f.TableFilter("vt_ks", "t1", []string{"name"})
+		assert.ErrorContains(t, err, "column id of the primary vindex of table t1 is not in the backup")
+	}
+
+	// A shard can be restored into the shards it is split into, not into
+	// a shard it is merged into.
+	_, err = newKeyRangeRowFilter(ctx, ts, sqlparser.NewTestParser(), "ks", "vt_ks", "-80", keyRanges[0])
+	assert.NoError(t, err)
+	_, err = newKeyRangeRowFilter(ctx, ts, sqlparser.NewTestParser(), "ks", "vt_ks", "-40", keyRanges[0])
+	assert.ErrorContains(t, err, "shard -40 does not cover key range -80, restoring a merge of shards is not supported")
+	_, err = newKeyRangeRowFilter(ctx, ts, sqlparser.NewTestParser(), "ks", "vt_ks", "80-", keyRanges[0])
+	assert.ErrorContains(t, err, "shard 80- does not cover key range -80")
+
+	require.NoError(t, ts.SaveVSchema(ctx, &topo.KeyspaceVSchemaInfo{Name: "unsharded", Keyspace: &vschemapb.Keyspace{}}))
+	_, err = newKeyRangeRowFilter(ctx, ts, sqlparser.NewTestParser(), "unsharded", "vt_unsharded", "0", keyRanges[0])
+	assert.ErrorContains(t, err, "keyspace unsharded is not sharded")
+}
@@ -39,6 +39,8 @@ import (
 const (
 	backupModeOnline  = "online"
 	backupModeOffline = "offline"
+	// backupModeBinlogArchive is the mode of the binary log archiving runs, which exclude backups.
+	backupModeBinlogArchive = "binlog_archive"
 )
 
 // Backup takes a db backup and sends it to the BackupStorage.
@@ -172,6 +174,7 @@ func (tm *TabletManager) Backup(ctx context.Context, logger logutil.Logger, req
 		UpgradeSafe:          req.UpgradeSafe,
 		MysqlShutdownTimeout: shutdownTimeout(l, req.MysqlShutdownTimeout),
 		BackupEngine:         backupEngine,
+		IOThrottler:          newBackupThrottler(tm),
 	}
 
 	returnErr := mysqlctl.Backup(ctx, backupParams)
@@ -217,6 +217,13 @@ type TabletManager struct {
 	// in progress
 	_rebuildKeyspaceCancel context.CancelFunc
 
+	// _binlogArchiveDone is a channel for waiting until the binlog archive
+	// goroutine has really finished after _binlogArchiveCancel was called.
+	_binlogArchiveDone chan struct{}
+
+	// _binlogArchiveCancel is the function to stop the background binlog archive goroutine.
+	_binlogArchiveCancel context.CancelFunc
+
 	// _lockTablesConnection is used to get and release the table read locks to pause replication
 	_lockTablesConnection *dbconnpool.DBConnection
 	_lockTablesTimer      *time.Timer
@@ -424,6 +431,7 @@ func (tm *TabletManager) Start(tablet *topodatapb.Tablet, config *tabletenv.Tabl
 	// The following initializations don't need to be done
 	// in any specific order.
 	tm.startShardSync()
+	tm.startBinlogArchive()
 	tm.exportStats()
 	servenv.OnRun(tm.registerTabletManager)
 
@@ -462,6 +470,7 @@ func (tm *TabletManager) Close() {
 	// running during lame duck.
 	tm.stopShardSync()
 	tm.stopRebuildKeyspace()
+	tm.stopBinlogArchive()
 
 	// cleanup initialized fields in the tablet entry
 	f := func(tablet *topodatapb.Tablet) error {
@@ -493,6 +502,7 @@ func (tm *TabletManager) Stop() {
 	// here in addition to in Close() because tests do not call Close().
 	tm.stopShardSync()
 	tm.stopRebuildKeyspace()
+	tm.stopBinlogArchive()
 
 	if tm.QueryServiceControl != nil {
 		tm.QueryServiceControl.Stats().Stop()
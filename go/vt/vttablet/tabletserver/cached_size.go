@@ -25,7 +25,7 @@ func (cached *TabletPlan) CachedSize(alloc bool) int64 {
 	}
 	size := int64(0)
 	if alloc {
-		size += int64(112)
+		size += int64(128)
 	}
 	// field Plan *vitess.io/vitess/go/vt/vttablet/tabletserver/planbuilder.Plan
 	size += cached.Plan.CachedSize(true)
@@ -40,5 +40,26 @@ func (cached *TabletPlan) CachedSize(alloc bool) int64 {
 			size += elem.CachedSize(true)
 		}
 	}
+	// field rewrites []vitess.io/vitess/go/vt/vttablet/tabletserver.rewrittenPlan
+	{
+		size += hack.RuntimeAllocSize(int64(cap(cached.rewrites)) * int64(16))
+		for _, elem := range cached.rewrites {
+			size += elem.CachedSize(false)
+		}
+	}
+	return size
+}
+func (cached *rewrittenPlan) CachedSize(alloc bool) int64 {
+	if cached == nil {
+		return int64(0)
+	}
+	size := int64(0)
+	if alloc {
+		size += int64(16)
+	}
+	// field rule *vitess.io/vitess/go/vt/vttablet/tabletserver/rules.Rule
+	size += cached.rule.CachedSize(true)
+	// field plan *vitess.io/vitess/go/vt/vttablet/tabletserver.TabletPlan
+	size += cached.plan.CachedSize(true)
 	return size
 }
@@ -93,12 +93,13 @@ func (dte *DTExecutor) Prepare(transactionID int64, dtid string) error {
 
 	// Fail Prepare if any query rule disallows it.
 	// This could be due to ongoing cutover happening in vreplication workflow
-	// regarding OnlineDDL or MoveTables.
+	// regarding OnlineDDL or MoveTables. Rate and concurrency limits were
+	// already applied when the queries ran.
 	for _, query := range queries {
 		qr := dte.qe.queryRuleSources.FilterByPlan(query.Sql, 0, query.Tables...)
 		if qr != nil {
-			act, _, _, _ := qr.GetAction("", "", nil, sqlparser.MarginComments{})
-			if act != rules.QRContinue {
+			act, _, _, _, _ := qr.GetAction("", "", nil, sqlparser.MarginComments{})
+			if act != rules.QRContinue && !act.IsLimit() {
 				dte.te.txPool.RollbackAndRelease(dte.ctx, conn)
 				return vterrors.VT10002("cannot prepare the transaction due to query rule")
 			}
@@ -117,8 +118,8 @@ func (dte *DTExecutor) Prepare(transactionID int64, dtid string) error {
 	for _, query := range queries {
 		qr := dte.qe.queryRuleSources.FilterByPlan(query.Sql, 0, query.Tables...)
 		if qr != nil {
-			act, _, _, _ := qr.GetAction("", "", nil, sqlparser.MarginComments{})
-			if act != rules.QRContinue {
+			act, _, _, _, _ := qr.GetAction("", "", nil, sqlparser.MarginComments{})
+			if act != rules.QRContinue && !act.IsLimit() {
 				dte.te.txPool.RollbackAndRelease(dte.ctx, conn)
 				dte.te.preparedPool.FetchForRollback(dtid)
 				return vterrors.VT10002("cannot prepare the transaction due to query rule")
@@ -23,6 +23,27 @@ type cachedObject interface {
 	CachedSize(alloc bool) int64
 }
 
+func (cached *IndexHintRewrite) CachedSize(alloc bool) int64 {
+	if cached == nil {
+		return int64(0)
+	}
+	size := int64(0)
+	if alloc {
+		size += int64(64)
+	}
+	// field Table string
+	size += hack.RuntimeAllocSize(int64(len(cached.Table)))
+	// field Type string
+	size += hack.RuntimeAllocSize(int64(len(cached.Type)))
+	// field Indexes []string
+	{
+		size += hack.RuntimeAllocSize(int64(cap(cached.Indexes)) * int64(16))
+		for _, elem := range cached.Indexes {
+			size += hack.RuntimeAllocSize(int64(len(elem)))
+		}
+	}
+	return size
+}
 func (cached *Permission) CachedSize(alloc bool) int64 {
 	if cached == nil {
 		return int64(0)
@@ -73,3 +94,20 @@ func (cached *Plan) CachedSize(alloc bool) int64 {
 	}
 	return size
 }
+func (cached *Rewrite) CachedSize(alloc bool) int64 {
+	if cached == nil {
+		return int64(0)
+	}
+	size := int64(0)
+	if alloc {
+		size += int64(48)
+	}
+	// field IndexHints []vitess.io/vitess/go/vt/vttablet/tabletserver/planbuilder.IndexHintRewrite
+	{
+		size += hack.RuntimeAllocSize(int64(cap(cached.IndexHints)) * int64(56))
+		for _, elem := range cached.IndexHints {
+			size += elem.CachedSize(false)
+		}
+	}
+	return size
+}
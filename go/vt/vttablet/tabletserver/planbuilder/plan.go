@@ -80,6 +80,7 @@ const (
 	PlanShowMigrationLogs
 	PlanShowThrottledApps
 	PlanShowThrottlerStatus
+	PlanShowVitessProcesslist
 	NumPlans
 )
 
@@ -116,6 +117,7 @@ var planName = []string{
 	"ShowMigrationLogs",
 	"ShowThrottledApps",
 	"ShowThrottlerStatus",
+	"ShowVitessProcesslist",
 }
 
 func (pt PlanType) String() string {
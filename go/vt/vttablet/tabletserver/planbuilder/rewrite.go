@@ -0,0 +1,179 @@
+/*
+Copyright 2026 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package planbuilder
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"vitess.io/vitess/go/vt/sqlparser"
+	"vitess.io/vitess/go/vt/vterrors"
+
+	vtrpcpb "vitess.io/vitess/go/vt/proto/vtrpc"
+)
+
+// Rewrite describes how to rewrite the statement of a query before it
+// is planned. It is used by the REWRITE action of the query rules, to fix
+// the plan of a query without changing the application.
+type Rewrite struct {
+	// IndexHints replace the index hints of the matching tables of the
+	// selects. The tables of DMLs, including those of their subqueries, are
+	// left untouched.
+	IndexHints []IndexHintRewrite `json:",omitempty"`
+
+	// Limit adds a LIMIT clause to the selects that have none, and lowers
+	// the literal limits that are higher. Limits that are bind variables
+	// are left untouched.
+	Limit int `json:",omitempty"`
+
+	// MaxExecutionTime adds a MAX_EXECUTION_TIME optimizer hint, in
+	// milliseconds, to the selects that do not have one.
+	MaxExecutionTime int `json:",omitempty"`
+}
+
+// IndexHintRewrite is an index hint injected by a Rewrite.
+type IndexHintRewrite struct {
+	// Table is the name of the table the hint applies to. If empty, the
+	// hint applies to all the tables of the query.
+	Table string `json:",omitempty"`
+	// Type is one of USE, FORCE or IGNORE.
+	Type    string
+	Indexes []string
+}
+
+var indexHintTypes = map[string]sqlparser.IndexHintType{
+	"USE":    sqlparser.UseOp,
+	"FORCE":  sqlparser.ForceOp,
+	"IGNORE": sqlparser.IgnoreOp,
+}
+
+// Validate returns an error if the Rewrite cannot be applied.
+func (rw *Rewrite) Validate() error {
+	if len(rw.IndexHints) == 0 && rw.Limit == 0 && rw.MaxExecutionTime == 0 {
+		return vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "empty rewrite")
+	}
+	for _, hint := range rw.IndexHints {
+		if _, ok := indexHintTypes[strings.ToUpper(hint.Type)]; !ok {
+			return vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "invalid index hint type: %s", hint.Type)
+		}
+		if len(hint.Indexes) == 0 && !strings.EqualFold(hint.Type, "USE") {
+			return vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "%s index hint requires indexes", strings.ToUpper(hint.Type))
+		}
+	}
+	if rw.Limit < 0 {
+		return vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "invalid limit: %d", rw.Limit)
+	}
+	if rw.MaxExecutionTime < 0 {
+		return vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "invalid max execution time: %d", rw.MaxExecutionTime)
+	}
+	return nil
+}
+
+// Apply returns a rewritten copy of the statement, and whether it differs
+// from the original. The original statement is never modified.
+func (rw *Rewrite) Apply(statement sqlparser.Statement) (sqlparser.Statement, bool) {
+	stmt := sqlparser.Clone(statement)
+	changed := false
+	if len(rw.IndexHints) != 0 {
+		changed = rw.applyIndexHints(stmt) || changed
+	}
+	if rw.Limit != 0 {
+		changed = rw.applyLimit(stmt) || changed
+	}
+	if rw.MaxExecutionTime != 0 {
+		changed = rw.applyMaxExecutionTime(stmt) || changed
+	}
+	if !changed {
+		return statement, false
+	}
+	return stmt, true
+}
+
+func (rw *Rewrite) applyIndexHints(stmt sqlparser.Statement) bool {
+	if _, ok := stmt.(sqlparser.SelectStatement); !ok {
+		return false
+	}
+	changed := false
+	_ = sqlparser.Walk(func(node sqlparser.SQLNode) (bool, error) {
+		aliased, ok := node.(*sqlparser.AliasedTableExpr)
+		if !ok {
+			return true, nil
+		}
+		tableName, ok := aliased.Expr.(sqlparser.TableName)
+		if !ok {
+			return true, nil
+		}
+		var hints sqlparser.IndexHints
+		for _, hint := range rw.IndexHints {
+			if hint.Table != "" && !strings.EqualFold(hint.Table, tableName.Name.String()) {
+				continue
+			}
+			indexHint := &sqlparser.IndexHint{Type: indexHintTypes[strings.ToUpper(hint.Type)]}
+			for _, index := range hint.Indexes {
+				indexHint.Indexes = append(indexHint.Indexes, sqlparser.NewIdentifierCI(index))
+			}
+			hints = append(hints, indexHint)
+		}
+		if hints != nil {
+			aliased.Hints = hints
+			changed = true
+		}
+		return true, nil
+	}, stmt)
+	return changed
+}
+
+func (rw *Rewrite) applyLimit(stmt sqlparser.Statement) bool {
+	sel, ok := stmt.(sqlparser.SelectStatement)
+	if !ok {
+		return false
+	}
+	limit := sel.GetLimit()
+	if limit == nil {
+		sel.SetLimit(&sqlparser.Limit{Rowcount: sqlparser.NewIntLiteral(strconv.Itoa(rw.Limit))})
+		return true
+	}
+	rowcount, ok := limit.Rowcount.(*sqlparser.Literal)
+	if !ok || rowcount.Type != sqlparser.IntVal {
+		return false
+	}
+	if n, err := strconv.ParseUint(rowcount.Val, 10, 64); err != nil || n <= uint64(rw.Limit) {
+		return false
+	}
+	limit.Rowcount = sqlparser.NewIntLiteral(strconv.Itoa(rw.Limit))
+	return true
+}
+
+func (rw *Rewrite) applyMaxExecutionTime(stmt sqlparser.Statement) bool {
+	// MySQL only honors MAX_EXECUTION_TIME for top level selects.
+	sel, ok := stmt.(*sqlparser.Select)
+	if !ok {
+		return false
+	}
+	for _, comment := range sel.GetParsedComments().GetComments() {
+		if strings.Contains(strings.ToUpper(comment), "MAX_EXECUTION_TIME") {
+			return false
+		}
+	}
+	comments, err := sel.GetParsedComments().AddQueryHint(fmt.Sprintf("MAX_EXECUTION_TIME(%d)", rw.MaxExecutionTime))
+	if err != nil {
+		return false
+	}
+	sel.SetComments(comments)
+	return true
+}
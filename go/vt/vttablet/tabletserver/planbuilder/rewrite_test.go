@@ -0,0 +1,139 @@
+/*
+Copyright 2026 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package planbuilder
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"vitess.io/vitess/go/vt/sqlparser"
+)
+
+func TestRewriteApply(t *testing.T) {
+	testcases := []struct {
+		name     string
+		rewrite  Rewrite
+		query    string
+		expected string
+	}{{
+		name:     "force index",
+		rewrite:  Rewrite{IndexHints: []IndexHintRewrite{{Table: "a", Type: "force", Indexes: []string{"idx_b"}}}},
+		query:    "select * from a join b on a.id = b.id where a.b = 1",
+		expected: "select * from a force index (idx_b) join b on a.id = b.id where a.b = 1",
+	}, {
+		name:     "index hint replaces the existing ones",
+		rewrite:  Rewrite{IndexHints: []IndexHintRewrite{{Type: "IGNORE", Indexes: []string{"idx_b"}}}},
+		query:    "select * from a use index (idx_c) where b = 1",
+		expected: "select * from a ignore index (idx_b) where b = 1",
+	}, {
+		name:     "index hint in a subquery",
+		rewrite:  Rewrite{IndexHints: []IndexHintRewrite{{Table: "b", Type: "USE", Indexes: []string{"idx_c"}}}},
+		query:    "select * from a where id in (select id from b where c = 2)",
+		expected: "select * from a where id in (select id from b use index (idx_c) where c = 2)",
+	}, {
+		name:    "index hint of an update",
+		rewrite: Rewrite{IndexHints: []IndexHintRewrite{{Type: "FORCE", Indexes: []string{"idx_c"}}}},
+		query:   "update a set c = 1 where id in (select id from b where c = 2)",
+	}, {
+		name:    "index hint of a delete",
+		rewrite: Rewrite{IndexHints: []IndexHintRewrite{{Type: "FORCE", Indexes: []string{"idx_c"}}}},
+		query:   "delete a from a join b on a.id = b.id where b.c = 2",
+	}, {
+		name:    "index hint for another table",
+		rewrite: Rewrite{IndexHints: []IndexHintRewrite{{Table: "c", Type: "USE", Indexes: []string{"idx_c"}}}},
+		query:   "select * from a",
+	}, {
+		name:     "add limit",
+		rewrite:  Rewrite{Limit: 100},
+		query:    "select * from a",
+		expected: "select * from a limit 100",
+	}, {
+		name:     "lower limit",
+		rewrite:  Rewrite{Limit: 100},
+		query:    "select * from a union select * from b limit 10, 1000",
+		expected: "select * from a union select * from b limit 10, 100",
+	}, {
+		name:    "smaller existing limit",
+		rewrite: Rewrite{Limit: 100},
+		query:   "select * from a limit 10",
+	}, {
+		name:    "limit bind variable",
+		rewrite: Rewrite{Limit: 100},
+		query:   "select * from a limit :vtg1",
+	}, {
+		name:    "limit of a dml",
+		rewrite: Rewrite{Limit: 100},
+		query:   "delete from a where b = 1",
+	}, {
+		name:     "max execution time",
+		rewrite:  Rewrite{MaxExecutionTime: 1000},
+		query:    "select /*+ SET_VAR(sort_buffer_size = 16M) */ * from a",
+		expected: "select /*+ SET_VAR(sort_buffer_size = 16M) MAX_EXECUTION_TIME(1000) */ * from a",
+	}, {
+		name:    "existing max execution time",
+		rewrite: Rewrite{MaxExecutionTime: 1000},
+		query:   "select /*+ MAX_EXECUTION_TIME(10) */ * from a",
+	}, {
+		name:     "everything",
+		rewrite:  Rewrite{IndexHints: []IndexHintRewrite{{Type: "FORCE", Indexes: []string{"idx_b"}}}, Limit: 10, MaxExecutionTime: 1000},
+		query:    "select * from a where b = 1",
+		expected: "select /*+ MAX_EXECUTION_TIME(1000) */ * from a force index (idx_b) where b = 1 limit 10",
+	}}
+	parser := sqlparser.NewTestParser()
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			require.NoError(t, tc.rewrite.Validate())
+			stmt, err := parser.Parse(tc.query)
+			require.NoError(t, err)
+
+			rewritten, changed := tc.rewrite.Apply(stmt)
+			assert.Equal(t, tc.expected != "", changed)
+			if changed {
+				assert.Equal(t, tc.expected, sqlparser.String(rewritten))
+			}
+			// The original statement is left untouched.
+			assert.Equal(t, tc.query, sqlparser.String(stmt))
+		})
+	}
+}
+
+func TestRewriteValidate(t *testing.T) {
+	testcases := []struct {
+		rewrite Rewrite
+		err     string
+	}{{
+		rewrite: Rewrite{},
+		err:     "empty rewrite",
+	}, {
+		rewrite: Rewrite{IndexHints: []IndexHintRewrite{{Type: "PREFER", Indexes: []string{"a"}}}},
+		err:     "invalid index hint type: PREFER",
+	}, {
+		rewrite: Rewrite{IndexHints: []IndexHintRewrite{{Type: "FORCE"}}},
+		err:     "FORCE index hint requires indexes",
+	}, {
+		rewrite: Rewrite{Limit: -1},
+		err:     "invalid limit: -1",
+	}, {
+		rewrite: Rewrite{MaxExecutionTime: -1},
+		err:     "invalid max execution time: -1",
+	}}
+	for _, tc := range testcases {
+		assert.EqualError(t, tc.rewrite.Validate(), tc.err)
+	}
+}
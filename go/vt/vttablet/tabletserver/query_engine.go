@@ -18,11 +18,13 @@ package tabletserver
 
 import (
 	"bytes"
+	"cmp"
 	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"net/http"
+	"slices"
 	"strings"
 	"sync"
 	"sync/atomic"
@@ -30,6 +32,7 @@ import (
 
 	"vitess.io/vitess/go/acl"
 	"vitess.io/vitess/go/cache/theine"
+	"vitess.io/vitess/go/cache/warmup"
 	"vitess.io/vitess/go/mysql/sqlerror"
 	"vitess.io/vitess/go/pools/smartconnpool"
 	"vitess.io/vitess/go/stats"
@@ -63,6 +66,10 @@ type TabletPlan struct {
 	Rules      *rules.Rules
 	Authorized []*tableacl.ACLResult
 
+	// rewrites are the plans of the query as rewritten by the QRRewrite
+	// rules matching it, in the order of the rules.
+	rewrites []rewrittenPlan
+
 	QueryCount   uint64
 	Time         uint64
 	MysqlTime    uint64
@@ -71,6 +78,12 @@ type TabletPlan struct {
 	ErrorCount   uint64
 }
 
+// rewrittenPlan is the plan of a query rewritten by a QRRewrite rule.
+type rewrittenPlan struct {
+	rule *rules.Rule
+	plan *TabletPlan
+}
+
 // AddStats updates the stats for the current TabletPlan.
 func (ep *TabletPlan) AddStats(queryCount uint64, duration, mysqlTime time.Duration, rowsAffected, rowsReturned, errorCount uint64) {
 	atomic.AddUint64(&ep.QueryCount, queryCount)
@@ -100,6 +113,28 @@ func (ep *TabletPlan) buildAuthorized() {
 	}
 }
 
+// buildRewrites builds 'rewrites', the plans of the statement rewritten by
+// the QRRewrite rules of 'Rules'. They are built along with, and cached
+// with, the plan of the original query. A rewrite that does not change the
+// statement, or that cannot be planned, is ignored.
+func (ep *TabletPlan) buildRewrites(statement sqlparser.Statement, build func(sqlparser.Statement) (*planbuilder.Plan, error)) {
+	for _, rule := range ep.Rules.Rewrites() {
+		stmt, changed := rule.Rewrite().Apply(statement)
+		if !changed {
+			continue
+		}
+		splan, err := build(stmt)
+		if err != nil {
+			log.Warningf("Ignoring query rule %s, the rewritten query cannot be planned: %v", rule.Name, err)
+			continue
+		}
+		ep.rewrites = append(ep.rewrites, rewrittenPlan{
+			rule: rule,
+			plan: &TabletPlan{Plan: splan, Original: ep.Original, Rules: ep.Rules, Authorized: ep.Authorized},
+		})
+	}
+}
+
 func (ep *TabletPlan) IsValid(hasReservedCon, hasSysSettings bool) error {
 	if !ep.NeedsReservedConn {
 		return nil
@@ -155,6 +190,10 @@ type QueryEngine struct {
 	settings         *SettingsCache
 	queryRuleSources *rules.Map
 
+	// planSnapshotter saves the keys of the plan cache, to warm it up
+	// when the engine opens.
+	planSnapshotter *warmup.Snapshotter[string]
+
 	// Pools
 	conns       *connpool.Pool
 	streamConns *connpool.Pool
@@ -216,6 +255,7 @@ func NewQueryEngine(env tabletenv.Env, se *schema.Engine) *QueryEngine {
 	// Cache for query plans: user configured size with a doorkeeper by default to prevent one-off queries
 	// from thrashing the cache.
 	qe.plans = theine.NewStore[PlanCacheKey, *TabletPlan](config.QueryCacheMemory, config.QueryCacheDoorkeeper)
+	qe.planSnapshotter = warmup.NewSnapshotter(config.QueryCacheWarmup, qe.planCacheKeys)
 
 	// cache for connection settings: default to 1/4th of the size for the query cache and do
 	// not use a doorkeeper because custom connection settings are rarely one-off and we always
@@ -350,6 +390,8 @@ func (qe *QueryEngine) Open() error {
 	qe.se.RegisterNotifier("qe", qe.schemaChanged, true)
 	qe.plans.EnsureOpen()
 	qe.settings.EnsureOpen()
+	qe.warmUpPlanCache()
+	qe.planSnapshotter.Start()
 	qe.isOpen.Store(true)
 	return nil
 }
@@ -362,6 +404,7 @@ func (qe *QueryEngine) Close() {
 		return
 	}
 	// Close in reverse order of Open.
+	qe.planSnapshotter.Stop()
 	qe.se.UnregisterNotifier("qe")
 
 	qe.plans.Close()
@@ -386,6 +429,9 @@ func (qe *QueryEngine) getPlan(curSchema *currentSchema, sql string, noRowsLimit
 	plan := &TabletPlan{Plan: splan, Original: sql}
 	plan.Rules = qe.queryRuleSources.FilterByPlan(sql, plan.PlanID, plan.TableNames()...)
 	plan.buildAuthorized()
+	plan.buildRewrites(statement, func(stmt sqlparser.Statement) (*planbuilder.Plan, error) {
+		return planbuilder.Build(qe.env.Environment(), stmt, curSchema.tables, qe.env.Config().DB.DBName, noRowsLimit)
+	})
 	if sqlparser.CachePlan(statement) {
 		return plan, nil
 	}
@@ -432,6 +478,9 @@ func (qe *QueryEngine) getStreamPlan(curSchema *currentSchema, sql string) (*Tab
 	plan := &TabletPlan{Plan: splan, Original: sql}
 	plan.Rules = qe.queryRuleSources.FilterByPlan(sql, plan.PlanID, plan.TableName().String())
 	plan.buildAuthorized()
+	plan.buildRewrites(statement, func(stmt sqlparser.Statement) (*planbuilder.Plan, error) {
+		return planbuilder.BuildStreaming(stmt, curSchema.tables)
+	})
 
 	if sqlparser.CachePlan(statement) {
 		return plan, nil
@@ -464,15 +513,20 @@ func (qe *QueryEngine) GetStreamPlan(ctx context.Context, logStats *tabletenv.Lo
 	return plan, err
 }
 
+const (
+	streamPlanCacheKeyPrefix    = "__STREAM__"
+	unlimitedPlanCacheKeyPrefix = "__UNLIMITED__"
+)
+
 // gets key used to cache stream query plan
 func (qe *QueryEngine) getStreamPlanCacheKey(sql string) string {
-	return "__STREAM__" + sql
+	return streamPlanCacheKeyPrefix + sql
 }
 
 // gets key used to cache stream query plan
 func (qe *QueryEngine) getPlanCacheKey(sql string, noRowsLimit bool) string {
 	if noRowsLimit {
-		return "__UNLIMITED__" + sql
+		return unlimitedPlanCacheKeyPrefix + sql
 	}
 	return sql
 }
@@ -533,6 +587,52 @@ func (qe *QueryEngine) ForEachPlan(each func(plan *TabletPlan) bool) {
 	})
 }
 
+// planCacheKeys returns the keys of the plan cache, the most executed
+// plans first, and at most maxEntries of them if it is positive.
+func (qe *QueryEngine) planCacheKeys(maxEntries int) []string {
+	type cachedPlan struct {
+		key   string
+		count uint64
+	}
+	var plans []cachedPlan
+	curSchema := qe.schema.Load()
+	qe.plans.Range(curSchema.epoch, func(key PlanCacheKey, plan *TabletPlan) bool {
+		plans = append(plans, cachedPlan{key: string(key), count: atomic.LoadUint64(&plan.QueryCount)})
+		return true
+	})
+	slices.SortStableFunc(plans, func(a, b cachedPlan) int {
+		return cmp.Compare(b.count, a.count)
+	})
+	if maxEntries > 0 && len(plans) > maxEntries {
+		plans = plans[:maxEntries]
+	}
+	keys := make([]string, 0, len(plans))
+	for _, plan := range plans {
+		keys = append(keys, plan.key)
+	}
+	return keys
+}
+
+// warmUpPlanCache replans the queries of the plan cache snapshot, so the
+// cache does not start empty after a restart.
+func (qe *QueryEngine) warmUpPlanCache() {
+	warmup.WarmUp(context.Background(), qe.env.Config().QueryCacheWarmup, func(ctx context.Context, key string) error {
+		// The queries of the snapshot are known not to be one-offs.
+		qe.plans.Admit(PlanCacheKey(key))
+		logStats := tabletenv.NewLogStats(ctx, "PlanCacheWarmup", streamlog.GetQueryLogConfig())
+		var err error
+		switch {
+		case strings.HasPrefix(key, streamPlanCacheKeyPrefix):
+			_, err = qe.GetStreamPlan(ctx, logStats, strings.TrimPrefix(key, streamPlanCacheKeyPrefix), false)
+		case strings.HasPrefix(key, unlimitedPlanCacheKeyPrefix):
+			_, err = qe.GetPlan(ctx, logStats, strings.TrimPrefix(key, unlimitedPlanCacheKeyPrefix), false, true)
+		default:
+			_, err = qe.GetPlan(ctx, logStats, key, false, false)
+		}
+		return err
+	})
+}
+
 // IsMySQLReachable returns an error if it cannot connect to MySQL.
 // This can be called before opening the QueryEngine.
 func (qe *QueryEngine) IsMySQLReachable() error {
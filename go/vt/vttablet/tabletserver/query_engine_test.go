@@ -24,6 +24,7 @@ import (
 	"net/http/httptest"
 	"os"
 	"path"
+	"path/filepath"
 	"reflect"
 	"strings"
 	"sync"
@@ -221,6 +222,60 @@ func TestQueryPlanCache(t *testing.T) {
 	qe.ClearQueryPlanCache()
 }
 
+func TestQueryPlanCacheWarmup(t *testing.T) {
+	db := fakesqldb.New(t)
+	defer db.Close()
+	schematest.AddDefaultQueries(db)
+	db.AddQuery("select * from test_table_01 where 1 != 1", &sqltypes.Result{})
+	db.AddQuery("select * from test_table_02 where 1 != 1", &sqltypes.Result{})
+
+	snapshot := filepath.Join(t.TempDir(), "plans")
+	newQueryEngine := func() *QueryEngine {
+		cfg := tabletenv.NewDefaultConfig()
+		cfg.DB = newDBConfigs(db)
+		cfg.QueryCacheWarmup.File = snapshot
+		cfg.QueryCacheWarmup.Interval = time.Hour
+		env := tabletenv.NewEnv(vtenv.NewTestEnv(), cfg, "TabletServerTest")
+		se := schema.NewEngine(env)
+		se.InitDBConfig(cfg.DB.DbaWithDB())
+		qe := NewQueryEngine(env, se)
+		se.Open()
+		require.NoError(t, qe.Open())
+		return qe
+	}
+
+	ctx := context.Background()
+	logStats := tabletenv.NewLogStats(ctx, "GetPlanStats", streamlog.NewQueryLogConfigForTest())
+	qe := newQueryEngine()
+	// The doorkeeper only caches the plans of the queries seen twice.
+	for range 2 {
+		_, err := qe.GetPlan(ctx, logStats, "select * from test_table_01", false, false)
+		require.NoError(t, err)
+		_, err = qe.GetPlan(ctx, logStats, "select * from test_table_02", false, true)
+		require.NoError(t, err)
+		_, err = qe.GetStreamPlan(ctx, logStats, "select * from test_table_01", false)
+		require.NoError(t, err)
+	}
+	assertPlanCacheSize(t, qe, 3)
+	// Closing the engine snapshots the plan cache.
+	qe.Close()
+
+	// Opening a new engine warms its plan cache up from the snapshot.
+	qe = newQueryEngine()
+	defer qe.Close()
+	assert.ElementsMatch(t, []string{
+		"select * from test_table_01",
+		"__UNLIMITED__select * from test_table_02",
+		"__STREAM__select * from test_table_01",
+	}, qe.planCacheKeys(0))
+
+	// The warmed up plans are cache hits.
+	initialMisses := qe.queryEnginePlanCacheMisses.Get()
+	_, err := qe.GetPlan(ctx, logStats, "select * from test_table_01", false, false)
+	require.NoError(t, err)
+	assert.Equal(t, initialMisses, qe.queryEnginePlanCacheMisses.Get())
+}
+
 func TestNoQueryPlanCache(t *testing.T) {
 	db := fakesqldb.New(t)
 	defer db.Close()
@@ -21,6 +21,7 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"slices"
 	"strings"
 	"sync"
 	"time"
@@ -123,7 +124,10 @@ func (qre *QueryExecutor) shouldConsolidate() bool {
 
 // Execute performs a non-streaming query execution.
 func (qre *QueryExecutor) Execute() (reply *sqltypes.Result, err error) {
-	planName := qre.plan.PlanID.String()
+	// The stats are recorded against the plan of the original query, even
+	// if it gets rewritten.
+	plan := qre.plan
+	planName := plan.PlanID.String()
 	qre.logStats.PlanType = planName
 	defer func(start time.Time) {
 		duration := time.Since(start)
@@ -132,7 +136,7 @@ func (qre *QueryExecutor) Execute() (reply *sqltypes.Result, err error) {
 		qre.recordUserQuery("Execute", int64(duration))
 
 		mysqlTime := qre.logStats.MysqlResponseTime
-		tableName := qre.plan.TableName().String()
+		tableName := plan.TableName().String()
 		if tableName == "" {
 			tableName = "Join"
 		}
@@ -142,21 +146,24 @@ func (qre *QueryExecutor) Execute() (reply *sqltypes.Result, err error) {
 		errCode = vtErrorCode.String()
 
 		if reply == nil {
-			qre.tsv.qe.AddStats(qre.plan, tableName, qre.options.GetWorkloadName(), qre.targetTabletType, 1, duration, mysqlTime, 0, 0, 1, errCode)
-			qre.plan.AddStats(1, duration, mysqlTime, 0, 0, 1)
+			qre.tsv.qe.AddStats(plan, tableName, qre.options.GetWorkloadName(), qre.targetTabletType, 1, duration, mysqlTime, 0, 0, 1, errCode)
+			plan.AddStats(1, duration, mysqlTime, 0, 0, 1)
 			return
 		}
 
-		qre.tsv.qe.AddStats(qre.plan, tableName, qre.options.GetWorkloadName(), qre.targetTabletType, 1, duration, mysqlTime, int64(reply.RowsAffected), int64(len(reply.Rows)), 0, errCode)
-		qre.plan.AddStats(1, duration, mysqlTime, reply.RowsAffected, uint64(len(reply.Rows)), 0)
+		qre.tsv.qe.AddStats(plan, tableName, qre.options.GetWorkloadName(), qre.targetTabletType, 1, duration, mysqlTime, int64(reply.RowsAffected), int64(len(reply.Rows)), 0, errCode)
+		plan.AddStats(1, duration, mysqlTime, reply.RowsAffected, uint64(len(reply.Rows)), 0)
 		qre.logStats.RowsAffected = int(reply.RowsAffected)
 		qre.logStats.Rows = reply.Rows
 		qre.tsv.Stats().ResultHistogram.Add(int64(len(reply.Rows)))
 	}(time.Now())
 
-	if err = qre.checkPermissions(); err != nil {
+	release, err := qre.checkPermissions()
+	if err != nil {
 		return nil, err
 	}
+	defer release()
+	qre.applyRewrite()
 
 	if qre.plan.PlanID == p.PlanNextval {
 		return qre.execNextval()
@@ -221,6 +228,8 @@ func (qre *QueryExecutor) Execute() (reply *sqltypes.Result, err error) {
 		return qre.execShowThrottledApps()
 	case p.PlanShowThrottlerStatus:
 		return qre.execShowThrottlerStatus()
+	case p.PlanShowVitessProcesslist:
+		return qre.execShowVitessProcesslist()
 	case p.PlanUnlockTables:
 		return nil, vterrors.Errorf(vtrpcpb.Code_FAILED_PRECONDITION, "unlock tables should be executed with an existing connection")
 	case p.PlanSet:
@@ -332,6 +341,8 @@ func (qre *QueryExecutor) txConnExec(conn *StatefulConnection) (*sqltypes.Result
 		return qre.execProc(conn)
 	case p.PlanShowMigrations:
 		return qre.execShowMigrations(conn)
+	case p.PlanShowVitessProcesslist:
+		return qre.execShowVitessProcesslist()
 	}
 	return nil, vterrors.Errorf(vtrpcpb.Code_INTERNAL, "[BUG] %s unexpected plan type", qre.plan.PlanID.String())
 }
@@ -346,9 +357,12 @@ func (qre *QueryExecutor) Stream(callback StreamCallback) error {
 		qre.recordUserQuery("Stream", int64(time.Since(start)))
 	}(time.Now())
 
-	if err := qre.checkPermissions(); err != nil {
+	release, err := qre.checkPermissions()
+	if err != nil {
 		return err
 	}
+	defer release()
+	qre.applyRewrite()
 
 	switch qre.plan.PlanID {
 	case p.PlanSelectStream:
@@ -436,9 +450,11 @@ func (qre *QueryExecutor) MessageStream(callback StreamCallback) error {
 		qre.recordUserQuery("MessageStream", int64(time.Since(start)))
 	}(time.Now())
 
-	if err := qre.checkPermissions(); err != nil {
+	release, err := qre.checkPermissions()
+	if err != nil {
 		return err
 	}
+	defer release()
 
 	done, err := qre.tsv.messager.Subscribe(qre.ctx, qre.plan.TableName().String(), func(r *sqltypes.Result) error {
 		select {
@@ -455,33 +471,53 @@ func (qre *QueryExecutor) MessageStream(callback StreamCallback) error {
 	return nil
 }
 
+// callerInfo returns the remote address and the user name of the caller.
+func (qre *QueryExecutor) callerInfo() (remoteAddr, username string) {
+	if ci, ok := callinfo.FromContext(qre.ctx); ok {
+		return ci.RemoteAddr(), ci.Username()
+	}
+	return "", ""
+}
+
+// applyRewrite switches to the plan of the rewritten query if a QRRewrite
+// rule matches the query. Only the first matching rule applies.
+func (qre *QueryExecutor) applyRewrite() {
+	if len(qre.plan.rewrites) == 0 || tabletenv.IsLocalContext(qre.ctx) {
+		return
+	}
+	remoteAddr, username := qre.callerInfo()
+	for _, rewrite := range qre.plan.rewrites {
+		if rewrite.rule.GetAction(remoteAddr, username, qre.bindVars, qre.marginComments) == rules.QRRewrite {
+			qre.tsv.stats.QueryRuleRewrites.Add(rewrite.rule.Name, 1)
+			qre.plan = rewrite.plan
+			return
+		}
+	}
+}
+
 // checkPermissions returns an error if the query does not pass all checks
-// (denied query, table ACL).
-func (qre *QueryExecutor) checkPermissions() error {
+// (denied query, query rule limits, table ACL). Otherwise, the returned
+// release function must be called once the query is done.
+func (qre *QueryExecutor) checkPermissions() (release func(), err error) {
+	release = func() {}
 	// Skip permissions check if the context is local.
 	if tabletenv.IsLocalContext(qre.ctx) {
-		return nil
+		return release, nil
 	}
 
 	// Check if the query relates to a table that is in the denylist.
-	remoteAddr := ""
-	username := ""
-	ci, ok := callinfo.FromContext(qre.ctx)
-	if ok {
-		remoteAddr = ci.RemoteAddr()
-		username = ci.Username()
-	}
+	remoteAddr, username := qre.callerInfo()
 
-	action, ruleCancelCtx, timeout, desc := qre.plan.Rules.GetAction(remoteAddr, username, qre.bindVars, qre.marginComments)
+	action, ruleCancelCtx, timeout, desc, limiter := qre.plan.Rules.GetAction(remoteAddr, username, qre.bindVars, qre.marginComments)
 
 	bufferingTimeoutCtx, cancel := context.WithTimeout(qre.ctx, timeout) // aborts buffering at given timeout
 	defer cancel()
 
 	switch action {
 	case rules.QRFail:
-		return vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "disallowed due to rule: %s", desc)
+		return nil, vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "disallowed due to rule: %s", desc)
 	case rules.QRFailRetry:
-		return vterrors.Errorf(vtrpcpb.Code_FAILED_PRECONDITION, "disallowed due to rule: %s", desc)
+		return nil, vterrors.Errorf(vtrpcpb.Code_FAILED_PRECONDITION, "disallowed due to rule: %s", desc)
 	case rules.QRBuffer:
 		if ruleCancelCtx != nil {
 			// We buffer up to some timeout. The timeout is determined by ctx.Done().
@@ -491,44 +527,60 @@ func (qre *QueryExecutor) checkPermissions() error {
 				// good! We have buffered the query, and buffering is completed
 			case <-bufferingTimeoutCtx.Done():
 				// Sorry, timeout while waiting for buffering to complete
-				return vterrors.Errorf(vtrpcpb.Code_FAILED_PRECONDITION, "buffer timeout after %v in rule: %s", timeout, desc)
+				return nil, vterrors.Errorf(vtrpcpb.Code_FAILED_PRECONDITION, "buffer timeout after %v in rule: %s", timeout, desc)
+			}
+		}
+	case rules.QRRateLimit, rules.QRConcurrencyLimit:
+		if !limiter.Acquire() {
+			qre.tsv.stats.QueryRuleRejections.Add(limiter.Name(), 1)
+			if action == rules.QRRateLimit {
+				return nil, vterrors.Errorf(vtrpcpb.Code_RESOURCE_EXHAUSTED, "rate limit exceeded for rule: %s", desc)
 			}
+			return nil, vterrors.Errorf(vtrpcpb.Code_RESOURCE_EXHAUSTED, "concurrency limit exceeded for rule: %s", desc)
 		}
+		// The query was admitted, the slot is released if the ACL check
+		// fails or once the query is done.
+		defer func() {
+			if err != nil {
+				limiter.Release()
+			}
+		}()
+		release = limiter.Release
 	default:
 		// no rules against this query. Good to proceed
 	}
 	// Skip ACL check for queries against the dummy dual table
 	if qre.plan.TableName().String() == "dual" {
-		return nil
+		return release, nil
 	}
 
 	// Skip the ACL check if the connecting user is an exempted superuser.
 	if qre.tsv.qe.exemptACL != nil && qre.tsv.qe.exemptACL.IsMember(&querypb.VTGateCallerID{Username: username}) {
 		qre.tsv.qe.tableaclExemptCount.Add(1)
-		return nil
+		return release, nil
 	}
 
 	callerID := callerid.ImmediateCallerIDFromContext(qre.ctx)
 	if callerID == nil {
 		if qre.tsv.qe.strictTableACL {
-			return vterrors.Errorf(vtrpcpb.Code_UNAUTHENTICATED, "missing caller id")
+			return nil, vterrors.Errorf(vtrpcpb.Code_UNAUTHENTICATED, "missing caller id")
 		}
-		return nil
+		return release, nil
 	}
 
 	// Skip the ACL check if the caller id is an exempted superuser.
 	if qre.tsv.qe.exemptACL != nil && qre.tsv.qe.exemptACL.IsMember(callerID) {
 		qre.tsv.qe.tableaclExemptCount.Add(1)
-		return nil
+		return release, nil
 	}
 
 	for i, auth := range qre.plan.Authorized {
 		if err := qre.checkAccess(auth, qre.plan.Permissions[i].TableName, callerID); err != nil {
-			return err
+			return nil, err
 		}
 	}
 
-	return nil
+	return release, nil
 }
 
 func (qre *QueryExecutor) checkAccess(authorized *tableacl.ACLResult, tableName string, callerID *querypb.VTGateCallerID) error {
@@ -1133,6 +1185,74 @@ func (qre *QueryExecutor) execShowThrottlerStatus() (*sqltypes.Result, error) {
 	return result, nil
 }
 
+// execShowVitessProcesslist returns the queries running on the tablet, as
+// listed by /livequeryz. vtgate matches them with the shard sessions of its
+// connections using the StatefulID column. Unless the caller is exempted
+// from the table ACLs, only its own queries are listed.
+func (qre *QueryExecutor) execShowVitessProcesslist() (*sqltypes.Result, error) {
+	var rows []QueryDetailzRow
+	for _, ql := range []*QueryList{qre.tsv.statelessql, qre.tsv.statefulql, qre.tsv.olapql} {
+		rows = ql.AppendQueryzRows(rows)
+	}
+	if !qre.canListAllQueries() {
+		username := callerid.ImmediateCallerIDFromContext(qre.ctx).GetUsername()
+		rows = slices.DeleteFunc(rows, func(row QueryDetailzRow) bool {
+			return row.Username != username
+		})
+	}
+	result := &sqltypes.Result{
+		Fields: []*querypb.Field{
+			{
+				Name: "type",
+				Type: sqltypes.VarChar,
+			},
+			{
+				Name: "connection_id",
+				Type: sqltypes.Int64,
+			},
+			{
+				Name: "stateful_id",
+				Type: sqltypes.Int64,
+			},
+			{
+				Name: "time",
+				Type: sqltypes.Int64,
+			},
+			{
+				Name: "query",
+				Type: sqltypes.VarChar,
+			},
+		},
+		Rows: [][]sqltypes.Value{},
+	}
+	for _, row := range rows {
+		result.Rows = append(result.Rows,
+			[]sqltypes.Value{
+				sqltypes.NewVarChar(row.Type),
+				sqltypes.NewInt64(row.ConnID),
+				sqltypes.NewInt64(row.StatefulID),
+				sqltypes.NewInt64(int64(row.Duration.Seconds())),
+				sqltypes.NewVarChar(row.Query),
+			})
+	}
+	return result, nil
+}
+
+// canListAllQueries returns true if the caller can list the queries of all
+// the callers in SHOW VITESS_PROCESSLIST: local callers, callers exempted
+// from the table ACLs, and callers without caller id when the table ACLs
+// are not strict.
+func (qre *QueryExecutor) canListAllQueries() bool {
+	if tabletenv.IsLocalContext(qre.ctx) {
+		return true
+	}
+	callerID := callerid.ImmediateCallerIDFromContext(qre.ctx)
+	if callerID == nil {
+		return !qre.tsv.qe.strictTableACL
+	}
+	return qre.tsv.qe.exemptACL != nil && qre.tsv.qe.exemptACL.IsMember(callerID)
+}
+
 func (qre *QueryExecutor) drainResultSetOnConn(conn *connpool.Conn) error {
 	more := true
 	for more {
@@ -532,6 +532,64 @@ func TestQueryExecutorSelectImpossible(t *testing.T) {
 	}
 }
 
+func TestQueryExecutorShowVitessProcesslist(t *testing.T) {
+	db := setUpQueryExecutorTest(t)
+	defer db.Close()
+	ctx := context.Background()
+	tsv := newTestTabletServer(ctx, noFlags, db)
+	defer tsv.StopService()
+
+	target := tsv.sm.Target()
+	state, err := tsv.Begin(ctx, target, nil)
+	require.NoError(t, err)
+	defer tsv.Rollback(ctx, target, state.TransactionID)
+	conn, err := tsv.te.txPool.GetAndLock(state.TransactionID, "for test")
+	require.NoError(t, err)
+	defer conn.Unlock()
+
+	aliceCtx := callerid.NewContext(ctx, nil, &querypb.VTGateCallerID{Username: "alice"})
+	stateless := NewQueryDetail(aliceCtx, &testConn{id: 12, query: "select * from t"})
+	require.NoError(t, tsv.statelessql.Add(stateless))
+	defer tsv.statelessql.Remove(stateless)
+	stateful := NewQueryDetail(ctx, conn)
+	require.NoError(t, tsv.statefulql.Add(stateful))
+	defer tsv.statefulql.Remove(stateful)
+
+	qre := newTestQueryExecutor(ctx, tsv, "show vitess_processlist", 0)
+	got, err := qre.Execute()
+	require.NoError(t, err)
+	assert.Equal(t, "ShowVitessProcesslist", qre.logStats.PlanType)
+	require.Len(t, got.Rows, 2)
+	rows := got.Named().Rows
+	assert.Equal(t, "oltp-stateless", rows[0].AsString("type", ""))
+	assert.EqualValues(t, 12, rows[0].AsInt64("connection_id", 0))
+	assert.EqualValues(t, 0, rows[0].AsInt64("stateful_id", -1))
+	assert.Equal(t, "select * from t", rows[0].AsString("query", ""))
+	assert.Equal(t, "oltp-stateful", rows[1].AsString("type", ""))
+	assert.Equal(t, state.TransactionID, rows[1].AsInt64("stateful_id", 0))
+
+	// Callers only list their own queries.
+	qre = newTestQueryExecutor(aliceCtx, tsv, "show vitess_processlist", 0)
+	got, err = qre.Execute()
+	require.NoError(t, err)
+	require.Len(t, got.Rows, 1)
+	assert.EqualValues(t, 12, got.Named().Rows[0].AsInt64("connection_id", 0))
+
+	bobCtx := callerid.NewContext(ctx, nil, &querypb.VTGateCallerID{Username: "bob"})
+	qre = newTestQueryExecutor(bobCtx, tsv, "show vitess_processlist", 0)
+	got, err = qre.Execute()
+	require.NoError(t, err)
+	assert.Empty(t, got.Rows)
+
+	// Unless they are exempted from the table ACLs.
+	tsv.qe.exemptACL, err = (&simpleacl.Factory{}).New([]string{"bob"})
+	require.NoError(t, err)
+	qre = newTestQueryExecutor(bobCtx, tsv, "show vitess_processlist", 0)
+	got, err = qre.Execute()
+	require.NoError(t, err)
+	assert.Len(t, got.Rows, 2)
+}
+
 // TestDisableOnlineDDL checks whether disabling online DDLs throws the correct error or not
 func TestDisableOnlineDDL(t *testing.T) {
 	db := setUpQueryExecutorTest(t)
@@ -1270,6 +1328,130 @@ func TestQueryExecutorDenyListQRRetry(t *testing.T) {
 	}
 }
 
+func TestQueryExecutorQueryRuleLimits(t *testing.T) {
+	db := setUpQueryExecutorTest(t)
+	defer db.Close()
+	query := "select * from test_table where name = 1 limit 1000"
+	expandedQuery := "select pk from test_table use index (`index`) where name = 1 limit 1000"
+	expected := &sqltypes.Result{
+		Fields: getTestTableFields(),
+	}
+	db.AddQuery(query, expected)
+	db.AddQuery(expandedQuery, expected)
+	db.AddQuery("select * from test_table where `name` = 1 limit 1000", expected)
+
+	db.AddQuery("select * from test_table where 1 != 1", &sqltypes.Result{
+		Fields: getTestTableFields(),
+	})
+
+	rateLimitRule := rules.NewRateLimitQueryRule("limit selects by u1", "select_rate_limit", 0.001, 1)
+	rateLimitRule.SetUserCond("u1")
+	rateLimitRule.AddTableCond("test_table")
+	concurrencyLimitRule := rules.NewConcurrencyLimitQueryRule("limit concurrent selects by u2", "select_concurrency_limit", 1)
+	concurrencyLimitRule.SetUserCond("u2")
+	concurrencyLimitRule.AddTableCond("test_table")
+
+	rulesName := "queryRuleLimits"
+	qrs := rules.New()
+	qrs.Add(rateLimitRule)
+	qrs.Add(concurrencyLimitRule)
+
+	ctx := context.Background()
+	tsv := newTestTabletServer(ctx, noFlags, db)
+	tsv.qe.queryRuleSources.UnRegisterSource(rulesName)
+	tsv.qe.queryRuleSources.RegisterSource(rulesName)
+	defer tsv.qe.queryRuleSources.UnRegisterSource(rulesName)
+	defer tsv.StopService()
+
+	require.NoError(t, tsv.qe.queryRuleSources.SetRules(rulesName, qrs))
+
+	ctx = callinfo.NewContext(context.Background(), &fakecallinfo.FakeCallInfo{User: "u1"})
+	qre := newTestQueryExecutor(ctx, tsv, query, 0)
+	_, err := qre.Execute()
+	require.NoError(t, err)
+
+	// The burst of 1 query is used up.
+	qre = newTestQueryExecutor(ctx, tsv, query, 0)
+	_, err = qre.Execute()
+	assert.Equal(t, vtrpcpb.Code_RESOURCE_EXHAUSTED, vterrors.Code(err))
+	assert.ErrorContains(t, err, "rate limit exceeded for rule: limit selects by u1")
+	assert.EqualValues(t, 1, tsv.stats.QueryRuleRejections.Counts()["select_rate_limit"])
+
+	// Queries of u2 are only limited by their concurrency. Hold the only
+	// slot as if a query was running.
+	ctx = callinfo.NewContext(context.Background(), &fakecallinfo.FakeCallInfo{User: "u2"})
+	qre = newTestQueryExecutor(ctx, tsv, query, 0)
+	action, _, _, _, limiter := qre.plan.Rules.GetAction("", "u2", nil, sqlparser.MarginComments{})
+	require.Equal(t, rules.QRConcurrencyLimit, action)
+	require.True(t, limiter.Acquire())
+
+	_, err = qre.Execute()
+	assert.Equal(t, vtrpcpb.Code_RESOURCE_EXHAUSTED, vterrors.Code(err))
+	assert.ErrorContains(t, err, "concurrency limit exceeded for rule: limit concurrent selects by u2")
+	assert.EqualValues(t, 1, tsv.stats.QueryRuleRejections.Counts()["select_concurrency_limit"])
+
+	limiter.Release()
+	for range 3 {
+		qre = newTestQueryExecutor(ctx, tsv, query, 0)
+		_, err = qre.Execute()
+		require.NoError(t, err, "the slot is released once the query is done")
+	}
+}
+
+func TestQueryExecutorQueryRuleRewrite(t *testing.T) {
+	db := setUpQueryExecutorTest(t)
+	defer db.Close()
+	query := "select * from test_table where name = 1"
+	expected := &sqltypes.Result{
+		Fields: getTestTableFields(),
+	}
+	db.AddQuery("select * from test_table where `name` = 1 limit 10001", expected)
+	db.AddQuery("select /*+ MAX_EXECUTION_TIME(100) */ * from test_table force index (`index`) where `name` = 1 limit 10", expected)
+	db.AddQuery("select * from test_table where 1 != 1", &sqltypes.Result{
+		Fields: getTestTableFields(),
+	})
+
+	rewriteRule, err := rules.NewRewriteQueryRule("rewrite selects by u1", "select_rewrite", &planbuilder.Rewrite{
+		IndexHints:       []planbuilder.IndexHintRewrite{{Table: "test_table", Type: "FORCE", Indexes: []string{"index"}}},
+		Limit:            10,
+		MaxExecutionTime: 100,
+	})
+	require.NoError(t, err)
+	require.NoError(t, rewriteRule.SetUserCond("u1"))
+	rewriteRule.AddTableCond("test_table")
+
+	rulesName := "queryRuleRewrite"
+	qrs := rules.New()
+	qrs.Add(rewriteRule)
+
+	ctx := context.Background()
+	tsv := newTestTabletServer(ctx, noFlags, db)
+	tsv.qe.queryRuleSources.UnRegisterSource(rulesName)
+	tsv.qe.queryRuleSources.RegisterSource(rulesName)
+	defer tsv.qe.queryRuleSources.UnRegisterSource(rulesName)
+	defer tsv.StopService()
+
+	require.NoError(t, tsv.qe.queryRuleSources.SetRules(rulesName, qrs))
+
+	for _, user := range []string{"u1", "u2"} {
+		db.ResetQueryLog()
+		ctx = callinfo.NewContext(context.Background(), &fakecallinfo.FakeCallInfo{User: user})
+		qre := newTestQueryExecutor(ctx, tsv, query, 0)
+		plan := qre.plan
+		_, err = qre.Execute()
+		require.NoError(t, err)
+		if user == "u1" {
+			assert.Contains(t, db.QueryLog(), "select /*+ max_execution_time(100) */ * from test_table force index (`index`) where `name` = 1 limit 10")
+		} else {
+			assert.Contains(t, db.QueryLog(), "select * from test_table where `name` = 1 limit 10001")
+		}
+		// The stats are recorded against the plan of the original query.
+		assert.EqualValues(t, 1, plan.QueryCount)
+		plan.QueryCount = 0
+	}
+	assert.EqualValues(t, 1, tsv.stats.QueryRuleRewrites.Counts()["select_rewrite"])
+}
+
 func TestReplaceSchemaName(t *testing.T) {
 	db := setUpQueryExecutorTest(t)
 	defer db.Close()
@@ -25,6 +25,7 @@ import (
 	"github.com/google/safehtml"
 
 	"vitess.io/vitess/go/streamlog"
+	"vitess.io/vitess/go/vt/callerid"
 	"vitess.io/vitess/go/vt/callinfo"
 	"vitess.io/vitess/go/vt/log"
 	vtrpcpb "vitess.io/vitess/go/vt/proto/vtrpc"
@@ -173,6 +174,8 @@ type QueryDetailzRow struct {
 	Start             time.Time
 	Duration          time.Duration
 	ConnID            int64
+	StatefulID        int64  // id of the transaction or reserved connection, if any
+	Username          string // immediate caller of the query, if any
 	State             string
 	ShowTerminateLink bool
 }
@@ -199,6 +202,10 @@ func (ql *QueryList) AppendQueryzRows(rows []QueryDetailzRow) []QueryDetailzRow
 				Start:       qd.start,
 				Duration:    time.Since(qd.start),
 				ConnID:      qd.connID,
+				Username:    callerid.ImmediateCallerIDFromContext(qd.ctx).GetUsername(),
+			}
+			if sc, ok := qd.conn.(*StatefulConnection); ok {
+				row.StatefulID = sc.ConnID
 			}
 			rows = append(rows, row)
 		}
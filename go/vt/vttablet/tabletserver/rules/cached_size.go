@@ -39,13 +39,30 @@ func (cached *BindVarCond) CachedSize(alloc bool) int64 {
 	}
 	return size
 }
+func (cached *Limiter) CachedSize(alloc bool) int64 {
+	if cached == nil {
+		return int64(0)
+	}
+	size := int64(0)
+	if alloc {
+		size += int64(64)
+	}
+	// field name string
+	size += hack.RuntimeAllocSize(int64(len(cached.name)))
+	// field limiter *golang.org/x/time/rate.Limiter
+	if cached.limiter != nil {
+		// WARNING: size of external type golang.org/x/time/rate.Limiter cannot be fully calculated
+		size += hack.RuntimeAllocSize(int64(80))
+	}
+	return size
+}
 func (cached *Rule) CachedSize(alloc bool) int64 {
 	if cached == nil {
 		return int64(0)
 	}
 	size := int64(0)
 	if alloc {
-		size += int64(256)
+		size += int64(288)
 	}
 	// field Description string
 	size += hack.RuntimeAllocSize(int64(len(cached.Description)))
@@ -79,6 +96,10 @@ func (cached *Rule) CachedSize(alloc bool) int64 {
 			size += elem.CachedSize(false)
 		}
 	}
+	// field limiter *vitess.io/vitess/go/vt/vttablet/tabletserver/rules.Limiter
+	size += cached.limiter.CachedSize(true)
+	// field rewrite *vitess.io/vitess/go/vt/vttablet/tabletserver/planbuilder.Rewrite
+	size += cached.rewrite.CachedSize(true)
 	return size
 }
 func (cached *Rules) CachedSize(alloc bool) int64 {
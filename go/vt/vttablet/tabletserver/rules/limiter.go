@@ -0,0 +1,97 @@
+/*
+Copyright 2026 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package rules
+
+import (
+	"math"
+	"sync/atomic"
+
+	"golang.org/x/time/rate"
+)
+
+// Limiter enforces the QPS or concurrency limit of a QRRateLimit or
+// QRConcurrencyLimit rule. Copies of a rule share its Limiter, so the
+// limit applies to all the queries matching the rule on the tablet,
+// whatever their plan.
+type Limiter struct {
+	name string
+
+	// maxQPS and burst configure the token bucket of rate limits.
+	maxQPS  float64
+	burst   int
+	limiter *rate.Limiter
+
+	// maxConcurrency is the number of queries allowed to run at the same
+	// time for concurrency limits.
+	maxConcurrency int64
+	running        atomic.Int64
+}
+
+func newRateLimiter(name string, maxQPS float64, burst int) *Limiter {
+	if burst <= 0 {
+		burst = max(1, int(math.Ceil(maxQPS)))
+	}
+	return &Limiter{
+		name:    name,
+		maxQPS:  maxQPS,
+		burst:   burst,
+		limiter: rate.NewLimiter(rate.Limit(maxQPS), burst),
+	}
+}
+
+func newConcurrencyLimiter(name string, maxConcurrency int64) *Limiter {
+	return &Limiter{
+		name:           name,
+		maxConcurrency: maxConcurrency,
+	}
+}
+
+// Name returns the name of the rule the Limiter belongs to.
+func (l *Limiter) Name() string {
+	return l.name
+}
+
+// Acquire returns false if running one more query would exceed the limit.
+// Otherwise, Release must be called once the query is done.
+func (l *Limiter) Acquire() bool {
+	if l.limiter != nil {
+		return l.limiter.Allow()
+	}
+	if l.running.Add(1) > l.maxConcurrency {
+		l.running.Add(-1)
+		return false
+	}
+	return true
+}
+
+// Release releases a query admitted by Acquire.
+func (l *Limiter) Release() {
+	if l.limiter == nil {
+		l.running.Add(-1)
+	}
+}
+
+// Equal returns true if other enforces the same limit, otherwise false.
+func (l *Limiter) Equal(other *Limiter) bool {
+	if l == nil || other == nil {
+		return l == nil && other == nil
+	}
+	return l.name == other.name &&
+		l.maxQPS == other.maxQPS &&
+		l.burst == other.burst &&
+		l.maxConcurrency == other.maxConcurrency
+}
@@ -19,6 +19,8 @@ package rules
 import (
 	"encoding/json"
 	"errors"
+	"maps"
+	"slices"
 	"sync"
 
 	"vitess.io/vitess/go/vt/log"
@@ -60,15 +62,18 @@ func (qri *Map) UnRegisterSource(ruleSource string) {
 }
 
 // SetRules takes an external Rules structure and overwrite one of the
-// internal Rules as designated by ruleSource parameter.
+// internal Rules as designated by ruleSource parameter. The limit rules
+// that did not change keep their current limits.
 func (qri *Map) SetRules(ruleSource string, newRules *Rules) error {
 	if newRules == nil {
 		newRules = New()
 	}
 	qri.mu.Lock()
 	defer qri.mu.Unlock()
-	if _, ok := qri.queryRulesMap[ruleSource]; ok {
-		qri.queryRulesMap[ruleSource] = newRules.Copy()
+	if oldRules, ok := qri.queryRulesMap[ruleSource]; ok {
+		newRules = newRules.Copy()
+		newRules.reuseLimiters(oldRules)
+		qri.queryRulesMap[ruleSource] = newRules
 		return nil
 	}
 	return errors.New("Rule source identifier " + ruleSource + " is not valid")
@@ -86,12 +91,14 @@ func (qri *Map) Get(ruleSource string) (*Rules, error) {
 
 // FilterByPlan creates a new Rules by prefiltering on all query rules that are contained in internal
 // Rules structures, in other words, query rules from all predefined sources will be applied.
+// The sources are appended in the order of their names, so that the first matching rule
+// of the same kind does not change from one call to the next.
 func (qri *Map) FilterByPlan(query string, planid planbuilder.PlanType, tableNames ...string) (newqrs *Rules) {
 	qri.mu.Lock()
 	defer qri.mu.Unlock()
 	newqrs = New()
-	for _, rules := range qri.queryRulesMap {
-		newqrs.Append(rules.FilterByPlan(query, planid, tableNames...))
+	for _, ruleSource := range slices.Sorted(maps.Keys(qri.queryRulesMap)) {
+		newqrs.Append(qri.queryRulesMap[ruleSource].FilterByPlan(query, planid, tableNames...))
 	}
 	return newqrs
 }
@@ -21,6 +21,10 @@ import (
 	"strings"
 	"testing"
 
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"vitess.io/vitess/go/vt/sqlparser"
 	"vitess.io/vitess/go/vt/vttablet/tabletserver/planbuilder"
 )
 
@@ -203,6 +207,63 @@ func TestMapFilterByPlan(t *testing.T) {
 	}
 }
 
+func TestMapFilterByPlanLimitAndDenyList(t *testing.T) {
+	setupRules()
+	qri := NewMap()
+	qri.RegisterSource(denyListQueryRules)
+	qri.RegisterSource(customQueryRules)
+	qri.SetRules(denyListQueryRules, denyRules)
+	limitRules := New()
+	limitRules.Add(NewRateLimitQueryRule("rate limit", "rl", 1000, 1000))
+	qri.SetRules(customQueryRules, limitRules)
+
+	// The limit of the custom source must not hide the denylist.
+	for range 10 {
+		qrs := qri.FilterByPlan("select * from bannedtable2", planbuilder.PlanSelect, "bannedtable2")
+		if action, _, _, _, _ := qrs.GetAction("", "", nil, sqlparser.MarginComments{}); action != QRFailRetry {
+			t.Fatalf("Select from bannedtable2 got action %v, but we expect %v", action, QRFailRetry)
+		}
+	}
+	qrs := qri.FilterByPlan("select * from t_customer", planbuilder.PlanSelect, "t_customer")
+	if action, _, _, _, _ := qrs.GetAction("", "", nil, sqlparser.MarginComments{}); action != QRRateLimit {
+		t.Errorf("Select from t_customer got action %v, but we expect %v", action, QRRateLimit)
+	}
+}
+
+func TestMapSetRulesKeepsLimits(t *testing.T) {
+	qri := NewMap()
+	qri.RegisterSource(customQueryRules)
+	limitRules := func(maxConcurrency int64) *Rules {
+		qrs := New()
+		qrs.Add(NewConcurrencyLimitQueryRule("concurrency limit", "cl", maxConcurrency))
+		qrs.Add(NewRateLimitQueryRule("rate limit", "rl", 0.001, 1))
+		return qrs
+	}
+	limiter := func(name string) *Limiter {
+		qrs, err := qri.Get(customQueryRules)
+		require.NoError(t, err)
+		return qrs.Find(name).limiter
+	}
+
+	require.NoError(t, qri.SetRules(customQueryRules, limitRules(1)))
+	require.True(t, limiter("cl").Acquire())
+	require.True(t, limiter("rl").Acquire())
+
+	// Reloading the same rules keeps the running query and the used burst.
+	require.NoError(t, qri.SetRules(customQueryRules, limitRules(1)))
+	assert.False(t, limiter("cl").Acquire())
+	assert.False(t, limiter("rl").Acquire())
+	limiter("cl").Release()
+	assert.True(t, limiter("cl").Acquire())
+
+	// A changed limit starts over.
+	require.NoError(t, qri.SetRules(customQueryRules, limitRules(2)))
+	assert.True(t, limiter("cl").Acquire())
+	assert.True(t, limiter("cl").Acquire())
+	assert.False(t, limiter("cl").Acquire())
+	assert.False(t, limiter("rl").Acquire())
+}
+
 func TestMapJSON(t *testing.T) {
 	setupRules()
 	qri := NewMap()
@@ -86,6 +86,28 @@ func (qrs *Rules) CopyUnderlying() []*Rule {
 	return cpy
 }
 
+// reuseLimiters gives the limit rules of qrs the Limiter of the rule of old
+// with the same name and limit, if any, so that reloading the rules neither
+// refills the token buckets nor forgets the queries running under a
+// concurrency limit.
+func (qrs *Rules) reuseLimiters(old *Rules) {
+	limiters := map[string]*Limiter{}
+	for _, qr := range old.rules {
+		if qr.limiter != nil {
+			limiters[qr.limiter.Name()] = qr.limiter
+		}
+	}
+	for _, qr := range qrs.rules {
+		if qr.limiter == nil {
+			continue
+		}
+		if limiter := limiters[qr.limiter.Name()]; qr.limiter.Equal(limiter) {
+			qr.limiter = limiter
+			delete(limiters, limiter.Name())
+		}
+	}
+}
+
 // Append merges the rules from another Rules into the receiver
 func (qrs *Rules) Append(otherqrs *Rules) {
 	qrs.rules = append(qrs.rules, otherqrs.rules...)
@@ -170,6 +192,10 @@ func (qrs *Rules) FilterByPlan(query string, planid planbuilder.PlanType, tableN
 }
 
 // GetAction runs the input against the rules engine and returns the action to be performed.
+// For QRRateLimit and QRConcurrencyLimit, the returned Limiter enforces the limit of the rule.
+// A limit is only returned if no rule failing or buffering the query fires, so
+// the first matching limit rule cannot hide them.
+// QRRewrite rules are applied when planning the query, so they are skipped here.
 func (qrs *Rules) GetAction(
 	ip,
 	user string,
@@ -179,13 +205,36 @@ func (qrs *Rules) GetAction(
 	action Action,
 	cancelCtx context.Context,
 	timeout time.Duration,
-	desc string) {
+	desc string,
+	limiter *Limiter) {
+	var limitRule *Rule
 	for _, qr := range qrs.rules {
-		if act := qr.GetAction(ip, user, bindVars, marginComments); act != QRContinue {
-			return act, qr.cancelCtx, qr.timeout, qr.Description
+		if qr.act == QRRewrite || (limitRule != nil && qr.act.IsLimit()) {
+			continue
+		}
+		act := qr.GetAction(ip, user, bindVars, marginComments)
+		switch {
+		case act == QRContinue:
+		case act.IsLimit():
+			limitRule = qr
+		default:
+			return act, qr.cancelCtx, qr.timeout, qr.Description, qr.limiter
 		}
 	}
-	return QRContinue, nil, 0, ""
+	if limitRule != nil {
+		return limitRule.act, limitRule.cancelCtx, limitRule.timeout, limitRule.Description, limitRule.limiter
+	}
+	return QRContinue, nil, 0, "", nil
+}
+
+// Rewrites returns the QRRewrite rules, in order.
+func (qrs *Rules) Rewrites() (rewrites []*Rule) {
+	for _, qr := range qrs.rules {
+		if qr.act == QRRewrite {
+			rewrites = append(rewrites, qr)
+		}
+	}
+	return rewrites
 }
 
 // -----------------------------------------------
@@ -224,6 +273,13 @@ type Rule struct {
 
 	// a rule can timeout.
 	timeout time.Duration
+
+	// a rule can limit the rate or the concurrency of the queries it matches.
+	// It is shared by the copies of the rule.
+	limiter *Limiter
+
+	// a rule can rewrite the queries it matches.
+	rewrite *planbuilder.Rewrite
 }
 
 type namedRegexp struct {
@@ -256,6 +312,30 @@ func NewBufferedTableQueryRule(cancelCtx context.Context, tableName string, buff
 	return &Rule{cancelCtx: cancelCtx, timeout: bufferTimeout, Description: description, Name: bufferedTableRuleName, tableNames: []string{tableName}, act: QRBuffer}
 }
 
+// NewRateLimitQueryRule creates a new Rule that allows at most maxQPS
+// queries per second, with bursts of up to burst queries. If burst is not
+// positive, it defaults to maxQPS rounded up. The name must not be empty, as
+// the rejections of the rule are counted by name.
+func NewRateLimitQueryRule(description, name string, maxQPS float64, burst int) (qr *Rule) {
+	return &Rule{Description: description, Name: name, act: QRRateLimit, limiter: newRateLimiter(name, maxQPS, burst)}
+}
+
+// NewConcurrencyLimitQueryRule creates a new Rule that allows at most
+// maxConcurrency queries to run at the same time. The name must not be
+// empty, as the rejections of the rule are counted by name.
+func NewConcurrencyLimitQueryRule(description, name string, maxConcurrency int64) (qr *Rule) {
+	return &Rule{Description: description, Name: name, act: QRConcurrencyLimit, limiter: newConcurrencyLimiter(name, maxConcurrency)}
+}
+
+// NewRewriteQueryRule creates a new Rule that rewrites the queries it
+// matches before they are planned.
+func NewRewriteQueryRule(description, name string, rewrite *planbuilder.Rewrite) (qr *Rule, err error) {
+	if err := rewrite.Validate(); err != nil {
+		return nil, err
+	}
+	return &Rule{Description: description, Name: name, act: QRRewrite, rewrite: rewrite}, nil
+}
+
 // Equal returns true if other is equal to this Rule, otherwise false.
 func (qr *Rule) Equal(other *Rule) bool {
 	if qr == nil || other == nil {
@@ -272,7 +352,14 @@ func (qr *Rule) Equal(other *Rule) bool {
 		reflect.DeepEqual(qr.plans, other.plans) &&
 		reflect.DeepEqual(qr.tableNames, other.tableNames) &&
 		reflect.DeepEqual(qr.bindVarConds, other.bindVarConds) &&
-		qr.act == other.act)
+		qr.act == other.act &&
+		qr.limiter.Equal(other.limiter) &&
+		reflect.DeepEqual(qr.rewrite, other.rewrite))
+}
+
+// Rewrite returns the rewrite of a QRRewrite rule, otherwise nil.
+func (qr *Rule) Rewrite() *planbuilder.Rewrite {
+	return qr.rewrite
 }
 
 // Copy performs a deep copy of a Rule.
@@ -288,6 +375,8 @@ func (qr *Rule) Copy() (newqr *Rule) {
 		act:             qr.act,
 		cancelCtx:       qr.cancelCtx,
 		timeout:         qr.timeout,
+		limiter:         qr.limiter,
+		rewrite:         qr.rewrite,
 	}
 	if qr.plans != nil {
 		newqr.plans = make([]planbuilder.PlanType, len(qr.plans))
@@ -339,6 +428,18 @@ func (qr *Rule) MarshalJSON() ([]byte, error) {
 	if qr.timeout != 0 {
 		safeEncode(b, `,"Timeout":`, qr.timeout)
 	}
+	if qr.limiter != nil {
+		switch qr.act {
+		case QRRateLimit:
+			safeEncode(b, `,"MaxQPS":`, qr.limiter.maxQPS)
+			safeEncode(b, `,"Burst":`, qr.limiter.burst)
+		case QRConcurrencyLimit:
+			safeEncode(b, `,"MaxConcurrency":`, qr.limiter.maxConcurrency)
+		}
+	}
+	if qr.rewrite != nil {
+		safeEncode(b, `,"Rewrite":`, qr.rewrite)
+	}
 	_, _ = b.WriteString("}")
 	return b.Bytes(), nil
 }
@@ -573,8 +674,17 @@ const (
 	QRFail
 	QRFailRetry
 	QRBuffer
+	QRRateLimit
+	QRConcurrencyLimit
+	QRRewrite
 )
 
+// IsLimit returns true for the actions that limit, rather than
+// disallow, the queries matching the rule.
+func (act Action) IsLimit() bool {
+	return act == QRRateLimit || act == QRConcurrencyLimit
+}
+
 // MarshalJSON marshals to JSON.
 func (act Action) MarshalJSON() ([]byte, error) {
 	// If we add more actions, we'll need to use a map.
@@ -586,6 +696,12 @@ func (act Action) MarshalJSON() ([]byte, error) {
 		str = "FAIL_RETRY"
 	case QRBuffer:
 		str = "BUFFER"
+	case QRRateLimit:
+		str = "RATE_LIMIT"
+	case QRConcurrencyLimit:
+		str = "CONCURRENCY_LIMIT"
+	case QRRewrite:
+		str = "REWRITE"
 	default:
 		str = "INVALID"
 	}
@@ -871,9 +987,17 @@ func MapStrOperator(strop string) (op Operator, err error) {
 // BuildQueryRule builds a query rule from a ruleInfo.
 func BuildQueryRule(ruleInfo map[string]any) (qr *Rule, err error) {
 	qr = NewQueryRule("", "", QRFail)
+	// The limits are only known to be valid once we have seen the Action.
+	var (
+		maxQPS         float64
+		burst          int64
+		maxConcurrency int64
+		rewrite        *planbuilder.Rewrite
+	)
 	for k, v := range ruleInfo {
 		var sv string
 		var lv []any
+		var nv json.Number
 		var ok bool
 		switch k {
 		case "Name", "Description", "RequestIP", "User", "Query", "Action", "LeadingComment", "TrailingComment":
@@ -886,6 +1010,15 @@ func BuildQueryRule(ruleInfo map[string]any) (qr *Rule, err error) {
 			if !ok {
 				return nil, vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "want list for %s", k)
 			}
+		case "MaxQPS", "Burst", "MaxConcurrency":
+			nv, ok = v.(json.Number)
+			if !ok {
+				return nil, vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "want number for %s", k)
+			}
+		case "Rewrite":
+			if _, ok = v.(map[string]any); !ok {
+				return nil, vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "want json object for %s", k)
+			}
 		default:
 			return nil, vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "unrecognized tag %s", k)
 		}
@@ -958,14 +1091,91 @@ func BuildQueryRule(ruleInfo map[string]any) (qr *Rule, err error) {
 				qr.act = QRFailRetry
 			case "BUFFER":
 				qr.act = QRBuffer
+			case "RATE_LIMIT":
+				qr.act = QRRateLimit
+			case "CONCURRENCY_LIMIT":
+				qr.act = QRConcurrencyLimit
+			case "REWRITE":
+				qr.act = QRRewrite
 			default:
 				return nil, vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "invalid Action %s", sv)
 			}
+		case "MaxQPS":
+			maxQPS, err = nv.Float64()
+			if err != nil || maxQPS <= 0 {
+				return nil, vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "want positive number for MaxQPS: %s", nv)
+			}
+		case "Burst":
+			burst, err = nv.Int64()
+			if err != nil || burst <= 0 {
+				return nil, vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "want positive integer for Burst: %s", nv)
+			}
+		case "MaxConcurrency":
+			maxConcurrency, err = nv.Int64()
+			if err != nil || maxConcurrency <= 0 {
+				return nil, vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "want positive integer for MaxConcurrency: %s", nv)
+			}
+		case "Rewrite":
+			rewrite, err = buildRewrite(v)
+			if err != nil {
+				return nil, err
+			}
 		}
 	}
+
+	if (qr.act == QRRewrite) != (rewrite != nil) {
+		return nil, vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "Rewrite is required for, and only allowed for, the REWRITE Action")
+	}
+	qr.rewrite = rewrite
+
+	switch qr.act {
+	case QRRateLimit:
+		if maxQPS == 0 {
+			return nil, vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "MaxQPS missing for RATE_LIMIT Action")
+		}
+		if maxConcurrency != 0 {
+			return nil, vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "MaxConcurrency not allowed for RATE_LIMIT Action")
+		}
+		qr.limiter = newRateLimiter(qr.Name, maxQPS, int(burst))
+	case QRConcurrencyLimit:
+		if maxConcurrency == 0 {
+			return nil, vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "MaxConcurrency missing for CONCURRENCY_LIMIT Action")
+		}
+		if maxQPS != 0 || burst != 0 {
+			return nil, vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "MaxQPS and Burst not allowed for CONCURRENCY_LIMIT Action")
+		}
+		qr.limiter = newConcurrencyLimiter(qr.Name, maxConcurrency)
+	default:
+		if maxQPS != 0 || burst != 0 || maxConcurrency != 0 {
+			return nil, vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "MaxQPS, Burst and MaxConcurrency are only allowed for RATE_LIMIT and CONCURRENCY_LIMIT Actions")
+		}
+	}
+	// The rejections of the limit rules are counted by name.
+	if qr.act.IsLimit() && qr.Name == "" {
+		return nil, vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "Name is required for RATE_LIMIT and CONCURRENCY_LIMIT Actions")
+	}
 	return qr, nil
 }
 
+func buildRewrite(v any) (*planbuilder.Rewrite, error) {
+	// The rewrite is a plain struct, so let the json package decode it,
+	// and reject the fields it does not know about.
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "invalid Rewrite: %v", err)
+	}
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.DisallowUnknownFields()
+	rewrite := &planbuilder.Rewrite{}
+	if err := dec.Decode(rewrite); err != nil {
+		return nil, vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "invalid Rewrite: %v", err)
+	}
+	if err := rewrite.Validate(); err != nil {
+		return nil, err
+	}
+	return rewrite, nil
+}
+
 func buildBindVarCondition(bvc any) (name string, onAbsent, onMismatch bool, op Operator, value any, err error) {
 	bvcinfo, ok := bvc.(map[string]any)
 	if !ok {
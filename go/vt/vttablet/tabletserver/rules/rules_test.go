@@ -26,6 +26,7 @@ import (
 	"time"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 
 	"vitess.io/vitess/go/sqltypes"
 	"vitess.io/vitess/go/vt/sqlparser"
@@ -543,23 +544,23 @@ func TestAction(t *testing.T) {
 		Trailing: "other trailing comments",
 	}
 
-	action, cancelCtx, timeout, desc := qrs.GetAction("123", "user1", bv, mc)
+	action, cancelCtx, timeout, desc, _ := qrs.GetAction("123", "user1", bv, mc)
 	assert.Equalf(t, action, QRFail, "expected fail, got %v", action)
 	assert.Equalf(t, timeout, time.Duration(0), "expected zero timeout")
 	assert.Equalf(t, desc, "rule 1", "want rule 1, got %s", desc)
 	assert.Nil(t, cancelCtx)
 
-	action, cancelCtx, timeout, desc = qrs.GetAction("1234", "user", bv, mc)
+	action, cancelCtx, timeout, desc, _ = qrs.GetAction("1234", "user", bv, mc)
 	assert.Equalf(t, action, QRFailRetry, "want fail_retry, got: %s", action)
 	assert.Equalf(t, timeout, time.Duration(0), "expected zero timeout")
 	assert.Equalf(t, desc, "rule 2", "want rule 2, got %s", desc)
 	assert.Nil(t, cancelCtx)
 
-	action, _, _, _ = qrs.GetAction("1234", "user1", bv, mc)
+	action, _, _, _, _ = qrs.GetAction("1234", "user1", bv, mc)
 	assert.Equalf(t, action, QRContinue, "want continue, got %s", action)
 
 	bv["a"] = sqltypes.Uint64BindVariable(1)
-	action, _, _, desc = qrs.GetAction("1234", "user1", bv, mc)
+	action, _, _, desc, _ = qrs.GetAction("1234", "user1", bv, mc)
 	assert.Equalf(t, action, QRFail, "want fail, got %s", action)
 	assert.Equalf(t, desc, "rule 3", "want rule 3, got %s", desc)
 
@@ -572,7 +573,7 @@ func TestAction(t *testing.T) {
 	newQrs := qrs.Copy()
 	newQrs.Add(qr4)
 
-	action, _, _, desc = newQrs.GetAction("1234", "user1", bv, mc)
+	action, _, _, desc, _ = newQrs.GetAction("1234", "user1", bv, mc)
 	assert.Equalf(t, action, QRFail, "want fail, got %s", action)
 	assert.Equalf(t, desc, "rule 4", "want rule 4, got %s", desc)
 
@@ -581,7 +582,7 @@ func TestAction(t *testing.T) {
 
 	newQrs = qrs.Copy()
 	newQrs.Add(qr5)
-	action, _, _, desc = newQrs.GetAction("1234", "user1", bv, mc)
+	action, _, _, desc, _ = newQrs.GetAction("1234", "user1", bv, mc)
 	assert.Equalf(t, action, QRFail, "want fail, got %s", action)
 	assert.Equalf(t, desc, "rule 5", "want rule 5, got %s", desc)
 }
@@ -612,6 +613,29 @@ func TestImport(t *testing.T) {
 		"Description": "desc2",
 		"Name": "name2",
 		"Action": "FAIL"
+	},{
+		"Description": "desc3",
+		"Name": "name3",
+		"Query": "select.*",
+		"Action": "RATE_LIMIT",
+		"MaxQPS": 2.5,
+		"Burst": 5
+	},{
+		"Description": "desc4",
+		"Name": "name4",
+		"Action": "CONCURRENCY_LIMIT",
+		"MaxConcurrency": 10
+	},{
+		"Description": "desc5",
+		"Name": "name5",
+		"User": "app",
+		"TableNames": ["a"],
+		"Action": "REWRITE",
+		"Rewrite": {
+			"IndexHints": [{"Table": "a", "Type": "FORCE", "Indexes": ["idx_b"]}],
+			"Limit": 1000,
+			"MaxExecutionTime": 500
+		}
 	}]`
 	err := qrs.UnmarshalJSON([]byte(jsondata))
 	if err != nil {
@@ -734,6 +758,21 @@ var invalidjsons = []InvalidJSONCase{
 	{`[{"BindVarConds": [{"Name": "a", "OnAbsent": true, "OnMismatch": true, "Operator": "NOMATCH", "Value": "["}]}]`, "processing [: error parsing regexp: missing closing ]: `[$`"},
 	{`[{"Action": 1 }]`, "want string for Action"},
 	{`[{"Action": "foo" }]`, "invalid Action foo"},
+	{`[{"Action": "RATE_LIMIT", "MaxQPS": "1" }]`, "want number for MaxQPS"},
+	{`[{"Action": "RATE_LIMIT", "MaxQPS": 0 }]`, "want positive number for MaxQPS: 0"},
+	{`[{"Action": "RATE_LIMIT", "MaxQPS": 1, "Burst": 1.5 }]`, "want positive integer for Burst: 1.5"},
+	{`[{"Action": "RATE_LIMIT" }]`, "MaxQPS missing for RATE_LIMIT Action"},
+	{`[{"Action": "RATE_LIMIT", "MaxQPS": 1, "MaxConcurrency": 1 }]`, "MaxConcurrency not allowed for RATE_LIMIT Action"},
+	{`[{"Action": "CONCURRENCY_LIMIT", "MaxConcurrency": -1 }]`, "want positive integer for MaxConcurrency: -1"},
+	{`[{"Action": "CONCURRENCY_LIMIT" }]`, "MaxConcurrency missing for CONCURRENCY_LIMIT Action"},
+	{`[{"Action": "CONCURRENCY_LIMIT", "MaxConcurrency": 1, "Burst": 1 }]`, "MaxQPS and Burst not allowed for CONCURRENCY_LIMIT Action"},
+	{`[{"Action": "FAIL", "MaxConcurrency": 1 }]`, "MaxQPS, Burst and MaxConcurrency are only allowed for RATE_LIMIT and CONCURRENCY_LIMIT Actions"},
+	{`[{"Action": "CONCURRENCY_LIMIT", "MaxConcurrency": 1 }]`, "Name is required for RATE_LIMIT and CONCURRENCY_LIMIT Actions"},
+	{`[{"Action": "REWRITE", "Rewrite": [] }]`, "want json object for Rewrite"},
+	{`[{"Action": "REWRITE", "Rewrite": {"Limt": 1} }]`, "invalid Rewrite: json: unknown field \"Limt\""},
+	{`[{"Action": "REWRITE", "Rewrite": {"Limit": -1} }]`, "invalid limit: -1"},
+	{`[{"Action": "REWRITE" }]`, "Rewrite is required for, and only allowed for, the REWRITE Action"},
+	{`[{"Action": "FAIL", "Rewrite": {"Limit": 1} }]`, "Rewrite is required for, and only allowed for, the REWRITE Action"},
 }
 
 func TestInvalidJSON(t *testing.T) {
@@ -771,6 +810,103 @@ func TestBuildQueryRuleActionFail(t *testing.T) {
 	}
 }
 
+func TestLimitActions(t *testing.T) {
+	qrs := New()
+	qrs.Add(NewRateLimitQueryRule("rate limit", "rl", 1, 2))
+	qr := NewConcurrencyLimitQueryRule("concurrency limit", "cl", 2)
+	require.NoError(t, qr.SetUserCond("user2"))
+	qrs.Add(qr)
+
+	// Copies of the rules, like the ones of the query plans, share the limits.
+	filtered := qrs.FilterByPlan("select * from a", planbuilder.PlanSelect, "a")
+	assert.True(t, filtered.Equal(qrs))
+
+	action, _, _, desc, limiter := filtered.GetAction("", "user1", nil, sqlparser.MarginComments{})
+	require.Equal(t, QRRateLimit, action)
+	assert.Equal(t, "rate limit", desc)
+	assert.Equal(t, "rl", limiter.Name())
+	assert.True(t, limiter.Acquire())
+	assert.True(t, limiter.Acquire())
+	_, _, _, _, limiter = qrs.GetAction("", "user1", nil, sqlparser.MarginComments{})
+	assert.False(t, limiter.Acquire(), "the burst was used by the copy of the rule")
+
+	qrs = New()
+	qrs.Add(qr)
+	action, _, _, _, limiter = qrs.Copy().GetAction("", "user2", nil, sqlparser.MarginComments{})
+	require.Equal(t, QRConcurrencyLimit, action)
+	assert.True(t, limiter.Acquire())
+	assert.True(t, limiter.Acquire())
+	assert.False(t, limiter.Acquire())
+	limiter.Release()
+	_, _, _, _, limiter = qrs.GetAction("", "user2", nil, sqlparser.MarginComments{})
+	assert.True(t, limiter.Acquire())
+	assert.False(t, limiter.Acquire())
+
+	action, _, _, _, limiter = qrs.GetAction("", "user1", nil, sqlparser.MarginComments{})
+	assert.Equal(t, QRContinue, action)
+	assert.Nil(t, limiter)
+
+	assert.True(t, QRRateLimit.IsLimit())
+	assert.True(t, QRConcurrencyLimit.IsLimit())
+	assert.False(t, QRBuffer.IsLimit())
+	assert.False(t, NewRateLimitQueryRule("rate limit", "rl", 1, 2).Equal(NewRateLimitQueryRule("rate limit", "rl", 2, 2)))
+}
+
+func TestLimitActionsDoNotHideFailures(t *testing.T) {
+	limit := NewConcurrencyLimitQueryRule("concurrency limit", "cl", 1)
+	fail := NewQueryRule("denied", "deny", QRFail)
+	fail.AddTableCond("a")
+	buffer := NewQueryRule("buffered", "buffer", QRBuffer)
+	buffer.AddTableCond("b")
+
+	for _, order := range [][]*Rule{{limit, fail, buffer}, {fail, buffer, limit}} {
+		qrs := New()
+		for _, qr := range order {
+			qrs.Add(qr)
+		}
+
+		action, _, _, desc, limiter := qrs.FilterByPlan("select * from a", planbuilder.PlanSelect, "a").GetAction("", "", nil, sqlparser.MarginComments{})
+		assert.Equal(t, QRFail, action)
+		assert.Equal(t, "denied", desc)
+		assert.Nil(t, limiter)
+
+		action, _, _, desc, _ = qrs.FilterByPlan("select * from b", planbuilder.PlanSelect, "b").GetAction("", "", nil, sqlparser.MarginComments{})
+		assert.Equal(t, QRBuffer, action)
+		assert.Equal(t, "buffered", desc)
+
+		action, _, _, desc, limiter = qrs.FilterByPlan("select * from c", planbuilder.PlanSelect, "c").GetAction("", "", nil, sqlparser.MarginComments{})
+		assert.Equal(t, QRConcurrencyLimit, action)
+		assert.Equal(t, "concurrency limit", desc)
+		assert.Equal(t, "cl", limiter.Name())
+	}
+}
+
+func TestRewriteAction(t *testing.T) {
+	qrs := New()
+	qrs.Add(NewQueryRule("fail", "f", QRFail))
+	require.NoError(t, qrs.rules[0].SetUserCond("user1"))
+	rewrite := &planbuilder.Rewrite{Limit: 10}
+	qr, err := NewRewriteQueryRule("rewrite", "rw", rewrite)
+	require.NoError(t, err)
+	qrs.Add(qr)
+
+	// Rewrites are applied when planning, they are not returned as the
+	// action of the rules and do not mask the other rules.
+	action, _, _, _, _ := qrs.GetAction("", "user2", nil, sqlparser.MarginComments{})
+	assert.Equal(t, QRContinue, action)
+	action, _, _, _, _ = qrs.GetAction("", "user1", nil, sqlparser.MarginComments{})
+	assert.Equal(t, QRFail, action)
+	assert.Equal(t, QRRewrite, qr.GetAction("", "user2", nil, sqlparser.MarginComments{}))
+
+	rewrites := qrs.FilterByPlan("select * from a", planbuilder.PlanSelect, "a").Rewrites()
+	require.Len(t, rewrites, 1)
+	assert.True(t, rewrites[0].Equal(qr))
+	assert.Equal(t, rewrite, rewrites[0].Rewrite())
+
+	_, err = NewRewriteQueryRule("rewrite", "rw", &planbuilder.Rewrite{})
+	assert.EqualError(t, err, "empty rewrite")
+}
+
 func TestBadAddBindVarCond(t *testing.T) {
 	qr1 := NewQueryRule("rule 1", "r1", QRFail)
 	err := qr1.AddBindVarCond("a", true, false, QRMatch, uint64(1))
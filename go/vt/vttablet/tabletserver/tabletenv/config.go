@@ -27,6 +27,7 @@ import (
 	"github.com/spf13/pflag"
 	"google.golang.org/protobuf/encoding/prototext"
 
+	"vitess.io/vitess/go/cache/warmup"
 	"vitess.io/vitess/go/mysql"
 	"vitess.io/vitess/go/streamlog"
 	"vitess.io/vitess/go/vt/dbconfigs"
@@ -135,6 +136,10 @@ func registerTabletEnvFlags(fs *pflag.FlagSet) {
 	fs.IntVar(&currentConfig.StreamBufferSize, "queryserver-config-stream-buffer-size", defaultConfig.StreamBufferSize, "query server stream buffer size, the maximum number of bytes sent from vttablet for each stream call. It's recommended to keep this value in sync with vtgate's stream-buffer-size.")
 
 	fs.Int64Var(&currentConfig.QueryCacheMemory, "queryserver-config-query-cache-memory", defaultConfig.QueryCacheMemory, "query server query cache size in bytes, maximum amount of memory to be used for caching. vttablet analyzes every incoming query and generate a query plan, these plans are being cached in a lru cache. This config controls the capacity of the lru cache.")
+	fs.StringVar(&currentConfig.QueryCacheWarmup.File, "queryserver-config-query-cache-warmup-file", defaultConfig.QueryCacheWarmup.File, "local file the queries of the query cache are periodically saved to, and replanned from when the query engine opens, so the cache does not start empty after a restart. Disabled if empty.")
+	fs.DurationVar(&currentConfig.QueryCacheWarmup.Interval, "queryserver-config-query-cache-warmup-interval", defaultConfig.QueryCacheWarmup.Interval, "how often the queries of the query cache are saved to the warm-up file.")
+	fs.IntVar(&currentConfig.QueryCacheWarmup.MaxEntries, "queryserver-config-query-cache-warmup-max-entries", defaultConfig.QueryCacheWarmup.MaxEntries, "maximum number of queries saved to, and replanned from, the query cache warm-up file. The most executed queries are kept.")
+	fs.DurationVar(&currentConfig.QueryCacheWarmup.MaxDuration, "queryserver-config-query-cache-warmup-max-duration", defaultConfig.QueryCacheWarmup.MaxDuration, "maximum time spent replanning the queries of the query cache warm-up file when the query engine opens.")
 
 	fs.DurationVar(&currentConfig.SchemaReloadInterval, "queryserver-config-schema-reload-time", defaultConfig.SchemaReloadInterval, "query server schema reload time, how often vttablet reloads schemas from underlying MySQL instance. vttablet keeps table schemas in its own memory and periodically refreshes it from MySQL. This config controls the reload time.")
 	fs.DurationVar(&currentConfig.SchemaChangeReloadTimeout, "schema-change-reload-timeout", defaultConfig.SchemaChangeReloadTimeout, "query server schema change reload timeout, this is how long to wait for the signaled schema reload operation to complete before giving up")
@@ -332,6 +337,7 @@ type TabletConfig struct {
 	ConsolidatorQueryWaiterCap  int64         `json:"consolidatorMaxQueryWait,omitempty"`
 	QueryCacheMemory            int64         `json:"queryCacheMemory,omitempty"`
 	QueryCacheDoorkeeper        bool          `json:"queryCacheDoorkeeper,omitempty"`
+	QueryCacheWarmup            warmup.Config `json:"-"`
 	SchemaReloadInterval        time.Duration `json:"schemaReloadIntervalSeconds,omitempty"`
 	SchemaChangeReloadTimeout   time.Duration `json:"schemaChangeReloadTimeout,omitempty"`
 	WatchReplication            bool          `json:"watchReplication,omitempty"`
@@ -1087,6 +1093,11 @@ var defaultConfig = TabletConfig{
 	// The doorkeeper for the plan cache is disabled by default in endtoend tests to ensure
 	// results are consistent between runs.
 	QueryCacheDoorkeeper: !servenv.TestingEndtoend,
+	QueryCacheWarmup: warmup.Config{
+		Interval:    time.Minute,
+		MaxEntries:  10000,
+		MaxDuration: 30 * time.Second,
+	},
 	SchemaReloadInterval: 30 * time.Minute,
 	// SchemaChangeReloadTimeout is used for the signal reload operation where we have to query mysqld.
 	// The queries during the signal reload operation are typically expected to have low load,
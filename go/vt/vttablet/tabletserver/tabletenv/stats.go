@@ -49,6 +49,9 @@ type Stats struct {
 
 	QueryTimingsByTabletType *servenv.TimingsWrapper // Query timings split by current tablet type
 
+	QueryRuleRejections *stats.CountersWithSingleLabel // Queries rejected by the limit of a query rule
+	QueryRuleRewrites   *stats.CountersWithSingleLabel // Queries rewritten by a query rule
+
 	// Atomic Transactions
 	Unresolved         *stats.GaugesWithSingleLabel
 	CommitPreparedFail *stats.CountersWithSingleLabel
@@ -102,6 +105,9 @@ func NewStats(exporter *servenv.Exporter) *Stats {
 
 		QueryTimingsByTabletType: exporter.NewTimings("QueryTimingsByTabletType", "Query timings broken down by active tablet type", "TabletType"),
 
+		QueryRuleRejections: exporter.NewCountersWithSingleLabel("QueryRuleRejections", "Queries rejected by the rate or concurrency limit of a query rule", "Rule"),
+		QueryRuleRewrites:   exporter.NewCountersWithSingleLabel("QueryRuleRewrites", "Queries rewritten by a query rule", "Rule"),
+
 		Unresolved:         exporter.NewGaugesWithSingleLabel("UnresolvedTransaction", "Current unresolved transactions", "ManagerType"),
 		CommitPreparedFail: exporter.NewCountersWithSingleLabel("CommitPreparedFail", "failed prepared transactions commit", "FailureType"),
 		RedoPreparedFail:   exporter.NewCountersWithSingleLabel("RedoPreparedFail", "failed prepared transactions on redo", "FailureType"),
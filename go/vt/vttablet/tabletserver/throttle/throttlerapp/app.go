@@ -77,6 +77,8 @@ const (
 	MessagerName      Name = "messager"
 	SchemaTrackerName Name = "schema-tracker"
 
+	BackupName Name = "backup"
+
 	TestingName                Name = "test"
 	TestingAlwaysThrottledName Name = "always-throttled-app"
 )